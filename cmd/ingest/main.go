@@ -0,0 +1,11 @@
+package main
+
+import (
+	"os"
+
+	"github.com/adwski/vidi/internal/app/ingest"
+)
+
+func main() {
+	os.Exit(ingest.NewApp().Run())
+}
@@ -23,12 +23,25 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-const (
-	endpointUserLogin    = "http://localhost:18081/api/user/login"
-	endpointUserRegister = "http://localhost:18081/api/user/register"
+// Endpoints of api services, they are changed by useStandaloneEndpoints.
+var (
+	endpointUserAPI      = "http://localhost:18081/api/user"
+	endpointUserLogin    = endpointUserAPI + "/login"
+	endpointUserRegister = endpointUserAPI + "/register"
 	endpointVideo        = "http://localhost:18082/api/video/"
 	endpointWatch        = "http://localhost:18082/api/watch/"
+)
 
+// useStandaloneEndpoints points api endpoints to listener of standalone app.
+func useStandaloneEndpoints() {
+	endpointUserAPI = "http://localhost:18080/api/user"
+	endpointUserLogin = endpointUserAPI + "/login"
+	endpointUserRegister = endpointUserAPI + "/register"
+	endpointVideo = "http://localhost:18080/api/video/"
+	endpointWatch = "http://localhost:18080/api/watch/"
+}
+
+const (
 	partSize                 = 10 * 1024 * 1024
 	contentTypeVidiMediaPart = "application/x-vidi-mediapart"
 
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/adwski/vidi/internal/app/processor"
+	"github.com/adwski/vidi/internal/app/standalone"
 	"github.com/adwski/vidi/internal/app/streamer"
 	"github.com/adwski/vidi/internal/app/uploader"
 	"github.com/adwski/vidi/internal/app/user"
@@ -36,6 +37,29 @@ func TestMain(m *testing.M) {
 		ctx, cancel = context.WithCancel(context.Background())
 	)
 
+	if os.Getenv("VIDI_E2E_STANDALONE") != "" {
+		// all services are run by single app without db, redis and s3
+		useStandaloneEndpoints()
+		wg.Add(1)
+		go func() {
+			standalone.NewApp().RunWithContextAndConfig(ctx, "standalone.yaml")
+			wg.Done()
+		}()
+	} else {
+		runApps(ctx, wg)
+	}
+
+	time.Sleep(5 * time.Second)
+
+	code := m.Run()
+	cancel()
+	wg.Wait()
+	defer func() {
+		os.Exit(code)
+	}()
+}
+
+func runApps(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(5)
 	go func() {
 		user.NewApp().RunWithContextAndConfig(ctx, "userapi.yaml")
@@ -57,15 +81,6 @@ func TestMain(m *testing.M) {
 		streamer.NewApp().RunWithContextAndConfig(ctx, "streamer.yaml")
 		wg.Done()
 	}()
-
-	time.Sleep(5 * time.Second)
-
-	code := m.Run()
-	cancel()
-	wg.Wait()
-	defer func() {
-		os.Exit(code)
-	}()
 }
 
 const (
@@ -29,7 +29,7 @@ const (
 	VIDITe2ePassword = "vidittestpassword"
 
 	testRCFG = `{
-  "user_api_url": "http://localhost:18081/api/user",
+  "user_api_url": "%s",
   "video_api_url": "localhost:18092",
   "vidi_ca": "%s"
 }`
@@ -53,7 +53,7 @@ func TestVidit_MainFlow(t *testing.T) {
 	b, err := os.ReadFile("cert.pem")
 	require.NoError(t, err)
 
-	remoteConfig := fmt.Sprintf(testRCFG, base64.StdEncoding.EncodeToString(b))
+	remoteConfig := fmt.Sprintf(testRCFG, endpointUserAPI, base64.StdEncoding.EncodeToString(b))
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -403,7 +403,7 @@ func TestVidit_Login(t *testing.T) {
 	b, err := os.ReadFile("cert.pem")
 	require.NoError(t, err)
 
-	remoteConfig := fmt.Sprintf(testRCFG, base64.StdEncoding.EncodeToString(b))
+	remoteConfig := fmt.Sprintf(testRCFG, endpointUserAPI, base64.StdEncoding.EncodeToString(b))
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -537,7 +537,7 @@ func TestVidit_LoginExistingUser(t *testing.T) {
 	b, err := os.ReadFile("cert.pem")
 	require.NoError(t, err)
 
-	remoteConfig := fmt.Sprintf(testRCFG, base64.StdEncoding.EncodeToString(b))
+	remoteConfig := fmt.Sprintf(testRCFG, endpointUserAPI, base64.StdEncoding.EncodeToString(b))
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -651,7 +651,7 @@ func TestVidit_ResumeUpload(t *testing.T) {
 	b, err := os.ReadFile("cert.pem")
 	require.NoError(t, err)
 
-	remoteConfig := fmt.Sprintf(testRCFG, base64.StdEncoding.EncodeToString(b))
+	remoteConfig := fmt.Sprintf(testRCFG, endpointUserAPI, base64.StdEncoding.EncodeToString(b))
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
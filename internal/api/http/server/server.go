@@ -50,6 +50,11 @@ func (s *Server) SetHandler(h http.Handler) {
 	s.srv.Handler = h
 }
 
+// Handler returns handler of server, so it could be served by other server.
+func (s *Server) Handler() http.Handler {
+	return s.srv.Handler
+}
+
 func (s *Server) Run(ctx context.Context, wg *sync.WaitGroup, errc chan<- error) {
 	defer wg.Done()
 	if s.srv.Handler == nil {
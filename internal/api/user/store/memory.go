@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	"github.com/adwski/vidi/internal/api/user/model"
+)
+
+// MemoryStore is a user store that keeps users in process memory.
+// It is intended for standalone deployments and tests, users are lost on restart.
+// Passwords are hashed the same way as in database store.
+type MemoryStore struct {
+	users map[string]*memoryUser
+	ids   map[string]struct{}
+	mx    sync.RWMutex
+}
+
+type memoryUser struct {
+	id   string
+	hash string
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		users: make(map[string]*memoryUser),
+		ids:   make(map[string]struct{}),
+	}
+}
+
+func (s *MemoryStore) Close() {}
+
+func (s *MemoryStore) Get(_ context.Context, u *model.User) error {
+	s.mx.RLock()
+	mu, ok := s.users[u.Name]
+	s.mx.RUnlock()
+	if !ok {
+		return model.ErrNotFound
+	}
+	u.ID = mu.id
+	return comparePwd(mu.hash, u.Password)
+}
+
+func (s *MemoryStore) Create(_ context.Context, u *model.User) error {
+	hash, err := hashPwd(u.Password)
+	if err != nil {
+		return err
+	}
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if _, ok := s.users[u.Name]; ok {
+		return model.ErrAlreadyExists
+	}
+	if _, ok := s.ids[u.ID]; ok {
+		return model.ErrUIDAlreadyExists
+	}
+	s.users[u.Name] = &memoryUser{id: u.ID, hash: hash}
+	s.ids[u.ID] = struct{}{}
+	return nil
+}
@@ -0,0 +1,29 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adwski/vidi/internal/api/user/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+	defer s.Close()
+	ctx := context.Background()
+
+	require.NoError(t, s.Create(ctx, &model.User{ID: "id1", Name: "user", Password: "password"}))
+	require.ErrorIs(t, s.Create(ctx, &model.User{ID: "id2", Name: "user", Password: "password"}),
+		model.ErrAlreadyExists)
+	require.ErrorIs(t, s.Create(ctx, &model.User{ID: "id1", Name: "other", Password: "password"}),
+		model.ErrUIDAlreadyExists)
+
+	u := &model.User{Name: "user", Password: "password"}
+	require.NoError(t, s.Get(ctx, u))
+	assert.Equal(t, "id1", u.ID)
+
+	require.ErrorIs(t, s.Get(ctx, &model.User{Name: "user", Password: "wrong"}), model.ErrIncorrectCredentials)
+	require.ErrorIs(t, s.Get(ctx, &model.User{Name: "other", Password: "password"}), model.ErrNotFound)
+}
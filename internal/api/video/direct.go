@@ -0,0 +1,117 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	user "github.com/adwski/vidi/internal/api/user/model"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"go.uber.org/zap"
+)
+
+// DirectUploadStore is a media store that allows to upload parts directly using presigned urls.
+// Presigned urls should only accept object of specified size and sha256 checksum,
+// and store should verify checksum on upload, so uploaded parts are never downloaded
+// to check them.
+type DirectUploadStore interface {
+	PresignPut(ctx context.Context, name string, size uint64, checksum string, expires time.Duration) (string, error)
+	StatSha256(ctx context.Context, name string) (int64, string, error)
+}
+
+// DirectUploadConfig enables direct uploads.
+type DirectUploadConfig struct {
+	Store DirectUploadStore
+	// PathPrefix is a prefix of upload parts in media store, it should be the same as uploader uses.
+	PathPrefix string
+	// URLTTL is a validity period of presigned urls.
+	URLTTL time.Duration
+}
+
+// CompleteUpload checks parts of directly uploaded video in media store.
+// Parts are checked the same way as uploader does it, so video becomes uploaded
+// when all parts are valid. Parts that are not uploaded yet are skipped,
+// and returned video has fresh upload urls of parts that should be uploaded (again).
+func (svc *Service) CompleteUpload(ctx context.Context, usr *user.User, vid string) (*model.Video, error) {
+	if svc.direct == nil {
+		return nil, model.ErrDirectUploadDisabled
+	}
+	video, err := svc.s.Get(ctx, vid, usr.ID)
+	if err != nil {
+		return nil, errors.Join(model.ErrStorage, err)
+	}
+	if !video.DirectUpload {
+		return nil, model.ErrNotDirectUpload
+	}
+	if !video.Resumable() {
+		return nil, model.ErrNotResumable
+	}
+	for _, part := range video.UploadInfo.Parts {
+		if part.Status == model.PartStatusOK {
+			continue
+		}
+		checksum, errC := svc.getPartChecksum(ctx, video, part)
+		if errC != nil {
+			if errors.Is(errC, fs.ErrNotExist) {
+				// part is not uploaded yet
+				continue
+			}
+			return nil, errors.Join(model.ErrInternal, errC)
+		}
+		if err = svc.s.UpdatePart(ctx, vid, &model.Part{Num: part.Num, Checksum: checksum}); err != nil {
+			return nil, errors.Join(model.ErrStorage, err)
+		}
+	}
+
+	if video, err = svc.s.Get(ctx, vid, usr.ID); err != nil {
+		return nil, errors.Join(model.ErrStorage, err)
+	}
+	if video.Resumable() {
+		if err = svc.presignParts(ctx, video); err != nil {
+			return nil, err
+		}
+	}
+	svc.logger.Debug("direct upload checked",
+		zap.String("vid", vid),
+		zap.String("status", video.Status.String()))
+	return video, nil
+}
+
+// getPartChecksum returns checksum of uploaded part that media store verified on upload.
+// If size of uploaded part does not match or part has no checksum, empty checksum
+// is returned, so part is marked as invalid (checksums are mandatory for direct uploads).
+func (svc *Service) getPartChecksum(ctx context.Context, video *model.Video, part *model.Part) (string, error) {
+	size, checksum, err := svc.direct.Store.StatSha256(ctx, svc.getDirectPartName(video, part.Num))
+	if err != nil {
+		return "", fmt.Errorf("cannot get uploaded part: %w", err)
+	}
+	if uint64(size) != part.Size {
+		return "", nil
+	}
+	return checksum, nil
+}
+
+// presignParts sets presigned upload urls for parts that are not uploaded yet.
+func (svc *Service) presignParts(ctx context.Context, video *model.Video) error {
+	for _, part := range video.UploadInfo.Parts {
+		if part.Status == model.PartStatusOK {
+			continue
+		}
+		url, err := svc.direct.Store.PresignPut(ctx, svc.getDirectPartName(video, part.Num),
+			part.Size, part.Checksum, svc.direct.URLTTL)
+		if err != nil {
+			return errors.Join(model.ErrInternal, err)
+		}
+		part.URL = url
+	}
+	return nil
+}
+
+// getDirectPartName returns name of part in media store. Parts are stored
+// under upload location the same way as uploader stores them.
+func (svc *Service) getDirectPartName(video *model.Video, num uint) string {
+	return fmt.Sprintf("%s/%s/%d", strings.TrimSuffix(svc.direct.PathPrefix, "/"), video.Location, num)
+}
@@ -0,0 +1,140 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"testing"
+	"time"
+
+	usermodel "github.com/adwski/vidi/internal/api/user/model"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeObject struct {
+	checksum string
+	size     int64
+}
+
+type fakeDirectStore struct {
+	objects map[string]fakeObject
+}
+
+func (f *fakeDirectStore) PresignPut(_ context.Context, name string, size uint64, checksum string, _ time.Duration) (string, error) {
+	return fmt.Sprintf("http://s3%s?size=%d&checksum=%s", name, size, checksum), nil
+}
+
+func (f *fakeDirectStore) StatSha256(_ context.Context, name string) (int64, string, error) {
+	obj, ok := f.objects[name]
+	if !ok {
+		return 0, "", fs.ErrNotExist
+	}
+	return obj.size, obj.checksum, nil
+}
+
+func TestService_CreateVideoDirect(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  s,
+		DirectUpload: &DirectUploadConfig{
+			Store:      &fakeDirectStore{},
+			PathPrefix: "/upload/",
+			URLTTL:     time.Hour,
+		},
+	})
+	u := &usermodel.User{ID: "test"}
+
+	_, err = svc.CreateVideo(ctx, u, &model.CreateRequest{
+		Name:         "test",
+		Size:         4,
+		DirectUpload: true,
+		Parts:        []*model.Part{{Num: 0, Size: 4}},
+	})
+	require.ErrorIs(t, err, model.ErrNoChecksums)
+
+	s.EXPECT().Create(ctx, mock.Anything).Run(func(_ context.Context, v *model.Video) {
+		assert.True(t, v.DirectUpload)
+	}).Return(nil).Once()
+
+	v, err := svc.CreateVideo(ctx, u, &model.CreateRequest{
+		Name:         "test",
+		Size:         4,
+		DirectUpload: true,
+		Parts:        []*model.Part{{Num: 0, Size: 4, Checksum: "qwe"}},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, v.UploadInfo.URL)
+	assert.Equal(t, "http://s3/upload/"+v.Location+"/0?size=4&checksum=qwe", v.UploadInfo.Parts[0].URL)
+
+	svc.direct = nil
+	_, err = svc.CreateVideo(ctx, u, &model.CreateRequest{
+		Name:         "test",
+		Size:         4,
+		DirectUpload: true,
+		Parts:        []*model.Part{{Num: 0, Size: 4, Checksum: "qwe"}},
+	})
+	require.ErrorIs(t, err, model.ErrDirectUploadDisabled)
+}
+
+func TestService_CompleteUpload(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	ds := &fakeDirectStore{objects: map[string]fakeObject{
+		"/upload/loc/0": {size: 4, checksum: "aaa"},
+		"/upload/loc/1": {size: 3, checksum: "bbb"},
+	}}
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  s,
+		DirectUpload: &DirectUploadConfig{
+			Store:      ds,
+			PathPrefix: "/upload",
+		},
+	})
+	u := &usermodel.User{ID: "test"}
+
+	newVideo := func() *model.Video {
+		return &model.Video{
+			ID:           "vid",
+			Location:     "loc",
+			Status:       model.StatusUploading,
+			DirectUpload: true,
+			UploadInfo: &model.UploadInfo{Parts: []*model.Part{
+				{Num: 0, Size: 4, Checksum: "aaa", Status: model.PartStatusInProgress},
+				{Num: 1, Size: 4, Checksum: "bbb", Status: model.PartStatusInProgress},
+				{Num: 2, Size: 2, Checksum: "ccc", Status: model.PartStatusInProgress},
+			}},
+		}
+	}
+
+	s.EXPECT().Get(ctx, "vid", "test").Return(newVideo(), nil).Twice()
+	// checksum verified by media store
+	s.EXPECT().UpdatePart(ctx, "vid", &model.Part{Num: 0, Checksum: "aaa"}).Return(nil).Once()
+	// size mismatch
+	s.EXPECT().UpdatePart(ctx, "vid", &model.Part{Num: 1}).Return(nil).Once()
+
+	v, err := svc.CompleteUpload(ctx, u, "vid")
+	require.NoError(t, err)
+	for _, part := range v.UploadInfo.Parts {
+		assert.Equal(t, fmt.Sprintf("http://s3/upload/loc/%d?size=%d&checksum=%s",
+			part.Num, part.Size, part.Checksum), part.URL)
+	}
+
+	notDirect := newVideo()
+	notDirect.DirectUpload = false
+	s.EXPECT().Get(ctx, "vid", "test").Return(notDirect, nil).Once()
+	_, err = svc.CompleteUpload(ctx, u, "vid")
+	require.ErrorIs(t, err, model.ErrNotDirectUpload)
+}
@@ -0,0 +1,88 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	user "github.com/adwski/vidi/internal/api/user/model"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"go.uber.org/zap"
+)
+
+// Duplicate policies.
+const (
+	// DuplicatesAllow does not check uploads for duplicates.
+	DuplicatesAllow DuplicatePolicy = iota
+	// DuplicatesReject rejects upload of content that already exists.
+	DuplicatesReject
+	// DuplicatesAlias creates video that uses processed output of existing video
+	// instead of uploading the same content again.
+	DuplicatesAlias
+)
+
+// DuplicatePolicy defines how upload of already existing content is handled.
+type DuplicatePolicy int
+
+// DuplicatesConfig configures duplicate detection.
+// Duplicates are detected by content fingerprint derived from part checksums,
+// so uploads without checksums are never considered duplicates.
+//
+// Checksums are claimed by client before anything is uploaded, so only videos
+// of the same user are matched: otherwise anyone who knows checksums of other
+// user's video would get access to it.
+type DuplicatesConfig struct {
+	Policy DuplicatePolicy
+}
+
+// ParseDuplicatePolicy parses duplicate policy, it could be "allow", "reject" or "alias".
+func ParseDuplicatePolicy(policy string) (DuplicatePolicy, error) {
+	switch policy {
+	case "allow", "":
+		return DuplicatesAllow, nil
+	case "reject":
+		return DuplicatesReject, nil
+	case "alias":
+		return DuplicatesAlias, nil
+	}
+	return 0, fmt.Errorf("unknown duplicate policy: %s", policy)
+}
+
+// handleDuplicate looks up ready video of user with the same content and applies duplicate policy.
+// It returns alias video if one is created, or nil if upload should proceed as usual.
+func (svc *Service) handleDuplicate(
+	ctx context.Context,
+	usr *user.User,
+	name, fingerprint string,
+) (*model.Video, error) {
+	if svc.duplicates.Policy == DuplicatesAllow || fingerprint == "" {
+		return nil, nil
+	}
+	source, err := svc.s.FindDuplicate(ctx, fingerprint, usr.ID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Join(model.ErrStorage, err)
+	}
+	if svc.duplicates.Policy == DuplicatesReject {
+		return nil, &model.DuplicateError{VideoID: source.ID}
+	}
+
+	// Alias has no media of its own, so it does not consume size quota.
+	// Output is not purged while it is referenced by alias.
+	alias := model.NewVideoNoID(usr.ID, name, 0)
+	alias.Status = model.StatusReady
+	alias.PlaybackMeta = source.PlaybackMeta
+	alias.OutputLocation = source.OutputLocation
+	alias.Fingerprint = fingerprint
+	alias.UploadInfo = &model.UploadInfo{}
+	if err = svc.storeNewVideo(ctx, alias); err != nil {
+		return nil, err
+	}
+	alias.UploadInfo = nil
+	svc.logger.Debug("alias created",
+		zap.String("vid", alias.ID),
+		zap.String("source", source.ID))
+	return alias, nil
+}
@@ -0,0 +1,144 @@
+package video
+
+import (
+	"context"
+	"testing"
+
+	usermodel "github.com/adwski/vidi/internal/api/user/model"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/mp4/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseDuplicatePolicy(t *testing.T) {
+	for policy, want := range map[string]DuplicatePolicy{
+		"":       DuplicatesAllow,
+		"allow":  DuplicatesAllow,
+		"reject": DuplicatesReject,
+		"alias":  DuplicatesAlias,
+	} {
+		got, err := ParseDuplicatePolicy(policy)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseDuplicatePolicy("qwe")
+	require.Error(t, err)
+}
+
+func TestService_CreateVideoDuplicate(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	u := &usermodel.User{ID: "test"}
+	req := func() *model.CreateRequest {
+		return &model.CreateRequest{
+			Name:     "test",
+			Size:     15,
+			PartSize: 10,
+			Parts:    []*model.Part{{Num: 0, Size: 10, Checksum: "aaa"}, {Num: 1, Size: 5, Checksum: "bbb"}},
+		}
+	}
+	fingerprint := model.Fingerprint(15, 10, req().Parts)
+	source := &model.Video{
+		ID:             "src",
+		UserID:         "test",
+		Status:         model.StatusReady,
+		OutputLocation: "srcloc",
+		PlaybackMeta:   &meta.Meta{},
+	}
+
+	tests := []struct {
+		name   string
+		cfg    DuplicatesConfig
+		source *model.Video
+		err    string
+		alias  bool
+	}{
+		{
+			name:   "reject own video",
+			cfg:    DuplicatesConfig{Policy: DuplicatesReject},
+			source: source,
+			err:    model.ErrDuplicate.Error() + ": src",
+		},
+		{
+			name:   "create alias",
+			cfg:    DuplicatesConfig{Policy: DuplicatesAlias},
+			source: source,
+			alias:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewMockStore(t)
+			svc := NewService(&ServiceConfig{
+				Logger:     logger,
+				Store:      s,
+				Duplicates: tt.cfg,
+			})
+			s.EXPECT().FindDuplicate(ctx, fingerprint, "test").Return(tt.source, nil).Once()
+			if tt.alias {
+				s.EXPECT().Create(ctx, mock.Anything).Run(func(_ context.Context, v *model.Video) {
+					assert.Equal(t, "srcloc", v.OutputLocation)
+					assert.NotEqual(t, "srcloc", v.Location)
+					assert.Equal(t, fingerprint, v.Fingerprint)
+					assert.Zero(t, v.Size)
+					assert.Empty(t, v.UploadInfo.Parts)
+				}).Return(nil).Once()
+			}
+
+			v, errC := svc.CreateVideo(ctx, u, req())
+			if tt.err != "" {
+				require.ErrorIs(t, errC, model.ErrDuplicate)
+				assert.Equal(t, tt.err, errC.Error())
+				return
+			}
+			require.NoError(t, errC)
+			assert.Equal(t, model.StatusReady, v.Status)
+			assert.Nil(t, v.UploadInfo)
+		})
+	}
+}
+
+func TestService_CreateVideoNoDuplicate(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	ss := NewMockSessionStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger:             logger,
+		Store:              s,
+		UploadSessionStore: ss,
+		UploadURLPrefix:    "http://test",
+		Duplicates:         DuplicatesConfig{Policy: DuplicatesAlias},
+	})
+	u := &usermodel.User{ID: "test"}
+	parts := []*model.Part{{Num: 0, Size: 10, Checksum: "aaa"}}
+
+	s.EXPECT().FindDuplicate(ctx, mock.Anything, "test").Return(nil, model.ErrNotFound).Once()
+	s.EXPECT().Create(ctx, mock.Anything).Run(func(_ context.Context, v *model.Video) {
+		assert.Equal(t, model.Fingerprint(10, 10, parts), v.Fingerprint)
+		assert.Equal(t, v.Location, v.OutputLocation)
+	}).Return(nil).Once()
+	ss.EXPECT().Set(ctx, mock.Anything).Return(nil).Once()
+
+	v, err := svc.CreateVideo(ctx, u, &model.CreateRequest{Name: "test", Size: 10, PartSize: 10, Parts: parts})
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusCreated, v.Status)
+
+	// parts without checksums are not checked
+	s.EXPECT().Create(ctx, mock.Anything).Return(nil).Once()
+	ss.EXPECT().Set(ctx, mock.Anything).Return(nil).Once()
+	_, err = svc.CreateVideo(ctx, u, &model.CreateRequest{
+		Name:     "test",
+		Size:     10,
+		PartSize: 10,
+		Parts:    []*model.Part{{Num: 0, Size: 10}},
+	})
+	require.NoError(t, err)
+}
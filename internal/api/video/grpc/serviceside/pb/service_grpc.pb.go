@@ -24,6 +24,14 @@ const (
 	Servicesideapi_UpdateVideo_FullMethodName       = "/videoapi.servicesideapi/UpdateVideo"
 	Servicesideapi_UpdateVideoStatus_FullMethodName = "/videoapi.servicesideapi/UpdateVideoStatus"
 	Servicesideapi_NotifyPartUpload_FullMethodName  = "/videoapi.servicesideapi/NotifyPartUpload"
+	Servicesideapi_ReprocessVideos_FullMethodName   = "/videoapi.servicesideapi/ReprocessVideos"
+	Servicesideapi_GetPurgeJobs_FullMethodName      = "/videoapi.servicesideapi/GetPurgeJobs"
+	Servicesideapi_UpdatePurgeJob_FullMethodName    = "/videoapi.servicesideapi/UpdatePurgeJob"
+	Servicesideapi_GetPurgeStatus_FullMethodName    = "/videoapi.servicesideapi/GetPurgeStatus"
+	Servicesideapi_GetImportJobs_FullMethodName     = "/videoapi.servicesideapi/GetImportJobs"
+	Servicesideapi_UpdateImportJob_FullMethodName   = "/videoapi.servicesideapi/UpdateImportJob"
+	Servicesideapi_ReportViews_FullMethodName       = "/videoapi.servicesideapi/ReportViews"
+	Servicesideapi_GetWatchMeta_FullMethodName      = "/videoapi.servicesideapi/GetWatchMeta"
 )
 
 // ServicesideapiClient is the client API for Servicesideapi service.
@@ -34,6 +42,14 @@ type ServicesideapiClient interface {
 	UpdateVideo(ctx context.Context, in *UpdateVideoRequest, opts ...grpc.CallOption) (*UpdateVideoResponse, error)
 	UpdateVideoStatus(ctx context.Context, in *UpdateVideoStatusRequest, opts ...grpc.CallOption) (*UpdateVideoStatusResponse, error)
 	NotifyPartUpload(ctx context.Context, in *NotifyPartUploadRequest, opts ...grpc.CallOption) (*NotifyPartUploadResponse, error)
+	ReprocessVideos(ctx context.Context, in *ReprocessVideosRequest, opts ...grpc.CallOption) (*ReprocessVideosResponse, error)
+	GetPurgeJobs(ctx context.Context, in *GetPurgeJobsRequest, opts ...grpc.CallOption) (*PurgeJobsResponse, error)
+	UpdatePurgeJob(ctx context.Context, in *UpdatePurgeJobRequest, opts ...grpc.CallOption) (*UpdatePurgeJobResponse, error)
+	GetPurgeStatus(ctx context.Context, in *GetPurgeStatusRequest, opts ...grpc.CallOption) (*PurgeStatusResponse, error)
+	GetImportJobs(ctx context.Context, in *GetImportJobsRequest, opts ...grpc.CallOption) (*ImportJobsResponse, error)
+	UpdateImportJob(ctx context.Context, in *UpdateImportJobRequest, opts ...grpc.CallOption) (*UpdateImportJobResponse, error)
+	ReportViews(ctx context.Context, in *ReportViewsRequest, opts ...grpc.CallOption) (*ReportViewsResponse, error)
+	GetWatchMeta(ctx context.Context, in *GetWatchMetaRequest, opts ...grpc.CallOption) (*WatchMetaResponse, error)
 }
 
 type servicesideapiClient struct {
@@ -80,6 +96,78 @@ func (c *servicesideapiClient) NotifyPartUpload(ctx context.Context, in *NotifyP
 	return out, nil
 }
 
+func (c *servicesideapiClient) ReprocessVideos(ctx context.Context, in *ReprocessVideosRequest, opts ...grpc.CallOption) (*ReprocessVideosResponse, error) {
+	out := new(ReprocessVideosResponse)
+	err := c.cc.Invoke(ctx, Servicesideapi_ReprocessVideos_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicesideapiClient) GetPurgeJobs(ctx context.Context, in *GetPurgeJobsRequest, opts ...grpc.CallOption) (*PurgeJobsResponse, error) {
+	out := new(PurgeJobsResponse)
+	err := c.cc.Invoke(ctx, Servicesideapi_GetPurgeJobs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicesideapiClient) UpdatePurgeJob(ctx context.Context, in *UpdatePurgeJobRequest, opts ...grpc.CallOption) (*UpdatePurgeJobResponse, error) {
+	out := new(UpdatePurgeJobResponse)
+	err := c.cc.Invoke(ctx, Servicesideapi_UpdatePurgeJob_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicesideapiClient) GetPurgeStatus(ctx context.Context, in *GetPurgeStatusRequest, opts ...grpc.CallOption) (*PurgeStatusResponse, error) {
+	out := new(PurgeStatusResponse)
+	err := c.cc.Invoke(ctx, Servicesideapi_GetPurgeStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicesideapiClient) GetImportJobs(ctx context.Context, in *GetImportJobsRequest, opts ...grpc.CallOption) (*ImportJobsResponse, error) {
+	out := new(ImportJobsResponse)
+	err := c.cc.Invoke(ctx, Servicesideapi_GetImportJobs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicesideapiClient) UpdateImportJob(ctx context.Context, in *UpdateImportJobRequest, opts ...grpc.CallOption) (*UpdateImportJobResponse, error) {
+	out := new(UpdateImportJobResponse)
+	err := c.cc.Invoke(ctx, Servicesideapi_UpdateImportJob_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicesideapiClient) ReportViews(ctx context.Context, in *ReportViewsRequest, opts ...grpc.CallOption) (*ReportViewsResponse, error) {
+	out := new(ReportViewsResponse)
+	err := c.cc.Invoke(ctx, Servicesideapi_ReportViews_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *servicesideapiClient) GetWatchMeta(ctx context.Context, in *GetWatchMetaRequest, opts ...grpc.CallOption) (*WatchMetaResponse, error) {
+	out := new(WatchMetaResponse)
+	err := c.cc.Invoke(ctx, Servicesideapi_GetWatchMeta_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ServicesideapiServer is the server API for Servicesideapi service.
 // All implementations must embed UnimplementedServicesideapiServer
 // for forward compatibility
@@ -88,6 +176,14 @@ type ServicesideapiServer interface {
 	UpdateVideo(context.Context, *UpdateVideoRequest) (*UpdateVideoResponse, error)
 	UpdateVideoStatus(context.Context, *UpdateVideoStatusRequest) (*UpdateVideoStatusResponse, error)
 	NotifyPartUpload(context.Context, *NotifyPartUploadRequest) (*NotifyPartUploadResponse, error)
+	ReprocessVideos(context.Context, *ReprocessVideosRequest) (*ReprocessVideosResponse, error)
+	GetPurgeJobs(context.Context, *GetPurgeJobsRequest) (*PurgeJobsResponse, error)
+	UpdatePurgeJob(context.Context, *UpdatePurgeJobRequest) (*UpdatePurgeJobResponse, error)
+	GetPurgeStatus(context.Context, *GetPurgeStatusRequest) (*PurgeStatusResponse, error)
+	GetImportJobs(context.Context, *GetImportJobsRequest) (*ImportJobsResponse, error)
+	UpdateImportJob(context.Context, *UpdateImportJobRequest) (*UpdateImportJobResponse, error)
+	ReportViews(context.Context, *ReportViewsRequest) (*ReportViewsResponse, error)
+	GetWatchMeta(context.Context, *GetWatchMetaRequest) (*WatchMetaResponse, error)
 	mustEmbedUnimplementedServicesideapiServer()
 }
 
@@ -107,6 +203,30 @@ func (UnimplementedServicesideapiServer) UpdateVideoStatus(context.Context, *Upd
 func (UnimplementedServicesideapiServer) NotifyPartUpload(context.Context, *NotifyPartUploadRequest) (*NotifyPartUploadResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NotifyPartUpload not implemented")
 }
+func (UnimplementedServicesideapiServer) ReprocessVideos(context.Context, *ReprocessVideosRequest) (*ReprocessVideosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReprocessVideos not implemented")
+}
+func (UnimplementedServicesideapiServer) GetPurgeJobs(context.Context, *GetPurgeJobsRequest) (*PurgeJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPurgeJobs not implemented")
+}
+func (UnimplementedServicesideapiServer) UpdatePurgeJob(context.Context, *UpdatePurgeJobRequest) (*UpdatePurgeJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePurgeJob not implemented")
+}
+func (UnimplementedServicesideapiServer) GetPurgeStatus(context.Context, *GetPurgeStatusRequest) (*PurgeStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPurgeStatus not implemented")
+}
+func (UnimplementedServicesideapiServer) GetImportJobs(context.Context, *GetImportJobsRequest) (*ImportJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetImportJobs not implemented")
+}
+func (UnimplementedServicesideapiServer) UpdateImportJob(context.Context, *UpdateImportJobRequest) (*UpdateImportJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateImportJob not implemented")
+}
+func (UnimplementedServicesideapiServer) ReportViews(context.Context, *ReportViewsRequest) (*ReportViewsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportViews not implemented")
+}
+func (UnimplementedServicesideapiServer) GetWatchMeta(context.Context, *GetWatchMetaRequest) (*WatchMetaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWatchMeta not implemented")
+}
 func (UnimplementedServicesideapiServer) mustEmbedUnimplementedServicesideapiServer() {}
 
 // UnsafeServicesideapiServer may be embedded to opt out of forward compatibility for this service.
@@ -192,6 +312,150 @@ func _Servicesideapi_NotifyPartUpload_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Servicesideapi_ReprocessVideos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReprocessVideosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicesideapiServer).ReprocessVideos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Servicesideapi_ReprocessVideos_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicesideapiServer).ReprocessVideos(ctx, req.(*ReprocessVideosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Servicesideapi_GetPurgeJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPurgeJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicesideapiServer).GetPurgeJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Servicesideapi_GetPurgeJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicesideapiServer).GetPurgeJobs(ctx, req.(*GetPurgeJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Servicesideapi_UpdatePurgeJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePurgeJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicesideapiServer).UpdatePurgeJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Servicesideapi_UpdatePurgeJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicesideapiServer).UpdatePurgeJob(ctx, req.(*UpdatePurgeJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Servicesideapi_GetPurgeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPurgeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicesideapiServer).GetPurgeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Servicesideapi_GetPurgeStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicesideapiServer).GetPurgeStatus(ctx, req.(*GetPurgeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Servicesideapi_GetImportJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetImportJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicesideapiServer).GetImportJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Servicesideapi_GetImportJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicesideapiServer).GetImportJobs(ctx, req.(*GetImportJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Servicesideapi_UpdateImportJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateImportJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicesideapiServer).UpdateImportJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Servicesideapi_UpdateImportJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicesideapiServer).UpdateImportJob(ctx, req.(*UpdateImportJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Servicesideapi_ReportViews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportViewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicesideapiServer).ReportViews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Servicesideapi_ReportViews_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicesideapiServer).ReportViews(ctx, req.(*ReportViewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Servicesideapi_GetWatchMeta_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWatchMetaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServicesideapiServer).GetWatchMeta(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Servicesideapi_GetWatchMeta_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServicesideapiServer).GetWatchMeta(ctx, req.(*GetWatchMetaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Servicesideapi_ServiceDesc is the grpc.ServiceDesc for Servicesideapi service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -215,6 +479,38 @@ var Servicesideapi_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "NotifyPartUpload",
 			Handler:    _Servicesideapi_NotifyPartUpload_Handler,
 		},
+		{
+			MethodName: "ReprocessVideos",
+			Handler:    _Servicesideapi_ReprocessVideos_Handler,
+		},
+		{
+			MethodName: "GetPurgeJobs",
+			Handler:    _Servicesideapi_GetPurgeJobs_Handler,
+		},
+		{
+			MethodName: "UpdatePurgeJob",
+			Handler:    _Servicesideapi_UpdatePurgeJob_Handler,
+		},
+		{
+			MethodName: "GetPurgeStatus",
+			Handler:    _Servicesideapi_GetPurgeStatus_Handler,
+		},
+		{
+			MethodName: "GetImportJobs",
+			Handler:    _Servicesideapi_GetImportJobs_Handler,
+		},
+		{
+			MethodName: "UpdateImportJob",
+			Handler:    _Servicesideapi_UpdateImportJob_Handler,
+		},
+		{
+			MethodName: "ReportViews",
+			Handler:    _Servicesideapi_ReportViews_Handler,
+		},
+		{
+			MethodName: "GetWatchMeta",
+			Handler:    _Servicesideapi_GetWatchMeta_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "internal/api/video/grpc/protobuf/service.proto",
@@ -11,6 +11,7 @@ import (
 	g "github.com/adwski/vidi/internal/api/video/grpc"
 	"github.com/adwski/vidi/internal/api/video/grpc/serviceside/pb"
 	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/vmihailenco/msgpack/v5"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -56,11 +57,16 @@ func (srv *Server) GetVideosByStatus(ctx context.Context, req *pb.GetByStatusReq
 	resp.Videos = make([]*pb.Video, 0, len(videos))
 	for _, v := range videos {
 		pbv := &pb.Video{
-			Id:        v.ID,
-			Status:    int32(v.Status),
-			CreatedAt: uint64(v.CreatedAt.Unix()),
-			Location:  v.Location,
-			Size:      v.Size,
+			Id:             v.ID,
+			Status:         int32(v.Status),
+			CreatedAt:      uint64(v.CreatedAt.Unix()),
+			Location:       v.Location,
+			OutputLocation: v.OutputLocation,
+			Size:           v.Size,
+			PartSize:       v.PartSize,
+		}
+		if v.ReprocessLocation != "" {
+			pbv.OutputLocation = v.ReprocessLocation
 		}
 		if v.UploadInfo != nil {
 			pbv.Parts = make([]*pb.Part, 0, len(v.UploadInfo.Parts))
@@ -120,6 +126,187 @@ func (srv *Server) NotifyPartUpload(
 	return &pb.NotifyPartUploadResponse{}, nil
 }
 
+func (srv *Server) ReprocessVideos(
+	ctx context.Context,
+	req *pb.ReprocessVideosRequest,
+) (*pb.ReprocessVideosResponse, error) {
+	if err := checkServiceClaims(ctx); err != nil {
+		return nil, err
+	}
+	ids, err := srv.videoSvc.ReprocessVideos(ctx, req.Ids, req.OutdatedVersion)
+	switch {
+	case errors.Is(err, model.ErrNoReprocessCriteria):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		srv.logger.Error("ReprocessVideos failed", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.ReprocessVideosResponse{Ids: ids}, nil
+}
+
+func (srv *Server) GetPurgeJobs(ctx context.Context, req *pb.GetPurgeJobsRequest) (*pb.PurgeJobsResponse, error) {
+	if err := checkServiceClaims(ctx); err != nil {
+		return nil, err
+	}
+	purges, err := srv.videoSvc.GetPurgeJobs(ctx, uint(req.Limit))
+	if err != nil {
+		srv.logger.Error("GetPurgeJobs failed", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &pb.PurgeJobsResponse{Jobs: make([]*pb.PurgeJob, 0, len(purges))}
+	for _, p := range purges {
+		resp.Jobs = append(resp.Jobs, &pb.PurgeJob{
+			Id:       p.ID,
+			VideoId:  p.VideoID,
+			Kind:     int32(p.Kind),
+			Location: p.Location,
+			Attempts: uint32(p.Attempts),
+		})
+	}
+	return resp, nil
+}
+
+func (srv *Server) UpdatePurgeJob(
+	ctx context.Context,
+	req *pb.UpdatePurgeJobRequest,
+) (*pb.UpdatePurgeJobResponse, error) {
+	if err := checkServiceClaims(ctx); err != nil {
+		return nil, err
+	}
+	err := srv.videoSvc.UpdatePurge(ctx, &model.PurgeUpdate{
+		ID:      req.Id,
+		Deleted: req.Deleted,
+		Done:    req.Done,
+		Error:   req.Error,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.UpdatePurgeJobResponse{}, nil
+}
+
+func (srv *Server) GetPurgeStatus(ctx context.Context, _ *pb.GetPurgeStatusRequest) (*pb.PurgeStatusResponse, error) {
+	if err := checkServiceClaims(ctx); err != nil {
+		return nil, err
+	}
+	stats, err := srv.videoSvc.GetPurgeStats(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.PurgeStatusResponse{
+		Pending:  stats.Pending,
+		Retrying: stats.Retrying,
+		Done:     stats.Done,
+		Deleted:  stats.Deleted,
+	}, nil
+}
+
+func (srv *Server) GetImportJobs(ctx context.Context, req *pb.GetImportJobsRequest) (*pb.ImportJobsResponse, error) {
+	if err := checkServiceClaims(ctx); err != nil {
+		return nil, err
+	}
+	imports, err := srv.videoSvc.GetImportJobs(ctx, uint(req.Limit))
+	if err != nil {
+		srv.logger.Error("GetImportJobs failed", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &pb.ImportJobsResponse{Jobs: make([]*pb.ImportJob, 0, len(imports))}
+	for _, i := range imports {
+		resp.Jobs = append(resp.Jobs, &pb.ImportJob{
+			Id:         i.ID,
+			VideoId:    i.VideoID,
+			Location:   i.Location,
+			Url:        i.URL,
+			Size:       i.Size,
+			PartSize:   i.PartSize,
+			Downloaded: i.Downloaded,
+			Attempts:   uint32(i.Attempts),
+		})
+	}
+	return resp, nil
+}
+
+func (srv *Server) UpdateImportJob(
+	ctx context.Context,
+	req *pb.UpdateImportJobRequest,
+) (*pb.UpdateImportJobResponse, error) {
+	if err := checkServiceClaims(ctx); err != nil {
+		return nil, err
+	}
+	err := srv.videoSvc.UpdateImport(ctx, &model.ImportUpdate{
+		ID:         req.Id,
+		Downloaded: req.Downloaded,
+		Done:       req.Done,
+		Error:      req.Error,
+		Fatal:      req.Fatal,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.UpdateImportJobResponse{}, nil
+}
+
+// ReportViews records playback activity aggregated by streamer.
+// Reports that cannot be recorded are returned to streamer, so it retries only them.
+func (srv *Server) ReportViews(ctx context.Context, req *pb.ReportViewsRequest) (*pb.ReportViewsResponse, error) {
+	if err := checkServiceClaims(ctx); err != nil {
+		return nil, err
+	}
+	reports := make([]*model.ViewReport, 0, len(req.Reports))
+	for _, r := range req.Reports {
+		segments := make([]uint, 0, len(r.Segments))
+		for _, seg := range r.Segments {
+			segments = append(segments, uint(seg))
+		}
+		reports = append(reports, &model.ViewReport{
+			SessionID: r.SessionId,
+			VideoID:   r.VideoId,
+			UserID:    r.UserId,
+			NewView:   r.NewView,
+			Requests:  r.Requests,
+			Bytes:     r.Bytes,
+			Segments:  segments,
+		})
+	}
+	failed, err := srv.videoSvc.ReportViews(ctx, reports)
+	if err != nil {
+		srv.logger.Error("ReportViews failed", zap.Int("failedReports", len(failed)), zap.Error(err))
+	}
+	resp := &pb.ReportViewsResponse{Failed: make([]*pb.ViewReportKey, 0, len(failed))}
+	for _, key := range failed {
+		resp.Failed = append(resp.Failed, &pb.ViewReportKey{SessionId: key.SessionID, VideoId: key.VideoID})
+	}
+	return resp, nil
+}
+
+func (srv *Server) GetWatchMeta(ctx context.Context, req *pb.GetWatchMetaRequest) (*pb.WatchMetaResponse, error) {
+	if err := checkServiceClaims(ctx); err != nil {
+		return nil, err
+	}
+	periods, err := srv.videoSvc.GetWatchMeta(ctx, req.Id, req.UserId, req.Playlist)
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, model.ErrNotReady),
+		errors.Is(err, model.ErrState),
+		errors.Is(err, model.ErrEmptyPlaylist):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		srv.logger.Error("GetWatchMeta failed", zap.Error(err))
+		return nil, status.Error(codes.Internal, "cannot get playback meta")
+	}
+	resp := &pb.WatchMetaResponse{PlaybackMeta: make([][]byte, 0, len(periods))}
+	for _, mt := range periods {
+		b, errM := msgpack.Marshal(mt)
+		if errM != nil {
+			srv.logger.Error("cannot encode playback meta", zap.Error(errM))
+			return nil, status.Error(codes.Internal, "cannot encode playback meta")
+		}
+		resp.PlaybackMeta = append(resp.PlaybackMeta, b)
+	}
+	return resp, nil
+}
+
 func checkServiceClaims(ctx context.Context) error {
 	claims, ok := auth.GetClaimsFromContext(ctx)
 	if !ok {
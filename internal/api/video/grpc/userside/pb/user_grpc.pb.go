@@ -20,12 +20,26 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Usersideapi_GetQuota_FullMethodName    = "/videoapi.usersideapi/GetQuota"
-	Usersideapi_CreateVideo_FullMethodName = "/videoapi.usersideapi/CreateVideo"
-	Usersideapi_GetVideo_FullMethodName    = "/videoapi.usersideapi/GetVideo"
-	Usersideapi_GetVideos_FullMethodName   = "/videoapi.usersideapi/GetVideos"
-	Usersideapi_DeleteVideo_FullMethodName = "/videoapi.usersideapi/DeleteVideo"
-	Usersideapi_WatchVideo_FullMethodName  = "/videoapi.usersideapi/WatchVideo"
+	Usersideapi_GetQuota_FullMethodName            = "/videoapi.usersideapi/GetQuota"
+	Usersideapi_CreateVideo_FullMethodName         = "/videoapi.usersideapi/CreateVideo"
+	Usersideapi_CreateLiveVideo_FullMethodName     = "/videoapi.usersideapi/CreateLiveVideo"
+	Usersideapi_CreateClip_FullMethodName          = "/videoapi.usersideapi/CreateClip"
+	Usersideapi_GetVideo_FullMethodName            = "/videoapi.usersideapi/GetVideo"
+	Usersideapi_CompleteUpload_FullMethodName      = "/videoapi.usersideapi/CompleteUpload"
+	Usersideapi_ImportFromURL_FullMethodName       = "/videoapi.usersideapi/ImportFromURL"
+	Usersideapi_GetImport_FullMethodName           = "/videoapi.usersideapi/GetImport"
+	Usersideapi_GetVideos_FullMethodName           = "/videoapi.usersideapi/GetVideos"
+	Usersideapi_GetVideoStats_FullMethodName       = "/videoapi.usersideapi/GetVideoStats"
+	Usersideapi_DeleteVideo_FullMethodName         = "/videoapi.usersideapi/DeleteVideo"
+	Usersideapi_WatchVideo_FullMethodName          = "/videoapi.usersideapi/WatchVideo"
+	Usersideapi_GetStreams_FullMethodName          = "/videoapi.usersideapi/GetStreams"
+	Usersideapi_GetSessions_FullMethodName         = "/videoapi.usersideapi/GetSessions"
+	Usersideapi_RevokeSession_FullMethodName       = "/videoapi.usersideapi/RevokeSession"
+	Usersideapi_RevokeVideoSessions_FullMethodName = "/videoapi.usersideapi/RevokeVideoSessions"
+	Usersideapi_CreatePlaylist_FullMethodName      = "/videoapi.usersideapi/CreatePlaylist"
+	Usersideapi_GetPlaylist_FullMethodName         = "/videoapi.usersideapi/GetPlaylist"
+	Usersideapi_DeletePlaylist_FullMethodName      = "/videoapi.usersideapi/DeletePlaylist"
+	Usersideapi_WatchPlaylist_FullMethodName       = "/videoapi.usersideapi/WatchPlaylist"
 )
 
 // UsersideapiClient is the client API for Usersideapi service.
@@ -34,10 +48,24 @@ const (
 type UsersideapiClient interface {
 	GetQuota(ctx context.Context, in *GetQuotaRequest, opts ...grpc.CallOption) (*QuotaResponse, error)
 	CreateVideo(ctx context.Context, in *CreateVideoRequest, opts ...grpc.CallOption) (*VideoResponse, error)
+	CreateLiveVideo(ctx context.Context, in *CreateLiveVideoRequest, opts ...grpc.CallOption) (*VideoResponse, error)
+	CreateClip(ctx context.Context, in *CreateClipRequest, opts ...grpc.CallOption) (*VideoResponse, error)
 	GetVideo(ctx context.Context, in *VideoRequest, opts ...grpc.CallOption) (*VideoResponse, error)
+	CompleteUpload(ctx context.Context, in *CompleteUploadRequest, opts ...grpc.CallOption) (*VideoResponse, error)
+	ImportFromURL(ctx context.Context, in *ImportFromURLRequest, opts ...grpc.CallOption) (*VideoResponse, error)
+	GetImport(ctx context.Context, in *GetImportRequest, opts ...grpc.CallOption) (*ImportResponse, error)
 	GetVideos(ctx context.Context, in *GetVideosRequest, opts ...grpc.CallOption) (*VideosResponse, error)
+	GetVideoStats(ctx context.Context, in *VideoStatsRequest, opts ...grpc.CallOption) (*VideoStatsResponse, error)
 	DeleteVideo(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteVideoResponse, error)
 	WatchVideo(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (*WatchVideoResponse, error)
+	GetStreams(ctx context.Context, in *GetStreamsRequest, opts ...grpc.CallOption) (*StreamsResponse, error)
+	GetSessions(ctx context.Context, in *GetSessionsRequest, opts ...grpc.CallOption) (*SessionsResponse, error)
+	RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error)
+	RevokeVideoSessions(ctx context.Context, in *RevokeVideoSessionsRequest, opts ...grpc.CallOption) (*RevokeVideoSessionsResponse, error)
+	CreatePlaylist(ctx context.Context, in *CreatePlaylistRequest, opts ...grpc.CallOption) (*PlaylistResponse, error)
+	GetPlaylist(ctx context.Context, in *PlaylistRequest, opts ...grpc.CallOption) (*PlaylistResponse, error)
+	DeletePlaylist(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeletePlaylistResponse, error)
+	WatchPlaylist(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (*WatchPlaylistResponse, error)
 }
 
 type usersideapiClient struct {
@@ -66,6 +94,24 @@ func (c *usersideapiClient) CreateVideo(ctx context.Context, in *CreateVideoRequ
 	return out, nil
 }
 
+func (c *usersideapiClient) CreateLiveVideo(ctx context.Context, in *CreateLiveVideoRequest, opts ...grpc.CallOption) (*VideoResponse, error) {
+	out := new(VideoResponse)
+	err := c.cc.Invoke(ctx, Usersideapi_CreateLiveVideo_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersideapiClient) CreateClip(ctx context.Context, in *CreateClipRequest, opts ...grpc.CallOption) (*VideoResponse, error) {
+	out := new(VideoResponse)
+	err := c.cc.Invoke(ctx, Usersideapi_CreateClip_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *usersideapiClient) GetVideo(ctx context.Context, in *VideoRequest, opts ...grpc.CallOption) (*VideoResponse, error) {
 	out := new(VideoResponse)
 	err := c.cc.Invoke(ctx, Usersideapi_GetVideo_FullMethodName, in, out, opts...)
@@ -75,6 +121,33 @@ func (c *usersideapiClient) GetVideo(ctx context.Context, in *VideoRequest, opts
 	return out, nil
 }
 
+func (c *usersideapiClient) CompleteUpload(ctx context.Context, in *CompleteUploadRequest, opts ...grpc.CallOption) (*VideoResponse, error) {
+	out := new(VideoResponse)
+	err := c.cc.Invoke(ctx, Usersideapi_CompleteUpload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersideapiClient) ImportFromURL(ctx context.Context, in *ImportFromURLRequest, opts ...grpc.CallOption) (*VideoResponse, error) {
+	out := new(VideoResponse)
+	err := c.cc.Invoke(ctx, Usersideapi_ImportFromURL_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersideapiClient) GetImport(ctx context.Context, in *GetImportRequest, opts ...grpc.CallOption) (*ImportResponse, error) {
+	out := new(ImportResponse)
+	err := c.cc.Invoke(ctx, Usersideapi_GetImport_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *usersideapiClient) GetVideos(ctx context.Context, in *GetVideosRequest, opts ...grpc.CallOption) (*VideosResponse, error) {
 	out := new(VideosResponse)
 	err := c.cc.Invoke(ctx, Usersideapi_GetVideos_FullMethodName, in, out, opts...)
@@ -84,6 +157,15 @@ func (c *usersideapiClient) GetVideos(ctx context.Context, in *GetVideosRequest,
 	return out, nil
 }
 
+func (c *usersideapiClient) GetVideoStats(ctx context.Context, in *VideoStatsRequest, opts ...grpc.CallOption) (*VideoStatsResponse, error) {
+	out := new(VideoStatsResponse)
+	err := c.cc.Invoke(ctx, Usersideapi_GetVideoStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *usersideapiClient) DeleteVideo(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteVideoResponse, error) {
 	out := new(DeleteVideoResponse)
 	err := c.cc.Invoke(ctx, Usersideapi_DeleteVideo_FullMethodName, in, out, opts...)
@@ -102,16 +184,102 @@ func (c *usersideapiClient) WatchVideo(ctx context.Context, in *WatchRequest, op
 	return out, nil
 }
 
+func (c *usersideapiClient) GetStreams(ctx context.Context, in *GetStreamsRequest, opts ...grpc.CallOption) (*StreamsResponse, error) {
+	out := new(StreamsResponse)
+	err := c.cc.Invoke(ctx, Usersideapi_GetStreams_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersideapiClient) GetSessions(ctx context.Context, in *GetSessionsRequest, opts ...grpc.CallOption) (*SessionsResponse, error) {
+	out := new(SessionsResponse)
+	err := c.cc.Invoke(ctx, Usersideapi_GetSessions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersideapiClient) RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error) {
+	out := new(RevokeSessionResponse)
+	err := c.cc.Invoke(ctx, Usersideapi_RevokeSession_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersideapiClient) RevokeVideoSessions(ctx context.Context, in *RevokeVideoSessionsRequest, opts ...grpc.CallOption) (*RevokeVideoSessionsResponse, error) {
+	out := new(RevokeVideoSessionsResponse)
+	err := c.cc.Invoke(ctx, Usersideapi_RevokeVideoSessions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersideapiClient) CreatePlaylist(ctx context.Context, in *CreatePlaylistRequest, opts ...grpc.CallOption) (*PlaylistResponse, error) {
+	out := new(PlaylistResponse)
+	err := c.cc.Invoke(ctx, Usersideapi_CreatePlaylist_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersideapiClient) GetPlaylist(ctx context.Context, in *PlaylistRequest, opts ...grpc.CallOption) (*PlaylistResponse, error) {
+	out := new(PlaylistResponse)
+	err := c.cc.Invoke(ctx, Usersideapi_GetPlaylist_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersideapiClient) DeletePlaylist(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeletePlaylistResponse, error) {
+	out := new(DeletePlaylistResponse)
+	err := c.cc.Invoke(ctx, Usersideapi_DeletePlaylist_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersideapiClient) WatchPlaylist(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (*WatchPlaylistResponse, error) {
+	out := new(WatchPlaylistResponse)
+	err := c.cc.Invoke(ctx, Usersideapi_WatchPlaylist_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UsersideapiServer is the server API for Usersideapi service.
 // All implementations must embed UnimplementedUsersideapiServer
 // for forward compatibility
 type UsersideapiServer interface {
 	GetQuota(context.Context, *GetQuotaRequest) (*QuotaResponse, error)
 	CreateVideo(context.Context, *CreateVideoRequest) (*VideoResponse, error)
+	CreateLiveVideo(context.Context, *CreateLiveVideoRequest) (*VideoResponse, error)
+	CreateClip(context.Context, *CreateClipRequest) (*VideoResponse, error)
 	GetVideo(context.Context, *VideoRequest) (*VideoResponse, error)
+	CompleteUpload(context.Context, *CompleteUploadRequest) (*VideoResponse, error)
+	ImportFromURL(context.Context, *ImportFromURLRequest) (*VideoResponse, error)
+	GetImport(context.Context, *GetImportRequest) (*ImportResponse, error)
 	GetVideos(context.Context, *GetVideosRequest) (*VideosResponse, error)
+	GetVideoStats(context.Context, *VideoStatsRequest) (*VideoStatsResponse, error)
 	DeleteVideo(context.Context, *DeleteRequest) (*DeleteVideoResponse, error)
 	WatchVideo(context.Context, *WatchRequest) (*WatchVideoResponse, error)
+	GetStreams(context.Context, *GetStreamsRequest) (*StreamsResponse, error)
+	GetSessions(context.Context, *GetSessionsRequest) (*SessionsResponse, error)
+	RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error)
+	RevokeVideoSessions(context.Context, *RevokeVideoSessionsRequest) (*RevokeVideoSessionsResponse, error)
+	CreatePlaylist(context.Context, *CreatePlaylistRequest) (*PlaylistResponse, error)
+	GetPlaylist(context.Context, *PlaylistRequest) (*PlaylistResponse, error)
+	DeletePlaylist(context.Context, *DeleteRequest) (*DeletePlaylistResponse, error)
+	WatchPlaylist(context.Context, *WatchRequest) (*WatchPlaylistResponse, error)
 	mustEmbedUnimplementedUsersideapiServer()
 }
 
@@ -125,18 +293,60 @@ func (UnimplementedUsersideapiServer) GetQuota(context.Context, *GetQuotaRequest
 func (UnimplementedUsersideapiServer) CreateVideo(context.Context, *CreateVideoRequest) (*VideoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateVideo not implemented")
 }
+func (UnimplementedUsersideapiServer) CreateLiveVideo(context.Context, *CreateLiveVideoRequest) (*VideoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateLiveVideo not implemented")
+}
+func (UnimplementedUsersideapiServer) CreateClip(context.Context, *CreateClipRequest) (*VideoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateClip not implemented")
+}
 func (UnimplementedUsersideapiServer) GetVideo(context.Context, *VideoRequest) (*VideoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetVideo not implemented")
 }
+func (UnimplementedUsersideapiServer) CompleteUpload(context.Context, *CompleteUploadRequest) (*VideoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteUpload not implemented")
+}
+func (UnimplementedUsersideapiServer) ImportFromURL(context.Context, *ImportFromURLRequest) (*VideoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportFromURL not implemented")
+}
+func (UnimplementedUsersideapiServer) GetImport(context.Context, *GetImportRequest) (*ImportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetImport not implemented")
+}
 func (UnimplementedUsersideapiServer) GetVideos(context.Context, *GetVideosRequest) (*VideosResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetVideos not implemented")
 }
+func (UnimplementedUsersideapiServer) GetVideoStats(context.Context, *VideoStatsRequest) (*VideoStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVideoStats not implemented")
+}
 func (UnimplementedUsersideapiServer) DeleteVideo(context.Context, *DeleteRequest) (*DeleteVideoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteVideo not implemented")
 }
 func (UnimplementedUsersideapiServer) WatchVideo(context.Context, *WatchRequest) (*WatchVideoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method WatchVideo not implemented")
 }
+func (UnimplementedUsersideapiServer) GetStreams(context.Context, *GetStreamsRequest) (*StreamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStreams not implemented")
+}
+func (UnimplementedUsersideapiServer) GetSessions(context.Context, *GetSessionsRequest) (*SessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSessions not implemented")
+}
+func (UnimplementedUsersideapiServer) RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeSession not implemented")
+}
+func (UnimplementedUsersideapiServer) RevokeVideoSessions(context.Context, *RevokeVideoSessionsRequest) (*RevokeVideoSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeVideoSessions not implemented")
+}
+func (UnimplementedUsersideapiServer) CreatePlaylist(context.Context, *CreatePlaylistRequest) (*PlaylistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePlaylist not implemented")
+}
+func (UnimplementedUsersideapiServer) GetPlaylist(context.Context, *PlaylistRequest) (*PlaylistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlaylist not implemented")
+}
+func (UnimplementedUsersideapiServer) DeletePlaylist(context.Context, *DeleteRequest) (*DeletePlaylistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePlaylist not implemented")
+}
+func (UnimplementedUsersideapiServer) WatchPlaylist(context.Context, *WatchRequest) (*WatchPlaylistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WatchPlaylist not implemented")
+}
 func (UnimplementedUsersideapiServer) mustEmbedUnimplementedUsersideapiServer() {}
 
 // UnsafeUsersideapiServer may be embedded to opt out of forward compatibility for this service.
@@ -186,6 +396,42 @@ func _Usersideapi_CreateVideo_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Usersideapi_CreateLiveVideo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateLiveVideoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersideapiServer).CreateLiveVideo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Usersideapi_CreateLiveVideo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersideapiServer).CreateLiveVideo(ctx, req.(*CreateLiveVideoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Usersideapi_CreateClip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateClipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersideapiServer).CreateClip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Usersideapi_CreateClip_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersideapiServer).CreateClip(ctx, req.(*CreateClipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Usersideapi_GetVideo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(VideoRequest)
 	if err := dec(in); err != nil {
@@ -204,6 +450,60 @@ func _Usersideapi_GetVideo_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Usersideapi_CompleteUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersideapiServer).CompleteUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Usersideapi_CompleteUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersideapiServer).CompleteUpload(ctx, req.(*CompleteUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Usersideapi_ImportFromURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportFromURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersideapiServer).ImportFromURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Usersideapi_ImportFromURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersideapiServer).ImportFromURL(ctx, req.(*ImportFromURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Usersideapi_GetImport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersideapiServer).GetImport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Usersideapi_GetImport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersideapiServer).GetImport(ctx, req.(*GetImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Usersideapi_GetVideos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetVideosRequest)
 	if err := dec(in); err != nil {
@@ -222,6 +522,24 @@ func _Usersideapi_GetVideos_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Usersideapi_GetVideoStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VideoStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersideapiServer).GetVideoStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Usersideapi_GetVideoStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersideapiServer).GetVideoStats(ctx, req.(*VideoStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Usersideapi_DeleteVideo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteRequest)
 	if err := dec(in); err != nil {
@@ -258,6 +576,150 @@ func _Usersideapi_WatchVideo_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Usersideapi_GetStreams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStreamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersideapiServer).GetStreams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Usersideapi_GetStreams_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersideapiServer).GetStreams(ctx, req.(*GetStreamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Usersideapi_GetSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersideapiServer).GetSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Usersideapi_GetSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersideapiServer).GetSessions(ctx, req.(*GetSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Usersideapi_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersideapiServer).RevokeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Usersideapi_RevokeSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersideapiServer).RevokeSession(ctx, req.(*RevokeSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Usersideapi_RevokeVideoSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeVideoSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersideapiServer).RevokeVideoSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Usersideapi_RevokeVideoSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersideapiServer).RevokeVideoSessions(ctx, req.(*RevokeVideoSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Usersideapi_CreatePlaylist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePlaylistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersideapiServer).CreatePlaylist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Usersideapi_CreatePlaylist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersideapiServer).CreatePlaylist(ctx, req.(*CreatePlaylistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Usersideapi_GetPlaylist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaylistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersideapiServer).GetPlaylist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Usersideapi_GetPlaylist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersideapiServer).GetPlaylist(ctx, req.(*PlaylistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Usersideapi_DeletePlaylist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersideapiServer).DeletePlaylist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Usersideapi_DeletePlaylist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersideapiServer).DeletePlaylist(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Usersideapi_WatchPlaylist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersideapiServer).WatchPlaylist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Usersideapi_WatchPlaylist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersideapiServer).WatchPlaylist(ctx, req.(*WatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Usersideapi_ServiceDesc is the grpc.ServiceDesc for Usersideapi service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -273,14 +735,38 @@ var Usersideapi_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateVideo",
 			Handler:    _Usersideapi_CreateVideo_Handler,
 		},
+		{
+			MethodName: "CreateLiveVideo",
+			Handler:    _Usersideapi_CreateLiveVideo_Handler,
+		},
+		{
+			MethodName: "CreateClip",
+			Handler:    _Usersideapi_CreateClip_Handler,
+		},
 		{
 			MethodName: "GetVideo",
 			Handler:    _Usersideapi_GetVideo_Handler,
 		},
+		{
+			MethodName: "CompleteUpload",
+			Handler:    _Usersideapi_CompleteUpload_Handler,
+		},
+		{
+			MethodName: "ImportFromURL",
+			Handler:    _Usersideapi_ImportFromURL_Handler,
+		},
+		{
+			MethodName: "GetImport",
+			Handler:    _Usersideapi_GetImport_Handler,
+		},
 		{
 			MethodName: "GetVideos",
 			Handler:    _Usersideapi_GetVideos_Handler,
 		},
+		{
+			MethodName: "GetVideoStats",
+			Handler:    _Usersideapi_GetVideoStats_Handler,
+		},
 		{
 			MethodName: "DeleteVideo",
 			Handler:    _Usersideapi_DeleteVideo_Handler,
@@ -289,6 +775,38 @@ var Usersideapi_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "WatchVideo",
 			Handler:    _Usersideapi_WatchVideo_Handler,
 		},
+		{
+			MethodName: "GetStreams",
+			Handler:    _Usersideapi_GetStreams_Handler,
+		},
+		{
+			MethodName: "GetSessions",
+			Handler:    _Usersideapi_GetSessions_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _Usersideapi_RevokeSession_Handler,
+		},
+		{
+			MethodName: "RevokeVideoSessions",
+			Handler:    _Usersideapi_RevokeVideoSessions_Handler,
+		},
+		{
+			MethodName: "CreatePlaylist",
+			Handler:    _Usersideapi_CreatePlaylist_Handler,
+		},
+		{
+			MethodName: "GetPlaylist",
+			Handler:    _Usersideapi_GetPlaylist_Handler,
+		},
+		{
+			MethodName: "DeletePlaylist",
+			Handler:    _Usersideapi_DeletePlaylist_Handler,
+		},
+		{
+			MethodName: "WatchPlaylist",
+			Handler:    _Usersideapi_WatchPlaylist_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "internal/api/video/grpc/protobuf/user.proto",
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 
 	"github.com/adwski/vidi/internal/api/user/auth"
 	user "github.com/adwski/vidi/internal/api/user/model"
@@ -15,6 +16,8 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -47,7 +50,7 @@ func (srv *Server) WatchVideo(ctx context.Context, req *pb.WatchRequest) (*pb.Wa
 	if err != nil {
 		return nil, err
 	}
-	url, err := srv.videoSvc.WatchVideo(ctx, usr, req.Id, true)
+	url, err := srv.videoSvc.WatchVideo(video.WithClientIP(ctx, clientIP(ctx)), usr, req.Id, true)
 	if err == nil {
 		return &pb.WatchVideoResponse{Url: string(url)}, nil
 	}
@@ -56,12 +59,103 @@ func (srv *Server) WatchVideo(ctx context.Context, req *pb.WatchRequest) (*pb.Wa
 		return nil, status.Error(codes.NotFound, "video is not found")
 	case errors.Is(err, model.ErrNotReady), errors.Is(err, model.ErrState):
 		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, model.ErrTooManyStreams):
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
 	default:
 		srv.logger.Error("WatchVideo failed", zap.Error(err))
 		return nil, status.Error(codes.Internal, "cannot get video")
 	}
 }
 
+// GetStreams returns active streams of user.
+func (srv *Server) GetStreams(ctx context.Context, _ *pb.GetStreamsRequest) (*pb.StreamsResponse, error) {
+	usr, err := getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	streams, err := srv.videoSvc.GetStreams(ctx, usr)
+	switch {
+	case errors.Is(err, model.ErrStreamsUntracked):
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	case err != nil:
+		srv.logger.Error("GetStreams failed", zap.Error(err))
+		return nil, status.Error(codes.Internal, "cannot get streams")
+	}
+	resp := &pb.StreamsResponse{Streams: make([]*pb.Stream, 0, len(streams))}
+	for _, s := range streams {
+		resp.Streams = append(resp.Streams, &pb.Stream{
+			SessionId: s.SessionID,
+			VideoId:   s.VideoID,
+			ActiveAt:  s.ActiveAt.UnixMilli(),
+		})
+	}
+	return resp, nil
+}
+
+// GetSessions returns active upload and watch sessions of user or of user's video.
+func (srv *Server) GetSessions(ctx context.Context, req *pb.GetSessionsRequest) (*pb.SessionsResponse, error) {
+	usr, err := getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := srv.videoSvc.GetSessions(ctx, usr, req.VideoId)
+	if err != nil {
+		return nil, srv.sessionsError("GetSessions", err)
+	}
+	resp := &pb.SessionsResponse{Sessions: make([]*pb.Session, 0, len(sessions))}
+	for _, s := range sessions {
+		resp.Sessions = append(resp.Sessions, &pb.Session{
+			Kind:     s.Kind,
+			Id:       s.ID,
+			VideoId:  s.VideoID,
+			ActiveAt: s.ActiveAt.UnixMilli(),
+		})
+	}
+	return resp, nil
+}
+
+// RevokeSession revokes upload or watch session of user.
+func (srv *Server) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	usr, err := getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = srv.videoSvc.RevokeSession(ctx, usr, req.Kind, req.Id); err != nil {
+		return nil, srv.sessionsError("RevokeSession", err)
+	}
+	return &pb.RevokeSessionResponse{}, nil
+}
+
+// RevokeVideoSessions revokes every upload and watch session of user's video.
+func (srv *Server) RevokeVideoSessions(
+	ctx context.Context,
+	req *pb.RevokeVideoSessionsRequest,
+) (*pb.RevokeVideoSessionsResponse, error) {
+	usr, err := getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := srv.videoSvc.RevokeVideoSessions(ctx, usr, req.VideoId)
+	if err != nil {
+		return nil, srv.sessionsError("RevokeVideoSessions", err)
+	}
+	return &pb.RevokeVideoSessionsResponse{Revoked: uint32(revoked)}, nil
+}
+
+func (srv *Server) sessionsError(rpc string, err error) error {
+	switch {
+	case errors.Is(err, model.ErrSessionsNotIndexed):
+		return status.Error(codes.Unimplemented, err.Error())
+	case errors.Is(err, model.ErrSessionKind):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, model.ErrNotFound), errors.Is(err, model.ErrSessionNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		srv.logger.Error(rpc+" failed", zap.Error(err))
+		return status.Error(codes.Internal, "cannot process sessions")
+	}
+}
+
 func (srv *Server) GetQuota(ctx context.Context, _ *pb.GetQuotaRequest) (*pb.QuotaResponse, error) {
 	usr, err := getUser(ctx)
 	if err != nil {
@@ -87,9 +181,11 @@ func (srv *Server) CreateVideo(ctx context.Context, req *pb.CreateVideoRequest)
 		return nil, err
 	}
 	var r = &model.CreateRequest{
-		Name:  req.Name,
-		Size:  req.Size,
-		Parts: make([]*model.Part, 0, len(req.Parts)),
+		Name:         req.Name,
+		Size:         req.Size,
+		PartSize:     req.PartSize,
+		DirectUpload: req.DirectUpload,
+		Parts:        make([]*model.Part, 0, len(req.Parts)),
 	}
 	for _, p := range req.Parts {
 		r.Parts = append(r.Parts, &model.Part{
@@ -104,14 +200,66 @@ func (srv *Server) CreateVideo(ctx context.Context, req *pb.CreateVideoRequest)
 		switch {
 		case errors.Is(err, model.ErrZeroSize),
 			errors.Is(err, model.ErrNoParts),
-			errors.Is(err, model.ErrNoName):
+			errors.Is(err, model.ErrNoName),
+			errors.Is(err, model.ErrInvalidPartSize),
+			errors.Is(err, model.ErrPartsMismatch),
+			errors.Is(err, model.ErrNoChecksums):
 			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, model.ErrDirectUploadDisabled):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		case errors.Is(err, model.ErrDuplicate):
+			return nil, status.Error(codes.AlreadyExists, err.Error())
 		default:
 			return nil, status.Error(codes.Internal, "cannot create video")
 		}
 	}
 	return videoResponse(vide), nil
 }
+
+// CreateLiveVideo handles live video create request.
+// Returned upload url should be used as ingest url.
+func (srv *Server) CreateLiveVideo(ctx context.Context, req *pb.CreateLiveVideoRequest) (*pb.VideoResponse, error) {
+	usr, err := getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vide, err := srv.videoSvc.CreateLiveVideo(ctx, usr, req.Name)
+	if err != nil {
+		srv.logger.Error("CreateLiveVideo failed", zap.Error(err))
+		if errors.Is(err, model.ErrNoName) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, "cannot create live video")
+	}
+	return videoResponse(vide), nil
+}
+
+// CreateClip handles clip create request.
+func (srv *Server) CreateClip(ctx context.Context, req *pb.CreateClipRequest) (*pb.VideoResponse, error) {
+	usr, err := getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vide, err := srv.videoSvc.CreateClip(ctx, usr, &model.CreateClipRequest{
+		SourceID: req.SourceId,
+		Name:     req.Name,
+		StartMs:  req.StartMs,
+		EndMs:    req.EndMs,
+	})
+	switch {
+	case errors.Is(err, model.ErrNoName), errors.Is(err, model.ErrInvalidClip):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, model.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "source video is not found")
+	case errors.Is(err, model.ErrNotReady):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		srv.logger.Error("CreateClip failed", zap.Error(err))
+		return nil, status.Error(codes.Internal, "cannot create clip")
+	}
+	return videoResponse(vide), nil
+}
+
 func (srv *Server) GetVideo(ctx context.Context, req *pb.VideoRequest) (*pb.VideoResponse, error) {
 	usr, err := getUser(ctx)
 	if err != nil {
@@ -121,7 +269,8 @@ func (srv *Server) GetVideo(ctx context.Context, req *pb.VideoRequest) (*pb.Vide
 	switch {
 	case errors.Is(err, model.ErrNotFound):
 		return nil, status.Error(codes.NotFound, "video is not found")
-	case errors.Is(err, model.ErrNotResumable):
+	case errors.Is(err, model.ErrNotResumable),
+		errors.Is(err, model.ErrDirectUploadDisabled):
 		return nil, status.Error(codes.FailedPrecondition, err.Error())
 	case err != nil:
 		srv.logger.Error("GetVideo failed", zap.Error(err))
@@ -129,6 +278,109 @@ func (srv *Server) GetVideo(ctx context.Context, req *pb.VideoRequest) (*pb.Vide
 	}
 	return videoResponse(vide), nil
 }
+
+// GetVideoStats returns view statistics of video.
+func (srv *Server) GetVideoStats(ctx context.Context, req *pb.VideoStatsRequest) (*pb.VideoStatsResponse, error) {
+	usr, err := getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := srv.videoSvc.GetVideoStats(ctx, usr, req.Id)
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "video is not found")
+	case err != nil:
+		srv.logger.Error("GetVideoStats failed", zap.Error(err))
+		return nil, status.Error(codes.Internal, "cannot get video stats")
+	}
+	return &pb.VideoStatsResponse{
+		Views:             stats.Views,
+		WatchTimeMs:       stats.WatchTimeMS,
+		Requests:          stats.Requests,
+		Bytes:             stats.Bytes,
+		SegmentDurationMs: stats.SegmentDurationMS,
+		Retention:         stats.Retention,
+	}, nil
+}
+
+// CompleteUpload checks parts of directly uploaded video.
+// Video status in response shows if upload is completed.
+func (srv *Server) CompleteUpload(ctx context.Context, req *pb.CompleteUploadRequest) (*pb.VideoResponse, error) {
+	usr, err := getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vide, err := srv.videoSvc.CompleteUpload(ctx, usr, req.Id)
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "video is not found")
+	case errors.Is(err, model.ErrNotResumable),
+		errors.Is(err, model.ErrNotDirectUpload),
+		errors.Is(err, model.ErrDirectUploadDisabled):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		srv.logger.Error("CompleteUpload failed", zap.Error(err))
+		return nil, status.Error(codes.Internal, "cannot complete upload")
+	}
+	return videoResponse(vide), nil
+}
+
+// ImportFromURL creates video that is imported from remote url in background.
+// Import progress could be checked with GetImport.
+func (srv *Server) ImportFromURL(ctx context.Context, req *pb.ImportFromURLRequest) (*pb.VideoResponse, error) {
+	usr, err := getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vide, err := srv.videoSvc.ImportFromURL(ctx, usr, &model.ImportRequest{
+		Name:     req.Name,
+		URL:      req.Url,
+		PartSize: req.PartSize,
+	})
+	switch {
+	case errors.Is(err, model.ErrNoName),
+		errors.Is(err, model.ErrInvalidPartSize),
+		errors.Is(err, model.ErrImportURL):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, model.ErrImportHostNotAllowed):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, model.ErrQuotaExceeded),
+		errors.Is(err, model.ErrImportTooLarge):
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, model.ErrImportDisabled),
+		errors.Is(err, model.ErrImportSource):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		srv.logger.Error("ImportFromURL failed", zap.Error(err))
+		return nil, status.Error(codes.Internal, "cannot import video")
+	}
+	return videoResponse(vide), nil
+}
+
+// GetImport returns import progress of video.
+func (srv *Server) GetImport(ctx context.Context, req *pb.GetImportRequest) (*pb.ImportResponse, error) {
+	usr, err := getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	imp, err := srv.videoSvc.GetImport(ctx, usr, req.Id)
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "import is not found")
+	case err != nil:
+		srv.logger.Error("GetImport failed", zap.Error(err))
+		return nil, status.Error(codes.Internal, "cannot get import")
+	}
+	return &pb.ImportResponse{
+		Url:        imp.URL,
+		Size:       imp.Size,
+		Downloaded: imp.Downloaded,
+		Done:       imp.Done,
+		Attempts:   uint32(imp.Attempts),
+		LastError:  imp.LastError,
+	}, nil
+}
+
 func (srv *Server) GetVideos(ctx context.Context, _ *pb.GetVideosRequest) (*pb.VideosResponse, error) {
 	usr, err := getUser(ctx)
 	if err != nil {
@@ -164,13 +416,102 @@ func (srv *Server) DeleteVideo(ctx context.Context, req *pb.DeleteRequest) (*pb.
 	return &pb.DeleteVideoResponse{}, nil
 }
 
+// CreatePlaylist handles playlist create request.
+func (srv *Server) CreatePlaylist(ctx context.Context, req *pb.CreatePlaylistRequest) (*pb.PlaylistResponse, error) {
+	usr, err := getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pl, err := srv.videoSvc.CreatePlaylist(ctx, usr, &model.CreatePlaylistRequest{
+		Name:   req.Name,
+		Videos: req.Videos,
+	})
+	switch {
+	case errors.Is(err, model.ErrNoPlaylistName), errors.Is(err, model.ErrEmptyPlaylist):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, model.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "video is not found")
+	case errors.Is(err, model.ErrNotReady):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		srv.logger.Error("CreatePlaylist failed", zap.Error(err))
+		return nil, status.Error(codes.Internal, "cannot create playlist")
+	}
+	return playlistResponse(pl), nil
+}
+
+func (srv *Server) GetPlaylist(ctx context.Context, req *pb.PlaylistRequest) (*pb.PlaylistResponse, error) {
+	usr, err := getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pl, err := srv.videoSvc.GetPlaylist(ctx, usr, req.Id)
+	switch {
+	case errors.Is(err, model.ErrPlaylistNotFound):
+		return nil, status.Error(codes.NotFound, "playlist is not found")
+	case err != nil:
+		srv.logger.Error("GetPlaylist failed", zap.Error(err))
+		return nil, status.Error(codes.Internal, "cannot get playlist")
+	}
+	return playlistResponse(pl), nil
+}
+
+func (srv *Server) DeletePlaylist(ctx context.Context, req *pb.DeleteRequest) (*pb.DeletePlaylistResponse, error) {
+	usr, err := getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = srv.videoSvc.DeletePlaylist(ctx, usr, req.Id)
+	switch {
+	case errors.Is(err, model.ErrPlaylistNotFound):
+		return nil, status.Error(codes.NotFound, "playlist is not found")
+	case err != nil:
+		srv.logger.Error("DeletePlaylist failed", zap.Error(err))
+		return nil, status.Error(codes.Internal, "cannot delete playlist")
+	}
+	return &pb.DeletePlaylistResponse{}, nil
+}
+
+// WatchPlaylist returns multi-period MPD of playlist.
+func (srv *Server) WatchPlaylist(ctx context.Context, req *pb.WatchRequest) (*pb.WatchPlaylistResponse, error) {
+	usr, err := getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mpd, err := srv.videoSvc.WatchPlaylist(video.WithClientIP(ctx, clientIP(ctx)), usr, req.Id)
+	switch {
+	case errors.Is(err, model.ErrPlaylistNotFound):
+		return nil, status.Error(codes.NotFound, "playlist is not found")
+	case errors.Is(err, model.ErrNotReady), errors.Is(err, model.ErrState), errors.Is(err, model.ErrEmptyPlaylist):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, model.ErrTooManyStreams):
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	case err != nil:
+		srv.logger.Error("WatchPlaylist failed", zap.Error(err))
+		return nil, status.Error(codes.Internal, "cannot watch playlist")
+	}
+	return &pb.WatchPlaylistResponse{Mpd: mpd}, nil
+}
+
+func playlistResponse(pl *model.Playlist) *pb.PlaylistResponse {
+	return &pb.PlaylistResponse{
+		Id:        pl.ID,
+		Name:      pl.Name,
+		CreatedAt: pl.CreatedAt.UnixMilli(),
+		Videos:    pl.VideoIDs,
+	}
+}
+
 func videoResponse(v *model.Video) *pb.VideoResponse {
 	r := &pb.VideoResponse{
-		Id:        v.ID,
-		Status:    int32(v.Status),
-		CreatedAt: v.CreatedAt.UnixMilli(),
-		Name:      v.Name,
-		Size:      v.Size,
+		Id:           v.ID,
+		Status:       int32(v.Status),
+		StatusReason: v.StatusReason,
+		CreatedAt:    v.CreatedAt.UnixMilli(),
+		Name:         v.Name,
+		Size:         v.Size,
+		PartSize:     v.PartSize,
+		DirectUpload: v.DirectUpload,
 	}
 	if v.UploadInfo == nil {
 		return r
@@ -179,15 +520,35 @@ func videoResponse(v *model.Video) *pb.VideoResponse {
 	r.UploadParts = make([]*pb.VideoPart, 0, len(v.UploadInfo.Parts))
 	for _, p := range v.UploadInfo.Parts {
 		r.UploadParts = append(r.UploadParts, &pb.VideoPart{
-			Num:      uint32(p.Num),
-			Status:   int32(p.Status),
-			Size:     p.Size,
-			Checksum: p.Checksum,
+			Num:       uint32(p.Num),
+			Status:    int32(p.Status),
+			Size:      p.Size,
+			Checksum:  p.Checksum,
+			UploadUrl: p.URL,
 		})
 	}
 	return r
 }
 
+// clientIP returns ip of client, it is taken from x-real-ip metadata
+// if request is proxied by gateway, or from peer address otherwise.
+func clientIP(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ip := md.Get("x-real-ip"); len(ip) > 0 {
+			return ip[0]
+		}
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
 func getUser(ctx context.Context) (*user.User, error) {
 	claims, ok := auth.GetClaimsFromContext(ctx)
 	if !ok {
@@ -11,21 +11,43 @@ type VideoResponse struct {
 	ID         string            `json:"id"`
 	Name       string            `json:"name"`
 	Status     string            `json:"status"`
+	Reason     string            `json:"status_reason,omitempty"`
 	CreatedAt  string            `json:"created_at"`
 	Size       uint64            `json:"size"`
+	PartSize   uint64            `json:"part_size,omitempty"`
+	Direct     bool              `json:"direct_upload,omitempty"`
 }
 
 func NewVideoResponse(v *model.Video) *VideoResponse {
 	return &VideoResponse{
 		ID:         v.ID,
 		Status:     v.Status.String(),
+		Reason:     v.StatusReason,
 		Name:       v.Name,
 		Size:       v.Size,
+		PartSize:   v.PartSize,
+		Direct:     v.DirectUpload,
 		CreatedAt:  v.CreatedAt.Format(time.RFC3339),
 		UploadInfo: v.UploadInfo,
 	}
 }
 
+type PlaylistResponse struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	CreatedAt string   `json:"created_at"`
+	Videos    []string `json:"videos"`
+}
+
+func NewPlaylistResponse(pl *model.Playlist) *PlaylistResponse {
+	return &PlaylistResponse{
+		ID:        pl.ID,
+		Name:      pl.Name,
+		CreatedAt: pl.CreatedAt.Format(time.RFC3339),
+		Videos:    pl.VideoIDs,
+	}
+}
+
 type ListRequest struct {
 	Status string `json:"status"`
 }
@@ -38,3 +60,7 @@ type UpdateRequest struct {
 type WatchResponse struct {
 	WatchURL string `json:"watch_url"`
 }
+
+type RevokeResponse struct {
+	Revoked int `json:"revoked"`
+}
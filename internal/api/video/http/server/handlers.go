@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	common "github.com/adwski/vidi/internal/api/model"
+	"github.com/adwski/vidi/internal/api/video"
 	httpmodel "github.com/adwski/vidi/internal/api/video/http"
 	"github.com/adwski/vidi/internal/api/video/model"
 	"github.com/labstack/echo/v4"
@@ -37,6 +38,18 @@ func (srv *Server) getVideo(c echo.Context) error {
 	return srv.erroredResponse(c, err)
 }
 
+func (srv *Server) completeUpload(c echo.Context) error {
+	usr, err, ok := srv.getUser(c)
+	if !ok {
+		return err
+	}
+	vide, err := srv.videoSvc.CompleteUpload(c.Request().Context(), usr, c.Param("id"))
+	if err == nil {
+		return c.JSON(http.StatusOK, httpmodel.NewVideoResponse(vide))
+	}
+	return srv.erroredResponse(c, err)
+}
+
 func (srv *Server) getVideos(c echo.Context) error {
 	usr, err, ok := srv.getUser(c)
 	if !ok {
@@ -60,7 +73,8 @@ func (srv *Server) watchVideo(c echo.Context) error {
 	}
 
 	generateURL := c.QueryParam("mode") == "url"
-	resp, err := srv.videoSvc.WatchVideo(c.Request().Context(), usr, c.Param("id"), generateURL)
+	ctx := video.WithClientIP(c.Request().Context(), c.RealIP())
+	resp, err := srv.videoSvc.WatchVideo(ctx, usr, c.Param("id"), generateURL)
 	switch {
 	case err == nil:
 		if generateURL {
@@ -77,12 +91,54 @@ func (srv *Server) watchVideo(c echo.Context) error {
 		return c.JSON(http.StatusMethodNotAllowed, &common.Response{
 			Error: err.Error(),
 		})
+	case errors.Is(err, model.ErrTooManyStreams):
+		return c.JSON(http.StatusTooManyRequests, &common.Response{
+			Error: err.Error(),
+		})
 	default:
 		srv.logger.Error("watchVideo failed", zap.Error(err))
 		return c.JSON(http.StatusInternalServerError, common.ResponseInternalError)
 	}
 }
 
+func (srv *Server) getVideoStats(c echo.Context) error {
+	usr, err, ok := srv.getUser(c)
+	if !ok {
+		return err
+	}
+	stats, err := srv.videoSvc.GetVideoStats(c.Request().Context(), usr, c.Param("id"))
+	switch {
+	case err == nil:
+		return c.JSON(http.StatusOK, stats)
+	case errors.Is(err, model.ErrNotFound):
+		return c.JSON(http.StatusNotFound, &common.Response{
+			Error: err.Error(),
+		})
+	default:
+		srv.logger.Error("getVideoStats failed", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, common.ResponseInternalError)
+	}
+}
+
+func (srv *Server) getStreams(c echo.Context) error {
+	usr, err, ok := srv.getUser(c)
+	if !ok {
+		return err
+	}
+	streams, err := srv.videoSvc.GetStreams(c.Request().Context(), usr)
+	switch {
+	case err == nil:
+		return c.JSON(http.StatusOK, streams)
+	case errors.Is(err, model.ErrStreamsUntracked):
+		return c.JSON(http.StatusNotImplemented, &common.Response{
+			Error: err.Error(),
+		})
+	default:
+		srv.logger.Error("getStreams failed", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, common.ResponseInternalError)
+	}
+}
+
 func (srv *Server) deleteVideo(c echo.Context) error {
 	usr, err, ok := srv.getUser(c)
 	if !ok {
@@ -109,13 +165,126 @@ func (srv *Server) createVideo(c echo.Context) error {
 		switch {
 		case errors.Is(err, model.ErrZeroSize),
 			errors.Is(err, model.ErrNoParts),
-			errors.Is(err, model.ErrNoName):
+			errors.Is(err, model.ErrNoName),
+			errors.Is(err, model.ErrInvalidPartSize),
+			errors.Is(err, model.ErrPartsMismatch),
+			errors.Is(err, model.ErrNoChecksums),
+			errors.Is(err, model.ErrDirectUploadDisabled):
 			return c.JSON(http.StatusBadRequest, &common.Response{
 				Error: err.Error(),
 			})
+		case errors.Is(err, model.ErrDuplicate):
+			return c.JSON(http.StatusConflict, &common.Response{
+				Error: err.Error(),
+			})
 		default:
 			return c.JSON(http.StatusInternalServerError, common.ResponseInternalError)
 		}
 	}
 	return c.JSON(http.StatusCreated, httpmodel.NewVideoResponse(vide))
 }
+
+func (srv *Server) createLiveVideo(c echo.Context) error {
+	usr, err, ok := srv.getUser(c)
+	if !ok {
+		return err
+	}
+	var req model.CreateLiveRequest
+	if err = c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, common.ResponseIncorrectParams)
+	}
+	vide, err := srv.videoSvc.CreateLiveVideo(c.Request().Context(), usr, req.Name)
+	if err != nil {
+		if errors.Is(err, model.ErrNoName) {
+			return c.JSON(http.StatusBadRequest, &common.Response{
+				Error: err.Error(),
+			})
+		}
+		srv.logger.Error("createLiveVideo failed", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, common.ResponseInternalError)
+	}
+	return c.JSON(http.StatusCreated, httpmodel.NewVideoResponse(vide))
+}
+
+func (srv *Server) importFromURL(c echo.Context) error {
+	usr, err, ok := srv.getUser(c)
+	if !ok {
+		return err
+	}
+	var req model.ImportRequest
+	if err = c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, common.ResponseIncorrectParams)
+	}
+	vide, err := srv.videoSvc.ImportFromURL(c.Request().Context(), usr, &req)
+	switch {
+	case err == nil:
+		return c.JSON(http.StatusCreated, httpmodel.NewVideoResponse(vide))
+	case errors.Is(err, model.ErrNoName),
+		errors.Is(err, model.ErrInvalidPartSize),
+		errors.Is(err, model.ErrImportURL),
+		errors.Is(err, model.ErrImportDisabled):
+		return c.JSON(http.StatusBadRequest, &common.Response{
+			Error: err.Error(),
+		})
+	case errors.Is(err, model.ErrImportHostNotAllowed),
+		errors.Is(err, model.ErrQuotaExceeded):
+		return c.JSON(http.StatusForbidden, &common.Response{
+			Error: err.Error(),
+		})
+	case errors.Is(err, model.ErrImportTooLarge):
+		return c.JSON(http.StatusRequestEntityTooLarge, &common.Response{
+			Error: err.Error(),
+		})
+	case errors.Is(err, model.ErrImportSource):
+		return c.JSON(http.StatusBadGateway, &common.Response{
+			Error: err.Error(),
+		})
+	default:
+		srv.logger.Error("importFromURL failed", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, common.ResponseInternalError)
+	}
+}
+
+func (srv *Server) getImport(c echo.Context) error {
+	usr, err, ok := srv.getUser(c)
+	if !ok {
+		return err
+	}
+	status, err := srv.videoSvc.GetImport(c.Request().Context(), usr, c.Param("id"))
+	if err != nil {
+		return srv.erroredResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+func (srv *Server) createClip(c echo.Context) error {
+	usr, err, ok := srv.getUser(c)
+	if !ok {
+		return err
+	}
+	var req model.CreateClipRequest
+	if err = c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, common.ResponseIncorrectParams)
+	}
+	vide, err := srv.videoSvc.CreateClip(c.Request().Context(), usr, &req)
+	switch {
+	case err == nil:
+		return c.JSON(http.StatusCreated, httpmodel.NewVideoResponse(vide))
+	case errors.Is(err, model.ErrNoName),
+		errors.Is(err, model.ErrInvalidClip):
+		return c.JSON(http.StatusBadRequest, &common.Response{
+			Error: err.Error(),
+		})
+	case errors.Is(err, model.ErrNotFound):
+		return c.JSON(http.StatusNotFound, &common.Response{
+			Error: err.Error(),
+		})
+	case errors.Is(err, model.ErrNotReady):
+		return c.JSON(http.StatusMethodNotAllowed, &common.Response{
+			Error: err.Error(),
+		})
+	default:
+		srv.logger.Error("createClip failed", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, common.ResponseInternalError)
+	}
+}
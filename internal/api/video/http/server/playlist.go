@@ -0,0 +1,115 @@
+//nolint:wrapcheck  // we use echo-style handler returns, i.e. return c.JSON(..)
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	common "github.com/adwski/vidi/internal/api/model"
+	"github.com/adwski/vidi/internal/api/video"
+	httpmodel "github.com/adwski/vidi/internal/api/video/http"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+func (srv *Server) createPlaylist(c echo.Context) error {
+	usr, err, ok := srv.getUser(c)
+	if !ok {
+		return err
+	}
+	var req model.CreatePlaylistRequest
+	if err = c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, common.ResponseIncorrectParams)
+	}
+	pl, err := srv.videoSvc.CreatePlaylist(c.Request().Context(), usr, &req)
+	switch {
+	case err == nil:
+		return c.JSON(http.StatusCreated, httpmodel.NewPlaylistResponse(pl))
+	case errors.Is(err, model.ErrNoPlaylistName),
+		errors.Is(err, model.ErrEmptyPlaylist):
+		return c.JSON(http.StatusBadRequest, &common.Response{
+			Error: err.Error(),
+		})
+	case errors.Is(err, model.ErrNotFound):
+		return c.JSON(http.StatusNotFound, &common.Response{
+			Error: err.Error(),
+		})
+	case errors.Is(err, model.ErrNotReady):
+		return c.JSON(http.StatusMethodNotAllowed, &common.Response{
+			Error: err.Error(),
+		})
+	default:
+		srv.logger.Error("createPlaylist failed", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, common.ResponseInternalError)
+	}
+}
+
+func (srv *Server) getPlaylist(c echo.Context) error {
+	usr, err, ok := srv.getUser(c)
+	if !ok {
+		return err
+	}
+	pl, err := srv.videoSvc.GetPlaylist(c.Request().Context(), usr, c.Param("id"))
+	if err != nil {
+		return srv.erroredResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, httpmodel.NewPlaylistResponse(pl))
+}
+
+func (srv *Server) getPlaylists(c echo.Context) error {
+	usr, err, ok := srv.getUser(c)
+	if !ok {
+		return err
+	}
+	playlists, err := srv.videoSvc.GetPlaylists(c.Request().Context(), usr)
+	if err != nil {
+		return srv.erroredResponse(c, err)
+	}
+	resp := make([]*httpmodel.PlaylistResponse, 0, len(playlists))
+	for _, pl := range playlists {
+		resp = append(resp, httpmodel.NewPlaylistResponse(pl))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (srv *Server) deletePlaylist(c echo.Context) error {
+	usr, err, ok := srv.getUser(c)
+	if !ok {
+		return err
+	}
+	if err = srv.videoSvc.DeletePlaylist(c.Request().Context(), usr, c.Param("id")); err != nil {
+		return srv.erroredResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, common.ResponseOK)
+}
+
+func (srv *Server) watchPlaylist(c echo.Context) error {
+	usr, err, ok := srv.getUser(c)
+	if !ok {
+		return err
+	}
+	ctx := video.WithClientIP(c.Request().Context(), c.RealIP())
+	resp, err := srv.videoSvc.WatchPlaylist(ctx, usr, c.Param("id"))
+	switch {
+	case err == nil:
+		return c.XMLBlob(http.StatusOK, resp)
+	case errors.Is(err, model.ErrPlaylistNotFound):
+		return c.JSON(http.StatusNotFound, &common.Response{
+			Error: err.Error(),
+		})
+	case errors.Is(err, model.ErrNotReady),
+		errors.Is(err, model.ErrState),
+		errors.Is(err, model.ErrEmptyPlaylist):
+		return c.JSON(http.StatusMethodNotAllowed, &common.Response{
+			Error: err.Error(),
+		})
+	case errors.Is(err, model.ErrTooManyStreams):
+		return c.JSON(http.StatusTooManyRequests, &common.Response{
+			Error: err.Error(),
+		})
+	default:
+		srv.logger.Error("watchPlaylist failed", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, common.ResponseInternalError)
+	}
+}
@@ -59,12 +59,35 @@ func NewServer(cfg *Config, videoSvc *video.Service) (*Server, error) {
 	videoAPI.GET("/:id", srv.getVideo)
 	videoAPI.GET("/", srv.getVideos)
 	videoAPI.POST("/", srv.createVideo)
+	videoAPI.POST("/live", srv.createLiveVideo)
+	videoAPI.POST("/clip", srv.createClip)
+	videoAPI.POST("/:id/complete", srv.completeUpload)
+	videoAPI.POST("/import", srv.importFromURL)
+	videoAPI.GET("/:id/import", srv.getImport)
+	videoAPI.GET("/:id/stats", srv.getVideoStats)
+	videoAPI.DELETE("/:id/sessions", srv.revokeVideoSessions)
 	videoAPI.DELETE("/:id", srv.deleteVideo)
 
 	// Watch zone
 	watchAPI := api.Group("/watch")
 	watchAPI.Use(cfg.Auth.EchoAuthUserSide())
+	watchAPI.GET("/streams", srv.getStreams)
 	watchAPI.GET("/:id", srv.watchVideo)
+	watchAPI.GET("/playlist/:id", srv.watchPlaylist)
+
+	// Sessions zone
+	sessionsAPI := api.Group("/sessions")
+	sessionsAPI.Use(cfg.Auth.EchoAuthUserSide())
+	sessionsAPI.GET("/", srv.getSessions)
+	sessionsAPI.DELETE("/:kind/:id", srv.revokeSession)
+
+	// Playlist zone
+	playlistAPI := api.Group("/playlist")
+	playlistAPI.Use(cfg.Auth.EchoAuthUserSide())
+	playlistAPI.GET("/:id", srv.getPlaylist)
+	playlistAPI.GET("/", srv.getPlaylists)
+	playlistAPI.POST("/", srv.createPlaylist)
+	playlistAPI.DELETE("/:id", srv.deletePlaylist)
 
 	// Quota zone
 	quotaAPI := api.Group("/quota")
@@ -89,11 +112,14 @@ func (srv *Server) getUser(c echo.Context) (*user.User, error, bool) {
 
 func (srv *Server) erroredResponse(c echo.Context, err error) error {
 	switch {
-	case errors.Is(err, model.ErrNotFound):
+	case errors.Is(err, model.ErrNotFound),
+		errors.Is(err, model.ErrPlaylistNotFound):
 		return c.JSON(http.StatusNotFound, &common.Response{
 			Error: err.Error(),
 		})
-	case errors.Is(err, model.ErrNotResumable):
+	case errors.Is(err, model.ErrNotResumable),
+		errors.Is(err, model.ErrNotDirectUpload),
+		errors.Is(err, model.ErrDirectUploadDisabled):
 		return c.JSON(http.StatusNotAcceptable, &common.Response{Error: err.Error()})
 	default:
 		srv.logger.Error("internal error", zap.Error(err))
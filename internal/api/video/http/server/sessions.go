@@ -0,0 +1,67 @@
+//nolint:wrapcheck  // we use echo-style handler returns, i.e. return c.JSON(..)
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	common "github.com/adwski/vidi/internal/api/model"
+	httpmodel "github.com/adwski/vidi/internal/api/video/http"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+func (srv *Server) getSessions(c echo.Context) error {
+	usr, err, ok := srv.getUser(c)
+	if !ok {
+		return err
+	}
+	sessions, err := srv.videoSvc.GetSessions(c.Request().Context(), usr, c.QueryParam("video_id"))
+	if err != nil {
+		return srv.sessionsErrorResponse(c, "getSessions", err)
+	}
+	if sessions == nil {
+		sessions = []*model.UserSession{}
+	}
+	return c.JSON(http.StatusOK, sessions)
+}
+
+func (srv *Server) revokeSession(c echo.Context) error {
+	usr, err, ok := srv.getUser(c)
+	if !ok {
+		return err
+	}
+	err = srv.videoSvc.RevokeSession(c.Request().Context(), usr, c.Param("kind"), c.Param("id"))
+	if err != nil {
+		return srv.sessionsErrorResponse(c, "revokeSession", err)
+	}
+	return c.JSON(http.StatusOK, common.ResponseOK)
+}
+
+func (srv *Server) revokeVideoSessions(c echo.Context) error {
+	usr, err, ok := srv.getUser(c)
+	if !ok {
+		return err
+	}
+	revoked, err := srv.videoSvc.RevokeVideoSessions(c.Request().Context(), usr, c.Param("id"))
+	if err != nil {
+		return srv.sessionsErrorResponse(c, "revokeVideoSessions", err)
+	}
+	return c.JSON(http.StatusOK, &httpmodel.RevokeResponse{Revoked: revoked})
+}
+
+func (srv *Server) sessionsErrorResponse(c echo.Context, handler string, err error) error {
+	switch {
+	case errors.Is(err, model.ErrSessionsNotIndexed):
+		return c.JSON(http.StatusNotImplemented, &common.Response{Error: err.Error()})
+	case errors.Is(err, model.ErrSessionKind):
+		return c.JSON(http.StatusBadRequest, &common.Response{Error: err.Error()})
+	case errors.Is(err, model.ErrNotFound),
+		errors.Is(err, model.ErrSessionNotFound):
+		return c.JSON(http.StatusNotFound, &common.Response{Error: err.Error()})
+	default:
+		srv.logger.Error(handler+" failed", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, common.ResponseInternalError)
+	}
+}
@@ -0,0 +1,192 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	user "github.com/adwski/vidi/internal/api/user/model"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"go.uber.org/zap"
+)
+
+const (
+	// importLease is a time during which claimed import is not given to other importers.
+	// Importer prolongs lease every time it reports progress.
+	importLease = 10 * time.Minute
+
+	importBackoff    = 10 * time.Second
+	importMaxBackoff = time.Hour
+
+	defaultImportProbeTimeout = 10 * time.Second
+)
+
+// ImportConfig enables imports from remote urls.
+type ImportConfig struct {
+	// AllowedHosts is a list of hosts from which videos could be imported.
+	// Entry with leading dot allows any subdomain.
+	AllowedHosts []string
+	// MaxSize limits size of imported file. Zero does not limit size.
+	MaxSize uint64
+	// ProbeTimeout limits time of import source probe.
+	ProbeTimeout time.Duration
+}
+
+type importer struct {
+	client       *http.Client
+	allowedHosts []string
+	maxSize      uint64
+}
+
+func newImporter(cfg *ImportConfig) *importer {
+	if cfg == nil {
+		return nil
+	}
+	timeout := cfg.ProbeTimeout
+	if timeout == 0 {
+		timeout = defaultImportProbeTimeout
+	}
+	imp := &importer{
+		allowedHosts: cfg.AllowedHosts,
+		maxSize:      cfg.MaxSize,
+	}
+	imp.client = &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, _ []*http.Request) error {
+			if !model.ImportHostAllowed(imp.allowedHosts, req.URL.Hostname()) {
+				return model.ErrImportHostNotAllowed
+			}
+			return nil
+		},
+	}
+	return imp
+}
+
+// ImportFromURL creates video that is downloaded from remote url by importer.
+// Source is probed first, so size limit and quota are checked before video is created.
+// Imported video is stored as regular upload parts, so it goes through usual status flow.
+func (svc *Service) ImportFromURL(ctx context.Context, usr *user.User, req *model.ImportRequest) (*model.Video, error) {
+	if svc.imports == nil {
+		return nil, model.ErrImportDisabled
+	}
+	if len(req.Name) == 0 {
+		return nil, model.ErrNoName
+	}
+	u, err := svc.imports.checkURL(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	partSize, err := svc.getPartSize(req.PartSize)
+	if err != nil {
+		return nil, err
+	}
+	size, err := svc.imports.probe(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	if err = svc.checkQuotas(ctx, usr, size); err != nil {
+		return nil, err
+	}
+
+	newVideo := model.NewVideoNoID(usr.ID, req.Name, size)
+	newVideo.PartSize = partSize
+	newVideo.ImportURL = u.String()
+	newVideo.UploadInfo = &model.UploadInfo{
+		Parts: model.NewParts(size, partSize),
+	}
+	if err = svc.storeNewVideo(ctx, newVideo); err != nil {
+		return nil, err
+	}
+	svc.logger.Debug("import created",
+		zap.String("vid", newVideo.ID),
+		zap.String("url", newVideo.ImportURL),
+		zap.Uint64("size", size))
+	return newVideo, nil
+}
+
+// GetImport returns import progress of video.
+func (svc *Service) GetImport(ctx context.Context, usr *user.User, vid string) (*model.ImportStatus, error) {
+	status, err := svc.s.GetImport(ctx, vid, usr.ID)
+	if err != nil {
+		return nil, errors.Join(model.ErrStorage, err)
+	}
+	return status, nil
+}
+
+// GetImportJobs returns imports that should be performed by importer.
+func (svc *Service) GetImportJobs(ctx context.Context, limit uint) ([]*model.Import, error) {
+	imports, err := svc.s.ClaimImports(ctx, time.Now(), importLease, limit)
+	if err != nil {
+		return nil, errors.Join(model.ErrStorage, err)
+	}
+	return imports, nil
+}
+
+// UpdateImport records import progress reported by importer.
+// Failed imports are retried with exponential backoff.
+func (svc *Service) UpdateImport(ctx context.Context, upd *model.ImportUpdate) error {
+	if err := svc.s.UpdateImport(ctx, upd, time.Now(), importLease, importBackoff, importMaxBackoff); err != nil {
+		return errors.Join(model.ErrStorage, err)
+	}
+	return nil
+}
+
+// checkQuotas checks that user could create one more video of specified size.
+func (svc *Service) checkQuotas(ctx context.Context, usr *user.User, size uint64) error {
+	usage, err := svc.s.Usage(ctx, usr.ID)
+	if err != nil {
+		return errors.Join(model.ErrStorage, err)
+	}
+	if svc.quotas.VideosPerUser > 0 && usage.Videos >= svc.quotas.VideosPerUser {
+		return model.ErrQuotaExceeded
+	}
+	if svc.quotas.MaxTotalSize > 0 && usage.Size+size > svc.quotas.MaxTotalSize {
+		return model.ErrQuotaExceeded
+	}
+	return nil
+}
+
+func (imp *importer) checkURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Join(model.ErrImportURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, model.ErrImportURL
+	}
+	if !model.ImportHostAllowed(imp.allowedHosts, u.Hostname()) {
+		return nil, model.ErrImportHostNotAllowed
+	}
+	return u, nil
+}
+
+// probe returns size of import source. Source must support range requests,
+// so download could be resumed.
+func (imp *importer) probe(ctx context.Context, u *url.URL) (uint64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.String(), http.NoBody)
+	if err != nil {
+		return 0, errors.Join(model.ErrImportURL, err)
+	}
+	resp, err := imp.client.Do(req)
+	if err != nil {
+		if errors.Is(err, model.ErrImportHostNotAllowed) {
+			return 0, model.ErrImportHostNotAllowed
+		}
+		return 0, errors.Join(model.ErrImportSource, err)
+	}
+	_ = resp.Body.Close()
+	switch {
+	case resp.StatusCode != http.StatusOK:
+		return 0, errors.Join(model.ErrImportSource, fmt.Errorf("unexpected status: %s", resp.Status))
+	case resp.ContentLength <= 0:
+		return 0, errors.Join(model.ErrImportSource, errors.New("size is unknown"))
+	case resp.Header.Get("Accept-Ranges") != "bytes":
+		return 0, errors.Join(model.ErrImportSource, errors.New("range requests are not supported"))
+	case imp.maxSize > 0 && uint64(resp.ContentLength) > imp.maxSize:
+		return 0, model.ErrImportTooLarge
+	}
+	return uint64(resp.ContentLength), nil
+}
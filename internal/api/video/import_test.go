@@ -0,0 +1,169 @@
+package video
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	usermodel "github.com/adwski/vidi/internal/api/user/model"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestService_ImportFromURL(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	src := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/video.mp4":
+			w.Header().Set("Accept-Ranges", "bytes")
+			w.Header().Set("Content-Length", "25")
+		case "/large.mp4":
+			w.Header().Set("Accept-Ranges", "bytes")
+			w.Header().Set("Content-Length", strconv.Itoa(1000))
+		case "/noranges.mp4":
+			w.Header().Set("Content-Length", "25")
+		case "/redirect.mp4":
+			http.Redirect(w, r, "http://example.com/video.mp4", http.StatusFound)
+			return
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer src.Close()
+	srcURL, err := url.Parse(src.URL)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  s,
+		Quotas: Quotas{VideosPerUser: 10, MaxTotalSize: 100},
+		Import: &ImportConfig{
+			AllowedHosts: []string{srcURL.Hostname()},
+			MaxSize:      500,
+		},
+	})
+	u := &usermodel.User{ID: "test"}
+
+	s.EXPECT().Usage(ctx, "test").Return(&model.UserUsage{Videos: 1, Size: 50}, nil).Once()
+	s.EXPECT().Create(ctx, mock.Anything).Run(func(_ context.Context, v *model.Video) {
+		assert.Equal(t, src.URL+"/video.mp4", v.ImportURL)
+		assert.Equal(t, uint64(25), v.Size)
+		assert.Equal(t, uint64(10), v.PartSize)
+		require.Len(t, v.UploadInfo.Parts, 3)
+		assert.Equal(t, uint64(5), v.UploadInfo.Parts[2].Size)
+	}).Return(nil).Once()
+
+	v, err := svc.ImportFromURL(ctx, u, &model.ImportRequest{
+		Name:     "test",
+		URL:      src.URL + "/video.mp4",
+		PartSize: 10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusCreated, v.Status)
+
+	tests := []struct {
+		name  string
+		url   string
+		usage *model.UserUsage
+		err   error
+	}{
+		{
+			name: "not allowed host",
+			url:  "http://example.com/video.mp4",
+			err:  model.ErrImportHostNotAllowed,
+		},
+		{
+			name: "redirect to not allowed host",
+			url:  src.URL + "/redirect.mp4",
+			err:  model.ErrImportHostNotAllowed,
+		},
+		{
+			name: "invalid scheme",
+			url:  "file:///etc/passwd",
+			err:  model.ErrImportURL,
+		},
+		{
+			name: "source not found",
+			url:  src.URL + "/missing.mp4",
+			err:  model.ErrImportSource,
+		},
+		{
+			name: "range requests not supported",
+			url:  src.URL + "/noranges.mp4",
+			err:  model.ErrImportSource,
+		},
+		{
+			name: "too large",
+			url:  src.URL + "/large.mp4",
+			err:  model.ErrImportTooLarge,
+		},
+		{
+			name:  "size quota exceeded",
+			url:   src.URL + "/video.mp4",
+			usage: &model.UserUsage{Videos: 1, Size: 80},
+			err:   model.ErrQuotaExceeded,
+		},
+		{
+			name:  "videos quota exceeded",
+			url:   src.URL + "/video.mp4",
+			usage: &model.UserUsage{Videos: 10},
+			err:   model.ErrQuotaExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.usage != nil {
+				s.EXPECT().Usage(ctx, "test").Return(tt.usage, nil).Once()
+			}
+			_, errI := svc.ImportFromURL(ctx, u, &model.ImportRequest{Name: "test", URL: tt.url})
+			require.ErrorIs(t, errI, tt.err)
+		})
+	}
+}
+
+func TestService_ImportFromURLDisabled(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  NewMockStore(t),
+	})
+	_, err = svc.ImportFromURL(context.Background(), &usermodel.User{ID: "test"}, &model.ImportRequest{
+		Name: "test",
+		URL:  "http://example.com/video.mp4",
+	})
+	require.ErrorIs(t, err, model.ErrImportDisabled)
+}
+
+func TestService_UpdateImport(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  s,
+	})
+	imports := []*model.Import{{ID: 1, VideoID: "test", Location: "loc", URL: "http://test"}}
+	s.EXPECT().ClaimImports(ctx, mock.Anything, importLease, uint(10)).Return(imports, nil)
+
+	got, err := svc.GetImportJobs(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, imports, got)
+
+	upd := &model.ImportUpdate{ID: 1, Downloaded: 10}
+	s.EXPECT().UpdateImport(ctx, upd, mock.Anything, importLease, importBackoff, importMaxBackoff).Return(nil)
+	require.NoError(t, svc.UpdateImport(ctx, upd))
+}
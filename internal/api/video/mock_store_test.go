@@ -4,6 +4,7 @@ package video
 
 import (
 	context "context"
+	time "time"
 
 	model "github.com/adwski/vidi/internal/api/video/model"
 	mock "github.com/stretchr/testify/mock"
@@ -22,6 +23,128 @@ func (_m *MockStore) EXPECT() *MockStore_Expecter {
 	return &MockStore_Expecter{mock: &_m.Mock}
 }
 
+// ClaimImports provides a mock function with given fields: ctx, now, lease, limit
+func (_m *MockStore) ClaimImports(ctx context.Context, now time.Time, lease time.Duration, limit uint) ([]*model.Import, error) {
+	ret := _m.Called(ctx, now, lease, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimImports")
+	}
+
+	var r0 []*model.Import
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Duration, uint) ([]*model.Import, error)); ok {
+		return rf(ctx, now, lease, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Duration, uint) []*model.Import); ok {
+		r0 = rf(ctx, now, lease, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Import)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Duration, uint) error); ok {
+		r1 = rf(ctx, now, lease, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_ClaimImports_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimImports'
+type MockStore_ClaimImports_Call struct {
+	*mock.Call
+}
+
+// ClaimImports is a helper method to define mock.On call
+//   - ctx context.Context
+//   - now time.Time
+//   - lease time.Duration
+//   - limit uint
+func (_e *MockStore_Expecter) ClaimImports(ctx interface{}, now interface{}, lease interface{}, limit interface{}) *MockStore_ClaimImports_Call {
+	return &MockStore_ClaimImports_Call{Call: _e.mock.On("ClaimImports", ctx, now, lease, limit)}
+}
+
+func (_c *MockStore_ClaimImports_Call) Run(run func(ctx context.Context, now time.Time, lease time.Duration, limit uint)) *MockStore_ClaimImports_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Duration), args[3].(uint))
+	})
+	return _c
+}
+
+func (_c *MockStore_ClaimImports_Call) Return(_a0 []*model.Import, _a1 error) *MockStore_ClaimImports_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_ClaimImports_Call) RunAndReturn(run func(context.Context, time.Time, time.Duration, uint) ([]*model.Import, error)) *MockStore_ClaimImports_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ClaimPurges provides a mock function with given fields: ctx, now, lease, limit
+func (_m *MockStore) ClaimPurges(ctx context.Context, now time.Time, lease time.Duration, limit uint) ([]*model.Purge, error) {
+	ret := _m.Called(ctx, now, lease, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimPurges")
+	}
+
+	var r0 []*model.Purge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Duration, uint) ([]*model.Purge, error)); ok {
+		return rf(ctx, now, lease, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Duration, uint) []*model.Purge); ok {
+		r0 = rf(ctx, now, lease, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Purge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Duration, uint) error); ok {
+		r1 = rf(ctx, now, lease, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_ClaimPurges_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimPurges'
+type MockStore_ClaimPurges_Call struct {
+	*mock.Call
+}
+
+// ClaimPurges is a helper method to define mock.On call
+//   - ctx context.Context
+//   - now time.Time
+//   - lease time.Duration
+//   - limit uint
+func (_e *MockStore_Expecter) ClaimPurges(ctx interface{}, now interface{}, lease interface{}, limit interface{}) *MockStore_ClaimPurges_Call {
+	return &MockStore_ClaimPurges_Call{Call: _e.mock.On("ClaimPurges", ctx, now, lease, limit)}
+}
+
+func (_c *MockStore_ClaimPurges_Call) Run(run func(ctx context.Context, now time.Time, lease time.Duration, limit uint)) *MockStore_ClaimPurges_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Duration), args[3].(uint))
+	})
+	return _c
+}
+
+func (_c *MockStore_ClaimPurges_Call) Return(_a0 []*model.Purge, _a1 error) *MockStore_ClaimPurges_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_ClaimPurges_Call) RunAndReturn(run func(context.Context, time.Time, time.Duration, uint) ([]*model.Purge, error)) *MockStore_ClaimPurges_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function with given fields: ctx, vi
 func (_m *MockStore) Create(ctx context.Context, vi *model.Video) error {
 	ret := _m.Called(ctx, vi)
@@ -69,6 +192,53 @@ func (_c *MockStore_Create_Call) RunAndReturn(run func(context.Context, *model.V
 	return _c
 }
 
+// CreatePlaylist provides a mock function with given fields: ctx, pl
+func (_m *MockStore) CreatePlaylist(ctx context.Context, pl *model.Playlist) error {
+	ret := _m.Called(ctx, pl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePlaylist")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Playlist) error); ok {
+		r0 = rf(ctx, pl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_CreatePlaylist_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePlaylist'
+type MockStore_CreatePlaylist_Call struct {
+	*mock.Call
+}
+
+// CreatePlaylist is a helper method to define mock.On call
+//   - ctx context.Context
+//   - pl *model.Playlist
+func (_e *MockStore_Expecter) CreatePlaylist(ctx interface{}, pl interface{}) *MockStore_CreatePlaylist_Call {
+	return &MockStore_CreatePlaylist_Call{Call: _e.mock.On("CreatePlaylist", ctx, pl)}
+}
+
+func (_c *MockStore_CreatePlaylist_Call) Run(run func(ctx context.Context, pl *model.Playlist)) *MockStore_CreatePlaylist_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Playlist))
+	})
+	return _c
+}
+
+func (_c *MockStore_CreatePlaylist_Call) Return(_a0 error) *MockStore_CreatePlaylist_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_CreatePlaylist_Call) RunAndReturn(run func(context.Context, *model.Playlist) error) *MockStore_CreatePlaylist_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Delete provides a mock function with given fields: ctx, id, userID
 func (_m *MockStore) Delete(ctx context.Context, id string, userID string) error {
 	ret := _m.Called(ctx, id, userID)
@@ -81,112 +251,750 @@ func (_m *MockStore) Delete(ctx context.Context, id string, userID string) error
 	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
 		r0 = rf(ctx, id, userID)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockStore_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - userID string
+func (_e *MockStore_Expecter) Delete(ctx interface{}, id interface{}, userID interface{}) *MockStore_Delete_Call {
+	return &MockStore_Delete_Call{Call: _e.mock.On("Delete", ctx, id, userID)}
+}
+
+func (_c *MockStore_Delete_Call) Run(run func(ctx context.Context, id string, userID string)) *MockStore_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_Delete_Call) Return(_a0 error) *MockStore_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_Delete_Call) RunAndReturn(run func(context.Context, string, string) error) *MockStore_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteExpiredSources provides a mock function with given fields: ctx, before
+func (_m *MockStore) DeleteExpiredSources(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredSources")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_DeleteExpiredSources_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpiredSources'
+type MockStore_DeleteExpiredSources_Call struct {
+	*mock.Call
+}
+
+// DeleteExpiredSources is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *MockStore_Expecter) DeleteExpiredSources(ctx interface{}, before interface{}) *MockStore_DeleteExpiredSources_Call {
+	return &MockStore_DeleteExpiredSources_Call{Call: _e.mock.On("DeleteExpiredSources", ctx, before)}
+}
+
+func (_c *MockStore_DeleteExpiredSources_Call) Run(run func(ctx context.Context, before time.Time)) *MockStore_DeleteExpiredSources_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockStore_DeleteExpiredSources_Call) Return(_a0 int64, _a1 error) *MockStore_DeleteExpiredSources_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_DeleteExpiredSources_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *MockStore_DeleteExpiredSources_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeletePlaylist provides a mock function with given fields: ctx, id, userID
+func (_m *MockStore) DeletePlaylist(ctx context.Context, id string, userID string) error {
+	ret := _m.Called(ctx, id, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePlaylist")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_DeletePlaylist_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeletePlaylist'
+type MockStore_DeletePlaylist_Call struct {
+	*mock.Call
+}
+
+// DeletePlaylist is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - userID string
+func (_e *MockStore_Expecter) DeletePlaylist(ctx interface{}, id interface{}, userID interface{}) *MockStore_DeletePlaylist_Call {
+	return &MockStore_DeletePlaylist_Call{Call: _e.mock.On("DeletePlaylist", ctx, id, userID)}
+}
+
+func (_c *MockStore_DeletePlaylist_Call) Run(run func(ctx context.Context, id string, userID string)) *MockStore_DeletePlaylist_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_DeletePlaylist_Call) Return(_a0 error) *MockStore_DeletePlaylist_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_DeletePlaylist_Call) RunAndReturn(run func(context.Context, string, string) error) *MockStore_DeletePlaylist_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteUploadedParts provides a mock function with given fields: ctx, vid
+func (_m *MockStore) DeleteUploadedParts(ctx context.Context, vid string) error {
+	ret := _m.Called(ctx, vid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUploadedParts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, vid)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_DeleteUploadedParts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUploadedParts'
+type MockStore_DeleteUploadedParts_Call struct {
+	*mock.Call
+}
+
+// DeleteUploadedParts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - vid string
+func (_e *MockStore_Expecter) DeleteUploadedParts(ctx interface{}, vid interface{}) *MockStore_DeleteUploadedParts_Call {
+	return &MockStore_DeleteUploadedParts_Call{Call: _e.mock.On("DeleteUploadedParts", ctx, vid)}
+}
+
+func (_c *MockStore_DeleteUploadedParts_Call) Run(run func(ctx context.Context, vid string)) *MockStore_DeleteUploadedParts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_DeleteUploadedParts_Call) Return(_a0 error) *MockStore_DeleteUploadedParts_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_DeleteUploadedParts_Call) RunAndReturn(run func(context.Context, string) error) *MockStore_DeleteUploadedParts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExpireUploads provides a mock function with given fields: ctx, before, reason
+func (_m *MockStore) ExpireUploads(ctx context.Context, before time.Time, reason string) (int64, error) {
+	ret := _m.Called(ctx, before, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireUploads")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string) (int64, error)); ok {
+		return rf(ctx, before, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string) int64); ok {
+		r0 = rf(ctx, before, reason)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, string) error); ok {
+		r1 = rf(ctx, before, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_ExpireUploads_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExpireUploads'
+type MockStore_ExpireUploads_Call struct {
+	*mock.Call
+}
+
+// ExpireUploads is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+//   - reason string
+func (_e *MockStore_Expecter) ExpireUploads(ctx interface{}, before interface{}, reason interface{}) *MockStore_ExpireUploads_Call {
+	return &MockStore_ExpireUploads_Call{Call: _e.mock.On("ExpireUploads", ctx, before, reason)}
+}
+
+func (_c *MockStore_ExpireUploads_Call) Run(run func(ctx context.Context, before time.Time, reason string)) *MockStore_ExpireUploads_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_ExpireUploads_Call) Return(_a0 int64, _a1 error) *MockStore_ExpireUploads_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_ExpireUploads_Call) RunAndReturn(run func(context.Context, time.Time, string) (int64, error)) *MockStore_ExpireUploads_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindDuplicate provides a mock function with given fields: ctx, fingerprint, userID
+func (_m *MockStore) FindDuplicate(ctx context.Context, fingerprint string, userID string) (*model.Video, error) {
+	ret := _m.Called(ctx, fingerprint, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDuplicate")
+	}
+
+	var r0 *model.Video
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.Video, error)); ok {
+		return rf(ctx, fingerprint, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.Video); ok {
+		r0 = rf(ctx, fingerprint, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Video)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, fingerprint, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_FindDuplicate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindDuplicate'
+type MockStore_FindDuplicate_Call struct {
+	*mock.Call
+}
+
+// FindDuplicate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fingerprint string
+//   - userID string
+func (_e *MockStore_Expecter) FindDuplicate(ctx interface{}, fingerprint interface{}, userID interface{}) *MockStore_FindDuplicate_Call {
+	return &MockStore_FindDuplicate_Call{Call: _e.mock.On("FindDuplicate", ctx, fingerprint, userID)}
+}
+
+func (_c *MockStore_FindDuplicate_Call) Run(run func(ctx context.Context, fingerprint string, userID string)) *MockStore_FindDuplicate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_FindDuplicate_Call) Return(_a0 *model.Video, _a1 error) *MockStore_FindDuplicate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_FindDuplicate_Call) RunAndReturn(run func(context.Context, string, string) (*model.Video, error)) *MockStore_FindDuplicate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function with given fields: ctx, id, userID
+func (_m *MockStore) Get(ctx context.Context, id string, userID string) (*model.Video, error) {
+	ret := _m.Called(ctx, id, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *model.Video
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.Video, error)); ok {
+		return rf(ctx, id, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.Video); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Video)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockStore_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - userID string
+func (_e *MockStore_Expecter) Get(ctx interface{}, id interface{}, userID interface{}) *MockStore_Get_Call {
+	return &MockStore_Get_Call{Call: _e.mock.On("Get", ctx, id, userID)}
+}
+
+func (_c *MockStore_Get_Call) Run(run func(ctx context.Context, id string, userID string)) *MockStore_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_Get_Call) Return(_a0 *model.Video, _a1 error) *MockStore_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_Get_Call) RunAndReturn(run func(context.Context, string, string) (*model.Video, error)) *MockStore_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAll provides a mock function with given fields: ctx, userID
+func (_m *MockStore) GetAll(ctx context.Context, userID string) ([]*model.Video, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 []*model.Video
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*model.Video, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.Video); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Video)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_GetAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAll'
+type MockStore_GetAll_Call struct {
+	*mock.Call
+}
+
+// GetAll is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *MockStore_Expecter) GetAll(ctx interface{}, userID interface{}) *MockStore_GetAll_Call {
+	return &MockStore_GetAll_Call{Call: _e.mock.On("GetAll", ctx, userID)}
+}
+
+func (_c *MockStore_GetAll_Call) Run(run func(ctx context.Context, userID string)) *MockStore_GetAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_GetAll_Call) Return(_a0 []*model.Video, _a1 error) *MockStore_GetAll_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_GetAll_Call) RunAndReturn(run func(context.Context, string) ([]*model.Video, error)) *MockStore_GetAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetImport provides a mock function with given fields: ctx, vid, userID
+func (_m *MockStore) GetImport(ctx context.Context, vid string, userID string) (*model.ImportStatus, error) {
+	ret := _m.Called(ctx, vid, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetImport")
+	}
+
+	var r0 *model.ImportStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.ImportStatus, error)); ok {
+		return rf(ctx, vid, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.ImportStatus); ok {
+		r0 = rf(ctx, vid, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ImportStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, vid, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_GetImport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetImport'
+type MockStore_GetImport_Call struct {
+	*mock.Call
+}
+
+// GetImport is a helper method to define mock.On call
+//   - ctx context.Context
+//   - vid string
+//   - userID string
+func (_e *MockStore_Expecter) GetImport(ctx interface{}, vid interface{}, userID interface{}) *MockStore_GetImport_Call {
+	return &MockStore_GetImport_Call{Call: _e.mock.On("GetImport", ctx, vid, userID)}
+}
+
+func (_c *MockStore_GetImport_Call) Run(run func(ctx context.Context, vid string, userID string)) *MockStore_GetImport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_GetImport_Call) Return(_a0 *model.ImportStatus, _a1 error) *MockStore_GetImport_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_GetImport_Call) RunAndReturn(run func(context.Context, string, string) (*model.ImportStatus, error)) *MockStore_GetImport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetListByStatus provides a mock function with given fields: ctx, status
+func (_m *MockStore) GetListByStatus(ctx context.Context, status model.Status) ([]*model.Video, error) {
+	ret := _m.Called(ctx, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetListByStatus")
+	}
+
+	var r0 []*model.Video
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Status) ([]*model.Video, error)); ok {
+		return rf(ctx, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Status) []*model.Video); ok {
+		r0 = rf(ctx, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Video)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Status) error); ok {
+		r1 = rf(ctx, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_GetListByStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetListByStatus'
+type MockStore_GetListByStatus_Call struct {
+	*mock.Call
+}
+
+// GetListByStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - status model.Status
+func (_e *MockStore_Expecter) GetListByStatus(ctx interface{}, status interface{}) *MockStore_GetListByStatus_Call {
+	return &MockStore_GetListByStatus_Call{Call: _e.mock.On("GetListByStatus", ctx, status)}
+}
+
+func (_c *MockStore_GetListByStatus_Call) Run(run func(ctx context.Context, status model.Status)) *MockStore_GetListByStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.Status))
+	})
+	return _c
+}
+
+func (_c *MockStore_GetListByStatus_Call) Return(_a0 []*model.Video, _a1 error) *MockStore_GetListByStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_GetListByStatus_Call) RunAndReturn(run func(context.Context, model.Status) ([]*model.Video, error)) *MockStore_GetListByStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPlaylist provides a mock function with given fields: ctx, id, userID
+func (_m *MockStore) GetPlaylist(ctx context.Context, id string, userID string) (*model.Playlist, error) {
+	ret := _m.Called(ctx, id, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPlaylist")
+	}
+
+	var r0 *model.Playlist
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.Playlist, error)); ok {
+		return rf(ctx, id, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.Playlist); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Playlist)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStore_GetPlaylist_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPlaylist'
+type MockStore_GetPlaylist_Call struct {
+	*mock.Call
+}
+
+// GetPlaylist is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - userID string
+func (_e *MockStore_Expecter) GetPlaylist(ctx interface{}, id interface{}, userID interface{}) *MockStore_GetPlaylist_Call {
+	return &MockStore_GetPlaylist_Call{Call: _e.mock.On("GetPlaylist", ctx, id, userID)}
+}
+
+func (_c *MockStore_GetPlaylist_Call) Run(run func(ctx context.Context, id string, userID string)) *MockStore_GetPlaylist_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockStore_GetPlaylist_Call) Return(_a0 *model.Playlist, _a1 error) *MockStore_GetPlaylist_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStore_GetPlaylist_Call) RunAndReturn(run func(context.Context, string, string) (*model.Playlist, error)) *MockStore_GetPlaylist_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPlaylists provides a mock function with given fields: ctx, userID
+func (_m *MockStore) GetPlaylists(ctx context.Context, userID string) ([]*model.Playlist, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPlaylists")
+	}
+
+	var r0 []*model.Playlist
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*model.Playlist, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.Playlist); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Playlist)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
-// MockStore_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
-type MockStore_Delete_Call struct {
+// MockStore_GetPlaylists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPlaylists'
+type MockStore_GetPlaylists_Call struct {
 	*mock.Call
 }
 
-// Delete is a helper method to define mock.On call
+// GetPlaylists is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id string
 //   - userID string
-func (_e *MockStore_Expecter) Delete(ctx interface{}, id interface{}, userID interface{}) *MockStore_Delete_Call {
-	return &MockStore_Delete_Call{Call: _e.mock.On("Delete", ctx, id, userID)}
+func (_e *MockStore_Expecter) GetPlaylists(ctx interface{}, userID interface{}) *MockStore_GetPlaylists_Call {
+	return &MockStore_GetPlaylists_Call{Call: _e.mock.On("GetPlaylists", ctx, userID)}
 }
 
-func (_c *MockStore_Delete_Call) Run(run func(ctx context.Context, id string, userID string)) *MockStore_Delete_Call {
+func (_c *MockStore_GetPlaylists_Call) Run(run func(ctx context.Context, userID string)) *MockStore_GetPlaylists_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_Delete_Call) Return(_a0 error) *MockStore_Delete_Call {
-	_c.Call.Return(_a0)
+func (_c *MockStore_GetPlaylists_Call) Return(_a0 []*model.Playlist, _a1 error) *MockStore_GetPlaylists_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_Delete_Call) RunAndReturn(run func(context.Context, string, string) error) *MockStore_Delete_Call {
+func (_c *MockStore_GetPlaylists_Call) RunAndReturn(run func(context.Context, string) ([]*model.Playlist, error)) *MockStore_GetPlaylists_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteUploadedParts provides a mock function with given fields: ctx, vid
-func (_m *MockStore) DeleteUploadedParts(ctx context.Context, vid string) error {
-	ret := _m.Called(ctx, vid)
+// GetPurgeStats provides a mock function with given fields: ctx
+func (_m *MockStore) GetPurgeStats(ctx context.Context) (*model.PurgeStats, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteUploadedParts")
+		panic("no return value specified for GetPurgeStats")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, vid)
+	var r0 *model.PurgeStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.PurgeStats, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.PurgeStats); ok {
+		r0 = rf(ctx)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PurgeStats)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// MockStore_DeleteUploadedParts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUploadedParts'
-type MockStore_DeleteUploadedParts_Call struct {
+// MockStore_GetPurgeStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPurgeStats'
+type MockStore_GetPurgeStats_Call struct {
 	*mock.Call
 }
 
-// DeleteUploadedParts is a helper method to define mock.On call
+// GetPurgeStats is a helper method to define mock.On call
 //   - ctx context.Context
-//   - vid string
-func (_e *MockStore_Expecter) DeleteUploadedParts(ctx interface{}, vid interface{}) *MockStore_DeleteUploadedParts_Call {
-	return &MockStore_DeleteUploadedParts_Call{Call: _e.mock.On("DeleteUploadedParts", ctx, vid)}
+func (_e *MockStore_Expecter) GetPurgeStats(ctx interface{}) *MockStore_GetPurgeStats_Call {
+	return &MockStore_GetPurgeStats_Call{Call: _e.mock.On("GetPurgeStats", ctx)}
 }
 
-func (_c *MockStore_DeleteUploadedParts_Call) Run(run func(ctx context.Context, vid string)) *MockStore_DeleteUploadedParts_Call {
+func (_c *MockStore_GetPurgeStats_Call) Run(run func(ctx context.Context)) *MockStore_GetPurgeStats_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context))
 	})
 	return _c
 }
 
-func (_c *MockStore_DeleteUploadedParts_Call) Return(_a0 error) *MockStore_DeleteUploadedParts_Call {
-	_c.Call.Return(_a0)
+func (_c *MockStore_GetPurgeStats_Call) Return(_a0 *model.PurgeStats, _a1 error) *MockStore_GetPurgeStats_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_DeleteUploadedParts_Call) RunAndReturn(run func(context.Context, string) error) *MockStore_DeleteUploadedParts_Call {
+func (_c *MockStore_GetPurgeStats_Call) RunAndReturn(run func(context.Context) (*model.PurgeStats, error)) *MockStore_GetPurgeStats_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Get provides a mock function with given fields: ctx, id, userID
-func (_m *MockStore) Get(ctx context.Context, id string, userID string) (*model.Video, error) {
-	ret := _m.Called(ctx, id, userID)
+// GetReprocessCandidates provides a mock function with given fields: ctx, ids, outdatedVersion
+func (_m *MockStore) GetReprocessCandidates(ctx context.Context, ids []string, outdatedVersion string) ([]string, error) {
+	ret := _m.Called(ctx, ids, outdatedVersion)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Get")
+		panic("no return value specified for GetReprocessCandidates")
 	}
 
-	var r0 *model.Video
+	var r0 []string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.Video, error)); ok {
-		return rf(ctx, id, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string) ([]string, error)); ok {
+		return rf(ctx, ids, outdatedVersion)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.Video); ok {
-		r0 = rf(ctx, id, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string) []string); ok {
+		r0 = rf(ctx, ids, outdatedVersion)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Video)
+			r0 = ret.Get(0).([]string)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, id, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, []string, string) error); ok {
+		r1 = rf(ctx, ids, outdatedVersion)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -194,59 +1002,59 @@ func (_m *MockStore) Get(ctx context.Context, id string, userID string) (*model.
 	return r0, r1
 }
 
-// MockStore_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
-type MockStore_Get_Call struct {
+// MockStore_GetReprocessCandidates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetReprocessCandidates'
+type MockStore_GetReprocessCandidates_Call struct {
 	*mock.Call
 }
 
-// Get is a helper method to define mock.On call
+// GetReprocessCandidates is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id string
-//   - userID string
-func (_e *MockStore_Expecter) Get(ctx interface{}, id interface{}, userID interface{}) *MockStore_Get_Call {
-	return &MockStore_Get_Call{Call: _e.mock.On("Get", ctx, id, userID)}
+//   - ids []string
+//   - outdatedVersion string
+func (_e *MockStore_Expecter) GetReprocessCandidates(ctx interface{}, ids interface{}, outdatedVersion interface{}) *MockStore_GetReprocessCandidates_Call {
+	return &MockStore_GetReprocessCandidates_Call{Call: _e.mock.On("GetReprocessCandidates", ctx, ids, outdatedVersion)}
 }
 
-func (_c *MockStore_Get_Call) Run(run func(ctx context.Context, id string, userID string)) *MockStore_Get_Call {
+func (_c *MockStore_GetReprocessCandidates_Call) Run(run func(ctx context.Context, ids []string, outdatedVersion string)) *MockStore_GetReprocessCandidates_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string))
+		run(args[0].(context.Context), args[1].([]string), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_Get_Call) Return(_a0 *model.Video, _a1 error) *MockStore_Get_Call {
+func (_c *MockStore_GetReprocessCandidates_Call) Return(_a0 []string, _a1 error) *MockStore_GetReprocessCandidates_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_Get_Call) RunAndReturn(run func(context.Context, string, string) (*model.Video, error)) *MockStore_Get_Call {
+func (_c *MockStore_GetReprocessCandidates_Call) RunAndReturn(run func(context.Context, []string, string) ([]string, error)) *MockStore_GetReprocessCandidates_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetAll provides a mock function with given fields: ctx, userID
-func (_m *MockStore) GetAll(ctx context.Context, userID string) ([]*model.Video, error) {
-	ret := _m.Called(ctx, userID)
+// GetViewStats provides a mock function with given fields: ctx, vid
+func (_m *MockStore) GetViewStats(ctx context.Context, vid string) (*model.ViewStats, error) {
+	ret := _m.Called(ctx, vid)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAll")
+		panic("no return value specified for GetViewStats")
 	}
 
-	var r0 []*model.Video
+	var r0 *model.ViewStats
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*model.Video, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.ViewStats, error)); ok {
+		return rf(ctx, vid)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.Video); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.ViewStats); ok {
+		r0 = rf(ctx, vid)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Video)
+			r0 = ret.Get(0).(*model.ViewStats)
 		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, userID)
+		r1 = rf(ctx, vid)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -254,105 +1062,142 @@ func (_m *MockStore) GetAll(ctx context.Context, userID string) ([]*model.Video,
 	return r0, r1
 }
 
-// MockStore_GetAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAll'
-type MockStore_GetAll_Call struct {
+// MockStore_GetViewStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetViewStats'
+type MockStore_GetViewStats_Call struct {
 	*mock.Call
 }
 
-// GetAll is a helper method to define mock.On call
+// GetViewStats is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID string
-func (_e *MockStore_Expecter) GetAll(ctx interface{}, userID interface{}) *MockStore_GetAll_Call {
-	return &MockStore_GetAll_Call{Call: _e.mock.On("GetAll", ctx, userID)}
+//   - vid string
+func (_e *MockStore_Expecter) GetViewStats(ctx interface{}, vid interface{}) *MockStore_GetViewStats_Call {
+	return &MockStore_GetViewStats_Call{Call: _e.mock.On("GetViewStats", ctx, vid)}
 }
 
-func (_c *MockStore_GetAll_Call) Run(run func(ctx context.Context, userID string)) *MockStore_GetAll_Call {
+func (_c *MockStore_GetViewStats_Call) Run(run func(ctx context.Context, vid string)) *MockStore_GetViewStats_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetAll_Call) Return(_a0 []*model.Video, _a1 error) *MockStore_GetAll_Call {
+func (_c *MockStore_GetViewStats_Call) Return(_a0 *model.ViewStats, _a1 error) *MockStore_GetViewStats_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockStore_GetAll_Call) RunAndReturn(run func(context.Context, string) ([]*model.Video, error)) *MockStore_GetAll_Call {
+func (_c *MockStore_GetViewStats_Call) RunAndReturn(run func(context.Context, string) (*model.ViewStats, error)) *MockStore_GetViewStats_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetListByStatus provides a mock function with given fields: ctx, status
-func (_m *MockStore) GetListByStatus(ctx context.Context, status model.Status) ([]*model.Video, error) {
-	ret := _m.Called(ctx, status)
+// SetSourceExpiration provides a mock function with given fields: ctx, vid, at
+func (_m *MockStore) SetSourceExpiration(ctx context.Context, vid string, at time.Time) error {
+	ret := _m.Called(ctx, vid, at)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetListByStatus")
+		panic("no return value specified for SetSourceExpiration")
 	}
 
-	var r0 []*model.Video
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, model.Status) ([]*model.Video, error)); ok {
-		return rf(ctx, status)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, model.Status) []*model.Video); ok {
-		r0 = rf(ctx, status)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, vid, at)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Video)
-		}
+		r0 = ret.Error(0)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, model.Status) error); ok {
-		r1 = rf(ctx, status)
+	return r0
+}
+
+// MockStore_SetSourceExpiration_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetSourceExpiration'
+type MockStore_SetSourceExpiration_Call struct {
+	*mock.Call
+}
+
+// SetSourceExpiration is a helper method to define mock.On call
+//   - ctx context.Context
+//   - vid string
+//   - at time.Time
+func (_e *MockStore_Expecter) SetSourceExpiration(ctx interface{}, vid interface{}, at interface{}) *MockStore_SetSourceExpiration_Call {
+	return &MockStore_SetSourceExpiration_Call{Call: _e.mock.On("SetSourceExpiration", ctx, vid, at)}
+}
+
+func (_c *MockStore_SetSourceExpiration_Call) Run(run func(ctx context.Context, vid string, at time.Time)) *MockStore_SetSourceExpiration_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockStore_SetSourceExpiration_Call) Return(_a0 error) *MockStore_SetSourceExpiration_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_SetSourceExpiration_Call) RunAndReturn(run func(context.Context, string, time.Time) error) *MockStore_SetSourceExpiration_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartReprocessing provides a mock function with given fields: ctx, vid, location
+func (_m *MockStore) StartReprocessing(ctx context.Context, vid string, location string) error {
+	ret := _m.Called(ctx, vid, location)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartReprocessing")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, vid, location)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// MockStore_GetListByStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetListByStatus'
-type MockStore_GetListByStatus_Call struct {
+// MockStore_StartReprocessing_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartReprocessing'
+type MockStore_StartReprocessing_Call struct {
 	*mock.Call
 }
 
-// GetListByStatus is a helper method to define mock.On call
+// StartReprocessing is a helper method to define mock.On call
 //   - ctx context.Context
-//   - status model.Status
-func (_e *MockStore_Expecter) GetListByStatus(ctx interface{}, status interface{}) *MockStore_GetListByStatus_Call {
-	return &MockStore_GetListByStatus_Call{Call: _e.mock.On("GetListByStatus", ctx, status)}
+//   - vid string
+//   - location string
+func (_e *MockStore_Expecter) StartReprocessing(ctx interface{}, vid interface{}, location interface{}) *MockStore_StartReprocessing_Call {
+	return &MockStore_StartReprocessing_Call{Call: _e.mock.On("StartReprocessing", ctx, vid, location)}
 }
 
-func (_c *MockStore_GetListByStatus_Call) Run(run func(ctx context.Context, status model.Status)) *MockStore_GetListByStatus_Call {
+func (_c *MockStore_StartReprocessing_Call) Run(run func(ctx context.Context, vid string, location string)) *MockStore_StartReprocessing_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(model.Status))
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockStore_GetListByStatus_Call) Return(_a0 []*model.Video, _a1 error) *MockStore_GetListByStatus_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockStore_StartReprocessing_Call) Return(_a0 error) *MockStore_StartReprocessing_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockStore_GetListByStatus_Call) RunAndReturn(run func(context.Context, model.Status) ([]*model.Video, error)) *MockStore_GetListByStatus_Call {
+func (_c *MockStore_StartReprocessing_Call) RunAndReturn(run func(context.Context, string, string) error) *MockStore_StartReprocessing_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Update provides a mock function with given fields: ctx, vi
-func (_m *MockStore) Update(ctx context.Context, vi *model.Video) error {
-	ret := _m.Called(ctx, vi)
+// Update provides a mock function with given fields: ctx, vi, purgeAt
+func (_m *MockStore) Update(ctx context.Context, vi *model.Video, purgeAt time.Time) error {
+	ret := _m.Called(ctx, vi, purgeAt)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *model.Video) error); ok {
-		r0 = rf(ctx, vi)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Video, time.Time) error); ok {
+		r0 = rf(ctx, vi, purgeAt)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -368,13 +1213,14 @@ type MockStore_Update_Call struct {
 // Update is a helper method to define mock.On call
 //   - ctx context.Context
 //   - vi *model.Video
-func (_e *MockStore_Expecter) Update(ctx interface{}, vi interface{}) *MockStore_Update_Call {
-	return &MockStore_Update_Call{Call: _e.mock.On("Update", ctx, vi)}
+//   - purgeAt time.Time
+func (_e *MockStore_Expecter) Update(ctx interface{}, vi interface{}, purgeAt interface{}) *MockStore_Update_Call {
+	return &MockStore_Update_Call{Call: _e.mock.On("Update", ctx, vi, purgeAt)}
 }
 
-func (_c *MockStore_Update_Call) Run(run func(ctx context.Context, vi *model.Video)) *MockStore_Update_Call {
+func (_c *MockStore_Update_Call) Run(run func(ctx context.Context, vi *model.Video, purgeAt time.Time)) *MockStore_Update_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*model.Video))
+		run(args[0].(context.Context), args[1].(*model.Video), args[2].(time.Time))
 	})
 	return _c
 }
@@ -384,7 +1230,58 @@ func (_c *MockStore_Update_Call) Return(_a0 error) *MockStore_Update_Call {
 	return _c
 }
 
-func (_c *MockStore_Update_Call) RunAndReturn(run func(context.Context, *model.Video) error) *MockStore_Update_Call {
+func (_c *MockStore_Update_Call) RunAndReturn(run func(context.Context, *model.Video, time.Time) error) *MockStore_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateImport provides a mock function with given fields: ctx, upd, now, lease, backoff, maxBackoff
+func (_m *MockStore) UpdateImport(ctx context.Context, upd *model.ImportUpdate, now time.Time, lease time.Duration, backoff time.Duration, maxBackoff time.Duration) error {
+	ret := _m.Called(ctx, upd, now, lease, backoff, maxBackoff)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateImport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ImportUpdate, time.Time, time.Duration, time.Duration, time.Duration) error); ok {
+		r0 = rf(ctx, upd, now, lease, backoff, maxBackoff)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_UpdateImport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateImport'
+type MockStore_UpdateImport_Call struct {
+	*mock.Call
+}
+
+// UpdateImport is a helper method to define mock.On call
+//   - ctx context.Context
+//   - upd *model.ImportUpdate
+//   - now time.Time
+//   - lease time.Duration
+//   - backoff time.Duration
+//   - maxBackoff time.Duration
+func (_e *MockStore_Expecter) UpdateImport(ctx interface{}, upd interface{}, now interface{}, lease interface{}, backoff interface{}, maxBackoff interface{}) *MockStore_UpdateImport_Call {
+	return &MockStore_UpdateImport_Call{Call: _e.mock.On("UpdateImport", ctx, upd, now, lease, backoff, maxBackoff)}
+}
+
+func (_c *MockStore_UpdateImport_Call) Run(run func(ctx context.Context, upd *model.ImportUpdate, now time.Time, lease time.Duration, backoff time.Duration, maxBackoff time.Duration)) *MockStore_UpdateImport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.ImportUpdate), args[2].(time.Time), args[3].(time.Duration), args[4].(time.Duration), args[5].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockStore_UpdateImport_Call) Return(_a0 error) *MockStore_UpdateImport_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_UpdateImport_Call) RunAndReturn(run func(context.Context, *model.ImportUpdate, time.Time, time.Duration, time.Duration, time.Duration) error) *MockStore_UpdateImport_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -437,6 +1334,57 @@ func (_c *MockStore_UpdatePart_Call) RunAndReturn(run func(context.Context, stri
 	return _c
 }
 
+// UpdatePurge provides a mock function with given fields: ctx, upd, now, lease, backoff, maxBackoff
+func (_m *MockStore) UpdatePurge(ctx context.Context, upd *model.PurgeUpdate, now time.Time, lease time.Duration, backoff time.Duration, maxBackoff time.Duration) error {
+	ret := _m.Called(ctx, upd, now, lease, backoff, maxBackoff)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePurge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.PurgeUpdate, time.Time, time.Duration, time.Duration, time.Duration) error); ok {
+		r0 = rf(ctx, upd, now, lease, backoff, maxBackoff)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_UpdatePurge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePurge'
+type MockStore_UpdatePurge_Call struct {
+	*mock.Call
+}
+
+// UpdatePurge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - upd *model.PurgeUpdate
+//   - now time.Time
+//   - lease time.Duration
+//   - backoff time.Duration
+//   - maxBackoff time.Duration
+func (_e *MockStore_Expecter) UpdatePurge(ctx interface{}, upd interface{}, now interface{}, lease interface{}, backoff interface{}, maxBackoff interface{}) *MockStore_UpdatePurge_Call {
+	return &MockStore_UpdatePurge_Call{Call: _e.mock.On("UpdatePurge", ctx, upd, now, lease, backoff, maxBackoff)}
+}
+
+func (_c *MockStore_UpdatePurge_Call) Run(run func(ctx context.Context, upd *model.PurgeUpdate, now time.Time, lease time.Duration, backoff time.Duration, maxBackoff time.Duration)) *MockStore_UpdatePurge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.PurgeUpdate), args[2].(time.Time), args[3].(time.Duration), args[4].(time.Duration), args[5].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockStore_UpdatePurge_Call) Return(_a0 error) *MockStore_UpdatePurge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_UpdatePurge_Call) RunAndReturn(run func(context.Context, *model.PurgeUpdate, time.Time, time.Duration, time.Duration, time.Duration) error) *MockStore_UpdatePurge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateStatus provides a mock function with given fields: ctx, vi
 func (_m *MockStore) UpdateStatus(ctx context.Context, vi *model.Video) error {
 	ret := _m.Called(ctx, vi)
@@ -484,6 +1432,53 @@ func (_c *MockStore_UpdateStatus_Call) RunAndReturn(run func(context.Context, *m
 	return _c
 }
 
+// UpdateViewStats provides a mock function with given fields: ctx, upd
+func (_m *MockStore) UpdateViewStats(ctx context.Context, upd *model.ViewStatsUpdate) error {
+	ret := _m.Called(ctx, upd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateViewStats")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ViewStatsUpdate) error); ok {
+		r0 = rf(ctx, upd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStore_UpdateViewStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateViewStats'
+type MockStore_UpdateViewStats_Call struct {
+	*mock.Call
+}
+
+// UpdateViewStats is a helper method to define mock.On call
+//   - ctx context.Context
+//   - upd *model.ViewStatsUpdate
+func (_e *MockStore_Expecter) UpdateViewStats(ctx interface{}, upd interface{}) *MockStore_UpdateViewStats_Call {
+	return &MockStore_UpdateViewStats_Call{Call: _e.mock.On("UpdateViewStats", ctx, upd)}
+}
+
+func (_c *MockStore_UpdateViewStats_Call) Run(run func(ctx context.Context, upd *model.ViewStatsUpdate)) *MockStore_UpdateViewStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.ViewStatsUpdate))
+	})
+	return _c
+}
+
+func (_c *MockStore_UpdateViewStats_Call) Return(_a0 error) *MockStore_UpdateViewStats_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStore_UpdateViewStats_Call) RunAndReturn(run func(context.Context, *model.ViewStatsUpdate) error) *MockStore_UpdateViewStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Usage provides a mock function with given fields: ctx, userID
 func (_m *MockStore) Usage(ctx context.Context, userID string) (*model.UserUsage, error) {
 	ret := _m.Called(ctx, userID)
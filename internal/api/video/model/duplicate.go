@@ -0,0 +1,45 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+)
+
+// DuplicateError is returned when uploaded video is identical to existing one.
+type DuplicateError struct {
+	// VideoID is an id of existing video.
+	VideoID string
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrDuplicate.Error(), e.VideoID)
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return ErrDuplicate
+}
+
+// Fingerprint derives content fingerprint of file from checksums of its parts.
+// Parts are hashed in order along with file and part sizes, so the same file
+// split into parts of different size has different fingerprint.
+// Empty fingerprint is returned if any part has no checksum.
+func Fingerprint(size, partSize uint64, parts []*Part) string {
+	if len(parts) == 0 {
+		return ""
+	}
+	h := sha256.New()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], size)
+	h.Write(buf[:])
+	binary.BigEndian.PutUint64(buf[:], partSize)
+	h.Write(buf[:])
+	for _, p := range parts {
+		if p.Checksum == "" {
+			return ""
+		}
+		h.Write([]byte(p.Checksum))
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
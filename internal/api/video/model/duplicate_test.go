@@ -0,0 +1,26 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	parts := []*Part{{Num: 0, Checksum: "aaa"}, {Num: 1, Checksum: "bbb"}}
+	fp := Fingerprint(15, 10, parts)
+	assert.NotEmpty(t, fp)
+	assert.LessOrEqual(t, len(fp), 64)
+	assert.Equal(t, fp, Fingerprint(15, 10, []*Part{{Num: 0, Checksum: "aaa"}, {Num: 1, Checksum: "bbb"}}))
+	assert.NotEqual(t, fp, Fingerprint(15, 8, parts))
+	assert.NotEqual(t, fp, Fingerprint(15, 10, []*Part{{Num: 0, Checksum: "bbb"}, {Num: 1, Checksum: "aaa"}}))
+	assert.Empty(t, Fingerprint(15, 10, []*Part{{Num: 0, Checksum: "aaa"}, {Num: 1}}))
+	assert.Empty(t, Fingerprint(15, 10, nil))
+}
+
+func TestDuplicateError(t *testing.T) {
+	var err error = &DuplicateError{VideoID: "vid"}
+	assert.True(t, errors.Is(err, ErrDuplicate))
+	assert.Equal(t, ErrDuplicate.Error()+": vid", err.Error())
+}
@@ -0,0 +1,61 @@
+package model
+
+import "strings"
+
+// ImportRequest is a request to import video from remote url.
+type ImportRequest struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// PartSize is a size of parts in which imported file is stored.
+	// Server default is used if not set.
+	PartSize uint64 `json:"part_size,omitempty"`
+}
+
+// Import is a download of remote file into upload parts of video.
+// Parts are downloaded in order, so Downloaded is always an offset of the next part.
+type Import struct {
+	VideoID    string
+	Location   string
+	URL        string
+	LastError  string
+	ID         int64
+	Size       uint64
+	PartSize   uint64
+	Downloaded uint64
+	Attempts   int
+	Done       bool
+}
+
+// ImportUpdate is an import progress reported by importer.
+// Failed import is retried later unless failure is fatal, in that case
+// video is moved to error state. Finished import is never returned to importer again.
+type ImportUpdate struct {
+	Error      string
+	ID         int64
+	Downloaded uint64
+	Done       bool
+	Fatal      bool
+}
+
+// ImportStatus is an import progress visible to user.
+type ImportStatus struct {
+	URL        string `json:"url"`
+	LastError  string `json:"last_error,omitempty"`
+	Size       uint64 `json:"size"`
+	Downloaded uint64 `json:"downloaded"`
+	Attempts   int    `json:"attempts"`
+	Done       bool   `json:"done"`
+}
+
+// ImportHostAllowed checks host against allowlist. Allowlist entry matches
+// host exactly, entry with leading dot matches any subdomain.
+func ImportHostAllowed(allowed []string, host string) bool {
+	host = strings.ToLower(host)
+	for _, entry := range allowed {
+		entry = strings.ToLower(entry)
+		if host == entry || (strings.HasPrefix(entry, ".") && strings.HasSuffix(host, entry)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportHostAllowed(t *testing.T) {
+	allowed := []string{"media.example.com", ".cdn.example.com"}
+	assert.True(t, ImportHostAllowed(allowed, "media.example.com"))
+	assert.True(t, ImportHostAllowed(allowed, "Media.Example.com"))
+	assert.True(t, ImportHostAllowed(allowed, "eu.cdn.example.com"))
+	assert.False(t, ImportHostAllowed(allowed, "cdn.example.com"))
+	assert.False(t, ImportHostAllowed(allowed, "example.com"))
+	assert.False(t, ImportHostAllowed(allowed, "evilmedia.example.com"))
+	assert.False(t, ImportHostAllowed(nil, "media.example.com"))
+}
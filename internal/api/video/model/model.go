@@ -24,6 +24,33 @@ var (
 	ErrZeroSize = errors.New("video size cannot be zero")
 	ErrNoName   = errors.New("video name cannot be empty")
 
+	ErrInvalidPartSize = errors.New("part size is out of allowed bounds")
+	ErrPartsMismatch   = errors.New("parts amount does not match video size")
+
+	ErrDirectUploadDisabled = errors.New("direct upload is not enabled")
+	ErrNotDirectUpload      = errors.New("video is not uploaded directly")
+	ErrNoChecksums          = errors.New("direct upload requires checksums of all parts")
+
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	ErrDuplicate     = errors.New("video is a duplicate of existing video")
+
+	ErrImportDisabled       = errors.New("import is not enabled")
+	ErrImportHostNotAllowed = errors.New("import from this host is not allowed")
+	ErrImportURL            = errors.New("invalid import url")
+	ErrImportSource         = errors.New("import source is not available")
+	ErrImportTooLarge       = errors.New("import source is too large")
+
+	ErrInvalidClip = errors.New("invalid clip")
+
+	ErrTooManyStreams   = errors.New("too many active streams")
+	ErrStreamsUntracked = errors.New("active streams are not tracked")
+
+	ErrSessionsNotIndexed = errors.New("sessions are not indexed")
+	ErrSessionKind        = errors.New("invalid session kind")
+	ErrSessionNotFound    = errors.New("session not found")
+
+	ErrNoReprocessCriteria = errors.New("no videos or version specified for reprocessing")
+
 	ErrInvalidPlaybackMeta = errors.New("invalid playback meta")
 	ErrEmptyPlaybackMeta   = errors.New("empty playback meta")
 )
@@ -39,8 +66,26 @@ type Video struct {
 	Name     string `json:"name"`
 	Location string `json:"location,omitempty"`
 
-	Status Status `json:"status,omitempty"`
-	Size   uint64 `json:"size,omitempty"`
+	// OutputLocation is a location of processed media. It differs from Location
+	// after video is reprocessed. ReprocessLocation is set while video is being reprocessed.
+	OutputLocation    string `json:"-"`
+	ReprocessLocation string `json:"-"`
+
+	// StatusReason explains current status, i.e. why upload has expired.
+	StatusReason string `json:"status_reason,omitempty"`
+
+	Status   Status `json:"status,omitempty"`
+	Size     uint64 `json:"size,omitempty"`
+	PartSize uint64 `json:"part_size,omitempty"`
+
+	// Fingerprint identifies content of uploaded file, see Fingerprint.
+	Fingerprint string `json:"-"`
+
+	// ImportURL is set if video is created to be imported from remote url.
+	ImportURL string `json:"-"`
+
+	// DirectUpload is set if parts are uploaded directly to media store.
+	DirectUpload bool `json:"direct_upload,omitempty"`
 }
 
 type UploadInfo struct {
@@ -64,6 +109,24 @@ type CreateRequest struct {
 	Name  string  `json:"name"`
 	Parts []*Part `json:"parts"`
 	Size  uint64  `json:"size_total"`
+	// PartSize is a size of every part except the last one.
+	// Server default is used if not set.
+	PartSize uint64 `json:"part_size,omitempty"`
+	// DirectUpload requests presigned urls to upload parts directly to media store.
+	DirectUpload bool `json:"direct_upload,omitempty"`
+}
+
+// CreateClipRequest is a request to create clip of existing video.
+// Start and end are clip boundaries in milliseconds relative to source video.
+type CreateClipRequest struct {
+	SourceID string `json:"source_id"`
+	Name     string `json:"name"`
+	StartMs  uint64 `json:"start_ms"`
+	EndMs    uint64 `json:"end_ms"`
+}
+
+type CreateLiveRequest struct {
+	Name string `json:"name"`
 }
 
 func NewVideoNoID(userID, name string, size uint64) *Video {
@@ -76,8 +139,15 @@ func NewVideoNoID(userID, name string, size uint64) *Video {
 	}
 }
 
+// IsReady returns true if video can be played.
+// Video that is being reprocessed is played using previous output.
 func (v *Video) IsReady() bool {
-	return v.Status == StatusReady
+	return v.Status == StatusReady || v.Status == StatusReprocessing
+}
+
+// IsLive returns true if video is an ongoing live stream.
+func (v *Video) IsLive() bool {
+	return v.Status == StatusLive
 }
 
 func (v *Video) IsErrored() bool {
@@ -6,9 +6,37 @@ const (
 	PartStatusOK
 )
 
+// DefaultPartSize is a part size that is used if it is not specified by client.
+// It is also a part size of videos created before part size became configurable.
+const DefaultPartSize = 10 * 1024 * 1024
+
 type Part struct {
+	// URL is a presigned url of part for direct upload.
+	URL      string `json:"url,omitempty"`
 	Checksum string `json:"checksum"`
 	Num      uint   `json:"num"`
 	Size     uint64 `json:"size"`
 	Status   int    `json:"status"`
 }
+
+// PartsMatchSize checks that file of specified size is split
+// into specified amount of parts of specified size.
+func PartsMatchSize(parts int, partSize, size uint64) bool {
+	if parts == 0 || partSize == 0 {
+		return false
+	}
+	return partSize*uint64(parts-1) < size && size <= partSize*uint64(parts)
+}
+
+// NewParts splits file of specified size into parts of specified size.
+// Parts have no checksums, so they take checksum of uploaded part.
+func NewParts(size, partSize uint64) []*Part {
+	var parts []*Part
+	for offset := uint64(0); offset < size; offset += partSize {
+		parts = append(parts, &Part{
+			Num:  uint(len(parts)),
+			Size: min(partSize, size-offset),
+		})
+	}
+	return parts
+}
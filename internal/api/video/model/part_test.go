@@ -0,0 +1,26 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartsMatchSize(t *testing.T) {
+	assert.True(t, PartsMatchSize(1, 10, 1))
+	assert.True(t, PartsMatchSize(1, 10, 10))
+	assert.True(t, PartsMatchSize(2, 10, 11))
+	assert.False(t, PartsMatchSize(2, 10, 10), "last part is empty")
+	assert.False(t, PartsMatchSize(2, 10, 21), "not enough parts")
+	assert.False(t, PartsMatchSize(0, 10, 1))
+	assert.False(t, PartsMatchSize(1, 0, 1))
+}
+
+func TestNewParts(t *testing.T) {
+	parts := NewParts(25, 10)
+	assert.Len(t, parts, 3)
+	assert.Equal(t, uint(2), parts[2].Num)
+	assert.Equal(t, uint64(5), parts[2].Size)
+	assert.True(t, PartsMatchSize(len(parts), 10, 25))
+	assert.Len(t, NewParts(20, 10), 2)
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNoPlaylistName   = errors.New("playlist name cannot be empty")
+	ErrEmptyPlaylist    = errors.New("playlist has no videos")
+	ErrPlaylistNotFound = errors.New("playlist not found")
+)
+
+// Playlist is an ordered list of user's videos that are played back to back.
+// Videos are filled only when playlist is retrieved for playback.
+type Playlist struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+
+	VideoIDs []string `json:"videos"`
+	Videos   []*Video `json:"-"`
+}
+
+type CreatePlaylistRequest struct {
+	Name   string   `json:"name"`
+	Videos []string `json:"videos"`
+}
+
+func NewPlaylistNoID(userID, name string, videoIDs []string) *Playlist {
+	return &Playlist{
+		CreatedAt: time.Now().In(time.UTC),
+		UserID:    userID,
+		Name:      name,
+		VideoIDs:  videoIDs,
+	}
+}
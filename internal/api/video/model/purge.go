@@ -0,0 +1,37 @@
+package model
+
+// Purge kinds.
+const (
+	PurgeKindUpload PurgeKind = iota
+	PurgeKindOutput
+)
+
+// PurgeKind tells which media storage prefix purge location belongs to.
+type PurgeKind int
+
+// Purge is a media location of deleted video which should be removed from media storage.
+type Purge struct {
+	VideoID  string
+	Location string
+	ID       int64
+	Deleted  uint64
+	Kind     PurgeKind
+	Attempts int
+}
+
+// PurgeUpdate is a purge progress reported by purger.
+// Failed purge is retried later, finished purge is never returned to purger again.
+type PurgeUpdate struct {
+	Error   string
+	ID      int64
+	Deleted uint64
+	Done    bool
+}
+
+// PurgeStats is a summary of media purge progress.
+type PurgeStats struct {
+	Pending  uint64
+	Retrying uint64
+	Done     uint64
+	Deleted  uint64
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// UserSession is an active upload or watch session of user.
+// Kind is either "upload" or "watch". Playlist watch sessions have PlaylistID instead of VideoID.
+type UserSession struct {
+	ActiveAt   time.Time `json:"active_at"`
+	Kind       string    `json:"kind"`
+	ID         string    `json:"id"`
+	VideoID    string    `json:"video_id,omitempty"`
+	PlaylistID string    `json:"playlist_id,omitempty"`
+}
@@ -0,0 +1,46 @@
+package model
+
+// ViewReport is a playback activity of watch session aggregated by streamer since previous report.
+// Segments are numbers of media segments which were served to session for the first time.
+// NewView is set in the first report of session.
+type ViewReport struct {
+	SessionID string
+	VideoID   string
+	UserID    string
+	Segments  []uint
+	Requests  uint64
+	Bytes     uint64
+	NewView   bool
+}
+
+// ViewReportKey identifies report of watch session about one of session videos,
+// since playlist session is reported separately for every video.
+type ViewReportKey struct {
+	SessionID string
+	VideoID   string
+}
+
+// ViewStatsUpdate is an increment of video view statistics.
+// Retention maps segment index (starting from 0) to amount of views of that segment.
+type ViewStatsUpdate struct {
+	Retention   map[uint]uint64
+	VideoID     string
+	Views       uint64
+	WatchTimeMS uint64
+	Requests    uint64
+	Bytes       uint64
+}
+
+// ViewStats are view statistics of video.
+//
+// Retention is an audience retention histogram: amount of views of every segment of video.
+// Segments are SegmentDurationMS long, so retention value of index i corresponds
+// to views of i*SegmentDurationMS playback position.
+type ViewStats struct {
+	Retention         []uint64 `json:"retention"`
+	Views             uint64   `json:"views"`
+	WatchTimeMS       uint64   `json:"watch_time_ms"`
+	Requests          uint64   `json:"requests"`
+	Bytes             uint64   `json:"bytes"`
+	SegmentDurationMS uint64   `json:"segment_duration_ms"`
+}
@@ -15,6 +15,9 @@ const (
 	StatusUploaded
 	StatusProcessing
 	StatusReady
+	StatusLive
+	StatusReprocessing
+	StatusExpired
 )
 
 type Status int
@@ -24,12 +27,15 @@ var (
 	ErrIncorrectStatusNum  = errors.New("incorrect status number")
 
 	statusNames = map[Status]string{
-		StatusError:      "error",
-		StatusCreated:    "created",
-		StatusUploading:  "uploading",
-		StatusUploaded:   "uploaded",
-		StatusProcessing: "processing",
-		StatusReady:      "ready",
+		StatusError:        "error",
+		StatusCreated:      "created",
+		StatusUploading:    "uploading",
+		StatusUploaded:     "uploaded",
+		StatusProcessing:   "processing",
+		StatusReady:        "ready",
+		StatusLive:         "live",
+		StatusReprocessing: "reprocessing",
+		StatusExpired:      "expired",
 	}
 
 	statusFromName = make(map[string]Status)
@@ -19,6 +19,21 @@ func TestStatus_UnmarshalJSON(t *testing.T) {
 			arg:    []byte(`"ready"`),
 			status: StatusReady,
 		},
+		{
+			name:   "unmarshall live",
+			arg:    []byte(`"live"`),
+			status: StatusLive,
+		},
+		{
+			name:   "unmarshall reprocessing",
+			arg:    []byte(`"reprocessing"`),
+			status: StatusReprocessing,
+		},
+		{
+			name:   "unmarshall expired",
+			arg:    []byte(`"expired"`),
+			status: StatusExpired,
+		},
 		{
 			name:   "unmarshall num",
 			arg:    []byte(`0`),
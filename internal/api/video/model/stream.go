@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Stream is an active watch session of user.
+// Playlist streams have PlaylistID instead of VideoID.
+type Stream struct {
+	ActiveAt   time.Time `json:"active_at"`
+	SessionID  string    `json:"session_id"`
+	VideoID    string    `json:"video_id,omitempty"`
+	PlaylistID string    `json:"playlist_id,omitempty"`
+}
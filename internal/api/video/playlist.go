@@ -0,0 +1,124 @@
+package video
+
+import (
+	"context"
+	"errors"
+
+	user "github.com/adwski/vidi/internal/api/user/model"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/mp4/meta"
+	"github.com/adwski/vidi/internal/session"
+	"go.uber.org/zap"
+)
+
+// CreatePlaylist creates playlist from user's ready videos.
+// Videos are played in the same order as they're listed in request.
+func (svc *Service) CreatePlaylist(ctx context.Context, usr *user.User, req *model.CreatePlaylistRequest) (*model.Playlist, error) {
+	if len(req.Name) == 0 {
+		return nil, model.ErrNoPlaylistName
+	}
+	if len(req.Videos) == 0 {
+		return nil, model.ErrEmptyPlaylist
+	}
+	for _, vid := range req.Videos {
+		video, err := svc.s.Get(ctx, vid, usr.ID)
+		if err != nil {
+			return nil, errors.Join(model.ErrStorage, err)
+		}
+		if !video.IsReady() {
+			return nil, model.ErrNotReady
+		}
+	}
+	pl := model.NewPlaylistNoID(usr.ID, req.Name, req.Videos)
+	var err error
+	for i := 1; ; i++ {
+		pl.ID, err = svc.idGen.Get()
+		if err != nil {
+			return nil, errors.Join(errors.New("cannot generate playlist id"), err)
+		}
+		if err = svc.s.CreatePlaylist(ctx, pl); err == nil || !errors.Is(err, model.ErrAlreadyExists) {
+			break
+		}
+		if i >= videoCreateRetries {
+			err = errors.Join(model.ErrGivenUp, err)
+			break
+		}
+	}
+	if err != nil {
+		return nil, errors.Join(model.ErrStorage, err)
+	}
+	return pl, nil
+}
+
+func (svc *Service) GetPlaylist(ctx context.Context, usr *user.User, id string) (*model.Playlist, error) {
+	pl, err := svc.s.GetPlaylist(ctx, id, usr.ID)
+	if err != nil {
+		return nil, errors.Join(model.ErrStorage, err)
+	}
+	return pl, nil
+}
+
+func (svc *Service) GetPlaylists(ctx context.Context, usr *user.User) ([]*model.Playlist, error) {
+	playlists, err := svc.s.GetPlaylists(ctx, usr.ID)
+	if err != nil {
+		return nil, errors.Join(model.ErrStorage, err)
+	}
+	return playlists, nil
+}
+
+func (svc *Service) DeletePlaylist(ctx context.Context, usr *user.User, id string) error {
+	if err := svc.s.DeletePlaylist(ctx, id, usr.ID); err != nil {
+		return errors.Join(model.ErrStorage, err)
+	}
+	return nil
+}
+
+// WatchPlaylist creates watch session for playlist and returns multi-period MPD
+// with one period per video. Session is allowed to read locations of all videos,
+// streamer routes segment requests using period index.
+func (svc *Service) WatchPlaylist(ctx context.Context, usr *user.User, id string) ([]byte, error) {
+	pl, err := svc.s.GetPlaylist(ctx, id, usr.ID)
+	if err != nil {
+		return nil, errors.Join(model.ErrStorage, err)
+	}
+	if len(pl.Videos) == 0 {
+		return nil, model.ErrEmptyPlaylist
+	}
+	var (
+		videoIDs  = make([]string, 0, len(pl.Videos))
+		locations = make([]string, 0, len(pl.Videos))
+		periods   = make([]*meta.Meta, 0, len(pl.Videos))
+	)
+	for _, video := range pl.Videos {
+		if err = checkWatchable(video, false); err != nil {
+			return nil, err
+		}
+		videoIDs = append(videoIDs, video.ID)
+		locations = append(locations, playbackLocation(video))
+		periods = append(periods, video.PlaybackMeta)
+	}
+	sessID, err := svc.idGen.Get()
+	if err != nil {
+		return nil, errors.Join(errors.New("cannot generate watch session id"), err)
+	}
+	sess := &session.Session{
+		ID:         sessID,
+		PlaylistID: pl.ID,
+		VideoIDs:   videoIDs,
+		UserID:     usr.ID,
+		Locations:  locations,
+	}
+	watchID, err := svc.storeWatchSession(ctx, sess)
+	if err != nil {
+		return nil, err
+	}
+	bMPD, err := meta.MultiPeriodMPD(svc.getWatchBaseURL(watchID), periods)
+	if err != nil {
+		return nil, errors.Join(model.ErrInternal, err)
+	}
+	svc.logger.Debug("playlist watch session created",
+		zap.String("playlist", pl.ID),
+		zap.String("session", sess.ID),
+		zap.Int("periods", len(periods)))
+	return bMPD, nil
+}
@@ -0,0 +1,156 @@
+package video
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	usermodel "github.com/adwski/vidi/internal/api/user/model"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestService_CreatePlaylist(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  s,
+	})
+
+	u := &usermodel.User{ID: "test"}
+	v1, v2 := testSourceVideo(), testSourceVideo()
+	v2.ID = "srcvid2"
+	s.EXPECT().Get(ctx, v1.ID, u.ID).Return(v1, nil)
+	s.EXPECT().Get(ctx, v2.ID, u.ID).Return(v2, nil)
+	s.EXPECT().CreatePlaylist(ctx, mock.Anything).Run(func(_ context.Context, pl *model.Playlist) {
+		assert.NotEmpty(t, pl.ID)
+		assert.Equal(t, u.ID, pl.UserID)
+		assert.Equal(t, "course", pl.Name)
+		assert.Equal(t, []string{v2.ID, v1.ID}, pl.VideoIDs)
+	}).Return(nil)
+
+	pl, err := svc.CreatePlaylist(ctx, u, &model.CreatePlaylistRequest{
+		Name:   "course",
+		Videos: []string{v2.ID, v1.ID},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, pl.ID)
+}
+
+func TestService_CreatePlaylistErrors(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  s,
+	})
+	u := &usermodel.User{ID: "test"}
+
+	_, err = svc.CreatePlaylist(ctx, u, &model.CreatePlaylistRequest{Videos: []string{"srcvid"}})
+	require.ErrorIs(t, err, model.ErrNoPlaylistName)
+
+	_, err = svc.CreatePlaylist(ctx, u, &model.CreatePlaylistRequest{Name: "course"})
+	require.ErrorIs(t, err, model.ErrEmptyPlaylist)
+
+	notReady := testSourceVideo()
+	notReady.Status = model.StatusProcessing
+	s.EXPECT().Get(ctx, notReady.ID, u.ID).Return(notReady, nil).Once()
+	_, err = svc.CreatePlaylist(ctx, u, &model.CreatePlaylistRequest{
+		Name:   "course",
+		Videos: []string{notReady.ID},
+	})
+	require.ErrorIs(t, err, model.ErrNotReady)
+
+	s.EXPECT().Get(ctx, "notexist", u.ID).Return(nil, model.ErrNotFound).Once()
+	_, err = svc.CreatePlaylist(ctx, u, &model.CreatePlaylistRequest{
+		Name:   "course",
+		Videos: []string{"notexist"},
+	})
+	require.ErrorIs(t, err, model.ErrNotFound)
+}
+
+func TestService_WatchPlaylist(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	ss := NewMockSessionStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger:            logger,
+		Store:             s,
+		WatchSessionStore: ss,
+		WatchURLPrefix:    "http://test",
+	})
+
+	src := testSourceVideo()
+	clipMeta, err := src.PlaybackMeta.MakeClip(10*time.Second, 20*time.Second)
+	require.NoError(t, err)
+	clipMeta.Clip.SourceLocation = src.Location
+	clip := &model.Video{
+		ID:           "clipvid",
+		Location:     "cliploc",
+		Status:       model.StatusReady,
+		PlaybackMeta: clipMeta,
+	}
+	u := &usermodel.User{ID: "test"}
+	pl := &model.Playlist{
+		ID:     "testpl",
+		UserID: u.ID,
+		Videos: []*model.Video{src, clip},
+	}
+	s.EXPECT().GetPlaylist(ctx, pl.ID, u.ID).Return(pl, nil)
+	ss.EXPECT().Set(ctx, mock.Anything).Run(func(_ context.Context, sess *session.Session) {
+		assert.Equal(t, pl.ID, sess.PlaylistID)
+		assert.Empty(t, sess.VideoID)
+		assert.Equal(t, []string{src.ID, clip.ID}, sess.VideoIDs)
+		assert.Empty(t, sess.Location)
+		assert.Equal(t, []string{"srcloc", "srcloc"}, sess.Locations)
+	}).Return(nil)
+
+	b, err := svc.WatchPlaylist(ctx, u, pl.ID)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `mediaPresentationDuration="PT1M11S"`)
+	assert.Contains(t, string(b), `<Period id="p1" start="PT1M" duration="PT11S">`)
+}
+
+func TestService_WatchPlaylistErrors(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  s,
+	})
+	u := &usermodel.User{ID: "test"}
+
+	s.EXPECT().GetPlaylist(ctx, "notexist", u.ID).Return(nil, model.ErrPlaylistNotFound).Once()
+	_, err = svc.WatchPlaylist(ctx, u, "notexist")
+	require.ErrorIs(t, err, model.ErrPlaylistNotFound)
+
+	s.EXPECT().GetPlaylist(ctx, "empty", u.ID).Return(&model.Playlist{ID: "empty"}, nil).Once()
+	_, err = svc.WatchPlaylist(ctx, u, "empty")
+	require.ErrorIs(t, err, model.ErrEmptyPlaylist)
+
+	errored := testSourceVideo()
+	errored.Status = model.StatusError
+	s.EXPECT().GetPlaylist(ctx, "errored", u.ID).Return(&model.Playlist{
+		ID:     "errored",
+		Videos: []*model.Video{testSourceVideo(), errored},
+	}, nil).Once()
+	_, err = svc.WatchPlaylist(ctx, u, "errored")
+	require.ErrorIs(t, err, model.ErrState)
+}
@@ -0,0 +1,44 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/model"
+)
+
+const (
+	// purgeLease is a time during which claimed purge is not given to other purgers.
+	purgeLease = 5 * time.Minute
+
+	purgeBackoff    = 10 * time.Second
+	purgeMaxBackoff = 6 * time.Hour
+)
+
+// GetPurgeJobs returns media purges that should be performed by purger.
+func (svc *Service) GetPurgeJobs(ctx context.Context, limit uint) ([]*model.Purge, error) {
+	purges, err := svc.s.ClaimPurges(ctx, time.Now(), purgeLease, limit)
+	if err != nil {
+		return nil, errors.Join(model.ErrStorage, err)
+	}
+	return purges, nil
+}
+
+// UpdatePurge records media purge progress reported by purger.
+// Failed purges are retried with exponential backoff.
+func (svc *Service) UpdatePurge(ctx context.Context, upd *model.PurgeUpdate) error {
+	if err := svc.s.UpdatePurge(ctx, upd, time.Now(), purgeLease, purgeBackoff, purgeMaxBackoff); err != nil {
+		return errors.Join(model.ErrStorage, err)
+	}
+	return nil
+}
+
+// GetPurgeStats returns summary of media purge progress.
+func (svc *Service) GetPurgeStats(ctx context.Context) (*model.PurgeStats, error) {
+	stats, err := svc.s.GetPurgeStats(ctx)
+	if err != nil {
+		return nil, errors.Join(model.ErrStorage, err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,70 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestService_GetPurgeJobs(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  s,
+	})
+	purges := []*model.Purge{{ID: 1, VideoID: "test", Location: "loc", Kind: model.PurgeKindUpload}}
+	s.EXPECT().ClaimPurges(ctx, mock.Anything, purgeLease, uint(10)).Return(purges, nil)
+
+	got, err := svc.GetPurgeJobs(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, purges, got)
+}
+
+func TestService_UpdatePurge(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  s,
+	})
+	upd := &model.PurgeUpdate{ID: 1, Deleted: 3, Error: "test"}
+	s.EXPECT().UpdatePurge(ctx, upd, mock.Anything, purgeLease, purgeBackoff, purgeMaxBackoff).
+		Run(func(_ context.Context, _ *model.PurgeUpdate, now time.Time, _, _, _ time.Duration) {
+			assert.WithinDuration(t, time.Now(), now, time.Minute)
+		}).Return(errors.New("test"))
+
+	err = svc.UpdatePurge(ctx, upd)
+	require.ErrorIs(t, err, model.ErrStorage)
+}
+
+func TestService_GetPurgeStats(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  s,
+	})
+	stats := &model.PurgeStats{Pending: 2, Retrying: 1, Done: 5, Deleted: 100}
+	s.EXPECT().GetPurgeStats(ctx).Return(stats, nil)
+
+	got, err := svc.GetPurgeStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, stats, got)
+}
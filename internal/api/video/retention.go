@@ -0,0 +1,141 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/model"
+	"go.uber.org/zap"
+)
+
+const (
+	// RetainForever is a source retention policy that keeps upload parts forever.
+	RetainForever time.Duration = -1
+	// RetainNever is a source retention policy that deletes upload parts as soon as video is ready.
+	RetainNever time.Duration = 0
+
+	hoursInDay = 24
+)
+
+// ParseSourceRetention parses source retention policy.
+// Policy could be "forever", "never" or retention period.
+// Period is specified either as Go duration or in days, i.e. "30d".
+func ParseSourceRetention(policy string) (time.Duration, error) {
+	switch policy {
+	case "forever":
+		return RetainForever, nil
+	case "never", "":
+		return RetainNever, nil
+	}
+	if days, ok := strings.CutSuffix(policy, "d"); ok {
+		n, err := strconv.ParseUint(days, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid retention days: %w", err)
+		}
+		return time.Duration(n) * hoursInDay * time.Hour, nil
+	}
+	d, err := time.ParseDuration(policy)
+	if err != nil {
+		return 0, fmt.Errorf("invalid retention period: %w", err)
+	}
+	if d <= 0 {
+		return 0, errors.New("retention period must be positive")
+	}
+	return d, nil
+}
+
+// retainSource applies source retention policy to video that became ready.
+// Upload parts are required for reprocessing, so they are either deleted right away,
+// kept forever or kept until expiration time.
+func (svc *Service) retainSource(ctx context.Context, vid string) error {
+	var err error
+	switch {
+	case svc.sourceRetention == RetainNever:
+		err = svc.s.DeleteUploadedParts(ctx, vid)
+	case svc.sourceRetention > 0:
+		err = svc.s.SetSourceExpiration(ctx, vid, time.Now().Add(svc.sourceRetention))
+	}
+	if err != nil {
+		return errors.Join(model.ErrStorage, err)
+	}
+	return nil
+}
+
+// DeleteExpiredSources deletes upload parts of videos which retention period has ended
+// and queues purge of their sources. It returns number of videos which sources were deleted.
+func (svc *Service) DeleteExpiredSources(ctx context.Context) (int64, error) {
+	n, err := svc.s.DeleteExpiredSources(ctx, time.Now())
+	if err != nil {
+		return 0, errors.Join(model.ErrStorage, err)
+	}
+	return n, nil
+}
+
+// ExpireAbandonedUploads expires videos which upload had no activity during upload TTL.
+// Expired videos are not counted toward user quota and their upload parts are purged.
+func (svc *Service) ExpireAbandonedUploads(ctx context.Context) (int64, error) {
+	if svc.uploadTTL <= 0 {
+		return 0, nil
+	}
+	n, err := svc.s.ExpireUploads(ctx, time.Now().Add(-svc.uploadTTL),
+		fmt.Sprintf("upload was not completed within %s", svc.uploadTTL))
+	if err != nil {
+		return 0, errors.Join(model.ErrStorage, err)
+	}
+	return n, nil
+}
+
+// Janitor is videoapi background worker that periodically cleans up expired data.
+type Janitor struct {
+	logger *zap.Logger
+	svc    *Service
+	period time.Duration
+}
+
+func NewJanitor(logger *zap.Logger, svc *Service, period time.Duration) *Janitor {
+	return &Janitor{
+		logger: logger.With(zap.String("component", "janitor")),
+		svc:    svc,
+		period: period,
+	}
+}
+
+func (j *Janitor) Run(ctx context.Context, wg *sync.WaitGroup, _ chan<- error) {
+	defer wg.Done()
+	j.logger.Info("started")
+	ticker := time.NewTicker(j.period)
+	defer ticker.Stop()
+Loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break Loop
+		case <-ticker.C:
+			j.cleanup(ctx)
+		}
+	}
+	j.logger.Info("stopped")
+}
+
+func (j *Janitor) cleanup(ctx context.Context) {
+	n, err := j.svc.DeleteExpiredSources(ctx)
+	if err != nil {
+		j.logger.Error("cannot delete expired sources", zap.Error(err))
+	}
+	if n > 0 {
+		j.logger.Info("expired sources deleted", zap.Int64("videos", n))
+	}
+	n, err = j.svc.ExpireAbandonedUploads(ctx)
+	if err != nil {
+		j.logger.Error("cannot expire abandoned uploads", zap.Error(err))
+		return
+	}
+	if n > 0 {
+		j.logger.Info("abandoned uploads expired", zap.Int64("videos", n))
+	}
+}
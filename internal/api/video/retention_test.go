@@ -0,0 +1,130 @@
+package video
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseSourceRetention(t *testing.T) {
+	tests := []struct {
+		policy string
+		want   time.Duration
+		err    bool
+	}{
+		{policy: "forever", want: RetainForever},
+		{policy: "never", want: RetainNever},
+		{policy: "", want: RetainNever},
+		{policy: "30d", want: 30 * 24 * time.Hour},
+		{policy: "12h", want: 12 * time.Hour},
+		{policy: "-1h", err: true},
+		{policy: "xd", err: true},
+		{policy: "sometimes", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			d, err := ParseSourceRetention(tt.policy)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, d)
+		})
+	}
+}
+
+func TestService_UpdateVideoStatusReadyRetainForever(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger:          logger,
+		Store:           s,
+		SourceRetention: RetainForever,
+	})
+	s.EXPECT().UpdateStatus(ctx, mock.Anything).Return(nil)
+
+	err = svc.UpdateVideoStatus(ctx, "test", model.StatusReady)
+	require.NoError(t, err)
+}
+
+func TestService_UpdateVideoStatusReadyRetainPeriod(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger:          logger,
+		Store:           s,
+		SourceRetention: time.Hour,
+	})
+	s.EXPECT().UpdateStatus(ctx, mock.Anything).Return(nil)
+	s.EXPECT().SetSourceExpiration(ctx, "test", mock.Anything).Run(func(_ context.Context, _ string, at time.Time) {
+		assert.WithinDuration(t, time.Now().Add(time.Hour), at, time.Minute)
+	}).Return(nil)
+
+	err = svc.UpdateVideoStatus(ctx, "test", model.StatusReady)
+	require.NoError(t, err)
+}
+
+func TestService_DeleteExpiredSources(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  s,
+	})
+	s.EXPECT().DeleteExpiredSources(ctx, mock.Anything).Return(3, nil)
+
+	n, err := svc.DeleteExpiredSources(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+}
+
+func TestService_ExpireAbandonedUploads(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger:    logger,
+		Store:     s,
+		UploadTTL: time.Hour,
+	})
+	s.EXPECT().ExpireUploads(ctx, mock.Anything, "upload was not completed within 1h0m0s").
+		Run(func(_ context.Context, before time.Time, _ string) {
+			assert.WithinDuration(t, time.Now().Add(-time.Hour), before, time.Minute)
+		}).Return(2, nil)
+
+	n, err := svc.ExpireAbandonedUploads(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+}
+
+func TestService_ExpireAbandonedUploadsDisabled(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  NewMockStore(t),
+	})
+
+	n, err := svc.ExpireAbandonedUploads(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
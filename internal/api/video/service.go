@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/adwski/vidi/internal/api/video/model"
 	"github.com/adwski/vidi/internal/generators"
 	"github.com/adwski/vidi/internal/mp4"
 	"github.com/adwski/vidi/internal/session"
+	"github.com/adwski/vidi/internal/session/token"
 	"go.uber.org/zap"
 )
 
@@ -19,13 +21,36 @@ type Store interface {
 	GetAll(ctx context.Context, userID string) ([]*model.Video, error)
 	Delete(ctx context.Context, id string, userID string) error
 	Usage(ctx context.Context, userID string) (*model.UserUsage, error)
+	FindDuplicate(ctx context.Context, fingerprint string, userID string) (*model.Video, error)
 
 	GetListByStatus(ctx context.Context, status model.Status) ([]*model.Video, error)
-	Update(ctx context.Context, vi *model.Video) error
+	Update(ctx context.Context, vi *model.Video, purgeAt time.Time) error
 	UpdateStatus(ctx context.Context, vi *model.Video) error
 
 	UpdatePart(ctx context.Context, vid string, part *model.Part) error
 	DeleteUploadedParts(ctx context.Context, vid string) error
+	SetSourceExpiration(ctx context.Context, vid string, at time.Time) error
+	DeleteExpiredSources(ctx context.Context, before time.Time) (int64, error)
+	ExpireUploads(ctx context.Context, before time.Time, reason string) (int64, error)
+
+	GetReprocessCandidates(ctx context.Context, ids []string, outdatedVersion string) ([]string, error)
+	StartReprocessing(ctx context.Context, vid string, location string) error
+
+	ClaimPurges(ctx context.Context, now time.Time, lease time.Duration, limit uint) ([]*model.Purge, error)
+	UpdatePurge(ctx context.Context, upd *model.PurgeUpdate, now time.Time, lease, backoff, maxBackoff time.Duration) error
+	GetPurgeStats(ctx context.Context) (*model.PurgeStats, error)
+
+	ClaimImports(ctx context.Context, now time.Time, lease time.Duration, limit uint) ([]*model.Import, error)
+	UpdateImport(ctx context.Context, upd *model.ImportUpdate, now time.Time, lease, backoff, maxBackoff time.Duration) error
+	GetImport(ctx context.Context, vid string, userID string) (*model.ImportStatus, error)
+
+	UpdateViewStats(ctx context.Context, upd *model.ViewStatsUpdate) error
+	GetViewStats(ctx context.Context, vid string) (*model.ViewStats, error)
+
+	CreatePlaylist(ctx context.Context, pl *model.Playlist) error
+	GetPlaylist(ctx context.Context, id string, userID string) (*model.Playlist, error)
+	GetPlaylists(ctx context.Context, userID string) ([]*model.Playlist, error)
+	DeletePlaylist(ctx context.Context, id string, userID string) error
 }
 
 // SessionStore stores upload and watch sessions.
@@ -49,10 +74,23 @@ type Service struct {
 	idGen           *generators.ID
 	watchSessions   SessionStore
 	uploadSessions  SessionStore
+	ingestSessions  SessionStore
 	s               Store
 	watchURLPrefix  string
 	uploadURLPrefix string
+	ingestURLPrefix string
 	quotas          Quotas
+	sourceRetention time.Duration
+	uploadTTL       time.Duration
+	outputPurgeLag  time.Duration
+	partSize        PartSizeBounds
+	direct          *DirectUploadConfig
+	imports         *importer
+	duplicates      DuplicatesConfig
+	watchSigner     *token.Signer
+	streams         *streamLimiter
+	sessions        *SessionsConfig
+	bindWatchClient bool
 }
 
 type Quotas struct {
@@ -60,14 +98,62 @@ type Quotas struct {
 	MaxTotalSize  uint64
 }
 
+// PartSizeBounds limits part size that could be requested by client.
+// Zero value does not limit part size.
+type PartSizeBounds struct {
+	Min uint64
+	Max uint64
+}
+
 type ServiceConfig struct {
 	Logger             *zap.Logger
 	Store              Store
 	UploadSessionStore SessionStore
 	WatchSessionStore  SessionStore
+	IngestSessionStore SessionStore
 	WatchURLPrefix     string
 	UploadURLPrefix    string
+	IngestURLPrefix    string
 	Quotas             Quotas
+
+	// SourceRetention defines how long upload parts are kept after video is ready.
+	// Zero (RetainNever) deletes them right away, RetainForever keeps them forever.
+	SourceRetention time.Duration
+
+	// UploadTTL defines how long upload could stay without activity before it is expired.
+	// Zero disables upload expiration.
+	UploadTTL time.Duration
+
+	// OutputPurgeLag defines how long previous output of reprocessed video is kept
+	// after video is switched to new output, so playback that has started before switch
+	// is not interrupted. It should not be less than watch session ttl.
+	OutputPurgeLag time.Duration
+
+	// PartSize limits upload part size requested by client.
+	PartSize PartSizeBounds
+
+	// DirectUpload enables uploads directly to media store if set.
+	DirectUpload *DirectUploadConfig
+
+	// Import enables imports from remote urls if set.
+	Import *ImportConfig
+
+	// Duplicates defines how uploads of already existing content are handled.
+	Duplicates DuplicatesConfig
+
+	// WatchSigner enables signed watch urls if set, watch sessions
+	// are not stored in session store in this case.
+	WatchSigner *token.Signer
+	// BindWatchClient binds signed watch urls to client ip.
+	BindWatchClient bool
+
+	// Streams enables tracking and limiting of active streams per user if set.
+	// Watch sessions are stored in stream store instead of watch session store in this case.
+	// It cannot be used along with signed watch urls.
+	Streams *StreamsConfig
+
+	// Sessions enables listing and revocation of sessions if set.
+	Sessions *SessionsConfig
 }
 
 func NewService(cfg *ServiceConfig) *Service {
@@ -76,10 +162,23 @@ func NewService(cfg *ServiceConfig) *Service {
 		s:               cfg.Store,
 		uploadSessions:  cfg.UploadSessionStore,
 		watchSessions:   cfg.WatchSessionStore,
+		ingestSessions:  cfg.IngestSessionStore,
 		quotas:          cfg.Quotas,
+		sourceRetention: cfg.SourceRetention,
+		uploadTTL:       cfg.UploadTTL,
+		outputPurgeLag:  cfg.OutputPurgeLag,
+		partSize:        cfg.PartSize,
+		direct:          cfg.DirectUpload,
+		imports:         newImporter(cfg.Import),
+		duplicates:      cfg.Duplicates,
+		watchSigner:     cfg.WatchSigner,
+		bindWatchClient: cfg.BindWatchClient,
+		streams:         newStreamLimiter(cfg.Streams),
+		sessions:        cfg.Sessions,
 		idGen:           generators.NewID(),
 		watchURLPrefix:  strings.TrimRight(cfg.WatchURLPrefix, "/"),
 		uploadURLPrefix: strings.TrimRight(cfg.UploadURLPrefix, "/"),
+		ingestURLPrefix: strings.TrimRight(cfg.IngestURLPrefix, "/"),
 	}
 }
 
@@ -87,6 +186,10 @@ func (svc *Service) getUploadURL(sessID string) string {
 	return fmt.Sprintf("%s/%s", svc.uploadURLPrefix, sessID)
 }
 
+func (svc *Service) getIngestURL(sessID string) string {
+	return fmt.Sprintf("%s/%s", svc.ingestURLPrefix, sessID)
+}
+
 func (svc *Service) getWatchBaseURL(sessID string) string {
 	return fmt.Sprintf("%s/%s/", svc.watchURLPrefix, sessID) // trailing / is important!
 }
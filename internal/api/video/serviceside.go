@@ -3,10 +3,12 @@ package video
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/adwski/vidi/internal/api/video/model"
 	"github.com/adwski/vidi/internal/mp4/meta"
 	"github.com/vmihailenco/msgpack/v5"
+	"go.uber.org/zap"
 )
 
 func (svc *Service) UpdateVideoStatus(ctx context.Context, vid string, status model.Status) error {
@@ -20,9 +22,7 @@ func (svc *Service) UpdateVideoStatus(ctx context.Context, vid string, status mo
 		return errors.Join(model.ErrStorage, err)
 	}
 	if status == model.StatusReady {
-		if err := svc.s.DeleteUploadedParts(ctx, vid); err != nil {
-			return errors.Join(model.ErrStorage, err)
-		}
+		return svc.retainSource(ctx, vid)
 	}
 	return nil
 }
@@ -47,13 +47,11 @@ func (svc *Service) UpdateVideoStatusAndMeta(
 		ID:           vid,
 		Status:       status,
 		PlaybackMeta: &playbackMeta,
-	}); err != nil {
+	}, time.Now().Add(svc.outputPurgeLag)); err != nil {
 		return errors.Join(model.ErrStorage, err)
 	}
 	if status == model.StatusReady {
-		if err := svc.s.DeleteUploadedParts(ctx, vid); err != nil {
-			return errors.Join(model.ErrStorage, err)
-		}
+		return svc.retainSource(ctx, vid)
 	}
 	return nil
 }
@@ -69,9 +67,72 @@ func (svc *Service) GetVideosByStatus(ctx context.Context, status model.Status)
 	return videos, nil
 }
 
+// ReprocessVideos queues ready videos for reprocessing. Videos are selected by ids
+// or by processor version: if outdatedVersion is set, every video processed by
+// other version is selected. Only videos with retained source could be reprocessed.
+// Reprocessed video is written to new output location, and video is switched to it
+// when processing is finished. Ids of queued videos are returned.
+func (svc *Service) ReprocessVideos(ctx context.Context, ids []string, outdatedVersion string) ([]string, error) {
+	if len(ids) == 0 && outdatedVersion == "" {
+		return nil, model.ErrNoReprocessCriteria
+	}
+	candidates, err := svc.s.GetReprocessCandidates(ctx, ids, outdatedVersion)
+	if err != nil {
+		return nil, errors.Join(model.ErrStorage, err)
+	}
+	queued := make([]string, 0, len(candidates))
+	for _, vid := range candidates {
+		location, errG := svc.idGen.Get()
+		if errG != nil {
+			return queued, errors.Join(errors.New("cannot generate location id"), errG)
+		}
+		if err = svc.s.StartReprocessing(ctx, vid, location); err != nil {
+			// video state could change after candidates were selected
+			svc.logger.Warn("cannot start reprocessing", zap.String("vid", vid), zap.Error(err))
+			continue
+		}
+		queued = append(queued, vid)
+	}
+	svc.logger.Info("videos queued for reprocessing",
+		zap.Int("candidates", len(candidates)),
+		zap.Int("queued", len(queued)))
+	return queued, nil
+}
+
 func (svc *Service) NotifyPartUpload(ctx context.Context, vid string, part *model.Part) error {
 	if err := svc.s.UpdatePart(ctx, vid, part); err != nil {
 		return errors.Join(model.ErrStorage, err)
 	}
 	return nil
 }
+
+// GetWatchMeta returns playback meta of watch session target. Playlist target
+// returns meta of every video in playlist order, i.e. one meta per MPD period.
+// Streamer uses it to render MPDs of watch sessions.
+func (svc *Service) GetWatchMeta(ctx context.Context, id, userID string, playlist bool) ([]*meta.Meta, error) {
+	if !playlist {
+		video, err := svc.s.Get(ctx, id, userID)
+		if err != nil {
+			return nil, errors.Join(model.ErrStorage, err)
+		}
+		if err = checkWatchable(video, true); err != nil {
+			return nil, err
+		}
+		return []*meta.Meta{video.PlaybackMeta}, nil
+	}
+	pl, err := svc.s.GetPlaylist(ctx, id, userID)
+	if err != nil {
+		return nil, errors.Join(model.ErrStorage, err)
+	}
+	if len(pl.Videos) == 0 {
+		return nil, model.ErrEmptyPlaylist
+	}
+	periods := make([]*meta.Meta, 0, len(pl.Videos))
+	for _, video := range pl.Videos {
+		if err = checkWatchable(video, false); err != nil {
+			return nil, err
+		}
+		periods = append(periods, video.PlaybackMeta)
+	}
+	return periods, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/adwski/vidi/internal/api/video/model"
 	"github.com/adwski/vidi/internal/mp4/meta"
@@ -158,16 +159,20 @@ func TestService_UpdateVideoStatusAndMeta(t *testing.T) {
 
 	ctx := context.Background()
 	s := NewMockStore(t)
-	s.EXPECT().Update(ctx, mock.Anything).Run(func(_ context.Context, v *model.Video) {
+	start := time.Now()
+	s.EXPECT().Update(ctx, mock.Anything, mock.Anything).Run(func(_ context.Context, v *model.Video, purgeAt time.Time) {
 		assert.Equal(t, vid, v.ID)
 		assert.Equal(t, status, v.Status)
 		assert.Equal(t, m, v.PlaybackMeta)
+		// previous output must outlive watch sessions
+		assert.False(t, purgeAt.Before(start.Add(time.Hour)))
 	}).Return(nil)
 	s.EXPECT().DeleteUploadedParts(ctx, vid).Return(nil)
 
 	svc := NewService(&ServiceConfig{
-		Logger: logger,
-		Store:  s,
+		Logger:         logger,
+		Store:          s,
+		OutputPurgeLag: time.Hour,
 	})
 
 	err = svc.UpdateVideoStatusAndMeta(ctx, vid, status, b)
@@ -202,7 +207,7 @@ func TestService_UpdateVideoStatusAndMetaDBErrParts(t *testing.T) {
 
 	ctx := context.Background()
 	s := NewMockStore(t)
-	s.EXPECT().Update(ctx, mock.Anything).Run(func(_ context.Context, v *model.Video) {
+	s.EXPECT().Update(ctx, mock.Anything, mock.Anything).Run(func(_ context.Context, v *model.Video, _ time.Time) {
 		assert.Equal(t, vid, v.ID)
 		assert.Equal(t, status, v.Status)
 		assert.Equal(t, m, v.PlaybackMeta)
@@ -246,7 +251,7 @@ func TestService_UpdateVideoStatusAndMetaDBErr(t *testing.T) {
 
 	ctx := context.Background()
 	s := NewMockStore(t)
-	s.EXPECT().Update(ctx, mock.Anything).Run(func(_ context.Context, v *model.Video) {
+	s.EXPECT().Update(ctx, mock.Anything, mock.Anything).Run(func(_ context.Context, v *model.Video, _ time.Time) {
 		assert.Equal(t, vid, v.ID)
 		assert.Equal(t, status, v.Status)
 		assert.Equal(t, m, v.PlaybackMeta)
@@ -392,3 +397,88 @@ func TestService_NotifyPartUploadDBErr(t *testing.T) {
 	err = svc.NotifyPartUpload(ctx, vid, part)
 	require.ErrorIs(t, err, model.ErrStorage)
 }
+
+func TestService_ReprocessVideos(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  s,
+	})
+
+	_, err = svc.ReprocessVideos(ctx, nil, "")
+	require.ErrorIs(t, err, model.ErrNoReprocessCriteria)
+
+	s.EXPECT().GetReprocessCandidates(ctx, []string{"v1"}, "2").Return([]string{"v1", "v2", "v3"}, nil)
+	s.EXPECT().StartReprocessing(ctx, "v1", mock.Anything).Return(nil)
+	s.EXPECT().StartReprocessing(ctx, "v2", mock.Anything).Return(errors.New("affected rows: 0"))
+	s.EXPECT().StartReprocessing(ctx, "v3", mock.Anything).Run(func(_ context.Context, _ string, location string) {
+		assert.NotEmpty(t, location)
+	}).Return(nil)
+
+	ids, err := svc.ReprocessVideos(ctx, []string{"v1"}, "2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1", "v3"}, ids)
+}
+
+func TestService_ReprocessVideosDBErr(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  s,
+	})
+	s.EXPECT().GetReprocessCandidates(ctx, []string(nil), "2").Return(nil, errors.New("test"))
+
+	_, err = svc.ReprocessVideos(ctx, nil, "2")
+	require.ErrorIs(t, err, model.ErrStorage)
+}
+
+func TestService_GetWatchMeta(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  s,
+	})
+
+	v1, v2 := testSourceVideo(), testSourceVideo()
+	v2.ID, v2.Status = "uploaded", model.StatusUploaded
+	s.EXPECT().Get(ctx, v1.ID, "user").Return(v1, nil)
+	s.EXPECT().Get(ctx, v2.ID, "user").Return(v2, nil)
+	s.EXPECT().Get(ctx, "missing", "user").Return(nil, model.ErrNotFound)
+	s.EXPECT().GetPlaylist(ctx, "pl", "user").Return(&model.Playlist{
+		ID:     "pl",
+		Videos: []*model.Video{v1, v1},
+	}, nil)
+	s.EXPECT().GetPlaylist(ctx, "pl2", "user").Return(&model.Playlist{
+		ID:     "pl2",
+		Videos: []*model.Video{v1, v2},
+	}, nil)
+
+	periods, err := svc.GetWatchMeta(ctx, v1.ID, "user", false)
+	require.NoError(t, err)
+	assert.Equal(t, []*meta.Meta{v1.PlaybackMeta}, periods)
+
+	_, err = svc.GetWatchMeta(ctx, v2.ID, "user", false)
+	require.ErrorIs(t, err, model.ErrNotReady)
+
+	_, err = svc.GetWatchMeta(ctx, "missing", "user", false)
+	require.ErrorIs(t, err, model.ErrNotFound)
+
+	periods, err = svc.GetWatchMeta(ctx, "pl", "user", true)
+	require.NoError(t, err)
+	assert.Len(t, periods, 2)
+
+	_, err = svc.GetWatchMeta(ctx, "pl2", "user", true)
+	require.ErrorIs(t, err, model.ErrNotReady)
+}
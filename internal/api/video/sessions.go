@@ -0,0 +1,157 @@
+package video
+
+import (
+	"context"
+	"errors"
+
+	user "github.com/adwski/vidi/internal/api/user/model"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/session"
+	sessionStore "github.com/adwski/vidi/internal/session/store"
+)
+
+// SessionIndex lists and revokes sessions indexed per user and per video.
+// Revoked sessions are also evicted from in-memory caches of media services.
+type SessionIndex interface {
+	Get(ctx context.Context, id string) (*session.Session, error)
+	UserSessions(ctx context.Context, userID string) ([]*session.Activity, error)
+	VideoSessions(ctx context.Context, videoID string) ([]*session.Activity, error)
+	Revoke(ctx context.Context, sess *session.Session) error
+}
+
+// SessionsConfig enables listing and revocation of upload and watch sessions.
+// Signed watch urls are not stored, so they cannot be revoked.
+type SessionsConfig struct {
+	Upload SessionIndex
+	Watch  SessionIndex
+}
+
+// sessionKinds are kinds of sessions that could be listed and revoked by user.
+var sessionKinds = []string{session.KindUpload, session.KindWatch}
+
+func (cfg *SessionsConfig) index(kind string) (SessionIndex, error) {
+	switch kind {
+	case session.KindUpload:
+		return cfg.Upload, nil
+	case session.KindWatch:
+		return cfg.Watch, nil
+	}
+	return nil, model.ErrSessionKind
+}
+
+// GetSessions returns active upload and watch sessions of user.
+// If vid is set, only sessions of this video are returned.
+func (svc *Service) GetSessions(ctx context.Context, usr *user.User, vid string) ([]*model.UserSession, error) {
+	if svc.sessions == nil {
+		return nil, model.ErrSessionsNotIndexed
+	}
+	if vid != "" {
+		if _, err := svc.s.Get(ctx, vid, usr.ID); err != nil {
+			return nil, errors.Join(model.ErrStorage, err)
+		}
+	}
+	var sessions []*model.UserSession
+	for _, kind := range sessionKinds {
+		activities, err := svc.indexedSessions(ctx, usr, kind, vid)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range activities {
+			sessions = append(sessions, &model.UserSession{
+				Kind:       kind,
+				ID:         a.Session.ID,
+				VideoID:    a.Session.VideoID,
+				PlaylistID: a.Session.PlaylistID,
+				ActiveAt:   a.ActiveAt,
+			})
+		}
+	}
+	return sessions, nil
+}
+
+// RevokeSession revokes upload or watch session of user.
+func (svc *Service) RevokeSession(ctx context.Context, usr *user.User, kind, id string) error {
+	if svc.sessions == nil {
+		return model.ErrSessionsNotIndexed
+	}
+	idx, err := svc.sessions.index(kind)
+	if err != nil {
+		return err
+	}
+	sess, err := idx.Get(ctx, id)
+	switch {
+	case errors.Is(err, sessionStore.ErrNotFound):
+		return model.ErrSessionNotFound
+	case err != nil:
+		return errors.Join(model.ErrSessionStorage, err)
+	case sess.UserID != usr.ID:
+		// sessions of other users are not disclosed
+		return model.ErrSessionNotFound
+	}
+	return svc.revoke(ctx, kind, sess)
+}
+
+// RevokeVideoSessions revokes every upload and watch session of video
+// and returns amount of revoked sessions.
+func (svc *Service) RevokeVideoSessions(ctx context.Context, usr *user.User, vid string) (int, error) {
+	if svc.sessions == nil {
+		return 0, model.ErrSessionsNotIndexed
+	}
+	if _, err := svc.s.Get(ctx, vid, usr.ID); err != nil {
+		return 0, errors.Join(model.ErrStorage, err)
+	}
+	var revoked int
+	for _, kind := range sessionKinds {
+		activities, err := svc.indexedSessions(ctx, usr, kind, vid)
+		if err != nil {
+			return revoked, err
+		}
+		for _, a := range activities {
+			if err = svc.revoke(ctx, kind, a.Session); err != nil {
+				return revoked, errors.Join(model.ErrSessionStorage, err)
+			}
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (svc *Service) revoke(ctx context.Context, kind string, sess *session.Session) error {
+	idx, err := svc.sessions.index(kind)
+	if err != nil {
+		return err
+	}
+	if err = idx.Revoke(ctx, sess); err != nil {
+		return errors.Join(model.ErrSessionStorage, err)
+	}
+	return nil
+}
+
+// indexedSessions returns sessions of user or sessions of user's video if vid is set.
+func (svc *Service) indexedSessions(
+	ctx context.Context,
+	usr *user.User,
+	kind, vid string,
+) ([]*session.Activity, error) {
+	idx, err := svc.sessions.index(kind)
+	if err != nil {
+		return nil, err
+	}
+	var activities []*session.Activity
+	if vid == "" {
+		activities, err = idx.UserSessions(ctx, usr.ID)
+	} else {
+		activities, err = idx.VideoSessions(ctx, vid)
+	}
+	if err != nil {
+		return nil, errors.Join(model.ErrSessionStorage, err)
+	}
+	owned := activities[:0]
+	for _, a := range activities {
+		// sessions of other users are never disclosed
+		if a.Session.UserID == usr.ID {
+			owned = append(owned, a)
+		}
+	}
+	return owned, nil
+}
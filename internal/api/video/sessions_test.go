@@ -0,0 +1,149 @@
+package video
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	usermodel "github.com/adwski/vidi/internal/api/user/model"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/session"
+	sessionStore "github.com/adwski/vidi/internal/session/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeSessionIndex keeps sessions in order of creation, like redis index does.
+type fakeSessionIndex struct {
+	sessions []*session.Session
+	activeAt time.Time
+}
+
+func (f *fakeSessionIndex) Get(_ context.Context, id string) (*session.Session, error) {
+	for _, sess := range f.sessions {
+		if sess.ID == id {
+			return sess, nil
+		}
+	}
+	return nil, sessionStore.ErrNotFound
+}
+
+func (f *fakeSessionIndex) UserSessions(_ context.Context, userID string) ([]*session.Activity, error) {
+	return f.filter(func(sess *session.Session) bool { return sess.UserID == userID }), nil
+}
+
+func (f *fakeSessionIndex) VideoSessions(_ context.Context, videoID string) ([]*session.Activity, error) {
+	return f.filter(func(sess *session.Session) bool { return sess.VideoID == videoID }), nil
+}
+
+func (f *fakeSessionIndex) Revoke(_ context.Context, sess *session.Session) error {
+	for i, s := range f.sessions {
+		if s.ID == sess.ID {
+			f.sessions = append(f.sessions[:i], f.sessions[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (f *fakeSessionIndex) filter(match func(*session.Session) bool) []*session.Activity {
+	var activities []*session.Activity
+	for _, sess := range f.sessions {
+		if match(sess) {
+			activities = append(activities, &session.Activity{Session: sess, ActiveAt: f.activeAt})
+		}
+	}
+	return activities
+}
+
+func newTestSessionsService(t *testing.T) (*Service, *MockStore, *fakeSessionIndex, *fakeSessionIndex) {
+	t.Helper()
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	activeAt := time.Now()
+	upload := &fakeSessionIndex{activeAt: activeAt, sessions: []*session.Session{
+		{ID: "up1", VideoID: "vid1", UserID: "user"},
+	}}
+	watch := &fakeSessionIndex{activeAt: activeAt, sessions: []*session.Session{
+		{ID: "w1", VideoID: "vid1", UserID: "user"},
+		{ID: "w2", VideoID: "vid2", UserID: "user"},
+		{ID: "w3", VideoID: "vid1", UserID: "other"},
+	}}
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger:   logger,
+		Store:    s,
+		Sessions: &SessionsConfig{Upload: upload, Watch: watch},
+	})
+	return svc, s, upload, watch
+}
+
+func TestService_GetSessions(t *testing.T) {
+	svc, s, upload, _ := newTestSessionsService(t)
+	ctx := context.Background()
+	u := &usermodel.User{ID: "user"}
+	activeAt := upload.activeAt
+
+	sessions, err := svc.GetSessions(ctx, u, "")
+	require.NoError(t, err)
+	assert.Equal(t, []*model.UserSession{
+		{Kind: session.KindUpload, ID: "up1", VideoID: "vid1", ActiveAt: activeAt},
+		{Kind: session.KindWatch, ID: "w1", VideoID: "vid1", ActiveAt: activeAt},
+		{Kind: session.KindWatch, ID: "w2", VideoID: "vid2", ActiveAt: activeAt},
+	}, sessions)
+
+	s.EXPECT().Get(ctx, "vid1", u.ID).Return(&model.Video{ID: "vid1", UserID: u.ID}, nil)
+	sessions, err = svc.GetSessions(ctx, u, "vid1")
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "up1", sessions[0].ID)
+	assert.Equal(t, "w1", sessions[1].ID)
+
+	s.EXPECT().Get(ctx, "vid3", u.ID).Return(nil, model.ErrNotFound)
+	_, err = svc.GetSessions(ctx, u, "vid3")
+	require.ErrorIs(t, err, model.ErrNotFound)
+
+	svc.sessions = nil
+	_, err = svc.GetSessions(ctx, u, "")
+	require.ErrorIs(t, err, model.ErrSessionsNotIndexed)
+}
+
+func TestService_RevokeSession(t *testing.T) {
+	svc, _, upload, watch := newTestSessionsService(t)
+	ctx := context.Background()
+	u := &usermodel.User{ID: "user"}
+
+	require.NoError(t, svc.RevokeSession(ctx, u, session.KindWatch, "w2"))
+	assert.Len(t, watch.sessions, 2)
+
+	require.ErrorIs(t, svc.RevokeSession(ctx, u, session.KindWatch, "w2"), model.ErrSessionNotFound)
+	// session of other user
+	require.ErrorIs(t, svc.RevokeSession(ctx, u, session.KindWatch, "w3"), model.ErrSessionNotFound)
+	require.ErrorIs(t, svc.RevokeSession(ctx, u, session.KindIngest, "in1"), model.ErrSessionKind)
+
+	require.NoError(t, svc.RevokeSession(ctx, u, session.KindUpload, "up1"))
+	assert.Empty(t, upload.sessions)
+}
+
+func TestService_RevokeVideoSessions(t *testing.T) {
+	svc, s, upload, watch := newTestSessionsService(t)
+	ctx := context.Background()
+	u := &usermodel.User{ID: "user"}
+
+	s.EXPECT().Get(ctx, "vid1", u.ID).Return(&model.Video{ID: "vid1", UserID: u.ID}, nil)
+	revoked, err := svc.RevokeVideoSessions(ctx, u, "vid1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, revoked)
+	assert.Empty(t, upload.sessions)
+	// sessions of other videos and other users are kept
+	require.Len(t, watch.sessions, 2)
+	assert.Equal(t, "w2", watch.sessions[0].ID)
+	assert.Equal(t, "w3", watch.sessions[1].ID)
+
+	s.EXPECT().Get(ctx, "vid3", mock.Anything).Return(nil, model.ErrNotFound)
+	_, err = svc.RevokeVideoSessions(ctx, u, "vid3")
+	require.ErrorIs(t, err, model.ErrNotFound)
+}
@@ -0,0 +1,51 @@
+package video
+
+import (
+	"context"
+	"errors"
+
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/session"
+)
+
+type clientIPKey struct{}
+
+// WithClientIP returns context that carries client ip. It is used to bind signed watch urls to client.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+func clientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
+// storeWatchSession stores watch session and returns its identifier that is used in watch urls.
+// If signed urls are enabled, session is not stored and signed token is used as identifier instead,
+// so streamer could verify it without session store. If streams are tracked, session is stored
+// only if user does not exceed limit of active streams.
+func (svc *Service) storeWatchSession(ctx context.Context, sess *session.Session) (string, error) {
+	if svc.streams != nil {
+		if err := svc.storeLimitedWatchSession(ctx, sess); err != nil {
+			return "", err
+		}
+		return sess.ID, nil
+	}
+	if svc.watchSigner == nil {
+		if err := svc.watchSessions.Set(ctx, sess); err != nil {
+			return "", errors.Join(model.ErrSessionStorage, err)
+		}
+		return sess.ID, nil
+	}
+	var client string
+	if svc.bindWatchClient {
+		if client = clientIPFromContext(ctx); client == "" {
+			return "", errors.Join(model.ErrInternal, errors.New("client ip is unknown"))
+		}
+	}
+	tkn, err := svc.watchSigner.Sign(sess, client)
+	if err != nil {
+		return "", errors.Join(model.ErrInternal, err)
+	}
+	return tkn, nil
+}
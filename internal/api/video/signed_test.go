@@ -0,0 +1,60 @@
+package video
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	usermodel "github.com/adwski/vidi/internal/api/user/model"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/session/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestService_WatchVideoSigned(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	key := &token.Key{ID: "k1", Alg: token.AlgHS256, Secret: []byte(strings.Repeat("k", 32))}
+	signer, err := token.NewSigner(key, time.Minute)
+	require.NoError(t, err)
+	verifier, err := token.NewVerifier([]*token.Key{key})
+	require.NoError(t, err)
+
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger:          logger,
+		Store:           s,
+		WatchURLPrefix:  "http://test",
+		WatchSigner:     signer,
+		BindWatchClient: true,
+	})
+	v := &model.Video{
+		ID:             "testvid",
+		OutputLocation: "testloc",
+		Status:         model.StatusReady,
+	}
+	u := &usermodel.User{ID: "test"}
+	s.EXPECT().Get(mock.Anything, v.ID, u.ID).Return(v, nil)
+
+	// session store is not used, client ip is required for binding
+	_, err = svc.WatchVideo(context.Background(), u, v.ID, true)
+	require.ErrorIs(t, err, model.ErrInternal)
+
+	b, err := svc.WatchVideo(WithClientIP(context.Background(), "1.2.3.4"), u, v.ID, true)
+	require.NoError(t, err)
+	watchURL := string(b)
+	require.True(t, strings.HasPrefix(watchURL, "http://test/k1."))
+	require.True(t, strings.HasSuffix(watchURL, "/manifest.mpd"))
+
+	tkn := strings.TrimSuffix(strings.TrimPrefix(watchURL, "http://test/"), "/manifest.mpd")
+	sess, err := verifier.Verify(tkn, "1.2.3.4")
+	require.NoError(t, err)
+	assert.Equal(t, v.ID, sess.VideoID)
+	assert.Equal(t, v.OutputLocation, sess.Location)
+	assert.NotEmpty(t, sess.ID)
+}
@@ -0,0 +1,167 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	user "github.com/adwski/vidi/internal/api/user/model"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/mp4/meta"
+	"go.uber.org/zap"
+)
+
+// segmentTimeline describes segments of video: number of the first segment,
+// amount of segments and duration of segment.
+type segmentTimeline struct {
+	start      uint
+	count      uint
+	durationMS uint64
+}
+
+// ReportViews records view reports of streamer. Reports are aggregated per video,
+// watch time and retention are calculated from segment durations of video.
+// Segments of live streams are not included in retention since viewers join at live edge.
+//
+// Stats of every video are updated separately, so some reports could be recorded
+// while others are not. Keys of reports that were not recorded are returned
+// along with error, only these reports should be retried.
+func (svc *Service) ReportViews(ctx context.Context, reports []*model.ViewReport) ([]model.ViewReportKey, error) {
+	type videoKey struct{ id, userID string }
+	var (
+		updates  = make(map[videoKey]*model.ViewStatsUpdate)
+		sessions = make(map[videoKey][]string)
+	)
+	for _, r := range reports {
+		key := videoKey{id: r.VideoID, userID: r.UserID}
+		upd, ok := updates[key]
+		if !ok {
+			upd = &model.ViewStatsUpdate{VideoID: r.VideoID, Retention: make(map[uint]uint64)}
+			updates[key] = upd
+		}
+		if !slices.Contains(sessions[key], r.SessionID) {
+			sessions[key] = append(sessions[key], r.SessionID)
+		}
+		if r.NewView {
+			upd.Views++
+		}
+		upd.Requests += r.Requests
+		upd.Bytes += r.Bytes
+		for _, seg := range r.Segments {
+			// segment numbers are collected as is and mapped to timeline later
+			upd.Retention[seg]++
+		}
+	}
+	var (
+		errs   []error
+		failed []model.ViewReportKey
+	)
+	for key, upd := range updates {
+		video, err := svc.s.Get(ctx, key.id, key.userID)
+		if err != nil {
+			if errors.Is(err, model.ErrNotFound) {
+				svc.logger.Debug("views of unknown video are skipped",
+					zap.String("video_id", key.id),
+					zap.String("user_id", key.userID))
+				continue
+			}
+			errs = append(errs, err)
+			failed = appendFailed(failed, key.id, sessions[key])
+			continue
+		}
+		mapRetention(video, upd)
+		if err = svc.s.UpdateViewStats(ctx, upd); err != nil {
+			errs = append(errs, err)
+			failed = appendFailed(failed, key.id, sessions[key])
+		}
+	}
+	if len(errs) > 0 {
+		return failed, errors.Join(model.ErrStorage, errors.Join(errs...))
+	}
+	return nil, nil
+}
+
+func appendFailed(failed []model.ViewReportKey, vid string, sessionIDs []string) []model.ViewReportKey {
+	for _, id := range sessionIDs {
+		failed = append(failed, model.ViewReportKey{SessionID: id, VideoID: vid})
+	}
+	return failed
+}
+
+// mapRetention converts segment numbers of update to segment indexes and calculates watch time.
+// Segments outside of video timeline are dropped.
+func mapRetention(video *model.Video, upd *model.ViewStatsUpdate) {
+	tl, ok := getSegmentTimeline(video.PlaybackMeta)
+	segments := upd.Retention
+	upd.Retention = make(map[uint]uint64, len(segments))
+	if !ok {
+		return
+	}
+	for seg, views := range segments {
+		if seg < tl.start {
+			continue
+		}
+		idx := seg - tl.start
+		if tl.count != 0 && idx >= tl.count {
+			continue
+		}
+		upd.WatchTimeMS += views * tl.durationMS
+		if video.PlaybackMeta.Live == nil {
+			upd.Retention[idx] = views
+		}
+	}
+}
+
+// GetVideoStats returns view statistics of user's video.
+// Retention has value for every segment of video.
+func (svc *Service) GetVideoStats(ctx context.Context, usr *user.User, vid string) (*model.ViewStats, error) {
+	video, err := svc.s.Get(ctx, vid, usr.ID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrNotFound
+		}
+		return nil, errors.Join(model.ErrStorage, err)
+	}
+	stats, err := svc.s.GetViewStats(ctx, vid)
+	if err != nil {
+		return nil, errors.Join(model.ErrStorage, err)
+	}
+	if tl, ok := getSegmentTimeline(video.PlaybackMeta); ok {
+		stats.SegmentDurationMS = tl.durationMS
+		for uint(len(stats.Retention)) < tl.count {
+			stats.Retention = append(stats.Retention, 0)
+		}
+	}
+	if stats.Retention == nil {
+		stats.Retention = []uint64{}
+	}
+	return stats, nil
+}
+
+// getSegmentTimeline returns segments timeline of playback meta. All tracks are segmented
+// with the same duration, so timeline is taken from the first track.
+// Amount of segments is zero for live streams since it is not known in advance.
+func getSegmentTimeline(mt *meta.Meta) (segmentTimeline, bool) {
+	if mt == nil || len(mt.Tracks) == 0 {
+		return segmentTimeline{}, false
+	}
+	seg := mt.Tracks[0].Segment
+	if seg == nil || seg.Duration == 0 || seg.Timescale == 0 {
+		return segmentTimeline{}, false
+	}
+	tl := segmentTimeline{
+		start:      seg.StartNumber,
+		durationMS: seg.Duration * 1000 / uint64(seg.Timescale), //nolint:mnd // ms in second
+	}
+	switch {
+	case mt.Live != nil:
+		// live timeline grows while stream is ongoing
+	case seg.EndNumber != 0:
+		// clip
+		tl.count = seg.EndNumber - seg.StartNumber + 1
+	case tl.durationMS != 0:
+		durMS := uint64(mt.Duration.Milliseconds())
+		tl.count = uint((durMS + tl.durationMS - 1) / tl.durationMS)
+	}
+	return tl, tl.durationMS != 0
+}
@@ -0,0 +1,141 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	usermodel "github.com/adwski/vidi/internal/api/user/model"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/mp4/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func testStatsMeta() *meta.Meta {
+	return &meta.Meta{
+		Duration: 10 * time.Second,
+		Tracks: []meta.Track{{
+			Name:    "vide1",
+			Segment: &meta.SegmentConfig{StartNumber: 1, Duration: 3000, Timescale: 1000},
+		}},
+	}
+}
+
+func TestService_ReportViews(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{Logger: logger, Store: s})
+
+	v := &model.Video{ID: "vid", UserID: "user", PlaybackMeta: testStatsMeta()}
+	s.EXPECT().Get(mock.Anything, "vid", "user").Return(v, nil)
+	s.EXPECT().Get(mock.Anything, "unknown", "user").Return(nil, model.ErrNotFound)
+	s.EXPECT().UpdateViewStats(mock.Anything, &model.ViewStatsUpdate{
+		VideoID:     "vid",
+		Views:       2,
+		WatchTimeMS: 4 * 3000,
+		Requests:    6,
+		Bytes:       600,
+		// segment 0 and 5 are outside of video
+		Retention: map[uint]uint64{0: 2, 1: 1, 3: 1},
+	}).Return(nil)
+
+	failed, err := svc.ReportViews(context.Background(), []*model.ViewReport{
+		{SessionID: "s1", VideoID: "vid", UserID: "user", NewView: true, Requests: 2, Bytes: 200, Segments: []uint{1, 2}},
+		{SessionID: "s2", VideoID: "vid", UserID: "user", NewView: true, Requests: 2, Bytes: 200, Segments: []uint{0, 1}},
+		{SessionID: "s1", VideoID: "vid", UserID: "user", Requests: 2, Bytes: 200, Segments: []uint{4, 5}},
+		{SessionID: "s3", VideoID: "unknown", UserID: "user", NewView: true, Requests: 1, Bytes: 100},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, failed)
+}
+
+func TestService_ReportViewsPartialFailure(t *testing.T) {
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{Logger: zap.NewNop(), Store: s})
+
+	s.EXPECT().Get(mock.Anything, "vid", "user").Return(&model.Video{ID: "vid", UserID: "user"}, nil)
+	s.EXPECT().Get(mock.Anything, "other", "user").Return(&model.Video{ID: "other", UserID: "user"}, nil)
+	s.EXPECT().Get(mock.Anything, "broken", "user").Return(nil, errors.New("conn error"))
+	s.EXPECT().UpdateViewStats(mock.Anything, mock.MatchedBy(func(upd *model.ViewStatsUpdate) bool {
+		return upd.VideoID == "vid"
+	})).Return(nil)
+	s.EXPECT().UpdateViewStats(mock.Anything, mock.MatchedBy(func(upd *model.ViewStatsUpdate) bool {
+		return upd.VideoID == "other"
+	})).Return(errors.New("conn error"))
+
+	failed, err := svc.ReportViews(context.Background(), []*model.ViewReport{
+		{SessionID: "s1", VideoID: "vid", UserID: "user", NewView: true},
+		{SessionID: "s2", VideoID: "other", UserID: "user", NewView: true},
+		{SessionID: "s3", VideoID: "other", UserID: "user", NewView: true},
+		{SessionID: "s2", VideoID: "other", UserID: "user"},
+		{SessionID: "s4", VideoID: "broken", UserID: "user", NewView: true},
+		// playlist session reports every video separately
+		{SessionID: "s1", VideoID: "broken", UserID: "user", NewView: true},
+	})
+	require.ErrorIs(t, err, model.ErrStorage)
+	assert.ElementsMatch(t, []model.ViewReportKey{
+		{SessionID: "s2", VideoID: "other"},
+		{SessionID: "s3", VideoID: "other"},
+		{SessionID: "s4", VideoID: "broken"},
+		{SessionID: "s1", VideoID: "broken"},
+	}, failed)
+}
+
+func TestService_GetVideoStats(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{Logger: logger, Store: s})
+	u := &usermodel.User{ID: "user"}
+
+	v := &model.Video{ID: "vid", UserID: u.ID, PlaybackMeta: testStatsMeta()}
+	s.EXPECT().Get(mock.Anything, "vid", u.ID).Return(v, nil)
+	s.EXPECT().Get(mock.Anything, "other", u.ID).Return(nil, model.ErrNotFound)
+	s.EXPECT().GetViewStats(mock.Anything, "vid").Return(&model.ViewStats{
+		Views:       2,
+		WatchTimeMS: 9000,
+		Retention:   []uint64{2, 1},
+	}, nil)
+
+	stats, err := svc.GetVideoStats(context.Background(), u, "vid")
+	require.NoError(t, err)
+	assert.Equal(t, &model.ViewStats{
+		Views:             2,
+		WatchTimeMS:       9000,
+		SegmentDurationMS: 3000,
+		Retention:         []uint64{2, 1, 0, 0},
+	}, stats)
+
+	_, err = svc.GetVideoStats(context.Background(), u, "other")
+	require.ErrorIs(t, err, model.ErrNotFound)
+}
+
+func TestGetSegmentTimeline(t *testing.T) {
+	tl, ok := getSegmentTimeline(testStatsMeta())
+	require.True(t, ok)
+	assert.Equal(t, segmentTimeline{start: 1, count: 4, durationMS: 3000}, tl)
+
+	clip, err := testStatsMeta().MakeClip(3*time.Second, 9*time.Second)
+	require.NoError(t, err)
+	tl, ok = getSegmentTimeline(clip)
+	require.True(t, ok)
+	assert.Equal(t, segmentTimeline{start: 2, count: 2, durationMS: 3000}, tl)
+
+	live := testStatsMeta()
+	live.Live = &meta.LiveInfo{}
+	tl, ok = getSegmentTimeline(live)
+	require.True(t, ok)
+	assert.Zero(t, tl.count)
+
+	_, ok = getSegmentTimeline(&meta.Meta{})
+	assert.False(t, ok)
+	_, ok = getSegmentTimeline(nil)
+	assert.False(t, ok)
+}
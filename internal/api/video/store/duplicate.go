@@ -0,0 +1,22 @@
+package store
+
+import (
+	"context"
+
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/mp4/meta"
+)
+
+// FindDuplicate returns ready video of user with specified content fingerprint.
+func (s *Store) FindDuplicate(ctx context.Context, fingerprint, userID string) (*model.Video, error) {
+	vi := &model.Video{PlaybackMeta: &meta.Meta{}}
+	query := `select id, user_id, status, name, size, output_location, playback_meta, created_at from videos
+		where fingerprint = $1 and user_id = $2 and status in ($3, $4)
+		order by created_at limit 1`
+	if err := s.Pool().QueryRow(ctx, query, fingerprint, userID,
+		int(model.StatusReady), int(model.StatusReprocessing)).Scan(
+		&vi.ID, &vi.UserID, &vi.Status, &vi.Name, &vi.Size, &vi.OutputLocation, &vi.PlaybackMeta, &vi.CreatedAt); err != nil {
+		return nil, handleDBErr(err)
+	}
+	return vi, nil
+}
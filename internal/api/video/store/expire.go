@@ -0,0 +1,28 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/model"
+)
+
+// ExpireUploads marks videos which upload had no activity since specified time as expired.
+// Upload parts of expired videos are deleted and their upload location is queued for purge.
+// It returns number of expired videos.
+func (s *Store) ExpireUploads(ctx context.Context, before time.Time, reason string) (int64, error) {
+	query := `with expired as (
+			update videos set status = $2, status_reason = $3
+			where status in ($4, $5) and coalesce(upload_activity_at, created_at) < $1
+			returning id, location
+		), parts as (
+			delete from upload_parts where video_id in (select id from expired)
+		)
+		insert into media_purges (video_id, kind, location) select id, $6, location from expired`
+	tag, err := s.Pool().Exec(ctx, query, before, int(model.StatusExpired), reason,
+		int(model.StatusCreated), int(model.StatusUploading), int(model.PurgeKindUpload))
+	if err != nil {
+		return 0, handleDBErr(err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/jackc/pgx/v5"
+)
+
+// ClaimImports returns unfinished imports of not yet uploaded videos that are due at specified time.
+// Returned imports are not returned again until lease expires,
+// so several importers could work concurrently.
+func (s *Store) ClaimImports(ctx context.Context, now time.Time, lease time.Duration, limit uint) ([]*model.Import, error) {
+	query := `update media_imports i set next_attempt_at = $2 from videos v
+		where v.id = i.video_id and i.id in (select mi.id from media_imports mi
+			join videos mv on mv.id = mi.video_id
+			where not mi.done and mi.next_attempt_at <= $1 and mv.status in ($4, $5)
+			order by mi.next_attempt_at limit $3 for update of mi skip locked)
+		returning i.id, i.video_id, i.url, i.downloaded, i.attempts, v.location, v.size, v.part_size`
+	rows, err := s.Pool().Query(ctx, query, now, now.Add(lease), limit,
+		int(model.StatusCreated), int(model.StatusUploading))
+	if err != nil {
+		return nil, handleDBErr(err)
+	}
+	imports, errR := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*model.Import, error) {
+		var i model.Import
+		if errS := row.Scan(&i.ID, &i.VideoID, &i.URL, &i.Downloaded, &i.Attempts,
+			&i.Location, &i.Size, &i.PartSize); errS != nil {
+			return nil, fmt.Errorf("error while scanning row: %w", errS)
+		}
+		return &i, nil
+	})
+	if errR != nil {
+		return nil, fmt.Errorf("error while collecting rows: %w", errR)
+	}
+	return imports, nil
+}
+
+// UpdateImport records import progress. Unfinished import lease is prolonged and
+// video upload activity is updated, so import in progress does not expire.
+// Failed import is retried after exponential backoff based on amount of previous attempts.
+// Fatal failure finishes import and moves video to error state.
+func (s *Store) UpdateImport(
+	ctx context.Context,
+	upd *model.ImportUpdate,
+	now time.Time,
+	lease, backoff, maxBackoff time.Duration,
+) error {
+	tx, err := s.Pool().Begin(ctx)
+	if err != nil {
+		return handleDBErr(err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var vid string
+	query := `update media_imports set downloaded = greatest(downloaded, $2), done = $3, last_error = $4,
+		attempts = case when $4 != '' then attempts + 1 else attempts end,
+		next_attempt_at = case when $4 != ''
+			then $5::timestamptz + make_interval(secs => least($7::float8, $6::float8 * power(2, attempts)))
+			else $5::timestamptz + make_interval(secs => $8::float8) end
+		where id = $1 and not done returning video_id`
+	if err = tx.QueryRow(ctx, query, upd.ID, upd.Downloaded, upd.Done || upd.Fatal, upd.Error, now,
+		backoff.Seconds(), maxBackoff.Seconds(), lease.Seconds()).Scan(&vid); err != nil {
+		return handleDBErr(err)
+	}
+	switch {
+	case upd.Fatal:
+		query = `update videos set status = $2, status_reason = $3 where id = $1 and status in ($4, $5)`
+		_, err = tx.Exec(ctx, query, vid, int(model.StatusError), upd.Error,
+			int(model.StatusCreated), int(model.StatusUploading))
+	case upd.Error == "":
+		query = `update videos set status = case when status = $2 then $3 else status end, upload_activity_at = $4
+			where id = $1 and status in ($2, $3)`
+		_, err = tx.Exec(ctx, query, vid, int(model.StatusCreated), int(model.StatusUploading), now)
+	}
+	if err != nil {
+		return handleDBErr(err)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return handleDBErr(err)
+	}
+	return nil
+}
+
+// GetImport returns import progress of user's video.
+func (s *Store) GetImport(ctx context.Context, vid, userID string) (*model.ImportStatus, error) {
+	query := `select i.url, i.downloaded, i.done, i.attempts, i.last_error, v.size
+		from media_imports i join videos v on v.id = i.video_id
+		where i.video_id = $1 and v.user_id = $2`
+	var st model.ImportStatus
+	if err := s.Pool().QueryRow(ctx, query, vid, userID).Scan(
+		&st.URL, &st.Downloaded, &st.Done, &st.Attempts, &st.LastError, &st.Size); err != nil {
+		return nil, handleDBErr(err)
+	}
+	return &st, nil
+}
@@ -0,0 +1,796 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/mp4/meta"
+)
+
+// MemoryStore is a video store that keeps everything in process memory.
+// It has the same semantics as database store and is intended
+// for standalone deployments and tests. Data is lost on restart.
+//
+// Stored objects are copied, so callers could not modify them in place,
+// just like with rows fetched from database.
+type MemoryStore struct {
+	videos    map[string]*memoryVideo
+	playlists map[string]*memoryPlaylist
+	purges    []*memoryPurge
+	imports   []*memoryImport
+	stats     map[string]*memoryStats
+	seq       int64
+	mx        sync.Mutex
+}
+
+type memoryVideo struct {
+	uploadActivityAt time.Time
+	sourceExpiresAt  time.Time
+	vi               model.Video
+	parts            []model.Part
+}
+
+type memoryPlaylist struct {
+	pl       model.Playlist
+	videoIDs []string
+}
+
+type memoryPurge struct {
+	nextAttemptAt time.Time
+	lastError     string
+	p             model.Purge
+	done          bool
+}
+
+type memoryImport struct {
+	nextAttemptAt time.Time
+	i             model.Import
+}
+
+type memoryStats struct {
+	retention map[uint]uint64
+	stats     model.ViewStats
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		videos:    make(map[string]*memoryVideo),
+		playlists: make(map[string]*memoryPlaylist),
+		stats:     make(map[string]*memoryStats),
+	}
+}
+
+func (s *MemoryStore) Close() {}
+
+func (s *MemoryStore) Create(_ context.Context, vi *model.Video) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if _, ok := s.videos[vi.ID]; ok {
+		return model.ErrAlreadyExists
+	}
+	mv := &memoryVideo{vi: *vi}
+	mv.vi.UploadInfo = nil
+	mv.vi.ImportURL = ""
+	mv.vi.StatusReason = ""
+	mv.vi.ReprocessLocation = ""
+	if vi.PlaybackMeta != nil {
+		// video is created with known playback meta (i.e. clip)
+		mv.vi.PartSize = 0
+		mv.vi.DirectUpload = false
+		mv.vi.PlaybackMeta = copyMeta(vi.PlaybackMeta)
+	}
+	if vi.UploadInfo != nil {
+		for _, p := range vi.UploadInfo.Parts {
+			mv.parts = append(mv.parts, model.Part{Num: p.Num, Checksum: p.Checksum, Status: p.Status, Size: p.Size})
+		}
+	}
+	s.videos[vi.ID] = mv
+	if vi.ImportURL != "" {
+		s.seq++
+		s.imports = append(s.imports, &memoryImport{
+			nextAttemptAt: time.Now(),
+			i:             model.Import{ID: s.seq, VideoID: vi.ID, URL: vi.ImportURL},
+		})
+	}
+	return nil
+}
+
+func (s *MemoryStore) Get(_ context.Context, id, userID string) (*model.Video, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	mv, ok := s.videos[id]
+	if !ok || mv.vi.UserID != userID {
+		return nil, model.ErrNotFound
+	}
+	vi := mv.video()
+	vi.Name = ""
+	vi.Fingerprint = ""
+	vi.UploadInfo = &model.UploadInfo{Parts: mv.copyParts()}
+	return vi, nil
+}
+
+func (s *MemoryStore) GetAll(_ context.Context, userID string) ([]*model.Video, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	var videos []*model.Video
+	for _, mv := range s.sortedVideos() {
+		if mv.vi.UserID != userID {
+			continue
+		}
+		videos = append(videos, &model.Video{
+			ID:           mv.vi.ID,
+			UserID:       userID,
+			Location:     mv.vi.Location,
+			Status:       mv.vi.Status,
+			StatusReason: mv.vi.StatusReason,
+			Name:         mv.vi.Name,
+			Size:         mv.vi.Size,
+			CreatedAt:    mv.vi.CreatedAt,
+		})
+	}
+	return videos, nil
+}
+
+// Delete deletes video along with its upload parts and queues purge of its media.
+func (s *MemoryStore) Delete(_ context.Context, id, userID string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	mv, ok := s.videos[id]
+	if !ok || mv.vi.UserID != userID {
+		return model.ErrNotFound
+	}
+	s.deleteVideo(id)
+	var clipSourceLocation string
+	if mv.vi.PlaybackMeta != nil && mv.vi.PlaybackMeta.Clip != nil {
+		clipSourceLocation = mv.vi.PlaybackMeta.Clip.SourceLocation
+	}
+	s.queuePurges(id, time.Now(), mv.vi.Location, mv.vi.OutputLocation, mv.vi.ReprocessLocation, clipSourceLocation)
+	return nil
+}
+
+func (s *MemoryStore) Usage(_ context.Context, userID string) (*model.UserUsage, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	usage := &model.UserUsage{}
+	// expired videos are not counted
+	for _, mv := range s.videos {
+		if mv.vi.UserID == userID && mv.vi.Status != model.StatusExpired {
+			usage.Videos++
+			usage.Size += mv.vi.Size
+		}
+	}
+	return usage, nil
+}
+
+// FindDuplicate returns ready video of user with specified content fingerprint.
+func (s *MemoryStore) FindDuplicate(_ context.Context, fingerprint, userID string) (*model.Video, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	var found *memoryVideo
+	for _, mv := range s.sortedVideos() {
+		if mv.vi.Fingerprint == fingerprint && mv.vi.UserID == userID &&
+			(mv.vi.Status == model.StatusReady || mv.vi.Status == model.StatusReprocessing) {
+			found = mv
+			break
+		}
+	}
+	if found == nil {
+		return nil, model.ErrNotFound
+	}
+	return &model.Video{
+		ID:             found.vi.ID,
+		UserID:         found.vi.UserID,
+		Status:         found.vi.Status,
+		Name:           found.vi.Name,
+		Size:           found.vi.Size,
+		OutputLocation: found.vi.OutputLocation,
+		PlaybackMeta:   copyMeta(found.vi.PlaybackMeta),
+		CreatedAt:      found.vi.CreatedAt,
+	}, nil
+}
+
+func (s *MemoryStore) GetListByStatus(_ context.Context, status model.Status) ([]*model.Video, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	var videos []*model.Video
+	for _, mv := range s.sortedVideos() {
+		if mv.vi.Status != status {
+			continue
+		}
+		videos = append(videos, &model.Video{
+			ID:                mv.vi.ID,
+			UserID:            mv.vi.UserID,
+			Status:            status,
+			Location:          mv.vi.Location,
+			OutputLocation:    mv.vi.OutputLocation,
+			ReprocessLocation: mv.vi.ReprocessLocation,
+			Size:              mv.vi.Size,
+			PartSize:          mv.vi.PartSize,
+			CreatedAt:         mv.vi.CreatedAt,
+			UploadInfo:        &model.UploadInfo{Parts: mv.copyParts()},
+		})
+	}
+	if len(videos) == 0 {
+		return nil, model.ErrNotFound
+	}
+	return videos, nil
+}
+
+// Update updates video status and playback meta.
+// Late live update must not turn finished live stream back to live.
+// If video was reprocessed, its output location is switched to new location,
+// and previous output is queued for purge at specified time.
+func (s *MemoryStore) Update(_ context.Context, vi *model.Video, purgeAt time.Time) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	mv, ok := s.videos[vi.ID]
+	if !ok || (mv.vi.Status == model.StatusReady && vi.Status == model.StatusLive) {
+		return model.ErrNotFound
+	}
+	prevOutputLocation := mv.vi.OutputLocation
+	mv.vi.Status = vi.Status
+	mv.vi.PlaybackMeta = copyMeta(vi.PlaybackMeta)
+	if mv.vi.ReprocessLocation != "" {
+		mv.vi.OutputLocation = mv.vi.ReprocessLocation
+		mv.vi.ReprocessLocation = ""
+	}
+	if mv.vi.OutputLocation != prevOutputLocation {
+		s.queuePurges(vi.ID, purgeAt, "", prevOutputLocation)
+	}
+	return nil
+}
+
+// UpdateStatus updates video status. Any status update ends reprocessing,
+// and failed reprocessing returns video to ready state, since previous output is still valid.
+// Output of ended reprocessing is never switched to, so it is queued for purge.
+func (s *MemoryStore) UpdateStatus(_ context.Context, vi *model.Video) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	mv, ok := s.videos[vi.ID]
+	if !ok {
+		return fmt.Errorf("affected rows: 0, expected: 1")
+	}
+	if mv.vi.Status == model.StatusReprocessing && vi.Status == model.StatusError {
+		mv.vi.Status = model.StatusReady
+	} else {
+		mv.vi.Status = vi.Status
+	}
+	reprocessLocation := mv.vi.ReprocessLocation
+	mv.vi.ReprocessLocation = ""
+	s.queuePurges(vi.ID, time.Now(), "", reprocessLocation)
+	return nil
+}
+
+func (s *MemoryStore) UpdatePart(_ context.Context, vid string, part *model.Part) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	mv, ok := s.videos[vid]
+	// Expired upload cannot be continued.
+	if !ok || (mv.vi.Status != model.StatusCreated && mv.vi.Status != model.StatusUploading) {
+		return fmt.Errorf("affected rows: 0, expected: 1")
+	}
+	mv.uploadActivityAt = time.Now()
+	idx := slices.IndexFunc(mv.parts, func(p model.Part) bool { return p.Num == part.Num })
+	if idx < 0 {
+		return nil
+	}
+	p := &mv.parts[idx]
+	// Parts without expected checksum take checksum of uploaded part.
+	if p.Checksum != part.Checksum && p.Checksum != "" {
+		// checksum was not ok
+		p.Status = model.PartStatusInvalid
+		return nil
+	}
+	p.Status = model.PartStatusOK
+	p.Checksum = part.Checksum
+
+	// check if all parts are ok
+	for _, p := range mv.parts {
+		if p.Status != model.PartStatusOK {
+			return nil
+		}
+	}
+	// all parts are ok, update video status
+	mv.vi.Status = model.StatusUploaded
+	return nil
+}
+
+// DeleteUploadedParts deletes upload parts of video and queues purge of its upload location.
+func (s *MemoryStore) DeleteUploadedParts(_ context.Context, vid string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	mv, ok := s.videos[vid]
+	if !ok {
+		return model.ErrNotFound
+	}
+	mv.parts = nil
+	s.queuePurges(vid, time.Now(), mv.vi.Location)
+	return nil
+}
+
+// SetSourceExpiration sets time after which upload parts of video could be deleted.
+// Expiration is set only once, so reprocessing does not prolong source retention.
+func (s *MemoryStore) SetSourceExpiration(_ context.Context, vid string, at time.Time) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	mv, ok := s.videos[vid]
+	if !ok {
+		return fmt.Errorf("affected rows: 0, expected: 1")
+	}
+	if mv.sourceExpiresAt.IsZero() {
+		mv.sourceExpiresAt = at
+	}
+	return nil
+}
+
+// DeleteExpiredSources deletes upload parts of videos which source has expired before specified time
+// and queues purge of their upload locations. Videos that are being reprocessed are skipped.
+// It returns number of videos which sources were deleted.
+func (s *MemoryStore) DeleteExpiredSources(_ context.Context, before time.Time) (int64, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	var n int64
+	for _, mv := range s.videos {
+		if mv.sourceExpiresAt.IsZero() || !mv.sourceExpiresAt.Before(before) ||
+			mv.vi.Status == model.StatusReprocessing {
+			continue
+		}
+		mv.sourceExpiresAt = time.Time{}
+		mv.parts = nil
+		s.queuePurges(mv.vi.ID, before, mv.vi.Location)
+		n++
+	}
+	return n, nil
+}
+
+// ExpireUploads marks videos which upload had no activity since specified time as expired.
+// Upload parts of expired videos are deleted and their upload location is queued for purge.
+// It returns number of expired videos.
+func (s *MemoryStore) ExpireUploads(_ context.Context, before time.Time, reason string) (int64, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	var n int64
+	for _, mv := range s.sortedVideos() {
+		if mv.vi.Status != model.StatusCreated && mv.vi.Status != model.StatusUploading {
+			continue
+		}
+		activity := mv.uploadActivityAt
+		if activity.IsZero() {
+			activity = mv.vi.CreatedAt
+		}
+		if !activity.Before(before) {
+			continue
+		}
+		mv.vi.Status = model.StatusExpired
+		mv.vi.StatusReason = reason
+		mv.parts = nil
+		s.addPurge(mv.vi.ID, time.Now(), model.PurgeKindUpload, mv.vi.Location)
+		n++
+	}
+	return n, nil
+}
+
+// GetReprocessCandidates returns ids of ready videos that still have upload parts
+// and either have one of specified ids or were processed by processor version
+// other than outdatedVersion (if it is not empty).
+func (s *MemoryStore) GetReprocessCandidates(_ context.Context, ids []string, outdatedVersion string) ([]string, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	var candidates []string
+	for _, mv := range s.sortedVideos() {
+		if mv.vi.Status != model.StatusReady || len(mv.parts) == 0 {
+			continue
+		}
+		var version string
+		if mv.vi.PlaybackMeta != nil && mv.vi.PlaybackMeta.Processing != nil {
+			version = mv.vi.PlaybackMeta.Processing.Version
+		}
+		if slices.Contains(ids, mv.vi.ID) || (outdatedVersion != "" && version != outdatedVersion) {
+			candidates = append(candidates, mv.vi.ID)
+		}
+	}
+	return candidates, nil
+}
+
+// StartReprocessing queues ready video for reprocessing into new output location.
+func (s *MemoryStore) StartReprocessing(_ context.Context, vid, location string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	mv, ok := s.videos[vid]
+	if !ok || mv.vi.Status != model.StatusReady || len(mv.parts) == 0 {
+		return fmt.Errorf("affected rows: 0, expected: 1")
+	}
+	mv.vi.Status = model.StatusReprocessing
+	mv.vi.ReprocessLocation = location
+	return nil
+}
+
+// ClaimPurges returns unfinished purges that are due at specified time.
+// Returned purges are not returned again until lease expires.
+func (s *MemoryStore) ClaimPurges(_ context.Context, now time.Time, lease time.Duration, limit uint) ([]*model.Purge, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	due := make([]*memoryPurge, 0, len(s.purges))
+	for _, mp := range s.purges {
+		if !mp.done && !mp.nextAttemptAt.After(now) {
+			due = append(due, mp)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b *memoryPurge) int {
+		return a.nextAttemptAt.Compare(b.nextAttemptAt)
+	})
+	var purges []*model.Purge
+	for _, mp := range due[:min(uint(len(due)), limit)] {
+		mp.nextAttemptAt = now.Add(lease)
+		p := mp.p
+		purges = append(purges, &p)
+	}
+	return purges, nil
+}
+
+// UpdatePurge records purge progress. Unfinished purge lease is prolonged,
+// failed purge is retried after exponential backoff based on amount of previous attempts.
+func (s *MemoryStore) UpdatePurge(
+	_ context.Context,
+	upd *model.PurgeUpdate,
+	now time.Time,
+	lease, backoff, maxBackoff time.Duration,
+) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	idx := slices.IndexFunc(s.purges, func(mp *memoryPurge) bool { return mp.p.ID == upd.ID && !mp.done })
+	if idx < 0 {
+		return fmt.Errorf("affected rows: 0, expected: 1")
+	}
+	mp := s.purges[idx]
+	mp.p.Deleted += upd.Deleted
+	mp.done = upd.Done
+	mp.lastError = upd.Error
+	mp.nextAttemptAt = nextAttempt(now, upd.Error, mp.p.Attempts, lease, backoff, maxBackoff)
+	if upd.Error != "" {
+		mp.p.Attempts++
+	}
+	return nil
+}
+
+// GetPurgeStats returns summary of media purge progress.
+func (s *MemoryStore) GetPurgeStats(_ context.Context) (*model.PurgeStats, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	var stats model.PurgeStats
+	for _, mp := range s.purges {
+		switch {
+		case mp.done:
+			stats.Done++
+		case mp.p.Attempts > 0:
+			stats.Pending++
+			stats.Retrying++
+		default:
+			stats.Pending++
+		}
+		stats.Deleted += mp.p.Deleted
+	}
+	return &stats, nil
+}
+
+// ClaimImports returns unfinished imports of not yet uploaded videos that are due at specified time.
+// Returned imports are not returned again until lease expires.
+func (s *MemoryStore) ClaimImports(_ context.Context, now time.Time, lease time.Duration, limit uint) ([]*model.Import, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	due := make([]*memoryImport, 0, len(s.imports))
+	for _, mi := range s.imports {
+		mv := s.videos[mi.i.VideoID]
+		if !mi.i.Done && !mi.nextAttemptAt.After(now) &&
+			(mv.vi.Status == model.StatusCreated || mv.vi.Status == model.StatusUploading) {
+			due = append(due, mi)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b *memoryImport) int {
+		return a.nextAttemptAt.Compare(b.nextAttemptAt)
+	})
+	var imports []*model.Import
+	for _, mi := range due[:min(uint(len(due)), limit)] {
+		mi.nextAttemptAt = now.Add(lease)
+		mv := s.videos[mi.i.VideoID]
+		imports = append(imports, &model.Import{
+			ID:         mi.i.ID,
+			VideoID:    mi.i.VideoID,
+			URL:        mi.i.URL,
+			Downloaded: mi.i.Downloaded,
+			Attempts:   mi.i.Attempts,
+			Location:   mv.vi.Location,
+			Size:       mv.vi.Size,
+			PartSize:   mv.vi.PartSize,
+		})
+	}
+	return imports, nil
+}
+
+// UpdateImport records import progress. Unfinished import lease is prolonged and
+// video upload activity is updated, so import in progress does not expire.
+// Failed import is retried after exponential backoff based on amount of previous attempts.
+// Fatal failure finishes import and moves video to error state.
+func (s *MemoryStore) UpdateImport(
+	_ context.Context,
+	upd *model.ImportUpdate,
+	now time.Time,
+	lease, backoff, maxBackoff time.Duration,
+) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	idx := slices.IndexFunc(s.imports, func(mi *memoryImport) bool { return mi.i.ID == upd.ID && !mi.i.Done })
+	if idx < 0 {
+		return model.ErrNotFound
+	}
+	mi := s.imports[idx]
+	mi.i.Downloaded = max(mi.i.Downloaded, upd.Downloaded)
+	mi.i.Done = upd.Done || upd.Fatal
+	mi.i.LastError = upd.Error
+	mi.nextAttemptAt = nextAttempt(now, upd.Error, mi.i.Attempts, lease, backoff, maxBackoff)
+	if upd.Error != "" {
+		mi.i.Attempts++
+	}
+	mv := s.videos[mi.i.VideoID]
+	if mv.vi.Status != model.StatusCreated && mv.vi.Status != model.StatusUploading {
+		return nil
+	}
+	switch {
+	case upd.Fatal:
+		mv.vi.Status = model.StatusError
+		mv.vi.StatusReason = upd.Error
+	case upd.Error == "":
+		mv.vi.Status = model.StatusUploading
+		mv.uploadActivityAt = now
+	}
+	return nil
+}
+
+// GetImport returns import progress of user's video.
+func (s *MemoryStore) GetImport(_ context.Context, vid, userID string) (*model.ImportStatus, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	mv, ok := s.videos[vid]
+	if !ok || mv.vi.UserID != userID {
+		return nil, model.ErrNotFound
+	}
+	idx := slices.IndexFunc(s.imports, func(mi *memoryImport) bool { return mi.i.VideoID == vid })
+	if idx < 0 {
+		return nil, model.ErrNotFound
+	}
+	mi := s.imports[idx]
+	return &model.ImportStatus{
+		URL:        mi.i.URL,
+		Downloaded: mi.i.Downloaded,
+		Done:       mi.i.Done,
+		Attempts:   mi.i.Attempts,
+		LastError:  mi.i.LastError,
+		Size:       mv.vi.Size,
+	}, nil
+}
+
+// UpdateViewStats adds view statistics increment to video statistics.
+func (s *MemoryStore) UpdateViewStats(_ context.Context, upd *model.ViewStatsUpdate) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if _, ok := s.videos[upd.VideoID]; !ok {
+		return model.ErrNotFound
+	}
+	ms, ok := s.stats[upd.VideoID]
+	if !ok {
+		ms = &memoryStats{retention: make(map[uint]uint64)}
+		s.stats[upd.VideoID] = ms
+	}
+	ms.stats.Views += upd.Views
+	ms.stats.WatchTimeMS += upd.WatchTimeMS
+	ms.stats.Requests += upd.Requests
+	ms.stats.Bytes += upd.Bytes
+	for seg, views := range upd.Retention {
+		ms.retention[seg] += views
+	}
+	return nil
+}
+
+// GetViewStats returns view statistics of video. Retention is filled up to the last viewed segment.
+// Zero statistics are returned for video that was never watched.
+func (s *MemoryStore) GetViewStats(_ context.Context, vid string) (*model.ViewStats, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	ms, ok := s.stats[vid]
+	if !ok {
+		return &model.ViewStats{}, nil
+	}
+	stats := ms.stats
+	for seg, views := range ms.retention {
+		for uint(len(stats.Retention)) <= seg {
+			stats.Retention = append(stats.Retention, 0)
+		}
+		stats.Retention[seg] = views
+	}
+	return &stats, nil
+}
+
+func (s *MemoryStore) CreatePlaylist(_ context.Context, pl *model.Playlist) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if _, ok := s.playlists[pl.ID]; ok {
+		return model.ErrAlreadyExists
+	}
+	for _, vid := range pl.VideoIDs {
+		if _, ok := s.videos[vid]; !ok {
+			return fmt.Errorf("playlist video does not exist: %s", vid)
+		}
+	}
+	s.playlists[pl.ID] = &memoryPlaylist{
+		pl:       model.Playlist{ID: pl.ID, UserID: pl.UserID, Name: pl.Name, CreatedAt: pl.CreatedAt},
+		videoIDs: slices.Clone(pl.VideoIDs),
+	}
+	return nil
+}
+
+// GetPlaylist returns playlist with all its videos in playback order.
+func (s *MemoryStore) GetPlaylist(_ context.Context, id, userID string) (*model.Playlist, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	mp, ok := s.playlists[id]
+	if !ok || mp.pl.UserID != userID {
+		return nil, model.ErrPlaylistNotFound
+	}
+	pl := mp.pl
+	pl.VideoIDs = slices.Clone(mp.videoIDs)
+	pl.Videos = make([]*model.Video, 0, len(mp.videoIDs))
+	for _, vid := range mp.videoIDs {
+		mv := s.videos[vid]
+		pl.Videos = append(pl.Videos, &model.Video{
+			ID:             mv.vi.ID,
+			UserID:         userID,
+			Name:           mv.vi.Name,
+			Location:       mv.vi.Location,
+			OutputLocation: mv.vi.OutputLocation,
+			Status:         mv.vi.Status,
+			PlaybackMeta:   copyMeta(mv.vi.PlaybackMeta),
+		})
+	}
+	return &pl, nil
+}
+
+func (s *MemoryStore) GetPlaylists(_ context.Context, userID string) ([]*model.Playlist, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	var playlists []*model.Playlist
+	for _, mp := range s.playlists {
+		if mp.pl.UserID != userID {
+			continue
+		}
+		pl := mp.pl
+		pl.VideoIDs = slices.Clone(mp.videoIDs)
+		if pl.VideoIDs == nil {
+			pl.VideoIDs = []string{}
+		}
+		playlists = append(playlists, &pl)
+	}
+	slices.SortFunc(playlists, func(a, b *model.Playlist) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return playlists, nil
+}
+
+func (s *MemoryStore) DeletePlaylist(_ context.Context, id, userID string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	mp, ok := s.playlists[id]
+	if !ok || mp.pl.UserID != userID {
+		return model.ErrPlaylistNotFound
+	}
+	delete(s.playlists, id)
+	return nil
+}
+
+// deleteVideo deletes video and everything that references it.
+func (s *MemoryStore) deleteVideo(id string) {
+	delete(s.videos, id)
+	delete(s.stats, id)
+	s.imports = slices.DeleteFunc(s.imports, func(mi *memoryImport) bool { return mi.i.VideoID == id })
+	for _, mp := range s.playlists {
+		mp.videoIDs = slices.DeleteFunc(mp.videoIDs, func(vid string) bool { return vid == id })
+	}
+}
+
+// queuePurges queues purge of upload location and output locations of video, purges are due at specified time.
+// Output location is queued only if no other video or clip references it. Empty locations are skipped.
+func (s *MemoryStore) queuePurges(vid string, at time.Time, uploadLocation string, outputLocations ...string) {
+	if uploadLocation != "" {
+		s.addPurge(vid, at, model.PurgeKindUpload, uploadLocation)
+	}
+	for _, location := range outputLocations {
+		if location != "" && !s.outputReferenced(location) {
+			s.addPurge(vid, at, model.PurgeKindOutput, location)
+		}
+	}
+}
+
+func (s *MemoryStore) outputReferenced(location string) bool {
+	for _, mv := range s.videos {
+		if mv.vi.OutputLocation == location || mv.vi.ReprocessLocation == location ||
+			(mv.vi.PlaybackMeta != nil && mv.vi.PlaybackMeta.Clip != nil &&
+				mv.vi.PlaybackMeta.Clip.SourceLocation == location) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MemoryStore) addPurge(vid string, at time.Time, kind model.PurgeKind, location string) {
+	s.seq++
+	s.purges = append(s.purges, &memoryPurge{
+		nextAttemptAt: at,
+		p:             model.Purge{ID: s.seq, VideoID: vid, Kind: kind, Location: location},
+	})
+}
+
+// sortedVideos returns videos in creation order, so results are stable.
+func (s *MemoryStore) sortedVideos() []*memoryVideo {
+	videos := make([]*memoryVideo, 0, len(s.videos))
+	for _, mv := range s.videos {
+		videos = append(videos, mv)
+	}
+	slices.SortFunc(videos, func(a, b *memoryVideo) int {
+		if c := a.vi.CreatedAt.Compare(b.vi.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.vi.ID, b.vi.ID)
+	})
+	return videos
+}
+
+func (mv *memoryVideo) video() *model.Video {
+	vi := mv.vi
+	vi.PlaybackMeta = copyMeta(mv.vi.PlaybackMeta)
+	return &vi
+}
+
+func (mv *memoryVideo) copyParts() []*model.Part {
+	parts := make([]*model.Part, 0, len(mv.parts))
+	for _, p := range mv.parts {
+		parts = append(parts, &p)
+	}
+	return parts
+}
+
+// nextAttempt returns time of next attempt of purge or import. Failed attempt is retried
+// after exponential backoff, otherwise lease is prolonged.
+func nextAttempt(now time.Time, errMsg string, attempts int, lease, backoff, maxBackoff time.Duration) time.Time {
+	if errMsg == "" {
+		return now.Add(lease)
+	}
+	delay := math.Min(maxBackoff.Seconds(), backoff.Seconds()*math.Pow(2, float64(attempts))) //nolint:mnd // exponent base
+	return now.Add(time.Duration(delay * float64(time.Second)))
+}
+
+// copyMeta returns copy of playback meta, so stored meta is not modified by callers.
+func copyMeta(mt *meta.Meta) *meta.Meta {
+	if mt == nil {
+		return nil
+	}
+	cp := *mt
+	cp.Tracks = slices.Clone(mt.Tracks)
+	if mt.Live != nil {
+		live := *mt.Live
+		cp.Live = &live
+	}
+	if mt.Clip != nil {
+		clip := *mt.Clip
+		cp.Clip = &clip
+	}
+	if mt.Processing != nil {
+		processing := *mt.Processing
+		cp.Processing = &processing
+	}
+	return &cp
+}
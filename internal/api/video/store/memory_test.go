@@ -0,0 +1,343 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/mp4/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestVideo(id string, createdAt time.Time) *model.Video {
+	return &model.Video{
+		ID:             id,
+		UserID:         "user",
+		Name:           "name-" + id,
+		Status:         model.StatusCreated,
+		Location:       "upload-" + id,
+		OutputLocation: "output-" + id,
+		Size:           200,
+		PartSize:       100,
+		CreatedAt:      createdAt,
+		UploadInfo: &model.UploadInfo{Parts: []*model.Part{
+			{Num: 0, Size: 100, Checksum: "c0"},
+			{Num: 1, Size: 100},
+		}},
+	}
+}
+
+func TestMemoryStore_upload(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	require.NoError(t, s.Create(ctx, newTestVideo("v1", time.Now())))
+	require.ErrorIs(t, s.Create(ctx, newTestVideo("v1", time.Now())), model.ErrAlreadyExists)
+
+	_, err := s.Get(ctx, "v1", "other")
+	require.ErrorIs(t, err, model.ErrNotFound)
+
+	// checksum mismatch
+	require.NoError(t, s.UpdatePart(ctx, "v1", &model.Part{Num: 0, Checksum: "bad"}))
+	require.NoError(t, s.UpdatePart(ctx, "v1", &model.Part{Num: 1, Checksum: "c1"}))
+	vi, err := s.Get(ctx, "v1", "user")
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusCreated, vi.Status)
+	require.Len(t, vi.UploadInfo.Parts, 2)
+	assert.Equal(t, model.PartStatusInvalid, vi.UploadInfo.Parts[0].Status)
+	assert.Equal(t, "c1", vi.UploadInfo.Parts[1].Checksum)
+
+	require.NoError(t, s.UpdatePart(ctx, "v1", &model.Part{Num: 0, Checksum: "c0"}))
+	vi, err = s.Get(ctx, "v1", "user")
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusUploaded, vi.Status)
+	require.Error(t, s.UpdatePart(ctx, "v1", &model.Part{Num: 0, Checksum: "c0"}))
+
+	usage, err := s.Usage(ctx, "user")
+	require.NoError(t, err)
+	assert.Equal(t, &model.UserUsage{Videos: 1, Size: 200}, usage)
+
+	videos, err := s.GetListByStatus(ctx, model.StatusUploaded)
+	require.NoError(t, err)
+	require.Len(t, videos, 1)
+	assert.Equal(t, "v1", videos[0].ID)
+	_, err = s.GetListByStatus(ctx, model.StatusReady)
+	require.ErrorIs(t, err, model.ErrNotFound)
+
+	mt := &meta.Meta{Processing: &meta.ProcessingInfo{Version: "v1"}}
+	require.NoError(t, s.Update(ctx, &model.Video{ID: "v1", Status: model.StatusReady, PlaybackMeta: mt}, time.Now()))
+	mt.Processing.Version = "modified"
+	vi, err = s.Get(ctx, "v1", "user")
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusReady, vi.Status)
+	assert.Equal(t, "v1", vi.PlaybackMeta.Processing.Version)
+
+	// late live update
+	require.ErrorIs(t, s.Update(ctx, &model.Video{ID: "v1", Status: model.StatusLive}, time.Now()),
+		model.ErrNotFound)
+}
+
+func TestMemoryStore_ExpireUploads(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+	require.NoError(t, s.Create(ctx, newTestVideo("old", now.Add(-time.Hour))))
+	require.NoError(t, s.Create(ctx, newTestVideo("active", now.Add(-time.Hour))))
+	require.NoError(t, s.Create(ctx, newTestVideo("new", now)))
+	require.NoError(t, s.UpdatePart(ctx, "active", &model.Part{Num: 1, Checksum: "c1"}))
+
+	n, err := s.ExpireUploads(ctx, now.Add(-time.Minute), "expired")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	vi, err := s.Get(ctx, "old", "user")
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusExpired, vi.Status)
+	assert.Equal(t, "expired", vi.StatusReason)
+	assert.Empty(t, vi.UploadInfo.Parts)
+
+	usage, err := s.Usage(ctx, "user")
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), usage.Videos)
+
+	purges, err := s.ClaimPurges(ctx, time.Now(), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, purges, 1)
+	assert.Equal(t, model.Purge{ID: purges[0].ID, VideoID: "old", Kind: model.PurgeKindUpload, Location: "upload-old"},
+		*purges[0])
+}
+
+func TestMemoryStore_reprocess(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	vi := newTestVideo("v1", time.Now())
+	vi.Status = model.StatusReady
+	vi.PlaybackMeta = &meta.Meta{Processing: &meta.ProcessingInfo{Version: "old"}}
+	require.NoError(t, s.Create(ctx, vi))
+
+	ids, err := s.GetReprocessCandidates(ctx, nil, "new")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1"}, ids)
+
+	require.NoError(t, s.StartReprocessing(ctx, "v1", "output-failed"))
+	require.Error(t, s.StartReprocessing(ctx, "v1", "output-failed"))
+
+	// failed reprocessing keeps previous output and purges its own
+	require.NoError(t, s.UpdateStatus(ctx, &model.Video{ID: "v1", Status: model.StatusError}))
+	got, err := s.Get(ctx, "v1", "user")
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusReady, got.Status)
+	assert.Empty(t, got.ReprocessLocation)
+	purges, err := s.ClaimPurges(ctx, time.Now(), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, purges, 1)
+	assert.Equal(t, model.Purge{ID: purges[0].ID, VideoID: "v1", Kind: model.PurgeKindOutput, Location: "output-failed"},
+		*purges[0])
+
+	// previous output is purged only at specified time
+	require.NoError(t, s.StartReprocessing(ctx, "v1", "output-new"))
+	require.NoError(t, s.Update(ctx, &model.Video{ID: "v1", Status: model.StatusReady, PlaybackMeta: &meta.Meta{}},
+		time.Now().Add(time.Hour)))
+	got, err = s.Get(ctx, "v1", "user")
+	require.NoError(t, err)
+	assert.Equal(t, "output-new", got.OutputLocation)
+
+	purges, err = s.ClaimPurges(ctx, time.Now(), time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, purges)
+	// unfinished purge of failed output is claimed again since its lease has expired
+	purges, err = s.ClaimPurges(ctx, time.Now().Add(2*time.Hour), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, purges, 2)
+	assert.ElementsMatch(t, []string{"output-failed", "output-v1"},
+		[]string{purges[0].Location, purges[1].Location})
+
+	// source retention
+	require.NoError(t, s.SetSourceExpiration(ctx, "v1", time.Now().Add(-time.Minute)))
+	require.NoError(t, s.SetSourceExpiration(ctx, "v1", time.Now().Add(time.Hour)))
+	n, err := s.DeleteExpiredSources(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	ids, err = s.GetReprocessCandidates(ctx, []string{"v1"}, "")
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+	purges, err = s.ClaimPurges(ctx, time.Now(), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, purges, 1)
+	assert.Equal(t, model.Purge{ID: purges[0].ID, VideoID: "v1", Kind: model.PurgeKindUpload, Location: "upload-v1"},
+		*purges[0])
+}
+
+func TestMemoryStore_DeleteUploadedParts(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	require.NoError(t, s.Create(ctx, newTestVideo("v1", time.Now())))
+	require.ErrorIs(t, s.DeleteUploadedParts(ctx, "other"), model.ErrNotFound)
+
+	require.NoError(t, s.DeleteUploadedParts(ctx, "v1"))
+	vi, err := s.Get(ctx, "v1", "user")
+	require.NoError(t, err)
+	assert.Empty(t, vi.UploadInfo.Parts)
+	purges, err := s.ClaimPurges(ctx, time.Now(), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, purges, 1)
+	assert.Equal(t, model.Purge{ID: purges[0].ID, VideoID: "v1", Kind: model.PurgeKindUpload, Location: "upload-v1"},
+		*purges[0])
+}
+
+func TestMemoryStore_Delete(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	src := newTestVideo("src", time.Now())
+	src.Status = model.StatusReady
+	require.NoError(t, s.Create(ctx, src))
+	clip := &model.Video{
+		ID:             "clip",
+		UserID:         "user",
+		Status:         model.StatusReady,
+		OutputLocation: "output-src",
+		PlaybackMeta:   &meta.Meta{Clip: &meta.ClipInfo{SourceLocation: "output-src"}},
+		CreatedAt:      time.Now(),
+	}
+	require.NoError(t, s.Create(ctx, clip))
+	require.NoError(t, s.CreatePlaylist(ctx, &model.Playlist{ID: "pl", UserID: "user", VideoIDs: []string{"src", "clip"}}))
+
+	require.ErrorIs(t, s.Delete(ctx, "src", "other"), model.ErrNotFound)
+	require.NoError(t, s.Delete(ctx, "src", "user"))
+
+	// output is still referenced by clip
+	purges, err := s.ClaimPurges(ctx, time.Now(), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, purges, 1)
+	assert.Equal(t, model.PurgeKindUpload, purges[0].Kind)
+
+	pl, err := s.GetPlaylist(ctx, "pl", "user")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"clip"}, pl.VideoIDs)
+
+	// clip is the last one referencing source output
+	require.NoError(t, s.Delete(ctx, "clip", "user"))
+	purges, err = s.ClaimPurges(ctx, time.Now(), time.Minute, 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, purges)
+	for _, p := range purges {
+		assert.Equal(t, model.Purge{ID: p.ID, VideoID: "clip", Kind: model.PurgeKindOutput, Location: "output-src"}, *p)
+	}
+
+	require.NoError(t, s.DeletePlaylist(ctx, "pl", "user"))
+	require.ErrorIs(t, s.DeletePlaylist(ctx, "pl", "user"), model.ErrPlaylistNotFound)
+}
+
+func TestMemoryStore_purges(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	require.NoError(t, s.Create(ctx, newTestVideo("v1", time.Now())))
+	require.NoError(t, s.Delete(ctx, "v1", "user"))
+
+	now := time.Now()
+	purges, err := s.ClaimPurges(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, purges, 2)
+	// leased
+	leased, err := s.ClaimPurges(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, leased)
+
+	require.NoError(t, s.UpdatePurge(ctx, &model.PurgeUpdate{ID: purges[0].ID, Deleted: 3, Done: true},
+		now, time.Minute, time.Second, time.Minute))
+	require.Error(t, s.UpdatePurge(ctx, &model.PurgeUpdate{ID: purges[0].ID},
+		now, time.Minute, time.Second, time.Minute))
+	require.NoError(t, s.UpdatePurge(ctx, &model.PurgeUpdate{ID: purges[1].ID, Error: "unavailable"},
+		now, time.Minute, time.Second, time.Minute))
+
+	stats, err := s.GetPurgeStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &model.PurgeStats{Pending: 1, Retrying: 1, Done: 1, Deleted: 3}, stats)
+
+	// retried after backoff
+	purges, err = s.ClaimPurges(ctx, now.Add(time.Second), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, purges, 1)
+	assert.Equal(t, 1, purges[0].Attempts)
+}
+
+func TestMemoryStore_imports(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	vi := newTestVideo("v1", time.Now())
+	vi.ImportURL = "http://example.com/video.mp4"
+	require.NoError(t, s.Create(ctx, vi))
+
+	now := time.Now()
+	imports, err := s.ClaimImports(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, imports, 1)
+	assert.Equal(t, "upload-v1", imports[0].Location)
+
+	require.NoError(t, s.UpdateImport(ctx, &model.ImportUpdate{ID: imports[0].ID, Downloaded: 100},
+		now, time.Minute, time.Second, time.Minute))
+	got, err := s.Get(ctx, "v1", "user")
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusUploading, got.Status)
+
+	require.NoError(t, s.UpdateImport(ctx, &model.ImportUpdate{ID: imports[0].ID, Error: "gone", Fatal: true},
+		now, time.Minute, time.Second, time.Minute))
+	st, err := s.GetImport(ctx, "v1", "user")
+	require.NoError(t, err)
+	assert.Equal(t, &model.ImportStatus{
+		URL:        "http://example.com/video.mp4",
+		LastError:  "gone",
+		Size:       200,
+		Downloaded: 100,
+		Attempts:   1,
+		Done:       true,
+	}, st)
+	got, err = s.Get(ctx, "v1", "user")
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusError, got.Status)
+	require.ErrorIs(t, s.UpdateImport(ctx, &model.ImportUpdate{ID: imports[0].ID},
+		now, time.Minute, time.Second, time.Minute), model.ErrNotFound)
+}
+
+func TestMemoryStore_viewStats(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	require.NoError(t, s.Create(ctx, newTestVideo("v1", time.Now())))
+
+	stats, err := s.GetViewStats(ctx, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, &model.ViewStats{}, stats)
+
+	for range 2 {
+		require.NoError(t, s.UpdateViewStats(ctx, &model.ViewStatsUpdate{
+			VideoID:   "v1",
+			Views:     1,
+			Requests:  2,
+			Retention: map[uint]uint64{0: 1, 2: 1},
+		}))
+	}
+	require.ErrorIs(t, s.UpdateViewStats(ctx, &model.ViewStatsUpdate{VideoID: "v2"}), model.ErrNotFound)
+	stats, err = s.GetViewStats(ctx, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, &model.ViewStats{Views: 2, Requests: 4, Retention: []uint64{2, 0, 2}}, stats)
+}
+
+func TestMemoryStore_FindDuplicate(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+	for _, vi := range []*model.Video{
+		{ID: "other", UserID: "other", Fingerprint: "fp", Status: model.StatusReady, CreatedAt: now},
+		{ID: "own", UserID: "user", Fingerprint: "fp", Status: model.StatusReady, CreatedAt: now.Add(time.Second)},
+		{ID: "uploading", UserID: "user", Fingerprint: "fp", Status: model.StatusUploading, CreatedAt: now},
+	} {
+		require.NoError(t, s.Create(ctx, vi))
+	}
+	vi, err := s.FindDuplicate(ctx, "fp", "user")
+	require.NoError(t, err)
+	assert.Equal(t, "own", vi.ID)
+	// videos of other users are never matched
+	_, err = s.FindDuplicate(ctx, "fp", "third")
+	require.ErrorIs(t, err, model.ErrNotFound)
+}
@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE videos DROP CONSTRAINT size_not_negative;
+ALTER TABLE videos ADD CONSTRAINT size_positive CHECK (size > 0);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+
+-- live videos have no uploaded file, so their size is unknown at creation
+ALTER TABLE videos DROP CONSTRAINT size_positive;
+ALTER TABLE videos ADD CONSTRAINT size_not_negative CHECK (size >= 0);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+DROP TABLE playlist_videos;
+DROP TABLE playlists;
+
+COMMIT;
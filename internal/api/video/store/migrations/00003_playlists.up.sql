@@ -0,0 +1,26 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE playlists (
+                      id VARCHAR(50) NOT NULL PRIMARY KEY,
+                      user_id VARCHAR(50) NOT NULL,
+                      name VARCHAR(200) NOT NULL,
+                      created_at timestamptz default current_timestamp,
+                      CONSTRAINT id_not_empty CHECK (id != ''),
+                      CONSTRAINT user_uid_not_empty CHECK (user_id != ''),
+                      CONSTRAINT name_not_empty CHECK (name != '')
+);
+
+CREATE INDEX playlists_user_id ON playlists (user_id);
+
+-- deleted videos are silently removed from playlists
+CREATE TABLE playlist_videos (
+                      playlist_id VARCHAR(50) NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+                      position integer NOT NULL,
+                      video_id VARCHAR(50) NOT NULL REFERENCES videos (id) ON DELETE CASCADE,
+                      CONSTRAINT position_not_negative CHECK (position >= 0),
+                      PRIMARY KEY (playlist_id, position)
+);
+
+CREATE INDEX playlist_videos_video_id ON playlist_videos (video_id);
+
+COMMIT;
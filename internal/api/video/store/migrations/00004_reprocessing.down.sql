@@ -0,0 +1,8 @@
+BEGIN TRANSACTION;
+
+DROP INDEX videos_source_expires_at;
+ALTER TABLE videos DROP COLUMN source_expires_at;
+ALTER TABLE videos DROP COLUMN reprocess_location;
+ALTER TABLE videos DROP COLUMN output_location;
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN TRANSACTION;
+
+-- processed media location, it changes when video is reprocessed
+ALTER TABLE videos ADD COLUMN output_location VARCHAR(100);
+UPDATE videos SET output_location = location;
+ALTER TABLE videos ALTER COLUMN output_location SET NOT NULL;
+
+-- new output location while video is being reprocessed
+ALTER TABLE videos ADD COLUMN reprocess_location VARCHAR(100);
+
+-- upload parts of ready video are deleted after this time
+ALTER TABLE videos ADD COLUMN source_expires_at timestamptz;
+
+CREATE INDEX videos_source_expires_at ON videos (source_expires_at);
+
+COMMIT;
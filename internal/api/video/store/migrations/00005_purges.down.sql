@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+DROP TABLE media_purges;
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN TRANSACTION;
+
+-- media of deleted videos that should be removed from media storage
+CREATE TABLE media_purges (
+                      id bigserial PRIMARY KEY,
+                      video_id VARCHAR(50) NOT NULL,
+                      kind smallint NOT NULL,
+                      location VARCHAR(100) NOT NULL,
+                      done boolean NOT NULL DEFAULT false,
+                      attempts integer NOT NULL DEFAULT 0,
+                      deleted bigint NOT NULL DEFAULT 0,
+                      last_error text NOT NULL DEFAULT '',
+                      next_attempt_at timestamptz NOT NULL DEFAULT current_timestamp,
+                      created_at timestamptz default current_timestamp,
+                      CONSTRAINT location_not_empty CHECK (location != '')
+);
+
+CREATE INDEX media_purges_pending ON media_purges (next_attempt_at) WHERE NOT done;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE videos DROP COLUMN upload_activity_at;
+ALTER TABLE videos DROP COLUMN status_reason;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN TRANSACTION;
+
+-- explains current status, i.e. why upload has expired
+ALTER TABLE videos ADD COLUMN status_reason text NOT NULL DEFAULT '';
+
+-- time of last uploaded part, abandoned uploads are expired based on it
+ALTER TABLE videos ADD COLUMN upload_activity_at timestamptz;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE videos DROP COLUMN part_size;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+-- existing videos were uploaded with default part size
+ALTER TABLE videos ADD COLUMN part_size bigint NOT NULL DEFAULT 10485760;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+DELETE FROM upload_parts WHERE checksum = '';
+ALTER TABLE upload_parts ADD CONSTRAINT checksum_not_empty CHECK (checksum != '');
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+
+-- parts of tus uploaded videos have no expected checksum,
+-- they take checksum of uploaded part
+ALTER TABLE upload_parts DROP CONSTRAINT checksum_not_empty;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+-- parts of such videos are uploaded directly to media store
+ALTER TABLE videos ADD COLUMN direct_upload boolean NOT NULL DEFAULT false;
+
+COMMIT;
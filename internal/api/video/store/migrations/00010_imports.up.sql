@@ -0,0 +1,20 @@
+BEGIN TRANSACTION;
+
+-- remote files that should be downloaded into upload parts of video
+CREATE TABLE media_imports (
+                      id bigserial PRIMARY KEY,
+                      video_id VARCHAR(50) NOT NULL REFERENCES videos (id) ON DELETE CASCADE,
+                      url text NOT NULL,
+                      downloaded bigint NOT NULL DEFAULT 0,
+                      done boolean NOT NULL DEFAULT false,
+                      attempts integer NOT NULL DEFAULT 0,
+                      last_error text NOT NULL DEFAULT '',
+                      next_attempt_at timestamptz NOT NULL DEFAULT current_timestamp,
+                      created_at timestamptz default current_timestamp,
+                      CONSTRAINT url_not_empty CHECK (url != '')
+);
+
+CREATE UNIQUE INDEX media_imports_video_id ON media_imports (video_id);
+CREATE INDEX media_imports_pending ON media_imports (next_attempt_at) WHERE NOT done;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN TRANSACTION;
+
+-- content fingerprint derived from part checksums, used to detect duplicate uploads
+ALTER TABLE videos ADD COLUMN fingerprint VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX videos_fingerprint ON videos (user_id, fingerprint) WHERE fingerprint != '';
+
+COMMIT;
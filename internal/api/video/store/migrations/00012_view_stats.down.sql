@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+DROP TABLE video_retention;
+DROP TABLE video_stats;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN TRANSACTION;
+
+-- view statistics aggregated from streamer reports
+CREATE TABLE video_stats (
+                      video_id VARCHAR(50) NOT NULL PRIMARY KEY REFERENCES videos (id) ON DELETE CASCADE,
+                      views bigint NOT NULL DEFAULT 0,
+                      watch_time_ms bigint NOT NULL DEFAULT 0,
+                      requests bigint NOT NULL DEFAULT 0,
+                      bytes bigint NOT NULL DEFAULT 0,
+                      updated_at timestamptz default current_timestamp
+);
+
+-- audience retention: amount of views of every segment of video
+CREATE TABLE video_retention (
+                      video_id VARCHAR(50) NOT NULL REFERENCES videos (id) ON DELETE CASCADE,
+                      segment integer NOT NULL,
+                      views bigint NOT NULL DEFAULT 0,
+                      PRIMARY KEY (video_id, segment)
+);
+
+COMMIT;
@@ -0,0 +1,94 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/mp4/meta"
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *Store) CreatePlaylist(ctx context.Context, pl *model.Playlist) error {
+	batch := &pgx.Batch{}
+	batch.Queue(`insert into playlists (id, user_id, name, created_at) values ($1, $2, $3, $4)`,
+		pl.ID, pl.UserID, pl.Name, pl.CreatedAt)
+	for i, vid := range pl.VideoIDs {
+		batch.Queue(`insert into playlist_videos (playlist_id, position, video_id) values ($1, $2, $3)`,
+			pl.ID, i, vid)
+	}
+	if err := s.Pool().SendBatch(ctx, batch).Close(); err != nil {
+		return handleDBErr(err)
+	}
+	return nil
+}
+
+// GetPlaylist returns playlist with all its videos in playback order.
+func (s *Store) GetPlaylist(ctx context.Context, id, userID string) (*model.Playlist, error) {
+	pl := &model.Playlist{ID: id, UserID: userID}
+	query := `select name, created_at from playlists where id = $1 and user_id = $2`
+	if err := s.Pool().QueryRow(ctx, query, id, userID).Scan(&pl.Name, &pl.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrPlaylistNotFound
+		}
+		return nil, handleDBErr(err)
+	}
+
+	query = `select v.id, v.name, v.location, v.output_location, v.status, v.playback_meta from playlist_videos pv
+		join videos v on v.id = pv.video_id
+		where pv.playlist_id = $1 order by pv.position`
+	rows, err := s.Pool().Query(ctx, query, id)
+	if err != nil {
+		return nil, handleDBErr(err)
+	}
+	pl.Videos, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (*model.Video, error) {
+		vi := &model.Video{UserID: userID, PlaybackMeta: &meta.Meta{}}
+		if errS := row.Scan(&vi.ID, &vi.Name, &vi.Location, &vi.OutputLocation, &vi.Status, &vi.PlaybackMeta); errS != nil {
+			return nil, fmt.Errorf("error while scanning row: %w", errS)
+		}
+		return vi, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while collecting rows: %w", err)
+	}
+	pl.VideoIDs = make([]string, 0, len(pl.Videos))
+	for _, vi := range pl.Videos {
+		pl.VideoIDs = append(pl.VideoIDs, vi.ID)
+	}
+	return pl, nil
+}
+
+func (s *Store) GetPlaylists(ctx context.Context, userID string) ([]*model.Playlist, error) {
+	query := `select p.id, p.name, p.created_at,
+		coalesce(array_agg(pv.video_id order by pv.position) filter (where pv.video_id is not null), '{}')
+		from playlists p left join playlist_videos pv on pv.playlist_id = p.id
+		where p.user_id = $1 group by p.id`
+	rows, err := s.Pool().Query(ctx, query, userID)
+	if err != nil {
+		return nil, handleDBErr(err)
+	}
+	playlists, errR := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*model.Playlist, error) {
+		pl := &model.Playlist{UserID: userID}
+		if errS := row.Scan(&pl.ID, &pl.Name, &pl.CreatedAt, &pl.VideoIDs); errS != nil {
+			return nil, fmt.Errorf("error while scanning row: %w", errS)
+		}
+		return pl, nil
+	})
+	if errR != nil {
+		return nil, fmt.Errorf("error while collecting rows: %w", errR)
+	}
+	return playlists, nil
+}
+
+func (s *Store) DeletePlaylist(ctx context.Context, id, userID string) error {
+	query := `delete from playlists where id = $1 and user_id = $2`
+	tag, err := s.Pool().Exec(ctx, query, id, userID)
+	if err != nil {
+		return handleDBErr(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrPlaylistNotFound
+	}
+	return nil
+}
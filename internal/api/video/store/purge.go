@@ -0,0 +1,94 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/jackc/pgx/v5"
+)
+
+// queuePurges queues purge of upload location and output locations of video, purges are due at specified time.
+// Output location is queued only if no other video or clip references it. Empty locations are skipped.
+func queuePurges(ctx context.Context, tx pgx.Tx, vid string, at time.Time,
+	uploadLocation string, outputLocations ...string) error {
+	b := &pgx.Batch{}
+	if uploadLocation != "" {
+		b.Queue(`insert into media_purges (video_id, kind, location, next_attempt_at) values ($1, $2, $3, $4)`,
+			vid, int(model.PurgeKindUpload), uploadLocation, at)
+	}
+	for _, location := range outputLocations {
+		if location == "" {
+			continue
+		}
+		b.Queue(`insert into media_purges (video_id, kind, location, next_attempt_at)
+			select $1, $2, $3, $4 where not exists (select 1 from videos
+				where output_location = $3 or reprocess_location = $3
+				or playback_meta->'Clip'->>'SourceLocation' = $3)`,
+			vid, int(model.PurgeKindOutput), location, at)
+	}
+	if b.Len() == 0 {
+		return nil
+	}
+	if err := tx.SendBatch(ctx, b).Close(); err != nil {
+		return handleDBErr(err)
+	}
+	return nil
+}
+
+// ClaimPurges returns unfinished purges that are due at specified time.
+// Returned purges are not returned again until lease expires,
+// so several purgers could work concurrently.
+func (s *Store) ClaimPurges(ctx context.Context, now time.Time, lease time.Duration, limit uint) ([]*model.Purge, error) {
+	query := `update media_purges set next_attempt_at = $2
+		where id in (select id from media_purges where not done and next_attempt_at <= $1
+			order by next_attempt_at limit $3 for update skip locked)
+		returning id, video_id, kind, location, attempts, deleted`
+	rows, err := s.Pool().Query(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, handleDBErr(err)
+	}
+	purges, errR := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*model.Purge, error) {
+		var p model.Purge
+		if errS := row.Scan(&p.ID, &p.VideoID, &p.Kind, &p.Location, &p.Attempts, &p.Deleted); errS != nil {
+			return nil, fmt.Errorf("error while scanning row: %w", errS)
+		}
+		return &p, nil
+	})
+	if errR != nil {
+		return nil, fmt.Errorf("error while collecting rows: %w", errR)
+	}
+	return purges, nil
+}
+
+// UpdatePurge records purge progress. Unfinished purge lease is prolonged,
+// failed purge is retried after exponential backoff based on amount of previous attempts.
+func (s *Store) UpdatePurge(
+	ctx context.Context,
+	upd *model.PurgeUpdate,
+	now time.Time,
+	lease, backoff, maxBackoff time.Duration,
+) error {
+	query := `update media_purges set deleted = deleted + $2, done = $3, last_error = $4,
+		attempts = case when $4 != '' then attempts + 1 else attempts end,
+		next_attempt_at = case when $4 != ''
+			then $5::timestamptz + make_interval(secs => least($7::float8, $6::float8 * power(2, attempts)))
+			else $5::timestamptz + make_interval(secs => $8::float8) end
+		where id = $1 and not done`
+	tag, err := s.Pool().Exec(ctx, query, upd.ID, upd.Deleted, upd.Done, upd.Error, now,
+		backoff.Seconds(), maxBackoff.Seconds(), lease.Seconds())
+	return handleTagOneRowAndErr(&tag, err)
+}
+
+// GetPurgeStats returns summary of media purge progress.
+func (s *Store) GetPurgeStats(ctx context.Context) (*model.PurgeStats, error) {
+	query := `select count(*) filter (where not done), count(*) filter (where not done and attempts > 0),
+		count(*) filter (where done), coalesce(sum(deleted), 0)::bigint from media_purges`
+	var stats model.PurgeStats
+	if err := s.Pool().QueryRow(ctx, query).Scan(
+		&stats.Pending, &stats.Retrying, &stats.Done, &stats.Deleted); err != nil {
+		return nil, handleDBErr(err)
+	}
+	return &stats, nil
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/jackc/pgx/v5"
+)
+
+// SetSourceExpiration sets time after which upload parts of video could be deleted.
+// Expiration is set only once, so reprocessing does not prolong source retention.
+func (s *Store) SetSourceExpiration(ctx context.Context, vid string, at time.Time) error {
+	query := `update videos set source_expires_at = coalesce(source_expires_at, $2) where id = $1`
+	tag, err := s.Pool().Exec(ctx, query, vid, at)
+	return handleTagOneRowAndErr(&tag, err)
+}
+
+// DeleteExpiredSources deletes upload parts of videos which source has expired before specified time
+// and queues purge of their upload locations. Videos that are being reprocessed are skipped.
+// It returns number of videos which sources were deleted.
+func (s *Store) DeleteExpiredSources(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := s.Pool().Begin(ctx)
+	if err != nil {
+		return 0, handleDBErr(err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `update videos set source_expires_at = null
+		where source_expires_at < $1 and status != $2 returning id, location`
+	rows, err := tx.Query(ctx, query, before, int(model.StatusReprocessing))
+	if err != nil {
+		return 0, handleDBErr(err)
+	}
+	expired, errR := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*model.Video, error) {
+		var vi model.Video
+		if errS := row.Scan(&vi.ID, &vi.Location); errS != nil {
+			return nil, fmt.Errorf("error while scanning row: %w", errS)
+		}
+		return &vi, nil
+	})
+	if errR != nil {
+		return 0, fmt.Errorf("error while collecting rows: %w", errR)
+	}
+	for _, vi := range expired {
+		if _, err = tx.Exec(ctx, `delete from upload_parts where video_id = $1`, vi.ID); err != nil {
+			return 0, handleDBErr(err)
+		}
+		if err = queuePurges(ctx, tx, vi.ID, before, vi.Location); err != nil {
+			return 0, err
+		}
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return 0, handleDBErr(err)
+	}
+	return int64(len(expired)), nil
+}
+
+// GetReprocessCandidates returns ids of ready videos that still have upload parts
+// and either have one of specified ids or were processed by processor version
+// other than outdatedVersion (if it is not empty).
+func (s *Store) GetReprocessCandidates(ctx context.Context, ids []string, outdatedVersion string) ([]string, error) {
+	query := `select id from videos v
+		where status = $1 and exists (select 1 from upload_parts p where p.video_id = v.id)
+		and (id = any($2) or ($3 != '' and coalesce(playback_meta->'Processing'->>'Version', '') != $3))`
+	rows, err := s.Pool().Query(ctx, query, int(model.StatusReady), ids, outdatedVersion)
+	if err != nil {
+		return nil, handleDBErr(err)
+	}
+	candidates, errR := pgx.CollectRows(rows, pgx.RowTo[string])
+	if errR != nil {
+		return nil, fmt.Errorf("error while collecting rows: %w", errR)
+	}
+	return candidates, nil
+}
+
+// StartReprocessing queues ready video for reprocessing into new output location.
+func (s *Store) StartReprocessing(ctx context.Context, vid, location string) error {
+	query := `update videos set status = $2, reprocess_location = $3
+		where id = $1 and status = $4 and exists (select 1 from upload_parts p where p.video_id = $1)`
+	tag, err := s.Pool().Exec(ctx, query, vid, int(model.StatusReprocessing), location, int(model.StatusReady))
+	return handleTagOneRowAndErr(&tag, err)
+}
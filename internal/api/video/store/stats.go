@@ -0,0 +1,86 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/jackc/pgx/v5"
+)
+
+// UpdateViewStats adds view statistics increment to video statistics.
+func (s *Store) UpdateViewStats(ctx context.Context, upd *model.ViewStatsUpdate) error {
+	tx, err := s.Pool().Begin(ctx)
+	if err != nil {
+		return handleDBErr(err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `insert into video_stats (video_id, views, watch_time_ms, requests, bytes) values ($1, $2, $3, $4, $5)
+		on conflict (video_id) do update set views = video_stats.views + excluded.views,
+			watch_time_ms = video_stats.watch_time_ms + excluded.watch_time_ms,
+			requests = video_stats.requests + excluded.requests,
+			bytes = video_stats.bytes + excluded.bytes,
+			updated_at = current_timestamp`
+	if _, err = tx.Exec(ctx, query, upd.VideoID, upd.Views, upd.WatchTimeMS, upd.Requests, upd.Bytes); err != nil {
+		return handleDBErr(err)
+	}
+	if len(upd.Retention) > 0 {
+		var (
+			segments = make([]int32, 0, len(upd.Retention))
+			views    = make([]int64, 0, len(upd.Retention))
+		)
+		for seg, v := range upd.Retention {
+			segments = append(segments, int32(seg))
+			views = append(views, int64(v))
+		}
+		query = `insert into video_retention (video_id, segment, views)
+			select $1, r.segment, r.views from unnest($2::integer[], $3::bigint[]) as r(segment, views)
+			on conflict (video_id, segment) do update set views = video_retention.views + excluded.views`
+		if _, err = tx.Exec(ctx, query, upd.VideoID, segments, views); err != nil {
+			return handleDBErr(err)
+		}
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return handleDBErr(err)
+	}
+	return nil
+}
+
+// GetViewStats returns view statistics of video. Retention is filled up to the last viewed segment.
+// Zero statistics are returned for video that was never watched.
+func (s *Store) GetViewStats(ctx context.Context, vid string) (*model.ViewStats, error) {
+	var stats model.ViewStats
+	query := `select views, watch_time_ms, requests, bytes from video_stats where video_id = $1`
+	err := s.Pool().QueryRow(ctx, query, vid).Scan(&stats.Views, &stats.WatchTimeMS, &stats.Requests, &stats.Bytes)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &stats, nil
+		}
+		return nil, handleDBErr(err)
+	}
+	query = `select segment, views from video_retention where video_id = $1 order by segment`
+	rows, err := s.Pool().Query(ctx, query, vid)
+	if err != nil {
+		return nil, handleDBErr(err)
+	}
+	_, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (struct{}, error) {
+		var (
+			seg   uint
+			views uint64
+		)
+		if errS := row.Scan(&seg, &views); errS != nil {
+			return struct{}{}, fmt.Errorf("error while scanning row: %w", errS)
+		}
+		for uint(len(stats.Retention)) <= seg {
+			stats.Retention = append(stats.Retention, 0)
+		}
+		stats.Retention[seg] = views
+		return struct{}{}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while collecting rows: %w", err)
+	}
+	return &stats, nil
+}
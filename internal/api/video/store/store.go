@@ -5,6 +5,7 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/adwski/vidi/internal/api/store"
 	"github.com/adwski/vidi/internal/api/video/model"
@@ -42,20 +43,45 @@ func New(ctx context.Context, cfg *Config) (*Store, error) {
 	}, nil
 }
 
+// DeleteUploadedParts deletes upload parts of video and queues purge of its upload location.
 func (s *Store) DeleteUploadedParts(ctx context.Context, vid string) error {
-	query := `delete from upload_parts where video_id = $1`
-	_, err := s.Pool().Exec(ctx, query, vid)
+	tx, err := s.Pool().Begin(ctx)
 	if err != nil {
 		return handleDBErr(err)
 	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var location string
+	query := `select location from videos where id = $1 for update`
+	if err = tx.QueryRow(ctx, query, vid).Scan(&location); err != nil {
+		return handleDBErr(err)
+	}
+	if _, err = tx.Exec(ctx, `delete from upload_parts where video_id = $1`, vid); err != nil {
+		return handleDBErr(err)
+	}
+	if err = queuePurges(ctx, tx, vid, time.Now(), location); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return handleDBErr(err)
+	}
 	return nil
 }
 
 func (s *Store) UpdatePart(ctx context.Context, vid string, part *model.Part) error {
 	// TODO: may be make all this single pg transaction?
-	// This query actually compares base64 encoded checksum strings, but I guess this is ok
-	query := `update upload_parts set status = $1 where video_id = $2 and num = $3 and checksum = $4`
-	tag, err := s.Pool().Exec(ctx, query, model.PartStatusOK, vid, part.Num, part.Checksum)
+	// Expired upload cannot be continued.
+	query := `update videos set upload_activity_at = now() where id = $1 and status in ($2, $3)`
+	tag, err := s.Pool().Exec(ctx, query, vid, int(model.StatusCreated), int(model.StatusUploading))
+	if err = handleTagOneRowAndErr(&tag, err); err != nil {
+		return err
+	}
+
+	// This query actually compares base64 encoded checksum strings, but I guess this is ok.
+	// Parts without expected checksum take checksum of uploaded part.
+	query = `update upload_parts set status = $1, checksum = $4
+		where video_id = $2 and num = $3 and (checksum = $4 or checksum = '')`
+	tag, err = s.Pool().Exec(ctx, query, model.PartStatusOK, vid, part.Num, part.Checksum)
 	if err != nil {
 		return handleDBErr(err)
 	}
@@ -91,8 +117,10 @@ func (s *Store) UpdatePart(ctx context.Context, vid string, part *model.Part) er
 
 func (s *Store) Usage(ctx context.Context, userID string) (*model.UserUsage, error) {
 	usage := &model.UserUsage{}
-	query := `select count(*) as v_count, coalesce(sum(size), 0) as v_size from videos where user_id = $1`
-	if err := s.Pool().QueryRow(ctx, query, userID).Scan(&usage.Videos, &usage.Size); err != nil {
+	// expired videos are not counted
+	query := `select count(*) as v_count, coalesce(sum(size), 0) as v_size from videos
+		where user_id = $1 and status != $2`
+	if err := s.Pool().QueryRow(ctx, query, userID, int(model.StatusExpired)).Scan(&usage.Videos, &usage.Size); err != nil {
 		return nil, handleDBErr(err)
 	}
 	return usage, nil
@@ -100,12 +128,27 @@ func (s *Store) Usage(ctx context.Context, userID string) (*model.UserUsage, err
 
 func (s *Store) Create(ctx context.Context, vi *model.Video) error {
 	batch := &pgx.Batch{}
-	batch.Queue(`insert into videos (id, user_id, status, created_at, name, size, location)
-		values ($1, $2, $3, $4, $5, $6, $7)`, vi.ID, vi.UserID, int(vi.Status), vi.CreatedAt, vi.Name, vi.Size, vi.Location)
+	if vi.PlaybackMeta != nil {
+		// video is created with known playback meta (i.e. clip)
+		batch.Queue(`insert into videos (id, user_id, status, created_at, name, size, location, output_location,
+			playback_meta, fingerprint)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+			vi.ID, vi.UserID, int(vi.Status), vi.CreatedAt, vi.Name, vi.Size, vi.Location, vi.OutputLocation,
+			vi.PlaybackMeta, vi.Fingerprint)
+	} else {
+		batch.Queue(`insert into videos (id, user_id, status, created_at, name, size, part_size, direct_upload,
+			location, output_location, fingerprint)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+			vi.ID, vi.UserID, int(vi.Status), vi.CreatedAt, vi.Name, vi.Size, vi.PartSize, vi.DirectUpload,
+			vi.Location, vi.OutputLocation, vi.Fingerprint)
+	}
 	for _, p := range vi.UploadInfo.Parts {
 		batch.Queue(`insert into upload_parts (num, video_id, checksum, status, size)
 			values($1, $2, $3, $4, $5)`, p.Num, vi.ID, p.Checksum, p.Status, p.Size)
 	}
+	if vi.ImportURL != "" {
+		batch.Queue(`insert into media_imports (video_id, url) values ($1, $2)`, vi.ID, vi.ImportURL)
+	}
 
 	if err := s.Pool().SendBatch(ctx, batch).Close(); err != nil {
 		return handleDBErr(err)
@@ -115,9 +158,11 @@ func (s *Store) Create(ctx context.Context, vi *model.Video) error {
 
 func (s *Store) Get(ctx context.Context, id, userID string) (*model.Video, error) {
 	vi := &model.Video{ID: id, UserID: userID, PlaybackMeta: &meta.Meta{}}
-	query := `select location, status, playback_meta, created_at from videos where id = $1 and user_id = $2`
-	if err := s.Pool().QueryRow(ctx, query, id, userID).
-		Scan(&vi.Location, &vi.Status, &vi.PlaybackMeta, &vi.CreatedAt); err != nil {
+	query := `select location, output_location, coalesce(reprocess_location, ''), status, status_reason,
+		size, part_size, direct_upload, playback_meta, created_at from videos where id = $1 and user_id = $2`
+	if err := s.Pool().QueryRow(ctx, query, id, userID).Scan(&vi.Location, &vi.OutputLocation,
+		&vi.ReprocessLocation, &vi.Status, &vi.StatusReason, &vi.Size, &vi.PartSize, &vi.DirectUpload,
+		&vi.PlaybackMeta, &vi.CreatedAt); err != nil {
 		return nil, handleDBErr(err)
 	}
 
@@ -147,7 +192,7 @@ func (s *Store) Get(ctx context.Context, id, userID string) (*model.Video, error
 }
 
 func (s *Store) GetAll(ctx context.Context, userID string) ([]*model.Video, error) {
-	query := `select id, location, status, name, size, created_at from videos where user_id = $1`
+	query := `select id, location, status, status_reason, name, size, created_at from videos where user_id = $1`
 	rows, err := s.Pool().Query(ctx, query, userID)
 	if err != nil {
 		return nil, handleDBErr(err)
@@ -155,7 +200,8 @@ func (s *Store) GetAll(ctx context.Context, userID string) ([]*model.Video, erro
 	videos, errR := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*model.Video, error) {
 		var vi model.Video
 		vi.UserID = userID
-		if errS := row.Scan(&vi.ID, &vi.Location, &vi.Status, &vi.Name, &vi.Size, &vi.CreatedAt); errS != nil {
+		if errS := row.Scan(&vi.ID, &vi.Location, &vi.Status, &vi.StatusReason,
+			&vi.Name, &vi.Size, &vi.CreatedAt); errS != nil {
 			return nil, fmt.Errorf("error while scanning row: %w", errS)
 		}
 		return &vi, nil
@@ -166,14 +212,40 @@ func (s *Store) GetAll(ctx context.Context, userID string) ([]*model.Video, erro
 	return videos, nil
 }
 
+// Delete deletes video along with its upload parts and queues purge
+// of its media in the same transaction.
 func (s *Store) Delete(ctx context.Context, id, userID string) error {
-	query := `delete from videos where id = $1 and user_id = $2`
-	tag, err := s.Pool().Exec(ctx, query, id, userID)
-	return handleTagOneRowAndErr(&tag, err)
+	tx, err := s.Pool().Begin(ctx)
+	if err != nil {
+		return handleDBErr(err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var location, outputLocation, reprocessLocation, clipSourceLocation string
+	query := `delete from videos where id = $1 and user_id = $2
+		returning location, output_location, coalesce(reprocess_location, ''),
+		coalesce(playback_meta->'Clip'->>'SourceLocation', '')`
+	if err = tx.QueryRow(ctx, query, id, userID).Scan(
+		&location, &outputLocation, &reprocessLocation, &clipSourceLocation); err != nil {
+		return handleDBErr(err)
+	}
+	if _, err = tx.Exec(ctx, `delete from upload_parts where video_id = $1`, id); err != nil {
+		return handleDBErr(err)
+	}
+	// Clip has no media of its own, but it could be the last one
+	// referencing output of already deleted or reprocessed source.
+	if err = queuePurges(ctx, tx, id, time.Now(), location, outputLocation, reprocessLocation, clipSourceLocation); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return handleDBErr(err)
+	}
+	return nil
 }
 
 func (s *Store) GetListByStatus(ctx context.Context, status model.Status) ([]*model.Video, error) {
-	query := `select id, user_id, location, size, created_at from videos where status = $1`
+	query := `select id, user_id, location, output_location, coalesce(reprocess_location, ''), size, part_size,
+		created_at from videos where status = $1`
 	rows, err := s.Pool().Query(ctx, query, int(status))
 	if err != nil {
 		err = model.ErrNotFound
@@ -182,7 +254,8 @@ func (s *Store) GetListByStatus(ctx context.Context, status model.Status) ([]*mo
 	videos, errR := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*model.Video, error) {
 		var vi model.Video
 		vi.Status = status
-		if errS := row.Scan(&vi.ID, &vi.UserID, &vi.Location, &vi.Size, &vi.CreatedAt); errS != nil {
+		if errS := row.Scan(&vi.ID, &vi.UserID, &vi.Location, &vi.OutputLocation,
+			&vi.ReprocessLocation, &vi.Size, &vi.PartSize, &vi.CreatedAt); errS != nil {
 			return nil, fmt.Errorf("error while scanning row: %w", errS)
 		}
 		return &vi, nil
@@ -217,16 +290,66 @@ func (s *Store) GetListByStatus(ctx context.Context, status model.Status) ([]*mo
 	return videos, nil
 }
 
+// UpdateStatus updates video status. Any status update ends reprocessing,
+// and failed reprocessing returns video to ready state, since previous output is still valid.
+// Output of ended reprocessing is never switched to, so it is queued for purge.
 func (s *Store) UpdateStatus(ctx context.Context, vi *model.Video) error {
-	query := `update videos set status = $2 where id = $1`
-	tag, err := s.Pool().Exec(ctx, query, vi.ID, int(vi.Status))
-	return handleTagOneRowAndErr(&tag, err)
+	tx, err := s.Pool().Begin(ctx)
+	if err != nil {
+		return handleDBErr(err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var reprocessLocation string
+	query := `update videos v set status = case when v.status = $3 and $2 = $4 then $5 else $2 end,
+		reprocess_location = null
+		from (select reprocess_location from videos where id = $1 for update) prev
+		where v.id = $1
+		returning coalesce(prev.reprocess_location, '')`
+	if err = tx.QueryRow(ctx, query, vi.ID, int(vi.Status), int(model.StatusReprocessing),
+		int(model.StatusError), int(model.StatusReady)).Scan(&reprocessLocation); err != nil {
+		return handleDBErr(err)
+	}
+	if err = queuePurges(ctx, tx, vi.ID, time.Now(), "", reprocessLocation); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return handleDBErr(err)
+	}
+	return nil
 }
 
-func (s *Store) Update(ctx context.Context, vi *model.Video) error {
-	query := `update videos set status = $2, playback_meta = $3 where id = $1`
-	tag, err := s.Pool().Exec(ctx, query, vi.ID, int(vi.Status), vi.PlaybackMeta)
-	return handleTagOneRowAndErr(&tag, err)
+// Update updates video status and playback meta.
+// Live updates are delivered asynchronously, so late live update
+// must not turn finished live stream back to live.
+// If video was reprocessed, its output location is switched
+// to new location in the same query along with new playback meta,
+// and previous output is queued for purge at specified time,
+// so playback that has started before switch is not interrupted.
+func (s *Store) Update(ctx context.Context, vi *model.Video, purgeAt time.Time) error {
+	tx, err := s.Pool().Begin(ctx)
+	if err != nil {
+		return handleDBErr(err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var prevOutputLocation string
+	query := `update videos v set status = $2, playback_meta = $3,
+		output_location = coalesce(v.reprocess_location, v.output_location), reprocess_location = null
+		from (select output_location from videos where id = $1 for update) prev
+		where v.id = $1 and not (v.status = $4 and $2 = $5)
+		returning case when v.output_location != prev.output_location then prev.output_location else '' end`
+	if err = tx.QueryRow(ctx, query, vi.ID, int(vi.Status), vi.PlaybackMeta,
+		int(model.StatusReady), int(model.StatusLive)).Scan(&prevOutputLocation); err != nil {
+		return handleDBErr(err)
+	}
+	if err = queuePurges(ctx, tx, vi.ID, purgeAt, "", prevOutputLocation); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return handleDBErr(err)
+	}
+	return nil
 }
 
 func handleTagOneRowAndErr(tag *pgconn.CommandTag, err error) error {
@@ -0,0 +1,124 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	user "github.com/adwski/vidi/internal/api/user/model"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/session"
+	sessionStore "github.com/adwski/vidi/internal/session/store"
+)
+
+// Stream limit policies.
+const (
+	// StreamsReject rejects new watch session if user has maximum amount of active streams.
+	StreamsReject StreamLimitPolicy = iota
+	// StreamsEvictOldest deletes the oldest active streams of user to make room for new one.
+	StreamsEvictOldest
+)
+
+// StreamLimitPolicy defines what happens when user starts more streams than allowed.
+type StreamLimitPolicy int
+
+// StreamStore stores watch sessions and tracks active sessions of users.
+// Session stays active while streamer refreshes it.
+type StreamStore interface {
+	SetLimited(ctx context.Context, sess *session.Session, limit uint, evictOldest bool) error
+	UserSessions(ctx context.Context, userID string) ([]*session.Activity, error)
+}
+
+// StreamsConfig enables tracking of active streams per user.
+type StreamsConfig struct {
+	Store StreamStore
+	// MaxPerUser limits amount of simultaneous streams of user. Zero does not limit streams.
+	MaxPerUser uint
+	// Tiers override MaxPerUser for listed users.
+	Tiers  []StreamTier
+	Policy StreamLimitPolicy
+}
+
+// StreamTier is a stream limit for group of users.
+type StreamTier struct {
+	Name       string
+	Users      []string
+	MaxStreams uint
+}
+
+type streamLimiter struct {
+	store      StreamStore
+	userLimits map[string]uint
+	maxPerUser uint
+	evict      bool
+}
+
+// ParseStreamLimitPolicy parses stream limit policy, it could be "reject" or "evict".
+func ParseStreamLimitPolicy(policy string) (StreamLimitPolicy, error) {
+	switch policy {
+	case "reject", "":
+		return StreamsReject, nil
+	case "evict":
+		return StreamsEvictOldest, nil
+	}
+	return 0, fmt.Errorf("unknown stream limit policy: %s", policy)
+}
+
+func newStreamLimiter(cfg *StreamsConfig) *streamLimiter {
+	if cfg == nil {
+		return nil
+	}
+	sl := &streamLimiter{
+		store:      cfg.Store,
+		maxPerUser: cfg.MaxPerUser,
+		evict:      cfg.Policy == StreamsEvictOldest,
+		userLimits: make(map[string]uint),
+	}
+	for _, tier := range cfg.Tiers {
+		for _, uid := range tier.Users {
+			sl.userLimits[uid] = tier.MaxStreams
+		}
+	}
+	return sl
+}
+
+func (sl *streamLimiter) limit(userID string) uint {
+	if limit, ok := sl.userLimits[userID]; ok {
+		return limit
+	}
+	return sl.maxPerUser
+}
+
+// storeLimitedWatchSession stores watch session if user does not exceed amount of active streams.
+func (svc *Service) storeLimitedWatchSession(ctx context.Context, sess *session.Session) error {
+	err := svc.streams.store.SetLimited(ctx, sess, svc.streams.limit(sess.UserID), svc.streams.evict)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, sessionStore.ErrLimitExceeded):
+		return model.ErrTooManyStreams
+	default:
+		return errors.Join(model.ErrSessionStorage, err)
+	}
+}
+
+// GetStreams returns active streams of user, the least recently active first.
+func (svc *Service) GetStreams(ctx context.Context, usr *user.User) ([]*model.Stream, error) {
+	if svc.streams == nil {
+		return nil, model.ErrStreamsUntracked
+	}
+	activities, err := svc.streams.store.UserSessions(ctx, usr.ID)
+	if err != nil {
+		return nil, errors.Join(model.ErrSessionStorage, err)
+	}
+	streams := make([]*model.Stream, 0, len(activities))
+	for _, a := range activities {
+		streams = append(streams, &model.Stream{
+			SessionID:  a.Session.ID,
+			VideoID:    a.Session.VideoID,
+			PlaylistID: a.Session.PlaylistID,
+			ActiveAt:   a.ActiveAt,
+		})
+	}
+	return streams, nil
+}
@@ -0,0 +1,163 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	usermodel "github.com/adwski/vidi/internal/api/user/model"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/session"
+	sessionStore "github.com/adwski/vidi/internal/session/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type setLimitedCall struct {
+	sess  *session.Session
+	limit uint
+	evict bool
+}
+
+// fakeStreamStore keeps sessions in order of creation and enforces limit like redis store does.
+type fakeStreamStore struct {
+	err      error
+	calls    []setLimitedCall
+	sessions []*session.Session
+}
+
+func (f *fakeStreamStore) SetLimited(_ context.Context, sess *session.Session, limit uint, evict bool) error {
+	f.calls = append(f.calls, setLimitedCall{sess: sess, limit: limit, evict: evict})
+	if f.err != nil {
+		return f.err
+	}
+	if limit > 0 && uint(len(f.sessions)) >= limit {
+		if !evict {
+			return sessionStore.ErrLimitExceeded
+		}
+		f.sessions = f.sessions[uint(len(f.sessions))-limit+1:]
+	}
+	f.sessions = append(f.sessions, sess)
+	return nil
+}
+
+func (f *fakeStreamStore) UserSessions(_ context.Context, _ string) ([]*session.Activity, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	activities := make([]*session.Activity, 0, len(f.sessions))
+	for i, sess := range f.sessions {
+		activities = append(activities, &session.Activity{
+			Session:  sess,
+			ActiveAt: time.UnixMilli(int64(i)),
+		})
+	}
+	return activities, nil
+}
+
+func TestParseStreamLimitPolicy(t *testing.T) {
+	for policy, want := range map[string]StreamLimitPolicy{
+		"":       StreamsReject,
+		"reject": StreamsReject,
+		"evict":  StreamsEvictOldest,
+	} {
+		got, err := ParseStreamLimitPolicy(policy)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseStreamLimitPolicy("drop")
+	require.Error(t, err)
+}
+
+func TestService_WatchVideoStreamLimits(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	v := &model.Video{
+		ID:             "testvid",
+		OutputLocation: "testloc",
+		Status:         model.StatusReady,
+	}
+	for _, tt := range []struct {
+		name    string
+		user    string
+		policy  StreamLimitPolicy
+		limit   uint
+		streams int
+		err     error
+	}{
+		{name: "reject", user: "basic", policy: StreamsReject, limit: 2, streams: 2, err: model.ErrTooManyStreams},
+		{name: "evict", user: "basic", policy: StreamsEvictOldest, limit: 2, streams: 2},
+		{name: "tier", user: "premium", policy: StreamsReject, limit: 3, streams: 3, err: model.ErrTooManyStreams},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewMockStore(t)
+			streams := &fakeStreamStore{}
+			svc := NewService(&ServiceConfig{
+				Logger:         logger,
+				Store:          s,
+				WatchURLPrefix: "http://test",
+				Streams: &StreamsConfig{
+					Store:      streams,
+					MaxPerUser: 2,
+					Policy:     tt.policy,
+					Tiers:      []StreamTier{{Name: "premium", MaxStreams: 3, Users: []string{"premium"}}},
+				},
+			})
+			u := &usermodel.User{ID: tt.user}
+			s.EXPECT().Get(mock.Anything, v.ID, u.ID).Return(v, nil)
+
+			var watchURLs []string
+			for range tt.limit {
+				b, errW := svc.WatchVideo(context.Background(), u, v.ID, true)
+				require.NoError(t, errW)
+				watchURLs = append(watchURLs, string(b))
+			}
+			_, err = svc.WatchVideo(context.Background(), u, v.ID, true)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+			for _, call := range streams.calls {
+				assert.Equal(t, tt.limit, call.limit)
+				assert.Equal(t, tt.policy == StreamsEvictOldest, call.evict)
+				assert.Equal(t, u.ID, call.sess.UserID)
+			}
+
+			active, errS := svc.GetStreams(context.Background(), u)
+			require.NoError(t, errS)
+			require.Len(t, active, tt.streams)
+			for _, st := range active {
+				assert.Equal(t, v.ID, st.VideoID)
+			}
+			if tt.err == nil {
+				// the oldest stream is evicted
+				assert.NotEqual(t, svc.getWatchURL(active[0].SessionID), watchURLs[0])
+			}
+		})
+	}
+}
+
+func TestService_StreamsErrors(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+	u := &usermodel.User{ID: "test"}
+
+	svc := NewService(&ServiceConfig{Logger: logger})
+	_, err = svc.GetStreams(context.Background(), u)
+	require.ErrorIs(t, err, model.ErrStreamsUntracked)
+
+	streams := &fakeStreamStore{err: errors.New("redis is down")}
+	svc = NewService(&ServiceConfig{
+		Logger:  logger,
+		Streams: &StreamsConfig{Store: streams},
+	})
+	_, err = svc.GetStreams(context.Background(), u)
+	require.ErrorIs(t, err, model.ErrSessionStorage)
+	_, err = svc.storeWatchSession(context.Background(), &session.Session{ID: "sess", UserID: u.ID})
+	require.ErrorIs(t, err, model.ErrSessionStorage)
+}
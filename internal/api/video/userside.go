@@ -3,6 +3,7 @@ package video
 import (
 	"context"
 	"errors"
+	"time"
 
 	user "github.com/adwski/vidi/internal/api/user/model"
 	"github.com/adwski/vidi/internal/api/video/model"
@@ -11,7 +12,6 @@ import (
 )
 
 const (
-	defaultPartSize    = 10 * 1024 * 1024
 	videoCreateRetries = 3
 )
 
@@ -38,11 +38,13 @@ func (svc *Service) GetVideo(ctx context.Context, usr *user.User, vid string, re
 		if !video.Resumable() {
 			return nil, model.ErrNotResumable
 		}
-		sess := &session.Session{
-			ID:       video.Location,
-			VideoID:  video.ID,
-			PartSize: defaultPartSize,
+		if video.DirectUpload {
+			if svc.direct == nil {
+				return nil, model.ErrDirectUploadDisabled
+			}
+			return video, svc.presignParts(ctx, video)
 		}
+		sess := newUploadSession(video)
 		if err = svc.uploadSessions.Set(ctx, sess); err != nil {
 			return nil, errors.Join(model.ErrSessionStorage, err)
 		}
@@ -69,11 +71,8 @@ func (svc *Service) WatchVideo(ctx context.Context, usr *user.User, vid string,
 	if err != nil {
 		return nil, errors.Join(model.ErrStorage, err)
 	}
-	if video.IsErrored() {
-		return nil, model.ErrState
-	}
-	if !video.IsReady() {
-		return nil, model.ErrNotReady
+	if err = checkWatchable(video, true); err != nil {
+		return nil, err
 	}
 	var sessID string
 	sessID, err = svc.idGen.Get()
@@ -83,21 +82,64 @@ func (svc *Service) WatchVideo(ctx context.Context, usr *user.User, vid string,
 	sess := &session.Session{
 		ID:       sessID,
 		VideoID:  video.ID,
-		Location: video.Location,
+		UserID:   usr.ID,
+		Location: playbackLocation(video),
 	}
-	if err = svc.watchSessions.Set(ctx, sess); err != nil {
-		return nil, errors.Join(model.ErrSessionStorage, err)
+	watchID, err := svc.storeWatchSession(ctx, sess)
+	if err != nil {
+		return nil, err
 	}
 	if genURL {
-		return []byte(svc.getWatchURL(sess.ID)), nil
+		return []byte(svc.getWatchURL(watchID)), nil
 	}
-	bMPD, err := video.PlaybackMeta.StaticMPD(svc.getWatchBaseURL(sess.ID))
+	bMPD, err := video.PlaybackMeta.MPD(svc.getWatchBaseURL(watchID))
 	if err != nil {
 		return nil, errors.Join(model.ErrInternal, err)
 	}
 	return bMPD, nil
 }
 
+// checkWatchable checks if video could be watched.
+func checkWatchable(video *model.Video, allowLive bool) error {
+	if video.IsErrored() {
+		return model.ErrState
+	}
+	if !video.IsReady() && !(allowLive && video.IsLive()) {
+		return model.ErrNotReady
+	}
+	return nil
+}
+
+// playbackLocation returns location of video segments.
+func playbackLocation(video *model.Video) string {
+	if video.PlaybackMeta != nil && video.PlaybackMeta.Clip != nil {
+		// clip uses segments of source video
+		return video.PlaybackMeta.Clip.SourceLocation
+	}
+	return video.OutputLocation
+}
+
+// newUploadSession creates upload session for video. Session carries expected
+// checksums of parts, so uploader could reject corrupted parts right away.
+// Parts could be created without checksums (i.e. with tus), such parts are
+// accepted with any checksum.
+func newUploadSession(video *model.Video) *session.Session {
+	sess := &session.Session{
+		ID:            video.Location,
+		VideoID:       video.ID,
+		UserID:        video.UserID,
+		PartSize:      video.PartSize,
+		Size:          video.Size,
+		PartChecksums: make([]string, len(video.UploadInfo.Parts)),
+	}
+	for _, p := range video.UploadInfo.Parts {
+		if p.Num < uint(len(sess.PartChecksums)) {
+			sess.PartChecksums[p.Num] = p.Checksum
+		}
+	}
+	return sess
+}
+
 func (svc *Service) DeleteVideo(ctx context.Context, usr *user.User, vid string) error {
 	err := svc.s.Delete(ctx, vid, usr.ID)
 	if err != nil {
@@ -119,19 +161,166 @@ func (svc *Service) CreateVideo(ctx context.Context, usr *user.User, req *model.
 	if len(req.Name) == 0 {
 		return nil, model.ErrNoName
 	}
+	partSize, err := svc.getPartSize(req.PartSize)
+	if err != nil {
+		return nil, err
+	}
+	if !model.PartsMatchSize(len(req.Parts), partSize, req.Size) {
+		return nil, model.ErrPartsMismatch
+	}
+	if req.DirectUpload {
+		if err = svc.checkDirectUpload(req.Parts); err != nil {
+			return nil, err
+		}
+	}
+	fingerprint := model.Fingerprint(req.Size, partSize, req.Parts)
+	alias, err := svc.handleDuplicate(ctx, usr, req.Name, fingerprint)
+	if err != nil || alias != nil {
+		return alias, err
+	}
 	newVideo := model.NewVideoNoID(usr.ID, req.Name, req.Size)
+	newVideo.PartSize = partSize
+	newVideo.Fingerprint = fingerprint
+	newVideo.DirectUpload = req.DirectUpload
 	newVideo.UploadInfo = &model.UploadInfo{
 		Parts: req.Parts,
 	}
 
+	if err = svc.storeNewVideo(ctx, newVideo); err != nil {
+		return nil, err
+	}
+	if newVideo.DirectUpload {
+		// parts are uploaded with presigned urls, no upload session is needed
+		if err = svc.presignParts(ctx, newVideo); err != nil {
+			return nil, err
+		}
+		return newVideo, nil
+	}
+
+	sess := newUploadSession(newVideo)
+	if err = svc.uploadSessions.Set(ctx, sess); err != nil {
+		return nil, errors.Join(model.ErrSessionStorage, err)
+	}
+	newVideo.UploadInfo.URL = svc.getUploadURL(sess.ID)
+	return newVideo, nil
+}
+
+// checkDirectUpload checks that video could be uploaded directly to media store.
+// Uploaded parts are validated only by checksums, so checksums must be provided.
+func (svc *Service) checkDirectUpload(parts []*model.Part) error {
+	if svc.direct == nil {
+		return model.ErrDirectUploadDisabled
+	}
+	for _, p := range parts {
+		if p.Checksum == "" {
+			return model.ErrNoChecksums
+		}
+	}
+	return nil
+}
+
+// getPartSize checks requested part size against configured bounds.
+// If part size is not requested, default part size is used, adjusted to bounds.
+func (svc *Service) getPartSize(requested uint64) (uint64, error) {
+	if requested == 0 {
+		return svc.partSize.adjust(model.DefaultPartSize), nil
+	}
+	if svc.partSize.adjust(requested) != requested {
+		return 0, model.ErrInvalidPartSize
+	}
+	return requested, nil
+}
+
+func (b PartSizeBounds) adjust(partSize uint64) uint64 {
+	if b.Min > 0 && partSize < b.Min {
+		return b.Min
+	}
+	if b.Max > 0 && partSize > b.Max {
+		return b.Max
+	}
+	return partSize
+}
+
+// CreateLiveVideo creates video for live stream and ingest session.
+// Video stays in created state until first segments are ingested,
+// then it becomes live and after stream ends it becomes ready as regular VOD asset.
+func (svc *Service) CreateLiveVideo(ctx context.Context, usr *user.User, name string) (*model.Video, error) {
+	if len(name) == 0 {
+		return nil, model.ErrNoName
+	}
+	newVideo := model.NewVideoNoID(usr.ID, name, 0)
+	newVideo.UploadInfo = &model.UploadInfo{}
+	if err := svc.storeNewVideo(ctx, newVideo); err != nil {
+		return nil, err
+	}
+	sess := &session.Session{
+		ID:       newVideo.Location,
+		VideoID:  newVideo.ID,
+		Location: newVideo.Location,
+	}
+	if err := svc.ingestSessions.Set(ctx, sess); err != nil {
+		return nil, errors.Join(model.ErrSessionStorage, err)
+	}
+	newVideo.UploadInfo.URL = svc.getIngestURL(sess.ID)
+	return newVideo, nil
+}
+
+// CreateClip creates video that references segments of existing ready video.
+// Media is not copied, so clip does not consume size quota.
+func (svc *Service) CreateClip(ctx context.Context, usr *user.User, req *model.CreateClipRequest) (*model.Video, error) {
+	if len(req.Name) == 0 {
+		return nil, model.ErrNoName
+	}
+	source, err := svc.s.Get(ctx, req.SourceID, usr.ID)
+	if err != nil {
+		return nil, errors.Join(model.ErrStorage, err)
+	}
+	if !source.IsReady() {
+		return nil, model.ErrNotReady
+	}
+	clipMeta, err := source.PlaybackMeta.MakeClip(
+		time.Duration(req.StartMs)*time.Millisecond,
+		time.Duration(req.EndMs)*time.Millisecond)
+	if err != nil {
+		return nil, errors.Join(model.ErrInvalidClip, err)
+	}
+	clipMeta.Clip.SourceID = source.ID
+	clipMeta.Clip.SourceLocation = source.OutputLocation
+
+	newVideo := model.NewVideoNoID(usr.ID, req.Name, 0)
+	newVideo.Status = model.StatusReady
+	newVideo.PlaybackMeta = clipMeta
+	newVideo.UploadInfo = &model.UploadInfo{}
+	if err = svc.storeNewVideo(ctx, newVideo); err != nil {
+		return nil, err
+	}
+	newVideo.UploadInfo = nil
+	svc.logger.Debug("clip created",
+		zap.String("vid", newVideo.ID),
+		zap.String("source", source.ID),
+		zap.Duration("duration", clipMeta.Duration))
+	return newVideo, nil
+}
+
+// storeNewVideo generates ids for new video and stores it.
+func (svc *Service) storeNewVideo(ctx context.Context, newVideo *model.Video) error {
+	var (
+		err            error
+		outputLocation = newVideo.OutputLocation
+	)
 	for i := 1; ; i++ {
 		newVideo.ID, err = svc.idGen.Get()
 		if err != nil {
-			return nil, errors.Join(errors.New("cannot generate video id"), err)
+			return errors.Join(errors.New("cannot generate video id"), err)
 		}
 		newVideo.Location, err = svc.idGen.Get()
 		if err != nil {
-			return nil, errors.Join(errors.New("cannot generate location id"), err)
+			return errors.Join(errors.New("cannot generate location id"), err)
+		}
+		newVideo.OutputLocation = newVideo.Location
+		if outputLocation != "" {
+			// video uses existing output
+			newVideo.OutputLocation = outputLocation
 		}
 
 		if err = svc.s.Create(ctx, newVideo); err == nil {
@@ -146,17 +335,7 @@ func (svc *Service) CreateVideo(ctx context.Context, usr *user.User, req *model.
 		break
 	}
 	if err != nil {
-		return nil, errors.Join(model.ErrStorage, err)
-	}
-
-	sess := &session.Session{
-		ID:       newVideo.Location,
-		VideoID:  newVideo.ID,
-		PartSize: defaultPartSize,
-	}
-	if err = svc.uploadSessions.Set(ctx, sess); err != nil {
-		return nil, errors.Join(model.ErrSessionStorage, err)
+		return errors.Join(model.ErrStorage, err)
 	}
-	newVideo.UploadInfo.URL = svc.getUploadURL(sess.ID)
-	return newVideo, nil
+	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	usermodel "github.com/adwski/vidi/internal/api/user/model"
 	"github.com/adwski/vidi/internal/api/video/model"
@@ -144,12 +145,14 @@ func TestService_GetVideoResume(t *testing.T) {
 		ID:         "testvid",
 		Location:   "testloc",
 		Status:     model.StatusCreated,
+		PartSize:   1 << 20,
 		UploadInfo: &model.UploadInfo{},
 	}
 	s.EXPECT().Get(ctx, vid, u.ID).Return(v, nil)
 	ss.EXPECT().Set(ctx, mock.Anything).Run(func(_ context.Context, s *session.Session) {
 		assert.Equal(t, v.Location, s.ID)
 		assert.Equal(t, v.ID, s.VideoID)
+		assert.Equal(t, v.PartSize, s.PartSize)
 	}).Return(nil)
 
 	vi, err := svc.GetVideo(ctx, u, vid, true)
@@ -287,15 +290,16 @@ func TestService_WatchVideoGenURL(t *testing.T) {
 
 	var sessID string
 	v := &model.Video{
-		ID:       "testvid",
-		Location: "testloc",
-		Status:   model.StatusReady,
+		ID:             "testvid",
+		Location:       "testloc",
+		OutputLocation: "testloc",
+		Status:         model.StatusReady,
 	}
 	u := &usermodel.User{ID: "test"}
 	s.EXPECT().Get(ctx, v.ID, u.ID).Return(v, nil)
 	ss.EXPECT().Set(ctx, mock.Anything).Run(func(_ context.Context, sess *session.Session) {
 		sessID = sess.ID
-		assert.Equal(t, v.Location, sess.Location)
+		assert.Equal(t, v.OutputLocation, sess.Location)
 		assert.Equal(t, v.ID, sess.VideoID)
 	}).Return(nil)
 
@@ -332,9 +336,10 @@ func TestService_WatchVideoStaticMPD(t *testing.T) {
 </MPD>`
 	)
 	v := &model.Video{
-		ID:       "testvid",
-		Location: "testloc",
-		Status:   model.StatusReady,
+		ID:             "testvid",
+		Location:       "testloc",
+		OutputLocation: "testloc",
+		Status:         model.StatusReady,
 		PlaybackMeta: &meta.Meta{
 			Tracks: []meta.Track{{
 				Codec: &meta.Codec{
@@ -357,7 +362,7 @@ func TestService_WatchVideoStaticMPD(t *testing.T) {
 	s.EXPECT().Get(ctx, v.ID, u.ID).Return(v, nil)
 	ss.EXPECT().Set(ctx, mock.Anything).Run(func(_ context.Context, sess *session.Session) {
 		sessID = sess.ID
-		assert.Equal(t, v.Location, sess.Location)
+		assert.Equal(t, v.OutputLocation, sess.Location)
 		assert.Equal(t, v.ID, sess.VideoID)
 	}).Return(nil)
 
@@ -381,14 +386,15 @@ func TestService_WatchVideoSessErr(t *testing.T) {
 	})
 
 	v := &model.Video{
-		ID:       "testvid",
-		Location: "testloc",
-		Status:   model.StatusReady,
+		ID:             "testvid",
+		Location:       "testloc",
+		OutputLocation: "testloc",
+		Status:         model.StatusReady,
 	}
 	u := &usermodel.User{ID: "test"}
 	s.EXPECT().Get(ctx, v.ID, u.ID).Return(v, nil)
 	ss.EXPECT().Set(ctx, mock.Anything).Run(func(_ context.Context, sess *session.Session) {
-		assert.Equal(t, v.Location, sess.Location)
+		assert.Equal(t, v.OutputLocation, sess.Location)
 		assert.Equal(t, v.ID, sess.VideoID)
 	}).Return(errors.New("test"))
 
@@ -570,7 +576,8 @@ func TestService_CreateVideoAlreadyExistsSuccess(t *testing.T) {
 		sessID = sess.ID
 		assert.Equal(t, sessID, loc)
 		assert.Equal(t, sess.VideoID, vid)
-		assert.Equal(t, uint64(defaultPartSize), sess.PartSize)
+		assert.Equal(t, uint64(model.DefaultPartSize), sess.PartSize)
+		assert.Equal(t, []string{"checksum"}, sess.PartChecksums)
 	}).Return(nil)
 	v, err := svc.CreateVideo(ctx, u, cr)
 	require.NoError(t, err)
@@ -623,9 +630,331 @@ func TestService_CreateVideoSessStorageErr(t *testing.T) {
 		sessID = sess.ID
 		assert.Equal(t, sessID, loc)
 		assert.Equal(t, sess.VideoID, vid)
-		assert.Equal(t, uint64(defaultPartSize), sess.PartSize)
+		assert.Equal(t, uint64(model.DefaultPartSize), sess.PartSize)
 	}).Return(errors.New("test"))
 	v, err := svc.CreateVideo(ctx, u, cr)
 	require.ErrorIs(t, err, model.ErrSessionStorage)
 	require.Nil(t, v)
 }
+
+func TestService_CreateVideoPartSize(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	ss := NewMockSessionStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger:             logger,
+		Store:              s,
+		UploadSessionStore: ss,
+		UploadURLPrefix:    "http://test",
+		PartSize:           PartSizeBounds{Min: 100, Max: 1000},
+	})
+	u := &usermodel.User{ID: "test"}
+	parts := []*model.Part{{Num: 0, Size: 500}, {Num: 1, Size: 500}, {Num: 2, Size: 1}}
+
+	type args struct {
+		size     uint64
+		partSize uint64
+	}
+	tests := []struct {
+		name     string
+		args     args
+		err      error
+		partSize uint64
+	}{
+		{
+			name:     "requested part size",
+			args:     args{size: 1001, partSize: 500},
+			partSize: 500,
+		},
+		{
+			name:     "default part size adjusted to bounds",
+			args:     args{size: 2001},
+			partSize: 1000,
+		},
+		{
+			name: "part size is too small",
+			args: args{size: 21, partSize: 10},
+			err:  model.ErrInvalidPartSize,
+		},
+		{
+			name: "part size is too large",
+			args: args{size: 2001, partSize: 2000},
+			err:  model.ErrInvalidPartSize,
+		},
+		{
+			name: "parts amount does not match size",
+			args: args{size: 1000, partSize: 500},
+			err:  model.ErrPartsMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &model.CreateRequest{
+				Name:     "test",
+				Size:     tt.args.size,
+				PartSize: tt.args.partSize,
+				Parts:    parts,
+			}
+			if tt.err == nil {
+				s.EXPECT().Create(ctx, mock.Anything).Run(func(_ context.Context, v *model.Video) {
+					assert.Equal(t, tt.partSize, v.PartSize)
+				}).Return(nil).Once()
+				ss.EXPECT().Set(ctx, mock.Anything).Run(func(_ context.Context, sess *session.Session) {
+					assert.Equal(t, tt.partSize, sess.PartSize)
+				}).Return(nil).Once()
+			}
+			v, errC := svc.CreateVideo(ctx, u, cr)
+			if tt.err != nil {
+				require.ErrorIs(t, errC, tt.err)
+				return
+			}
+			require.NoError(t, errC)
+			assert.Equal(t, tt.partSize, v.PartSize)
+		})
+	}
+}
+
+func TestService_CreateLiveVideo(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	ss := NewMockSessionStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger:             logger,
+		Store:              s,
+		IngestSessionStore: ss,
+		IngestURLPrefix:    "http://test/",
+	})
+
+	u := &usermodel.User{ID: "test"}
+	var (
+		vid string
+		loc string
+	)
+	s.EXPECT().Create(ctx, mock.Anything).Run(func(_ context.Context, v *model.Video) {
+		loc = v.Location
+		vid = v.ID
+		assert.Equal(t, "live", v.Name)
+		assert.Equal(t, uint64(0), v.Size)
+		assert.Equal(t, model.StatusCreated, v.Status)
+	}).Return(nil)
+	ss.EXPECT().Set(ctx, mock.Anything).Run(func(_ context.Context, sess *session.Session) {
+		assert.Equal(t, loc, sess.ID)
+		assert.Equal(t, loc, sess.Location)
+		assert.Equal(t, vid, sess.VideoID)
+	}).Return(nil)
+	v, err := svc.CreateLiveVideo(ctx, u, "live")
+	require.NoError(t, err)
+	require.NotNil(t, v.UploadInfo)
+	assert.Equal(t, "http://test/"+loc, v.UploadInfo.URL)
+}
+
+func TestService_CreateLiveVideoNoName(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  NewMockStore(t),
+	})
+	v, err := svc.CreateLiveVideo(context.Background(), &usermodel.User{ID: "test"}, "")
+	require.ErrorIs(t, err, model.ErrNoName)
+	require.Nil(t, v)
+}
+
+func TestService_WatchVideoDynamicMPD(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	ss := NewMockSessionStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger:            logger,
+		Store:             s,
+		WatchSessionStore: ss,
+		WatchURLPrefix:    "http://test",
+	})
+
+	ast := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	v := &model.Video{
+		ID:             "testvid",
+		Location:       "testloc",
+		OutputLocation: "testloc",
+		Status:         model.StatusLive,
+		PlaybackMeta: &meta.Meta{
+			Tracks: []meta.Track{{
+				Codec: &meta.Codec{Profile: "prof1"},
+				Segment: &meta.SegmentConfig{
+					Init:        "init.mp4",
+					StartNumber: 1,
+					Duration:    60000,
+					Timescale:   30000,
+				},
+				Name:     "vide1",
+				MimeType: "video/mp4",
+			}},
+			Live: &meta.LiveInfo{
+				AvailabilityStartTime: ast,
+				PublishTime:           ast.Add(time.Minute),
+				TimeShiftBufferDepth:  time.Minute,
+			},
+		},
+	}
+	u := &usermodel.User{ID: "test"}
+	s.EXPECT().Get(ctx, v.ID, u.ID).Return(v, nil)
+	ss.EXPECT().Set(ctx, mock.Anything).Return(nil)
+
+	b, err := svc.WatchVideo(ctx, u, v.ID, false)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `type="dynamic"`)
+	assert.Contains(t, string(b), `availabilityStartTime="2024-01-02T03:04:05Z"`)
+	assert.Contains(t, string(b), `timeShiftBufferDepth="PT1M"`)
+	assert.Contains(t, string(b), `minimumUpdatePeriod="PT2S"`)
+}
+
+func testSourceVideo() *model.Video {
+	return &model.Video{
+		ID:             "srcvid",
+		Location:       "srcloc",
+		OutputLocation: "srcloc",
+		Status:         model.StatusReady,
+		PlaybackMeta: &meta.Meta{
+			Tracks: []meta.Track{{
+				Codec: &meta.Codec{Profile: "prof1"},
+				Segment: &meta.SegmentConfig{
+					Init:        "init.mp4",
+					StartNumber: 1,
+					Duration:    90000,
+					Timescale:   30000,
+				},
+				Name:     "vide1",
+				MimeType: "video/mp4",
+			}},
+			Duration: time.Minute,
+		},
+	}
+}
+
+func TestService_CreateClip(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  s,
+	})
+
+	u := &usermodel.User{ID: "test"}
+	src := testSourceVideo()
+	s.EXPECT().Get(ctx, src.ID, u.ID).Return(src, nil)
+	s.EXPECT().Create(ctx, mock.Anything).Run(func(_ context.Context, v *model.Video) {
+		assert.Equal(t, "clip", v.Name)
+		assert.Equal(t, uint64(0), v.Size)
+		assert.Equal(t, model.StatusReady, v.Status)
+		assert.NotEqual(t, src.Location, v.Location)
+		require.NotNil(t, v.PlaybackMeta)
+		require.NotNil(t, v.PlaybackMeta.Clip)
+		assert.Equal(t, src.ID, v.PlaybackMeta.Clip.SourceID)
+		assert.Equal(t, src.OutputLocation, v.PlaybackMeta.Clip.SourceLocation)
+		assert.Equal(t, uint(4), v.PlaybackMeta.Tracks[0].Segment.StartNumber)
+		assert.Equal(t, uint(7), v.PlaybackMeta.Tracks[0].Segment.EndNumber)
+	}).Return(nil)
+
+	v, err := svc.CreateClip(ctx, u, &model.CreateClipRequest{
+		SourceID: src.ID,
+		Name:     "clip",
+		StartMs:  10000,
+		EndMs:    20000,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, v.UploadInfo)
+	assert.Equal(t, 11*time.Second, v.PlaybackMeta.Duration)
+}
+
+func TestService_CreateClipErrors(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger: logger,
+		Store:  s,
+	})
+	u := &usermodel.User{ID: "test"}
+
+	_, err = svc.CreateClip(ctx, u, &model.CreateClipRequest{SourceID: "srcvid"})
+	require.ErrorIs(t, err, model.ErrNoName)
+
+	src := testSourceVideo()
+	s.EXPECT().Get(ctx, src.ID, u.ID).Return(src, nil).Once()
+	_, err = svc.CreateClip(ctx, u, &model.CreateClipRequest{
+		SourceID: src.ID,
+		Name:     "clip",
+		StartMs:  20000,
+		EndMs:    10000,
+	})
+	require.ErrorIs(t, err, model.ErrInvalidClip)
+
+	notReady := testSourceVideo()
+	notReady.Status = model.StatusProcessing
+	s.EXPECT().Get(ctx, notReady.ID, u.ID).Return(notReady, nil).Once()
+	_, err = svc.CreateClip(ctx, u, &model.CreateClipRequest{
+		SourceID: notReady.ID,
+		Name:     "clip",
+		EndMs:    10000,
+	})
+	require.ErrorIs(t, err, model.ErrNotReady)
+
+	s.EXPECT().Get(ctx, "notexist", u.ID).Return(nil, model.ErrNotFound).Once()
+	_, err = svc.CreateClip(ctx, u, &model.CreateClipRequest{
+		SourceID: "notexist",
+		Name:     "clip",
+		EndMs:    10000,
+	})
+	require.ErrorIs(t, err, model.ErrNotFound)
+}
+
+func TestService_WatchVideoClip(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	s := NewMockStore(t)
+	ss := NewMockSessionStore(t)
+	svc := NewService(&ServiceConfig{
+		Logger:            logger,
+		Store:             s,
+		WatchSessionStore: ss,
+		WatchURLPrefix:    "http://test",
+	})
+
+	clipMeta, err := testSourceVideo().PlaybackMeta.MakeClip(10*time.Second, 20*time.Second)
+	require.NoError(t, err)
+	clipMeta.Clip.SourceLocation = "srcloc"
+	v := &model.Video{
+		ID:           "clipvid",
+		Location:     "cliploc",
+		Status:       model.StatusReady,
+		PlaybackMeta: clipMeta,
+	}
+	u := &usermodel.User{ID: "test"}
+	s.EXPECT().Get(ctx, v.ID, u.ID).Return(v, nil)
+	ss.EXPECT().Set(ctx, mock.Anything).Run(func(_ context.Context, sess *session.Session) {
+		assert.Equal(t, "srcloc", sess.Location)
+		assert.Equal(t, v.ID, sess.VideoID)
+	}).Return(nil)
+
+	b, err := svc.WatchVideo(ctx, u, v.ID, false)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `presentationTimeOffset="270000"`)
+	assert.Contains(t, string(b), `endNumber="7"`)
+}
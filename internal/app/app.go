@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/adwski/vidi/internal/api/video/model"
 	"github.com/adwski/vidi/internal/logging"
 	"github.com/adwski/vidi/pkg/config"
 	"github.com/spf13/viper"
@@ -33,6 +34,43 @@ const (
 
 	defaultUploadSessionTTL = 300 * time.Second
 	defaultWatchSessionTTL  = 600 * time.Second
+	defaultIngestSessionTTL = 300 * time.Second
+
+	defaultLiveSegmentDuration = 2 * time.Second
+	defaultTimeShiftBuffer     = 60 * time.Second
+	defaultIngestIdleTimeout   = 30 * time.Second
+
+	defaultJanitorPeriod = time.Minute
+	defaultUploadTTL     = 24 * time.Hour
+
+	defaultMaxConcurrentUploads = 64
+	defaultMaxInflightSize      = 256 * 1 << 20
+
+	defaultPurgeCheckInterval = 30 * time.Second
+	defaultPurgeBatchSize     = 10
+
+	defaultMinPartSize = 1 << 20
+	defaultMaxPartSize = 128 * 1 << 20
+
+	defaultDirectUploadURLTTL = time.Hour
+
+	defaultSignedWatchTTL = time.Hour
+
+	defaultMaxStreamsPerUser = 3
+
+	defaultAnalyticsFlushInterval = 30 * time.Second
+
+	defaultSegmentCacheSize     = 512 * 1 << 20
+	defaultSegmentCacheItemSize = 16 * 1 << 20
+	defaultSegmentCacheTTL      = time.Hour
+
+	defaultManifestMetaTTL = 5 * time.Second
+
+	defaultImportMaxSize      = 10 * 1 << 30
+	defaultImportProbeTimeout = 10 * time.Second
+	defaultImportCheckPeriod  = 5 * time.Second
+	defaultImportBatchSize    = 1
+	defaultImportRetries      = 3
 
 	defaultMaxVideos = 100
 	defaultMaxSize   = 10 * 1 << 30
@@ -177,10 +215,13 @@ func (app *App) setConfigDefaults() {
 	// TLS
 	v.SetDefault("server.tls.cert", "")
 	v.SetDefault("server.tls.key", "")
+	// Session store: redis or memory
+	v.SetDefault("session_store.type", "redis")
 	// Redis
 	v.SetDefault("redis.dsn", "redis://localhost:6379/0")
 	v.SetDefault("redis.ttl.upload", defaultUploadSessionTTL)
 	v.SetDefault("redis.ttl.watch", defaultWatchSessionTTL)
+	v.SetDefault("redis.ttl.ingest", defaultIngestSessionTTL)
 	// S3
 	v.SetDefault("s3.prefix.upload", "/")
 	v.SetDefault("s3.prefix.watch", "/")
@@ -189,6 +230,9 @@ func (app *App) setConfigDefaults() {
 	v.SetDefault("s3.access_key", "access-key")
 	v.SetDefault("s3.secret_key", "secret-key")
 	v.SetDefault("s3.ssl", false)
+	// Media store: s3 or file
+	v.SetDefault("media_store.type", "s3")
+	v.SetDefault("media_store.path", "/var/lib/vidi/media")
 	// Video API Client
 	v.SetDefault("videoapi.token", "changeMe")
 	v.SetDefault("videoapi.endpoint", "http://videoapi:8080/api/video")
@@ -205,9 +249,69 @@ func (app *App) setConfigDefaults() {
 	// Processor
 	v.SetDefault("processor.segment_duration", defaultSegmentDuration)
 	v.SetDefault("processor.video_check_period", defaultVideoCheckInterval)
+	// Uploader
+	v.SetDefault("uploader.max_concurrent_uploads", defaultMaxConcurrentUploads)
+	v.SetDefault("uploader.max_inflight_size", defaultMaxInflightSize)
+	v.SetDefault("uploader.tus.enable", false)
+	v.SetDefault("uploader.tus.part_size", model.DefaultPartSize)
+	v.SetDefault("uploader.throttle.enable", false)
+	v.SetDefault("uploader.throttle.session_rate", 0)
+	v.SetDefault("uploader.throttle.user_rate", 0)
+	v.SetDefault("uploader.throttle.burst", 0)
+	v.SetDefault("uploader.throttle.tiers", map[string]any{})
+	// Purger
+	v.SetDefault("purger.check_period", defaultPurgeCheckInterval)
+	v.SetDefault("purger.batch_size", defaultPurgeBatchSize)
+	// Ingest
+	v.SetDefault("ingest.segment_duration", defaultLiveSegmentDuration)
+	v.SetDefault("ingest.timeshift_buffer", defaultTimeShiftBuffer)
+	v.SetDefault("ingest.idle_timeout", defaultIngestIdleTimeout)
 	// Media
 	v.SetDefault("media.user_quota.max_videos", defaultMaxVideos)
 	v.SetDefault("media.user_quota.max_size", defaultMaxSize)
+	v.SetDefault("media.source_retention", "never")
+	v.SetDefault("media.janitor_period", defaultJanitorPeriod)
+	v.SetDefault("media.upload_ttl", defaultUploadTTL)
+	v.SetDefault("media.part_size.min", defaultMinPartSize)
+	v.SetDefault("media.part_size.max", defaultMaxPartSize)
+	v.SetDefault("media.direct_upload.enable", false)
+	v.SetDefault("media.direct_upload.url_ttl", defaultDirectUploadURLTTL)
+	v.SetDefault("media.duplicates.policy", "allow")
+	v.SetDefault("media.watch.signed.enable", false)
+	v.SetDefault("media.watch.signed.ttl", defaultSignedWatchTTL)
+	v.SetDefault("media.watch.signed.bind_client", false)
+	v.SetDefault("media.streams.enable", false)
+	v.SetDefault("media.streams.max_per_user", defaultMaxStreamsPerUser)
+	v.SetDefault("media.streams.policy", "reject")
+	v.SetDefault("media.mpd.store", true)
+	v.SetDefault("media.sessions.index", false)
+	// Streamer
+	v.SetDefault("streamer.signed.enable", false)
+	v.SetDefault("streamer.signed.keys", []string{})
+	v.SetDefault("streamer.signed.client_ip_header", "")
+	v.SetDefault("streamer.analytics.enable", false)
+	v.SetDefault("streamer.analytics.flush_interval", defaultAnalyticsFlushInterval)
+	v.SetDefault("streamer.cache.enable", false)
+	v.SetDefault("streamer.cache.max_size", defaultSegmentCacheSize)
+	v.SetDefault("streamer.cache.max_item_size", defaultSegmentCacheItemSize)
+	v.SetDefault("streamer.cache.ttl", defaultSegmentCacheTTL)
+	v.SetDefault("streamer.cache.stats_path", "")
+	v.SetDefault("streamer.cache_control.segment", "")
+	v.SetDefault("streamer.cache_control.manifest", "")
+	v.SetDefault("streamer.manifests.enable", false)
+	v.SetDefault("streamer.manifests.base_url", "")
+	v.SetDefault("streamer.manifests.meta_ttl", defaultManifestMetaTTL)
+	// Import
+	v.SetDefault("import.enable", false)
+	v.SetDefault("import.allowed_hosts", []string{})
+	v.SetDefault("import.max_size", defaultImportMaxSize)
+	v.SetDefault("import.probe_timeout", defaultImportProbeTimeout)
+	v.SetDefault("import.check_period", defaultImportCheckPeriod)
+	v.SetDefault("import.batch_size", defaultImportBatchSize)
+	v.SetDefault("import.retries", defaultImportRetries)
+	// Standalone
+	v.SetDefault("standalone.public_url", "http://localhost:8080")
+	v.SetDefault("standalone.media_path", "media")
 
 	v.SetEnvPrefix(envPrefix)
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
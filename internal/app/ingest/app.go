@@ -0,0 +1,88 @@
+package ingest
+
+import (
+	"context"
+
+	"github.com/adwski/vidi/internal/app"
+	"github.com/adwski/vidi/internal/event/notificator"
+	"github.com/adwski/vidi/internal/media/ingest"
+	"github.com/adwski/vidi/internal/media/server"
+	"github.com/adwski/vidi/internal/media/store"
+	"github.com/adwski/vidi/internal/session"
+	sessionStore "github.com/adwski/vidi/internal/session/store"
+	"go.uber.org/zap"
+)
+
+type App struct {
+	*app.App
+}
+
+func NewApp() *App {
+	a := &App{}
+	a.App = app.New(a.configure)
+	return a
+}
+
+func (a *App) configure(_ context.Context) ([]app.Runner, []app.Closer, bool) {
+	var (
+		logger = a.Logger()
+		v      = a.Viper()
+	)
+
+	ingestCfg := &ingest.Config{
+		Logger:               logger,
+		URIPathPrefix:        v.GetURIPrefix("api.prefix"),
+		OutputPathPrefix:     v.GetURIPrefix("s3.prefix.watch"),
+		SegmentDuration:      v.GetDuration("ingest.segment_duration"),
+		TimeShiftBufferDepth: v.GetDuration("ingest.timeshift_buffer"),
+		IdleTimeout:          v.GetDuration("ingest.idle_timeout"),
+		SkipMPD:              !v.GetBool("media.mpd.store"),
+	}
+	storageCfg := a.MediaStoreConfig(true)
+	notificatorCfg := &notificator.Config{
+		Logger:        logger,
+		VideoAPIURL:   v.GetURL("videoapi.endpoint"),
+		VideoAPIToken: v.GetString("videoapi.token"),
+	}
+	srvCfg := &server.Config{
+		Logger:        logger,
+		ListenAddress: v.GetString("server.http.address"),
+		ReadTimeout:   v.GetDuration("server.http.timeouts.read"),
+		WriteTimeout:  v.GetDuration("server.http.timeouts.write"),
+		IdleTimeout:   v.GetDuration("server.http.timeouts.idle"),
+		MaxBodySize:   v.GetUint("server.http.max_body_size"),
+	}
+	sessionStoreCfg := a.SessionStoreConfig(session.KindIngest)
+	if v.HasErrors() {
+		for param, errP := range v.Errors() {
+			logger.Error("configuration error", zap.String("param", param), zap.Error(errP))
+		}
+		return nil, nil, false
+	}
+	sessStore, errSS := sessionStore.New(sessionStoreCfg)
+	if errSS != nil {
+		logger.Error("cannot configure session store", zap.Error(errSS))
+		return nil, nil, false
+	}
+	ingestCfg.SessionStorage = sessStore
+
+	mediaStore, err := store.New(storageCfg)
+	if err != nil {
+		logger.Error("cannot create media store", zap.Error(err))
+		return nil, nil, false
+	}
+	ingestCfg.MediaStore = mediaStore
+
+	ingestCfg.Notificator, err = notificator.New(notificatorCfg)
+	if err != nil {
+		logger.Error("cannot create notificator", zap.Error(err))
+		return nil, nil, false
+	}
+	ingestSvc, errIn := ingest.New(ingestCfg)
+	if errIn != nil {
+		logger.Error("cannot create ingest service", zap.Error(errIn))
+		return nil, nil, false
+	}
+	srvCfg.Handler = ingestSvc.Handler()
+	return []app.Runner{server.New(srvCfg), ingestSvc, ingestCfg.Notificator}, []app.Closer{sessStore}, true
+}
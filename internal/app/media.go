@@ -0,0 +1,25 @@
+package app
+
+import (
+	"github.com/adwski/vidi/internal/media/store"
+	"github.com/adwski/vidi/internal/media/store/s3"
+)
+
+// MediaStoreConfig returns media store config gathered from app config.
+// Bucket is created only by apps that write media.
+func (app *App) MediaStoreConfig(createBucket bool) *store.Config {
+	v := app.Viper()
+	return &store.Config{
+		Logger: app.Logger(),
+		Type:   v.GetString("media_store.type"),
+		Path:   v.GetString("media_store.path"),
+		S3: &s3.StoreConfig{
+			Endpoint:     v.GetString("s3.endpoint"),
+			AccessKey:    v.GetString("s3.access_key"),
+			SecretKey:    v.GetString("s3.secret_key"),
+			Bucket:       v.GetString("s3.bucket"),
+			SSL:          v.GetBool("s3.ssl"),
+			CreateBucket: createBucket,
+		},
+	}
+}
@@ -7,7 +7,8 @@ import (
 
 	"github.com/adwski/vidi/internal/app"
 	"github.com/adwski/vidi/internal/media/processor"
-	"github.com/adwski/vidi/internal/media/store/s3"
+	"github.com/adwski/vidi/internal/media/purger"
+	"github.com/adwski/vidi/internal/media/store"
 	"go.uber.org/zap"
 )
 
@@ -34,15 +35,18 @@ func (a *App) configure(_ context.Context) ([]app.Runner, []app.Closer, bool) {
 		OutputPathPrefix: v.GetURIPrefix("s3.prefix.watch"),
 		SegmentDuration:  v.GetDuration("processor.segment_duration"),
 		VideoCheckPeriod: v.GetDuration("processor.video_check_period"),
+		SkipMPD:          !v.GetBool("media.mpd.store"),
 	}
-	storageCfg := &s3.StoreConfig{
-		Logger:    logger,
-		Endpoint:  v.GetString("s3.endpoint"),
-		AccessKey: v.GetString("s3.access_key"),
-		SecretKey: v.GetString("s3.secret_key"),
-		Bucket:    v.GetString("s3.bucket"),
-		SSL:       v.GetBool("s3.ssl"),
+	purgerCfg := &purger.Config{
+		Logger:           logger,
+		VideoAPIEndpoint: v.GetURL("videoapi.endpoint"),
+		VideoAPIToken:    v.GetString("videoapi.token"),
+		InputPathPrefix:  v.GetURIPrefix("s3.prefix.upload"),
+		OutputPathPrefix: v.GetURIPrefix("s3.prefix.watch"),
+		CheckPeriod:      v.GetDuration("purger.check_period"),
+		BatchSize:        v.GetUint("purger.batch_size"),
 	}
+	storageCfg := a.MediaStoreConfig(false)
 	notificatorCfg := &notificator.Config{
 		Logger:        logger,
 		VideoAPIURL:   v.GetURL("videoapi.endpoint"),
@@ -54,9 +58,9 @@ func (a *App) configure(_ context.Context) ([]app.Runner, []app.Closer, bool) {
 		}
 		return nil, nil, false
 	}
-	store, err := s3.NewStore(storageCfg)
+	mediaStore, err := store.New(storageCfg)
 	if err != nil {
-		logger.Error("cannot create s3 storage", zap.Error(err))
+		logger.Error("cannot create media store", zap.Error(err))
 		return nil, nil, false
 	}
 	processorCfg.Notificator, err = notificator.New(notificatorCfg)
@@ -64,11 +68,17 @@ func (a *App) configure(_ context.Context) ([]app.Runner, []app.Closer, bool) {
 		logger.Error("cannot create notificator", zap.Error(err))
 		return nil, nil, false
 	}
-	processorCfg.Store = store
+	processorCfg.Store = mediaStore
 	proc, err := processor.New(processorCfg)
 	if err != nil {
 		logger.Error("cannot create processor", zap.Error(err))
 		return nil, nil, false
 	}
-	return []app.Runner{proc, processorCfg.Notificator}, nil, true
+	purgerCfg.Store = mediaStore
+	purg, err := purger.New(purgerCfg)
+	if err != nil {
+		logger.Error("cannot create purger", zap.Error(err))
+		return nil, nil, false
+	}
+	return []app.Runner{proc, purg, processorCfg.Notificator}, nil, true
 }
@@ -0,0 +1,21 @@
+package app
+
+import (
+	sessionStore "github.com/adwski/vidi/internal/session/store"
+)
+
+// SessionStoreConfig returns config of session store of given kind gathered from app config.
+// Redis DSN is required only by redis session store.
+func (app *App) SessionStoreConfig(kind string) *sessionStore.Config {
+	v := app.Viper()
+	cfg := &sessionStore.Config{
+		Logger: app.Logger(),
+		Name:   kind,
+		Type:   v.GetString("session_store.type"),
+		TTL:    v.GetDuration("redis.ttl." + kind),
+	}
+	if cfg.Type == sessionStore.TypeRedis {
+		cfg.RedisDSN = v.GetURL("redis.dsn")
+	}
+	return cfg
+}
@@ -0,0 +1,413 @@
+// Package standalone contains all-in-one application that runs userapi, videoapi,
+// uploader, streamer and processor in single process.
+//
+// Users, videos and sessions are kept in memory and media is stored in local
+// file system, so no external services are required. It is intended for local
+// development and testing, all data except media files is lost on restart.
+package standalone
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	httpserver "github.com/adwski/vidi/internal/api/http/server"
+	"github.com/adwski/vidi/internal/api/user"
+	"github.com/adwski/vidi/internal/api/user/auth"
+	userStore "github.com/adwski/vidi/internal/api/user/store"
+	"github.com/adwski/vidi/internal/api/video"
+	"github.com/adwski/vidi/internal/api/video/grpc"
+	"github.com/adwski/vidi/internal/api/video/grpc/serviceside"
+	"github.com/adwski/vidi/internal/api/video/grpc/userside"
+	videoServer "github.com/adwski/vidi/internal/api/video/http/server"
+	videoStore "github.com/adwski/vidi/internal/api/video/store"
+	"github.com/adwski/vidi/internal/app"
+	"github.com/adwski/vidi/internal/event/notificator"
+	"github.com/adwski/vidi/internal/media/processor"
+	"github.com/adwski/vidi/internal/media/purger"
+	"github.com/adwski/vidi/internal/media/server"
+	mediaStore "github.com/adwski/vidi/internal/media/store"
+	"github.com/adwski/vidi/internal/media/streamer"
+	"github.com/adwski/vidi/internal/media/uploader"
+	"github.com/adwski/vidi/internal/session"
+	sessionStore "github.com/adwski/vidi/internal/session/store"
+	"go.uber.org/zap"
+)
+
+const (
+	minTLSVersion = tls.VersionTLS13
+
+	// Path prefixes of media services, they are the same as in nginx config.
+	uploadURIPrefix = "/upload"
+	watchURIPrefix  = "/watch"
+
+	// serviceName is used in service token of internal videoapi clients.
+	serviceName = "standalone"
+	// serviceTokenExpiration is long enough to outlive any standalone process.
+	serviceTokenExpiration = 365 * 24 * time.Hour
+)
+
+type App struct {
+	*app.App
+}
+
+func NewApp() *App {
+	a := &App{}
+	a.App = app.New(a.configure)
+	return a
+}
+
+func (a *App) configure(_ context.Context) ([]app.Runner, []app.Closer, bool) {
+	var (
+		logger = a.Logger()
+		v      = a.Viper()
+	)
+	// --------------------------------------
+	// Gather config params
+	// --------------------------------------
+	apiPrefix := v.GetURIPrefix("api.prefix")
+	publicURL := v.GetURL("standalone.public_url")
+	svcCfg := &video.ServiceConfig{
+		Logger: logger,
+		Quotas: video.Quotas{
+			VideosPerUser: v.GetUint("media.user_quota.max_videos"),
+			MaxTotalSize:  v.GetUint64("media.user_quota.max_size"),
+		},
+		UploadTTL: v.GetDuration("media.upload_ttl"),
+		// previous output of reprocessed video is kept while watch sessions could refer to it
+		OutputPurgeLag: v.GetDuration("redis.ttl.watch"),
+		PartSize: video.PartSizeBounds{
+			Min: v.GetUint64("media.part_size.min"),
+			Max: v.GetUint64("media.part_size.max"),
+		},
+	}
+	sourceRetention, errRet := video.ParseSourceRetention(v.GetString("media.source_retention"))
+	if errRet != nil {
+		logger.Error("configuration error", zap.String("param", "media.source_retention"), zap.Error(errRet))
+		return nil, nil, false
+	}
+	svcCfg.SourceRetention = sourceRetention
+	duplicatePolicy, errDup := video.ParseDuplicatePolicy(v.GetString("media.duplicates.policy"))
+	if errDup != nil {
+		logger.Error("configuration error", zap.String("param", "media.duplicates.policy"), zap.Error(errDup))
+		return nil, nil, false
+	}
+	svcCfg.Duplicates = video.DuplicatesConfig{Policy: duplicatePolicy}
+	janitorPeriod := v.GetDuration("media.janitor_period")
+	authCfg := auth.Config{
+		Secret:       v.GetString("auth.jwt.secret"),
+		Expiration:   v.GetDuration("auth.jwt.expiration"),
+		Domain:       v.GetString("domain"),
+		SecureCookie: v.GetBool("https.enable"),
+	}
+	srvCfg := &server.Config{
+		Logger:        logger,
+		ListenAddress: v.GetString("server.http.address"),
+		ReadTimeout:   v.GetDuration("server.http.timeouts.read"),
+		WriteTimeout:  v.GetDuration("server.http.timeouts.write"),
+		IdleTimeout:   v.GetDuration("server.http.timeouts.idle"),
+		MaxBodySize:   v.GetUint("server.http.max_body_size"),
+
+		// uploads are streamed, api requests are read entirely by adaptor
+		StreamRequestBody: true,
+	}
+	gUserSrvCfg := &grpc.Config{
+		Logger:     logger,
+		ListenAddr: v.GetString("server.grpc.address"),
+		Reflection: v.GetBool("server.grpc.reflection"),
+	}
+	// serviceside api is used by media services of this process only, so it is never tls
+	gServiceSrvCfg := &grpc.Config{
+		Logger:     logger,
+		ListenAddr: v.GetString("server.grpc.svc_address"),
+		Reflection: v.GetBool("server.grpc.reflection"),
+	}
+	grpcTLSEnableUsr := v.GetBool("server.grpc.tls_userside_enable")
+	var tlsKeyPath, tlsCertPath string
+	if grpcTLSEnableUsr {
+		tlsCertPath = v.GetString("server.tls.cert")
+		tlsKeyPath = v.GetString("server.tls.key")
+	}
+	indexSessions := v.GetBool("media.sessions.index")
+	sessionStoreCfgs := map[string]*sessionStore.Config{
+		session.KindUpload: a.SessionStoreConfig(session.KindUpload),
+		session.KindWatch:  a.SessionStoreConfig(session.KindWatch),
+		session.KindIngest: a.SessionStoreConfig(session.KindIngest),
+	}
+	for kind, cfg := range sessionStoreCfgs {
+		cfg.Type = sessionStore.TypeMemory
+		cfg.Index = indexSessions && kind != session.KindIngest
+	}
+	mediaStoreCfg := &mediaStore.Config{
+		Logger: logger,
+		Type:   mediaStore.TypeFile,
+		Path:   v.GetString("standalone.media_path"),
+	}
+	uploadPathPrefix := v.GetURIPrefix("s3.prefix.upload")
+	watchPathPrefix := v.GetURIPrefix("s3.prefix.watch")
+	segmentDuration := v.GetDuration("processor.segment_duration")
+	videoCheckPeriod := v.GetDuration("processor.video_check_period")
+	skipMPD := !v.GetBool("media.mpd.store")
+	purgeCheckPeriod := v.GetDuration("purger.check_period")
+	purgeBatchSize := v.GetUint("purger.batch_size")
+	maxConcurrentUploads := v.GetUint("uploader.max_concurrent_uploads")
+	maxInflightSize := v.GetUint64("uploader.max_inflight_size")
+	var corsConfig *streamer.CORSConfig
+	if v.GetBoolNoError("cors.enable") {
+		corsConfig = &streamer.CORSConfig{AllowOrigin: v.GetString("cors.allow_origin")}
+	}
+	if v.HasErrors() {
+		for param, errP := range v.Errors() {
+			logger.Error("configuration error", zap.String("param", param), zap.Error(errP))
+		}
+		return nil, nil, false
+	}
+	publicURL = strings.TrimRight(publicURL, "/")
+	svcCfg.UploadURLPrefix = publicURL + uploadURIPrefix
+	svcCfg.WatchURLPrefix = publicURL + watchURIPrefix
+	videoAPIEndpoint, errEP := localEndpoint(gServiceSrvCfg.ListenAddr)
+	if errEP != nil {
+		logger.Error("configuration error", zap.String("param", "server.grpc.svc_address"), zap.Error(errEP))
+		return nil, nil, false
+	}
+
+	// --------------------------------------
+	// Spawn application entities
+	// --------------------------------------
+	// tls config
+	if grpcTLSEnableUsr {
+		cert, err := tls.LoadX509KeyPair(tlsCertPath, tlsKeyPath)
+		if err != nil {
+			logger.Error("cannot create tls config", zap.Error(err))
+			return nil, nil, false
+		}
+		gUserSrvCfg.TLSConfig = &tls.Config{
+			MinVersion:   minTLSVersion,
+			Certificates: []tls.Certificate{cert},
+		}
+	}
+
+	// authenticator
+	authenticator, errAuth := auth.NewAuth(&authCfg)
+	if errAuth != nil {
+		logger.Error("could not configure authenticator", zap.Error(errAuth))
+		return nil, nil, false
+	}
+	gUserSrvCfg.Auth = authenticator
+	gServiceSrvCfg.Auth = authenticator
+
+	// token of internal videoapi clients
+	videoAPIToken, errToken := newServiceToken(authCfg.Secret)
+	if errToken != nil {
+		logger.Error("could not create service token", zap.Error(errToken))
+		return nil, nil, false
+	}
+
+	// in-memory storages
+	userStorage := userStore.NewMemoryStore()
+	videoStorage := videoStore.NewMemoryStore()
+	svcCfg.Store = videoStorage
+	closers := []app.Closer{userStorage, videoStorage}
+	runners := make([]app.Runner, 0, 12) //nolint:mnd // every service of standalone app
+
+	// session storages, memory session store drops expired sessions while running
+	sessStores := make(map[string]sessionStore.SessionStore, len(sessionStoreCfgs))
+	for kind, cfg := range sessionStoreCfgs {
+		sessStore, errSS := sessionStore.New(cfg)
+		if errSS != nil {
+			logger.Error("cannot configure session store", zap.String("kind", kind), zap.Error(errSS))
+			return nil, nil, false
+		}
+		sessStores[kind] = sessStore
+		closers = append(closers, sessStore)
+		runners = append(runners, sessStore)
+	}
+	svcCfg.UploadSessionStore = sessStores[session.KindUpload]
+	svcCfg.WatchSessionStore = sessStores[session.KindWatch]
+	svcCfg.IngestSessionStore = sessStores[session.KindIngest]
+	if indexSessions {
+		svcCfg.Sessions = &video.SessionsConfig{
+			Upload: sessStores[session.KindUpload],
+			Watch:  sessStores[session.KindWatch],
+		}
+	}
+
+	// media storage
+	mediaStorage, errMS := mediaStore.New(mediaStoreCfg)
+	if errMS != nil {
+		logger.Error("cannot configure media store", zap.Error(errMS))
+		return nil, nil, false
+	}
+
+	// user service
+	userSvc, errUser := user.NewService(&user.ServiceConfig{
+		Logger:     logger,
+		Store:      userStorage,
+		APIPrefix:  apiPrefix + "/user",
+		AuthConfig: authCfg,
+	})
+	if errUser != nil {
+		logger.Error("could not configure user service", zap.Error(errUser))
+		return nil, nil, false
+	}
+
+	// video service
+	videoSvc := video.NewService(svcCfg)
+	runners = append(runners, video.NewJanitor(logger, videoSvc, janitorPeriod))
+
+	// video http api, it is served by common listener, so server itself is not started
+	videoSrv, errSrv := videoServer.NewServer(&videoServer.Config{
+		Logger:     logger,
+		Auth:       authenticator,
+		HTTPConfig: &httpserver.Config{Logger: logger},
+		APIPrefix:  apiPrefix,
+	}, videoSvc)
+	if errSrv != nil {
+		logger.Error("could not create http server", zap.Error(errSrv))
+		return nil, nil, false
+	}
+
+	// video grpc servers
+	gUserSrv, errGSrv := userside.NewServer(gUserSrvCfg, videoSvc)
+	if errGSrv != nil {
+		logger.Error("could not create grpc userside server", zap.Error(errGSrv))
+		return nil, nil, false
+	}
+	gServiceSrv, errGService := serviceside.NewServer(gServiceSrvCfg, videoSvc)
+	if errGService != nil {
+		logger.Error("could not create grpc serviceside server", zap.Error(errGService))
+		return nil, nil, false
+	}
+	runners = append(runners, gUserSrv, gServiceSrv)
+
+	// notificators, one for each media service as in multi-service deployment
+	newNotificator := func() (*notificator.Notificator, bool) {
+		n, err := notificator.New(&notificator.Config{
+			Logger:        logger,
+			VideoAPIURL:   videoAPIEndpoint,
+			VideoAPIToken: videoAPIToken,
+		})
+		if err != nil {
+			logger.Error("cannot create notificator", zap.Error(err))
+			return nil, false
+		}
+		runners = append(runners, n)
+		return n, true
+	}
+
+	// uploader
+	uploaderNotificator, ok := newNotificator()
+	if !ok {
+		return nil, nil, false
+	}
+	uploaderSvc, errUp := uploader.New(&uploader.Config{
+		Logger:               logger,
+		URIPathPrefix:        uploadURIPrefix,
+		PathPrefix:           uploadPathPrefix,
+		MaxConcurrentUploads: maxConcurrentUploads,
+		MaxInflightSize:      maxInflightSize,
+		SessionStorage:       sessStores[session.KindUpload],
+		MediaStore:           mediaStorage,
+		Notificator:          uploaderNotificator,
+	})
+	if errUp != nil {
+		logger.Error("cannot create uploader service", zap.Error(errUp))
+		return nil, nil, false
+	}
+
+	// streamer
+	streamerSvc, errSt := streamer.New(&streamer.Config{
+		Logger:        logger,
+		CORSConfig:    corsConfig,
+		URIPathPrefix: watchURIPrefix,
+		PathPrefix:    watchPathPrefix,
+		SessionStore:  sessStores[session.KindWatch],
+		MediaStore:    mediaStorage,
+	})
+	if errSt != nil {
+		logger.Error("cannot create streamer service", zap.Error(errSt))
+		return nil, nil, false
+	}
+	closers = append(closers, streamerSvc)
+
+	// processor and purger
+	processorNotificator, ok := newNotificator()
+	if !ok {
+		return nil, nil, false
+	}
+	proc, errProc := processor.New(&processor.Config{
+		Logger:           logger,
+		Store:            mediaStorage,
+		Notificator:      processorNotificator,
+		VideoAPIEndpoint: videoAPIEndpoint,
+		VideoAPIToken:    videoAPIToken,
+		InputPathPrefix:  uploadPathPrefix,
+		OutputPathPrefix: watchPathPrefix,
+		SegmentDuration:  segmentDuration,
+		VideoCheckPeriod: videoCheckPeriod,
+		SkipMPD:          skipMPD,
+	})
+	if errProc != nil {
+		logger.Error("cannot create processor", zap.Error(errProc))
+		return nil, nil, false
+	}
+	purg, errPurg := purger.New(&purger.Config{
+		Logger:           logger,
+		Store:            mediaStorage,
+		VideoAPIEndpoint: videoAPIEndpoint,
+		VideoAPIToken:    videoAPIToken,
+		InputPathPrefix:  uploadPathPrefix,
+		OutputPathPrefix: watchPathPrefix,
+		CheckPeriod:      purgeCheckPeriod,
+		BatchSize:        purgeBatchSize,
+	})
+	if errPurg != nil {
+		logger.Error("cannot create purger", zap.Error(errPurg))
+		return nil, nil, false
+	}
+	runners = append(runners, proc, purg)
+
+	// common http listener
+	r := newRouter()
+	r.HandleHTTP(apiPrefix+"/user", userSvc)
+	r.HandleHTTP(apiPrefix, videoSrv.Handler())
+	r.Handle(uploadURIPrefix, uploaderSvc.Handler())
+	r.Handle(watchURIPrefix, streamerSvc.Handler())
+	srvCfg.Handler = r.handler
+
+	// --------------------------------------
+	// Return initialized entities
+	// --------------------------------------
+	return append(runners, server.New(srvCfg)), closers, true
+}
+
+// newServiceToken creates token for videoapi clients of standalone app.
+func newServiceToken(secret string) (string, error) {
+	au, err := auth.NewAuth(&auth.Config{
+		Secret:     secret,
+		Expiration: serviceTokenExpiration,
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot init authenticator: %w", err)
+	}
+	token, err := au.NewTokenForService(serviceName)
+	if err != nil {
+		return "", fmt.Errorf("cannot create token: %w", err)
+	}
+	return token, nil
+}
+
+// localEndpoint returns endpoint that could be used to connect to listen address from the same host.
+func localEndpoint(listenAddr string) (string, error) {
+	host, port, err := net.SplitHostPort(listenAddr)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse listen address: %w", err)
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port), nil
+}
@@ -0,0 +1,27 @@
+package standalone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalEndpoint(t *testing.T) {
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{addr: ":8282", want: "localhost:8282"},
+		{addr: "0.0.0.0:8282", want: "localhost:8282"},
+		{addr: "[::]:8282", want: "localhost:8282"},
+		{addr: "127.0.0.1:8282", want: "127.0.0.1:8282"},
+		{addr: "vidi:8282", want: "vidi:8282"},
+	}
+	for _, tt := range tests {
+		got, err := localEndpoint(tt.addr)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+	_, err := localEndpoint("8282")
+	assert.Error(t, err)
+}
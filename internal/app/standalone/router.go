@@ -0,0 +1,58 @@
+package standalone
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// route serves requests which path is equal to prefix or starts with prefix followed by slash.
+type route struct {
+	handler fasthttp.RequestHandler
+	prefix  string
+}
+
+// router dispatches requests of single listener to services by path prefix,
+// the same way nginx does it in multi-service deployment.
+type router struct {
+	routes []route
+}
+
+func newRouter() *router {
+	return &router{}
+}
+
+// Handle adds media service route.
+func (r *router) Handle(prefix string, h fasthttp.RequestHandler) {
+	r.routes = append(r.routes, route{prefix: prefix, handler: h})
+	// the longest prefix wins
+	slices.SortStableFunc(r.routes, func(a, b route) int {
+		return len(b.prefix) - len(a.prefix)
+	})
+}
+
+// HandleHTTP adds api service route. API services are net/http handlers
+// and are adapted to fasthttp.
+func (r *router) HandleHTTP(prefix string, h http.Handler) {
+	r.Handle(prefix, fasthttpadaptor.NewFastHTTPHandler(h))
+}
+
+func (r *router) handler(ctx *fasthttp.RequestCtx) {
+	path := ctx.Path()
+	for _, rt := range r.routes {
+		if matchPrefix(path, rt.prefix) {
+			rt.handler(ctx)
+			return
+		}
+	}
+	ctx.Error(fasthttp.StatusMessage(fasthttp.StatusNotFound), fasthttp.StatusNotFound)
+}
+
+func matchPrefix(path []byte, prefix string) bool {
+	if len(path) < len(prefix) || string(path[:len(prefix)]) != prefix {
+		return false
+	}
+	return len(path) == len(prefix) || path[len(prefix)] == '/'
+}
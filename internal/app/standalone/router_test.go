@@ -0,0 +1,56 @@
+package standalone
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestRouter(t *testing.T) {
+	named := func(name string) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			ctx.SetBodyString(name)
+		}
+	}
+	namedHTTP := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(name))
+		})
+	}
+	r := newRouter()
+	r.Handle("/upload", named("uploader"))
+	r.HandleHTTP("/api", namedHTTP("videoapi"))
+	r.HandleHTTP("/api/user", namedHTTP("userapi"))
+	r.Handle("/watch", named("streamer"))
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/api/user/login", want: "userapi"},
+		{path: "/api/user", want: "userapi"},
+		{path: "/api/users", want: "videoapi"},
+		{path: "/api/video/", want: "videoapi"},
+		{path: "/api/watch/abc", want: "videoapi"},
+		{path: "/upload/sess/1", want: "uploader"},
+		{path: "/watch/sess/manifest.mpd", want: "streamer"},
+		{path: "/uploads/sess/1"},
+		{path: "/"},
+		{path: "/ap"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var ctx fasthttp.RequestCtx
+			ctx.Request.SetRequestURI(tt.path)
+			r.handler(&ctx)
+			if tt.want == "" {
+				assert.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+				return
+			}
+			assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+			assert.Equal(t, tt.want, string(ctx.Response.Body()))
+		})
+	}
+}
@@ -5,9 +5,11 @@ import (
 
 	"github.com/adwski/vidi/internal/app"
 	"github.com/adwski/vidi/internal/media/server"
+	"github.com/adwski/vidi/internal/media/store"
 	"github.com/adwski/vidi/internal/media/streamer"
 	"github.com/adwski/vidi/internal/session"
 	sessionStore "github.com/adwski/vidi/internal/session/store"
+	"github.com/adwski/vidi/internal/session/token"
 	"go.uber.org/zap"
 )
 
@@ -36,13 +38,62 @@ func (a *App) configure(_ context.Context) ([]app.Runner, []app.Closer, bool) {
 		Logger:        logger,
 		CORSConfig:    corsConfig,
 		URIPathPrefix: v.GetURIPrefix("api.prefix"),
-		S3PathPrefix:  v.GetURIPrefix("s3.prefix.watch"),
-		S3Endpoint:    v.GetString("s3.endpoint"),
-		S3AccessKey:   v.GetString("s3.access_key"),
-		S3SecretKey:   v.GetString("s3.secret_key"),
-		S3Bucket:      v.GetString("s3.bucket"),
-		S3SSL:         v.GetBool("s3.ssl"),
+		PathPrefix:    v.GetURIPrefix("s3.prefix.watch"),
 	}
+	if v.GetBool("streamer.signed.enable") {
+		// keys of all signers, old keys are kept until tokens signed by them are expired
+		var keys []*token.Key
+		for _, spec := range v.GetStringSlice("streamer.signed.keys") {
+			key, errKey := token.ParseKey(spec)
+			if errKey != nil {
+				logger.Error("configuration error", zap.String("param", "streamer.signed.keys"), zap.Error(errKey))
+				return nil, nil, false
+			}
+			keys = append(keys, key)
+		}
+		verifier, errV := token.NewVerifier(keys)
+		if errV != nil {
+			logger.Error("configuration error", zap.String("param", "streamer.signed.keys"), zap.Error(errV))
+			return nil, nil, false
+		}
+		streamerCfg.Verifier = verifier
+		streamerCfg.ClientIPHeader = v.Viper.GetString("streamer.signed.client_ip_header") // optional
+	}
+	streamerCfg.CacheControl = streamer.CacheControlConfig{
+		// optional, header is not set if empty
+		Segment:  v.Viper.GetString("streamer.cache_control.segment"),
+		Manifest: v.Viper.GetString("streamer.cache_control.manifest"),
+	}
+	if v.GetBool("streamer.cache.enable") {
+		streamerCfg.Cache = &streamer.CacheConfig{
+			MaxSize:     v.GetInt64("streamer.cache.max_size"),
+			MaxItemSize: v.GetInt64("streamer.cache.max_item_size"),
+			TTL:         v.GetDuration("streamer.cache.ttl"),
+		}
+		streamerCfg.CacheStatsPath = v.Viper.GetString("streamer.cache.stats_path") // optional
+	}
+	var analyticsCfg *streamer.AnalyticsConfig
+	if v.GetBool("streamer.analytics.enable") {
+		analyticsCfg = &streamer.AnalyticsConfig{
+			Logger:           logger,
+			VideoAPIEndpoint: v.GetURL("videoapi.endpoint"),
+			VideoAPIToken:    v.GetString("videoapi.token"),
+			FlushInterval:    v.GetDuration("streamer.analytics.flush_interval"),
+			// session could not be active after it is expired
+			IdleTimeout: v.GetDuration("redis.ttl.watch"),
+		}
+	}
+	var manifestsCfg *streamer.ManifestsConfig
+	if v.GetBool("streamer.manifests.enable") {
+		manifestsCfg = &streamer.ManifestsConfig{
+			Logger:           logger,
+			VideoAPIEndpoint: v.GetURL("videoapi.endpoint"),
+			VideoAPIToken:    v.GetString("videoapi.token"),
+			BaseURL:          v.Viper.GetString("streamer.manifests.base_url"), // optional
+			MetaTTL:          v.GetDuration("streamer.manifests.meta_ttl"),
+		}
+	}
+	mediaStoreCfg := a.MediaStoreConfig(false)
 	srvCfg := &server.Config{
 		Logger:        logger,
 		ListenAddress: v.GetString("server.http.address"),
@@ -50,29 +101,53 @@ func (a *App) configure(_ context.Context) ([]app.Runner, []app.Closer, bool) {
 		WriteTimeout:  v.GetDuration("server.http.timeouts.write"),
 		IdleTimeout:   v.GetDuration("server.http.timeouts.idle"),
 	}
-	sessionStoreCfg := &sessionStore.Config{
-		Logger:   logger,
-		Name:     session.KindWatch,
-		RedisDSN: v.GetURL("redis.dsn"),
-		TTL:      v.GetDuration("redis.ttl.watch"),
-	}
+	sessionStoreCfg := a.SessionStoreConfig(session.KindWatch)
+	// streamer activity keeps indexed sessions active
+	sessionStoreCfg.Index = v.GetBool("media.sessions.index") || v.GetBool("media.streams.enable")
 	if v.HasErrors() {
 		for param, errP := range v.Errors() {
 			logger.Error("configuration error", zap.String("param", param), zap.Error(errP))
 		}
 		return nil, nil, false
 	}
-	sessStore, errSS := sessionStore.NewStore(sessionStoreCfg)
+	sessStore, errSS := sessionStore.New(sessionStoreCfg)
 	if errSS != nil {
 		logger.Error("cannot configure session store", zap.Error(errSS))
 		return nil, nil, false
 	}
 	streamerCfg.SessionStore = sessStore
+	mediaStore, errMS := store.New(mediaStoreCfg)
+	if errMS != nil {
+		logger.Error("cannot configure media store", zap.Error(errMS))
+		return nil, nil, false
+	}
+	streamerCfg.MediaStore = mediaStore
+	// redis session store evicts revoked sessions from its cache,
+	// memory session store drops expired sessions
+	runners := make([]app.Runner, 0, 3) //nolint:mnd // server, session store and analytics
+	runners = append(runners, sessStore)
+	if analyticsCfg != nil {
+		analytics, errA := streamer.NewAnalytics(analyticsCfg)
+		if errA != nil {
+			logger.Error("cannot create analytics", zap.Error(errA))
+			return nil, nil, false
+		}
+		streamerCfg.Analytics = analytics
+		runners = append(runners, analytics)
+	}
+	if manifestsCfg != nil {
+		manifests, errM := streamer.NewManifests(manifestsCfg)
+		if errM != nil {
+			logger.Error("cannot create manifests", zap.Error(errM))
+			return nil, nil, false
+		}
+		streamerCfg.Manifests = manifests
+	}
 	streamerSvc, errUp := streamer.New(&streamerCfg)
 	if errUp != nil {
 		logger.Error("cannot create uploader service", zap.Error(errUp))
 		return nil, nil, false
 	}
 	srvCfg.Handler = streamerSvc.Handler()
-	return []app.Runner{server.New(srvCfg)}, []app.Closer{sessStore}, true
+	return append(runners, server.New(srvCfg)), []app.Closer{sessStore, streamerSvc}, true
 }
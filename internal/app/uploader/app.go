@@ -3,14 +3,19 @@ package uploader
 import (
 	"context"
 
+	"github.com/adwski/vidi/internal/api/video/grpc/userside/pb"
 	"github.com/adwski/vidi/internal/event/notificator"
 	"github.com/adwski/vidi/internal/session"
 	sessionStore "github.com/adwski/vidi/internal/session/store"
 
 	"github.com/adwski/vidi/internal/app"
+	"github.com/adwski/vidi/internal/media/importer"
 	"github.com/adwski/vidi/internal/media/server"
+	"github.com/adwski/vidi/internal/media/store"
 	"github.com/adwski/vidi/internal/media/uploader"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 type App struct {
@@ -32,13 +37,12 @@ func (a *App) configure(_ context.Context) ([]app.Runner, []app.Closer, bool) {
 	uploaderCfg := uploader.Config{
 		Logger:        logger,
 		URIPathPrefix: v.GetURIPrefix("api.prefix"),
-		S3PathPrefix:  v.GetURIPrefix("s3.prefix.upload"),
-		S3Endpoint:    v.GetString("s3.endpoint"),
-		S3AccessKey:   v.GetString("s3.access_key"),
-		S3SecretKey:   v.GetString("s3.secret_key"),
-		S3Bucket:      v.GetString("s3.bucket"),
-		S3SSL:         v.GetBool("s3.ssl"),
+		PathPrefix:    v.GetURIPrefix("s3.prefix.upload"),
+
+		MaxConcurrentUploads: v.GetUint("uploader.max_concurrent_uploads"),
+		MaxInflightSize:      v.GetUint64("uploader.max_inflight_size"),
 	}
+	mediaStoreCfg := a.MediaStoreConfig(true)
 	notificatorCfg := &notificator.Config{
 		Logger:        logger,
 		VideoAPIURL:   v.GetURL("videoapi.endpoint"),
@@ -51,27 +55,96 @@ func (a *App) configure(_ context.Context) ([]app.Runner, []app.Closer, bool) {
 		WriteTimeout:  v.GetDuration("server.http.timeouts.write"),
 		IdleTimeout:   v.GetDuration("server.http.timeouts.idle"),
 		MaxBodySize:   v.GetUint("server.http.max_body_size"),
+
+		StreamRequestBody: true,
+	}
+	var (
+		tusEnabled     = v.GetBool("uploader.tus.enable")
+		tusPartSize    uint64
+		tusVideoAPIURL string
+	)
+	if tusEnabled {
+		tusPartSize = v.GetUint64("uploader.tus.part_size")
+		tusVideoAPIURL = v.GetString("videoapi.userside_endpoint")
 	}
-	sessionStoreCfg := &sessionStore.Config{
-		Logger:   logger,
-		Name:     session.KindUpload,
-		RedisDSN: v.GetURL("redis.dsn"),
-		TTL:      v.GetDuration("redis.ttl.upload"),
+	var throttleCfg *uploader.ThrottleConfig
+	if v.GetBool("uploader.throttle.enable") {
+		// tiers are configured as uploader.throttle.tiers.<name>.{user_rate,users}
+		throttleCfg = &uploader.ThrottleConfig{
+			SessionRate: v.GetUint64("uploader.throttle.session_rate"),
+			UserRate:    v.GetUint64("uploader.throttle.user_rate"),
+			Burst:       v.GetUint64("uploader.throttle.burst"),
+		}
+		for name := range v.GetStringMap("uploader.throttle.tiers") {
+			throttleCfg.Tiers = append(throttleCfg.Tiers, uploader.ThrottleTier{
+				Name:     name,
+				UserRate: v.GetUint64("uploader.throttle.tiers." + name + ".user_rate"),
+				Users:    v.GetStringSlice("uploader.throttle.tiers." + name + ".users"),
+			})
+		}
 	}
+	var importerCfg *importer.Config
+	if v.GetBool("import.enable") {
+		// imports are downloaded into the same upload location as regular uploads
+		importerCfg = &importer.Config{
+			Logger:           logger,
+			VideoAPIEndpoint: v.GetURL("videoapi.endpoint"),
+			VideoAPIToken:    v.GetString("videoapi.token"),
+			PathPrefix:       v.GetURIPrefix("s3.prefix.upload"),
+			AllowedHosts:     v.GetStringSlice("import.allowed_hosts"),
+			CheckPeriod:      v.GetDuration("import.check_period"),
+			BatchSize:        v.GetUint("import.batch_size"),
+			Retries:          v.GetUint("import.retries"),
+		}
+	}
+	sessionStoreCfg := a.SessionStoreConfig(session.KindUpload)
 	if v.HasErrors() {
 		for param, errP := range v.Errors() {
 			logger.Error("configuration error", zap.String("param", param), zap.Error(errP))
 		}
 		return nil, nil, false
 	}
-	sessStore, errSS := sessionStore.NewStore(sessionStoreCfg)
+	sessStore, errSS := sessionStore.New(sessionStoreCfg)
 	if errSS != nil {
 		logger.Error("cannot configure session store", zap.Error(errSS))
 		return nil, nil, false
 	}
 	uploaderCfg.SessionStorage = sessStore
+	if throttleCfg != nil {
+		// buckets are shared between replicas through session store connection
+		redisStore, ok := sessStore.(*sessionStore.Store)
+		if !ok {
+			sessStore.Close()
+			logger.Error("upload throttling requires redis session store")
+			return nil, nil, false
+		}
+		throttleCfg.Redis = redisStore.Redis()
+		uploaderCfg.Throttle = throttleCfg
+	}
 
-	var err error
+	mediaStore, err := store.New(mediaStoreCfg)
+	if err != nil {
+		logger.Error("cannot configure media store", zap.Error(err))
+		return nil, nil, false
+	}
+	uploaderCfg.MediaStore = mediaStore
+	if tusEnabled {
+		// tus uploads are created on behalf of user with user-side videoapi
+		cc, errCC := grpc.Dial(tusVideoAPIURL, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if errCC != nil {
+			logger.Error("cannot create videoapi connection", zap.Error(errCC))
+			return nil, nil, false
+		}
+		uploaderCfg.Tus = &uploader.TusConfig{
+			VideoAPI:   pb.NewUsersideapiClient(cc),
+			MediaStore: mediaStore,
+			// uploads are locked in session store, so lock is shared between replicas
+			Locker: sessStore,
+			// request could not last longer than server timeouts
+			LockTTL:  srvCfg.ReadTimeout + srvCfg.WriteTimeout,
+			PartSize: tusPartSize,
+		}
+	}
 	uploaderCfg.Notificator, err = notificator.New(notificatorCfg)
 	if err != nil {
 		logger.Error("cannot create notificator", zap.Error(err))
@@ -83,5 +156,15 @@ func (a *App) configure(_ context.Context) ([]app.Runner, []app.Closer, bool) {
 		return nil, nil, false
 	}
 	srvCfg.Handler = uploaderSvc.Handler()
-	return []app.Runner{server.New(srvCfg), uploaderCfg.Notificator}, []app.Closer{sessStore}, true
+	runners := []app.Runner{server.New(srvCfg), uploaderCfg.Notificator}
+	if importerCfg != nil {
+		importerCfg.Store = mediaStore
+		imp, errImp := importer.New(importerCfg)
+		if errImp != nil {
+			logger.Error("cannot create importer", zap.Error(errImp))
+			return nil, nil, false
+		}
+		runners = append(runners, imp)
+	}
+	return runners, []app.Closer{sessStore}, true
 }
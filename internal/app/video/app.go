@@ -3,6 +3,7 @@ package video
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 
 	httpserver "github.com/adwski/vidi/internal/api/http/server"
 	"github.com/adwski/vidi/internal/api/user/auth"
@@ -13,8 +14,10 @@ import (
 	"github.com/adwski/vidi/internal/api/video/http/server"
 	"github.com/adwski/vidi/internal/api/video/store"
 	"github.com/adwski/vidi/internal/app"
+	mediaStore "github.com/adwski/vidi/internal/media/store"
 	"github.com/adwski/vidi/internal/session"
 	sessionStore "github.com/adwski/vidi/internal/session/store"
+	"github.com/adwski/vidi/internal/session/token"
 	"go.uber.org/zap"
 )
 
@@ -48,10 +51,96 @@ func (a *App) configure(ctx context.Context) ([]app.Runner, []app.Closer, bool)
 		Logger:          logger,
 		WatchURLPrefix:  v.GetURL("media.url.watch"),
 		UploadURLPrefix: v.GetURL("media.url.upload"),
+		IngestURLPrefix: v.GetURL("media.url.ingest"),
 		Quotas: video.Quotas{
 			VideosPerUser: v.GetUint("media.user_quota.max_videos"),
 			MaxTotalSize:  v.GetUint64("media.user_quota.max_size"),
 		},
+		UploadTTL: v.GetDuration("media.upload_ttl"),
+		// previous output of reprocessed video is kept while watch sessions could refer to it
+		OutputPurgeLag: v.GetDuration("redis.ttl.watch"),
+		PartSize: video.PartSizeBounds{
+			Min: v.GetUint64("media.part_size.min"),
+			Max: v.GetUint64("media.part_size.max"),
+		},
+	}
+	sourceRetention, errRet := video.ParseSourceRetention(v.GetString("media.source_retention"))
+	if errRet != nil {
+		logger.Error("configuration error", zap.String("param", "media.source_retention"), zap.Error(errRet))
+		return nil, nil, false
+	}
+	svcCfg.SourceRetention = sourceRetention
+	duplicatePolicy, errDup := video.ParseDuplicatePolicy(v.GetString("media.duplicates.policy"))
+	if errDup != nil {
+		logger.Error("configuration error", zap.String("param", "media.duplicates.policy"), zap.Error(errDup))
+		return nil, nil, false
+	}
+	svcCfg.Duplicates = video.DuplicatesConfig{Policy: duplicatePolicy}
+	if svcCfg.PartSize.Max > 0 && svcCfg.PartSize.Min > svcCfg.PartSize.Max {
+		logger.Error("configuration error", zap.String("param", "media.part_size"),
+			zap.Error(errors.New("min part size is greater than max part size")))
+		return nil, nil, false
+	}
+	janitorPeriod := v.GetDuration("media.janitor_period")
+	if v.GetBool("import.enable") {
+		svcCfg.Import = &video.ImportConfig{
+			AllowedHosts: v.GetStringSlice("import.allowed_hosts"),
+			MaxSize:      v.GetUint64("import.max_size"),
+			ProbeTimeout: v.GetDuration("import.probe_timeout"),
+		}
+		if len(svcCfg.Import.AllowedHosts) == 0 {
+			logger.Error("configuration error", zap.String("param", "import.allowed_hosts"),
+				zap.Error(errors.New("at least one host must be allowed")))
+			return nil, nil, false
+		}
+	}
+	if v.GetBool("media.watch.signed.enable") {
+		// watch sessions are issued as signed tokens instead of being stored in redis
+		key, errKey := token.ParseKey(v.GetString("media.watch.signed.key"))
+		if errKey != nil {
+			logger.Error("configuration error", zap.String("param", "media.watch.signed.key"), zap.Error(errKey))
+			return nil, nil, false
+		}
+		signer, errSig := token.NewSigner(key, v.GetDuration("media.watch.signed.ttl"))
+		if errSig != nil {
+			logger.Error("configuration error", zap.String("param", "media.watch.signed"), zap.Error(errSig))
+			return nil, nil, false
+		}
+		svcCfg.WatchSigner = signer
+		svcCfg.OutputPurgeLag = max(svcCfg.OutputPurgeLag, v.GetDuration("media.watch.signed.ttl"))
+		svcCfg.BindWatchClient = v.GetBool("media.watch.signed.bind_client")
+	}
+	trackStreams := v.GetBool("media.streams.enable")
+	if trackStreams {
+		if svcCfg.WatchSigner != nil {
+			logger.Error("configuration error", zap.String("param", "media.streams.enable"),
+				zap.Error(errors.New("streams cannot be tracked with signed watch urls")))
+			return nil, nil, false
+		}
+		streamPolicy, errPol := video.ParseStreamLimitPolicy(v.GetString("media.streams.policy"))
+		if errPol != nil {
+			logger.Error("configuration error", zap.String("param", "media.streams.policy"), zap.Error(errPol))
+			return nil, nil, false
+		}
+		// tiers are configured as media.streams.tiers.<name>.{max_streams,users}
+		svcCfg.Streams = &video.StreamsConfig{
+			MaxPerUser: v.GetUint("media.streams.max_per_user"),
+			Policy:     streamPolicy,
+		}
+		for name := range v.GetStringMap("media.streams.tiers") {
+			svcCfg.Streams.Tiers = append(svcCfg.Streams.Tiers, video.StreamTier{
+				Name:       name,
+				MaxStreams: v.GetUint("media.streams.tiers." + name + ".max_streams"),
+				Users:      v.GetStringSlice("media.streams.tiers." + name + ".users"),
+			})
+		}
+	}
+	var directUploadCfg *video.DirectUploadConfig
+	if v.GetBool("media.direct_upload.enable") {
+		directUploadCfg = &video.DirectUploadConfig{
+			PathPrefix: v.GetURIPrefix("s3.prefix.upload"),
+			URLTTL:     v.GetDuration("media.direct_upload.url_ttl"),
+		}
 	}
 	authCfg := auth.Config{
 		Secret:       v.GetString("auth.jwt.secret"),
@@ -81,18 +170,13 @@ func (a *App) configure(ctx context.Context) ([]app.Runner, []app.Closer, bool)
 		ListenAddr: v.GetString("server.grpc.svc_address"),
 		Reflection: v.GetBool("server.grpc.reflection"),
 	}
-	uploadSessionStoreCfg := &sessionStore.Config{
-		Logger:   logger,
-		Name:     session.KindUpload,
-		RedisDSN: v.GetURL("redis.dsn"),
-		TTL:      v.GetDuration("redis.ttl.upload"),
-	}
-	watchSessionStoreCfg := &sessionStore.Config{
-		Logger:   logger,
-		Name:     session.KindWatch,
-		RedisDSN: v.GetURL("redis.dsn"),
-		TTL:      v.GetDuration("redis.ttl.watch"),
-	}
+	indexSessions := v.GetBool("media.sessions.index")
+	uploadSessionStoreCfg := a.SessionStoreConfig(session.KindUpload)
+	uploadSessionStoreCfg.Index = indexSessions
+	watchSessionStoreCfg := a.SessionStoreConfig(session.KindWatch)
+	// stream limits use user index of watch sessions
+	watchSessionStoreCfg.Index = indexSessions || trackStreams
+	ingestSessionStoreCfg := a.SessionStoreConfig(session.KindIngest)
 	var (
 		tlsKeyPath, tlsCertPath string
 		grpcTLSEnableUsr        = v.GetBool("server.grpc.tls_userside_enable")
@@ -144,7 +228,7 @@ func (a *App) configure(ctx context.Context) ([]app.Runner, []app.Closer, bool)
 	srvCfg.Auth = authenticator
 
 	// upload session storage
-	uploadSessStore, errSSU := sessionStore.NewStore(uploadSessionStoreCfg)
+	uploadSessStore, errSSU := sessionStore.New(uploadSessionStoreCfg)
 	if errSSU != nil {
 		logger.Error("cannot configure upload session store", zap.Error(errSSU))
 		return nil, nil, false
@@ -152,12 +236,29 @@ func (a *App) configure(ctx context.Context) ([]app.Runner, []app.Closer, bool)
 	svcCfg.UploadSessionStore = uploadSessStore
 
 	// watch session storage
-	watchSessStore, errSSW := sessionStore.NewStore(watchSessionStoreCfg)
+	watchSessStore, errSSW := sessionStore.New(watchSessionStoreCfg)
 	if errSSW != nil {
 		logger.Error("cannot configure watch session store", zap.Error(errSSW))
 		return nil, nil, false
 	}
 	svcCfg.WatchSessionStore = watchSessStore
+	if svcCfg.Streams != nil {
+		svcCfg.Streams.Store = watchSessStore
+	}
+	if indexSessions {
+		svcCfg.Sessions = &video.SessionsConfig{
+			Upload: uploadSessStore,
+			Watch:  watchSessStore,
+		}
+	}
+
+	// ingest session storage
+	ingestSessStore, errSSI := sessionStore.New(ingestSessionStoreCfg)
+	if errSSI != nil {
+		logger.Error("cannot configure ingest session store", zap.Error(errSSI))
+		return nil, nil, false
+	}
+	svcCfg.IngestSessionStore = ingestSessStore
 
 	// video storage
 	videoStorage, errStore := store.New(ctx, storeCfg)
@@ -167,9 +268,29 @@ func (a *App) configure(ctx context.Context) ([]app.Runner, []app.Closer, bool)
 	}
 	svcCfg.Store = videoStorage
 
+	// media store for direct uploads
+	if directUploadCfg != nil {
+		ms, errMS := mediaStore.New(a.MediaStoreConfig(false))
+		if errMS != nil {
+			logger.Error("could not configure media store", zap.Error(errMS))
+			return nil, nil, false
+		}
+		directStore, ok := ms.(video.DirectUploadStore)
+		if !ok {
+			logger.Error("configuration error", zap.String("param", "media.direct_upload.enable"),
+				zap.Error(errors.New("media store does not support direct uploads")))
+			return nil, nil, false
+		}
+		directUploadCfg.Store = directStore
+		svcCfg.DirectUpload = directUploadCfg
+	}
+
 	// video service
 	svc := video.NewService(svcCfg)
 
+	// background cleanup
+	janitor := video.NewJanitor(logger, svc, janitorPeriod)
+
 	// video http server (userside)
 	srv, errSrv := server.NewServer(srvCfg, svc)
 	if errSrv != nil {
@@ -194,5 +315,5 @@ func (a *App) configure(ctx context.Context) ([]app.Runner, []app.Closer, bool)
 	// --------------------------------------
 	// Return initialized entities
 	// --------------------------------------
-	return []app.Runner{srv, gUserSrv, gServiceSrv}, []app.Closer{videoStorage, uploadSessStore, watchSessStore}, true
+	return []app.Runner{srv, gUserSrv, gServiceSrv, janitor}, []app.Closer{videoStorage, uploadSessStore, watchSessStore, ingestSessStore}, true
 }
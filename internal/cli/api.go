@@ -1,14 +1,20 @@
 package cli
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"time"
 
 	"github.com/adwski/vidi/internal/api/user/auth"
+	"github.com/adwski/vidi/internal/api/video/grpc/serviceside/pb"
 	"github.com/adwski/vidi/internal/logging"
 	"github.com/spf13/cast"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 )
 
 var apiCmd = &cobra.Command{
@@ -27,6 +33,78 @@ var createSvcTokenCmd = &cobra.Command{
 	},
 }
 
+var reprocessCmd = &cobra.Command{
+	Use:   "reprocess",
+	Short: "queue videos for reprocessing using videoapi service-side api",
+	Long: `Queue videos for reprocessing. Videos are selected by ids or,
+if --outdated is set, every video processed by other processor version is selected.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := cmd.Flags().GetStringSlice("id")
+		if err != nil {
+			return fmt.Errorf("cannot get ids: %w", err)
+		}
+		var version string
+		if outdated, _ := cmd.Flags().GetBool("outdated"); outdated {
+			version = cmd.Flag("version").Value.String()
+		}
+		return reprocessVideos(cmd.Context(), cmd.OutOrStdout(), &reprocessParams{
+			endpoint:   cmd.Flag("endpoint").Value.String(),
+			name:       cmd.Flag("svcname").Value.String(),
+			secret:     cmd.Flag("jwtsecret").Value.String(),
+			expiration: cast.ToDuration(cmd.Flag("expiration").Value.String()),
+			ids:        ids,
+			version:    version,
+		})
+	},
+}
+
+type reprocessParams struct {
+	endpoint   string
+	name       string
+	secret     string
+	version    string
+	ids        []string
+	expiration time.Duration
+}
+
+func reprocessVideos(ctx context.Context, w io.Writer, params *reprocessParams) error {
+	if len(params.ids) == 0 && params.version == "" {
+		return fmt.Errorf("either --id or --outdated must be specified")
+	}
+	au, err := auth.NewAuth(&auth.Config{
+		Secret:     params.secret,
+		Expiration: params.expiration,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot init authenticator: %w", err)
+	}
+	token, err := au.NewTokenForService(params.name)
+	if err != nil {
+		return fmt.Errorf("cannot create token: %w", err)
+	}
+	cc, err := grpc.Dial(params.endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("cannot create videoapi connection: %w", err)
+	}
+	defer func() { _ = cc.Close() }()
+
+	resp, err := pb.NewServicesideapiClient(cc).ReprocessVideos(
+		metadata.NewOutgoingContext(ctx, metadata.Pairs("authorization", "bearer "+token)),
+		&pb.ReprocessVideosRequest{
+			Ids:             params.ids,
+			OutdatedVersion: params.version,
+		})
+	if err != nil {
+		return fmt.Errorf("reprocess request failed: %w", err)
+	}
+	for _, id := range resp.Ids {
+		if _, err = fmt.Fprintln(w, id); err != nil {
+			return fmt.Errorf("cannot write output: %w", err)
+		}
+	}
+	return nil
+}
+
 func createServiceToken(w io.Writer, name, secret string, expiration time.Duration) {
 	logger := logging.GetZapLoggerWriter(w)
 
@@ -1,12 +1,15 @@
 // Package cli contains cli tool that has
 // - helpful mp4 operations like dumping and segmenting mp4 file
-// - video api service token creation (which can be used later in apps config).
+// - video api service token creation (which can be used later in apps config)
+// - video api service-side operations like queueing videos for reprocessing
+// - standalone mode that runs all vidi services in single process.
 package cli
 
 import (
 	"fmt"
 	"time"
 
+	"github.com/adwski/vidi/internal/media/processor"
 	"github.com/spf13/cobra"
 )
 
@@ -35,15 +38,25 @@ func Execute() int {
 func init() {
 	mp4Cmd.AddCommand(dumpCmd)
 	mp4Cmd.AddCommand(segmentCmd)
+	mp4Cmd.AddCommand(checkCmd)
+	dumpCmd.Flags().String("format", formatText, "output format: text or json")
 	mp4Cmd.PersistentFlags().StringP("file", "f", "input.mp4", "input file")
 	mp4Cmd.PersistentFlags().StringP("outdir", "o", "./output", "output dir")
 	mp4Cmd.PersistentFlags().DurationP("segduration", "s", defaultSegmentDuration, "segment duration")
 
 	apiCmd.AddCommand(createSvcTokenCmd)
+	apiCmd.AddCommand(reprocessCmd)
+	reprocessCmd.Flags().String("endpoint", "localhost:8282", "videoapi service-side grpc endpoint")
+	reprocessCmd.Flags().StringSlice("id", nil, "video id to reprocess (can be repeated)")
+	reprocessCmd.Flags().Bool("outdated", false, "reprocess videos processed by other processor version")
+	reprocessCmd.Flags().String("version", processor.Version, "current processor version")
 	apiCmd.PersistentFlags().StringP("svcname", "n", "service", "service name")
 	apiCmd.PersistentFlags().StringP("jwtsecret", "s", "changeMe", "jwt secret")
 	apiCmd.PersistentFlags().DurationP("expiration", "e", defaultServiceJWTExpiration, "token expiration")
 
+	standaloneCmd.Flags().StringP("config", "c", "config", "config file name without extension")
+
 	rootCmd.AddCommand(mp4Cmd)
 	rootCmd.AddCommand(apiCmd)
+	rootCmd.AddCommand(standaloneCmd)
 }
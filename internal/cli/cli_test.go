@@ -2,11 +2,13 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"strings"
 	"testing"
 
 	"github.com/adwski/vidi/internal/api/user/auth"
+	"github.com/adwski/vidi/internal/mp4"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -34,6 +36,16 @@ func TestAPICmd_CreateServiceToken(t *testing.T) {
 	assert.Equal(t, token.Claims.(*auth.Claims).Name, "testsvc")
 }
 
+func TestAPICmd_ReprocessNoCriteria(t *testing.T) {
+	buf := bytes.Buffer{}
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(&buf)
+	rootCmd.SetArgs([]string{"api", "reprocess", "-n", "testsvc", "-s", "jwtsecret", "-e", "1h"})
+	err := rootCmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "either --id or --outdated must be specified")
+}
+
 //nolint:lll // mp4 sample file dump
 const testFileDump = `ftyp: [isom iso2 avc1 mp41]
 segmented: false
@@ -73,6 +85,53 @@ func TestMP4Cmd_Dump(t *testing.T) {
 	require.Equal(t, testFileDump, out)
 }
 
+func TestMP4Cmd_DumpJSON(t *testing.T) {
+	buf := bytes.Buffer{}
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(&buf)
+	rootCmd.SetArgs([]string{"mp4", "dump", "-f", "../../testfiles/test_seq_h264_high.mp4", "-s", "1s", "--format", "json"})
+	err := rootCmd.Execute()
+	require.NoError(t, err)
+
+	var report mp4.Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, uint32(15360), report.Timescale)
+	assert.Len(t, report.Tracks, 3)
+
+	rootCmd.SetArgs([]string{"mp4", "dump", "-f", "../../testfiles/test_seq_h264_high.mp4", "--format", "qwe"})
+	err = rootCmd.Execute()
+	require.Error(t, err)
+
+	dumpCmd.Flags().Set("format", formatText) //nolint:errcheck // restore default for other tests
+}
+
+func TestMP4Cmd_Check(t *testing.T) {
+	buf := bytes.Buffer{}
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(&buf)
+	rootCmd.SetArgs([]string{"mp4", "check", "-f", "../../testfiles/test_seq_h264_high.mp4", "-s", "1s"})
+	err := rootCmd.Execute()
+	require.NoError(t, err)
+
+	var report mp4.Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.False(t, report.HasBlockingProblems())
+	require.NotNil(t, report.Segmentation)
+	assert.Len(t, report.Segmentation.Points, 10)
+
+	tmp := t.TempDir()
+	testFileName := tmp + "/test.mp4"
+	require.NoError(t, os.WriteFile(testFileName, []byte("qwqwdqsdsad"), 0600))
+
+	buf.Reset()
+	rootCmd.SetArgs([]string{"mp4", "check", "-f", testFileName})
+	err = rootCmd.Execute()
+	require.ErrorIs(t, err, errBlockingProblems)
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.True(t, report.HasBlockingProblems())
+}
+
 func TestMP4Cmd_Segment(t *testing.T) {
 	tmp := t.TempDir()
 	testFileName := tmp + "/test.mp4"
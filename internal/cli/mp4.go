@@ -2,6 +2,8 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"time"
@@ -15,6 +17,13 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+var errBlockingProblems = errors.New("mp4 file has blocking problems")
+
 var mp4Cmd = &cobra.Command{
 	Use:   "mp4",
 	Short: "mp4 isobmff command group",
@@ -34,10 +43,41 @@ var segmentCmd = &cobra.Command{
 var dumpCmd = &cobra.Command{
 	Use:   "dump",
 	Short: "dump mp4 file",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fileName := cmd.Flag("file").Value.String()
+		segDuration := cast.ToDuration(cmd.Flag("segduration").Value.String())
+		switch format := cmd.Flag("format").Value.String(); format {
+		case formatText:
+			mp4.Dump(cmd.OutOrStdout(), fileName, segDuration)
+		case formatJSON:
+			if _, err := mp4.DumpJSON(cmd.OutOrStdout(), fileName, segDuration); err != nil {
+				return err //nolint:wrapcheck // already descriptive
+			}
+		default:
+			return fmt.Errorf("unknown output format: %s", format)
+		}
+		return nil
+	},
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "check mp4 file and output conformance report in json format",
+	Long: "check mp4 file and output conformance report in json format.\n" +
+		"Command exits with non-zero code if file has blocking problems.",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		fileName := cmd.Flag("file").Value.String()
 		segDuration := cast.ToDuration(cmd.Flag("segduration").Value.String())
-		mp4.Dump(cmd.OutOrStdout(), fileName, segDuration)
+		report, err := mp4.DumpJSON(cmd.OutOrStdout(), fileName, segDuration)
+		if err != nil {
+			return err //nolint:wrapcheck // already descriptive
+		}
+		if report.HasBlockingProblems() {
+			return errBlockingProblems
+		}
+		return nil
 	},
 }
 
@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/adwski/vidi/internal/app/standalone"
+	"github.com/spf13/cobra"
+)
+
+var standaloneCmd = &cobra.Command{
+	Use:   "standalone",
+	Short: "run all vidi services in single process",
+	Long: `Run userapi, videoapi, uploader, streamer and processor in single process.
+Users, videos and sessions are kept in memory and media is stored in local directory,
+so no external services are required. All services are served by single http listener.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configName := cmd.Flag("config").Value.String()
+		if code := standalone.NewApp().RunWithContextAndConfig(cmd.Context(), configName); code != 0 {
+			return fmt.Errorf("standalone app exited with code %d", code)
+		}
+		return nil
+	},
+}
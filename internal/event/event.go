@@ -1,5 +1,5 @@
 // Package event defines generic event that
-// sent by processor and uploader to notify videoapi
+// sent by processor, uploader and ingest to notify videoapi
 // about video object changes.
 package event
 
@@ -7,6 +7,7 @@ const (
 	KindUpdateStatus = iota + 1
 	KindVideoPartUploaded
 	KindVideoReady
+	KindLiveUpdate
 )
 
 // Event is a Video API notification event.
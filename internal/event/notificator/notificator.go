@@ -81,7 +81,7 @@ func (n *Notificator) processEvent(ctx context.Context, ev *event.Event) {
 			n.logger.Error("PartInfo is nil", zap.Int("kind", ev.Kind))
 			return
 		}
-	case event.KindUpdateStatus, event.KindVideoReady:
+	case event.KindUpdateStatus, event.KindVideoReady, event.KindLiveUpdate:
 		if ev.VideoInfo == nil {
 			n.logger.Error("VideoInfo is nil", zap.Int("kind", ev.Kind))
 			return
@@ -110,6 +110,12 @@ func (n *Notificator) processEvent(ctx context.Context, ev *event.Event) {
 			Status:       int32(model.StatusReady),
 			PlaybackMeta: ev.VideoInfo.Meta,
 		})
+	case event.KindLiveUpdate:
+		_, err = n.c.UpdateVideo(metadata.NewOutgoingContext(ctx, n.authMD), &pb.UpdateVideoRequest{
+			Id:           ev.VideoInfo.VideoID,
+			Status:       int32(model.StatusLive),
+			PlaybackMeta: ev.VideoInfo.Meta,
+		})
 	}
 
 	if err != nil {
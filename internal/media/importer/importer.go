@@ -0,0 +1,222 @@
+// Package importer contains media importer which downloads videos from remote urls.
+package importer
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/grpc/serviceside/pb"
+	video "github.com/adwski/vidi/internal/api/video/model"
+	"github.com/minio/sha256-simd"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	defaultBatchSize  = 1
+	defaultRetries    = 3
+	defaultRetryDelay = time.Second
+)
+
+// errFatal marks import errors that cannot be fixed by retrying,
+// video of such import is moved to error state.
+var errFatal = errors.New("fatal import error")
+
+type MediaStore interface {
+	Put(ctx context.Context, name string, r io.Reader, size int64) error
+}
+
+// Importer is worker-style app that polls videoapi for import jobs,
+// downloads remote files into upload parts of videos and reports progress back to videoapi.
+//
+// Parts are downloaded in order with range requests. Interrupted download
+// is resumed from the last received byte, failed import is continued later
+// from the last stored part. Videos become uploaded the same way as with uploader,
+// when all parts are notified.
+type Importer struct {
+	logger       *zap.Logger
+	videoAPI     pb.ServicesideapiClient
+	authMD       metadata.MD
+	st           MediaStore
+	client       *http.Client
+	pathPrefix   string
+	allowedHosts []string
+	checkPeriod  time.Duration
+	retryDelay   time.Duration
+	batchSize    uint32
+	retries      uint
+}
+
+type Config struct {
+	Logger           *zap.Logger
+	Store            MediaStore
+	VideoAPIEndpoint string
+	VideoAPIToken    string
+	PathPrefix       string
+	AllowedHosts     []string
+	CheckPeriod      time.Duration
+	BatchSize        uint
+	// Retries is an amount of attempts to resume interrupted part download.
+	Retries uint
+}
+
+func New(cfg *Config) (*Importer, error) {
+	cc, err := grpc.Dial(cfg.VideoAPIEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create vidi connection: %w", err)
+	}
+	batchSize := uint32(cfg.BatchSize)
+	if batchSize == 0 {
+		batchSize = defaultBatchSize
+	}
+	retries := cfg.Retries
+	if retries == 0 {
+		retries = defaultRetries
+	}
+	imp := &Importer{
+		logger:       cfg.Logger.With(zap.String("component", "importer")),
+		st:           cfg.Store,
+		checkPeriod:  cfg.CheckPeriod,
+		batchSize:    batchSize,
+		retries:      retries,
+		retryDelay:   defaultRetryDelay,
+		pathPrefix:   strings.TrimSuffix(cfg.PathPrefix, "/"),
+		allowedHosts: cfg.AllowedHosts,
+		videoAPI:     pb.NewServicesideapiClient(cc),
+		authMD:       metadata.Pairs("authorization", "bearer "+cfg.VideoAPIToken),
+	}
+	imp.client = &http.Client{
+		CheckRedirect: func(req *http.Request, _ []*http.Request) error {
+			return imp.checkHost(req.URL)
+		},
+	}
+	return imp, nil
+}
+
+func (imp *Importer) Run(ctx context.Context, wg *sync.WaitGroup, _ chan<- error) {
+	defer wg.Done()
+	imp.logger.Info("started")
+	ticker := time.NewTicker(imp.checkPeriod)
+	defer ticker.Stop()
+Loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break Loop
+		case <-ticker.C:
+			imp.importVideos(ctx)
+		}
+	}
+	imp.logger.Info("stopped")
+}
+
+func (imp *Importer) importVideos(ctx context.Context) {
+	ctx = metadata.NewOutgoingContext(ctx, imp.authMD)
+	resp, err := imp.videoAPI.GetImportJobs(ctx, &pb.GetImportJobsRequest{Limit: imp.batchSize})
+	if err != nil {
+		imp.logger.Error("cannot get import jobs from video API", zap.Error(err))
+		return
+	}
+	for _, job := range resp.Jobs {
+		downloaded, errI := imp.importVideo(ctx, job)
+		upd := &pb.UpdateImportJobRequest{Id: job.Id, Downloaded: downloaded, Done: true}
+		if errI != nil {
+			upd.Done = false
+			upd.Error = errI.Error()
+			upd.Fatal = errors.Is(errI, errFatal)
+			imp.logger.Error("cannot import video",
+				zap.String("video_id", job.VideoId),
+				zap.Uint64("downloaded", downloaded),
+				zap.Uint32("attempts", job.Attempts),
+				zap.Bool("fatal", upd.Fatal),
+				zap.Error(errI))
+		} else {
+			imp.logger.Debug("video imported",
+				zap.String("video_id", job.VideoId),
+				zap.Uint64("size", job.Size))
+		}
+		if _, err = imp.videoAPI.UpdateImportJob(ctx, upd); err != nil {
+			// job will be returned again after lease expires
+			imp.logger.Error("cannot report import result", zap.Int64("id", job.Id), zap.Error(err))
+		}
+	}
+}
+
+// importVideo downloads remaining parts of import and returns offset of the next part to download.
+func (imp *Importer) importVideo(ctx context.Context, job *pb.ImportJob) (uint64, error) {
+	downloaded := job.Downloaded
+	u, err := url.Parse(job.Url)
+	if err != nil {
+		return downloaded, errors.Join(errFatal, err)
+	}
+	if err = imp.checkHost(u); err != nil {
+		return downloaded, err
+	}
+	if job.PartSize == 0 || downloaded%job.PartSize != 0 {
+		return downloaded, errors.Join(errFatal, errors.New("invalid import parts"))
+	}
+	for downloaded < job.Size {
+		var (
+			num  = downloaded / job.PartSize
+			size = min(job.PartSize, job.Size-downloaded)
+		)
+		checksum, errP := imp.importPart(ctx, job, num, downloaded, size)
+		if errP != nil {
+			return downloaded, fmt.Errorf("cannot import part %d: %w", num, errP)
+		}
+		if _, err = imp.videoAPI.NotifyPartUpload(ctx, &pb.NotifyPartUploadRequest{
+			VideoId:  job.VideoId,
+			Num:      uint32(num),
+			Checksum: checksum,
+		}); err != nil {
+			return downloaded, fmt.Errorf("cannot notify part %d upload: %w", num, err)
+		}
+		downloaded += size
+		if downloaded == job.Size {
+			break
+		}
+		if _, err = imp.videoAPI.UpdateImportJob(ctx, &pb.UpdateImportJobRequest{
+			Id:         job.Id,
+			Downloaded: downloaded,
+		}); err != nil {
+			imp.logger.Error("cannot report import progress", zap.Int64("id", job.Id), zap.Error(err))
+		}
+	}
+	return downloaded, nil
+}
+
+// importPart downloads part of remote file into media store and returns its checksum.
+func (imp *Importer) importPart(ctx context.Context, job *pb.ImportJob, num, offset, size uint64) (string, error) {
+	r := &rangeReader{
+		ctx:     ctx,
+		imp:     imp,
+		url:     job.Url,
+		offset:  offset,
+		end:     offset + size,
+		srcSize: job.Size,
+	}
+	defer r.close()
+	shaW := sha256.New()
+	name := fmt.Sprintf("%s/%s/%d", imp.pathPrefix, job.Location, num)
+	if err := imp.st.Put(ctx, name, io.TeeReader(r, shaW), int64(size)); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(shaW.Sum(nil)), nil
+}
+
+func (imp *Importer) checkHost(u *url.URL) error {
+	if !video.ImportHostAllowed(imp.allowedHosts, u.Hostname()) {
+		return errors.Join(errFatal, video.ErrImportHostNotAllowed)
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/grpc/serviceside/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+type fakeVideoAPI struct {
+	pb.ServicesideapiClient
+	jobs     []*pb.ImportJob
+	updates  []*pb.UpdateImportJobRequest
+	notified []*pb.NotifyPartUploadRequest
+}
+
+func (f *fakeVideoAPI) GetImportJobs(
+	_ context.Context,
+	_ *pb.GetImportJobsRequest,
+	_ ...grpc.CallOption,
+) (*pb.ImportJobsResponse, error) {
+	return &pb.ImportJobsResponse{Jobs: f.jobs}, nil
+}
+
+func (f *fakeVideoAPI) UpdateImportJob(
+	_ context.Context,
+	req *pb.UpdateImportJobRequest,
+	_ ...grpc.CallOption,
+) (*pb.UpdateImportJobResponse, error) {
+	f.updates = append(f.updates, req)
+	return &pb.UpdateImportJobResponse{}, nil
+}
+
+func (f *fakeVideoAPI) NotifyPartUpload(
+	_ context.Context,
+	req *pb.NotifyPartUploadRequest,
+	_ ...grpc.CallOption,
+) (*pb.NotifyPartUploadResponse, error) {
+	f.notified = append(f.notified, req)
+	return &pb.NotifyPartUploadResponse{}, nil
+}
+
+type fakeStore struct {
+	mx      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeStore) Put(_ context.Context, name string, r io.Reader, size int64) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if int64(len(b)) != size {
+		return errors.New("size mismatch")
+	}
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	f.objects[name] = b
+	return nil
+}
+
+// newSource returns server that serves ranges of data. Every first response
+// starting from offsets in breakAt is interrupted in the middle.
+func newSource(t *testing.T, data []byte, breakAt ...int) *httptest.Server {
+	t.Helper()
+	var (
+		mx     sync.Mutex
+		broken = make(map[int]bool)
+	)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var start, end int
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &end); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+		w.Header().Set("Content-Length", strconv.Itoa(end-start+1))
+		w.WriteHeader(http.StatusPartialContent)
+
+		mx.Lock()
+		interrupt := false
+		for _, offset := range breakAt {
+			if offset == start && !broken[start] {
+				broken[start] = true
+				interrupt = true
+			}
+		}
+		mx.Unlock()
+		if interrupt {
+			// connection is closed since response is shorter than content length
+			_, _ = w.Write(data[start : start+(end-start)/2])
+			return
+		}
+		_, _ = w.Write(data[start : end+1])
+	}))
+}
+
+func newTestImporter(t *testing.T, api pb.ServicesideapiClient, st MediaStore, src *httptest.Server) *Importer {
+	t.Helper()
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+	srcURL, err := url.Parse(src.URL)
+	require.NoError(t, err)
+
+	imp, err := New(&Config{
+		Logger:           logger,
+		Store:            st,
+		VideoAPIEndpoint: "localhost:1",
+		PathPrefix:       "/upload/",
+		AllowedHosts:     []string{srcURL.Hostname()},
+		CheckPeriod:      time.Second,
+	})
+	require.NoError(t, err)
+	imp.videoAPI = api
+	imp.retryDelay = time.Millisecond
+	return imp
+}
+
+func checksum(b []byte) string {
+	sum := sha256.Sum256(b)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func TestImporter_importVideos(t *testing.T) {
+	data := []byte("0123456789abcdefghijklmnopqrstuvwxy")
+	src := newSource(t, data, 10, 30)
+	defer src.Close()
+
+	api := &fakeVideoAPI{
+		jobs: []*pb.ImportJob{{
+			Id:         1,
+			VideoId:    "vid",
+			Location:   "loc",
+			Url:        src.URL + "/video.mp4",
+			Size:       uint64(len(data)),
+			PartSize:   10,
+			Downloaded: 10, // first part is imported already
+		}},
+	}
+	st := &fakeStore{objects: make(map[string][]byte)}
+	imp := newTestImporter(t, api, st, src)
+
+	imp.importVideos(context.Background())
+
+	// interrupted downloads are resumed
+	assert.Equal(t, map[string][]byte{
+		"/upload/loc/1": data[10:20],
+		"/upload/loc/2": data[20:30],
+		"/upload/loc/3": data[30:],
+	}, st.objects)
+	assert.Equal(t, []*pb.NotifyPartUploadRequest{
+		{VideoId: "vid", Num: 1, Checksum: checksum(data[10:20])},
+		{VideoId: "vid", Num: 2, Checksum: checksum(data[20:30])},
+		{VideoId: "vid", Num: 3, Checksum: checksum(data[30:])},
+	}, api.notified)
+	assert.Equal(t, []*pb.UpdateImportJobRequest{
+		{Id: 1, Downloaded: 20},
+		{Id: 1, Downloaded: 30},
+		{Id: 1, Downloaded: 35, Done: true},
+	}, api.updates)
+}
+
+func TestImporter_importVideosErrors(t *testing.T) {
+	data := []byte("0123456789abcdefghij")
+	src := newSource(t, data)
+	defer src.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	api := &fakeVideoAPI{
+		jobs: []*pb.ImportJob{
+			// source size has changed
+			{Id: 1, VideoId: "v1", Location: "l1", Url: src.URL, Size: 30, PartSize: 10},
+			// not allowed host
+			{Id: 2, VideoId: "v2", Location: "l2", Url: "http://example.com/video.mp4", Size: 20, PartSize: 10},
+			// source is temporarily unavailable
+			{Id: 3, VideoId: "v3", Location: "l3", Url: failing.URL, Size: 20, PartSize: 10, Downloaded: 10},
+		},
+	}
+	st := &fakeStore{objects: make(map[string][]byte)}
+	imp := newTestImporter(t, api, st, src)
+
+	imp.importVideos(context.Background())
+
+	assert.Empty(t, st.objects)
+	assert.Empty(t, api.notified)
+	require.Len(t, api.updates, 3)
+	assert.True(t, api.updates[0].Fatal)
+	assert.Contains(t, api.updates[0].Error, "source size has changed")
+	assert.True(t, api.updates[1].Fatal)
+	assert.False(t, api.updates[2].Fatal)
+	assert.Equal(t, uint64(10), api.updates[2].Downloaded)
+	assert.Contains(t, api.updates[2].Error, "retries")
+	for _, upd := range api.updates {
+		assert.False(t, upd.Done)
+		assert.NotEmpty(t, upd.Error)
+	}
+}
+
+func Test_rangeReaderLimit(t *testing.T) {
+	data := []byte("0123456789")
+	src := newSource(t, data)
+	defer src.Close()
+	imp := newTestImporter(t, &fakeVideoAPI{}, &fakeStore{}, src)
+
+	r := &rangeReader{ctx: context.Background(), imp: imp, url: src.URL, offset: 2, end: 6, srcSize: 10}
+	defer r.close()
+	var buf bytes.Buffer
+	_, err := io.Copy(&buf, r)
+	require.NoError(t, err)
+	assert.Equal(t, "2345", buf.String())
+}
@@ -0,0 +1,129 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// rangeReader reads byte range of remote file. If connection is interrupted,
+// download is resumed from current offset with new range request.
+type rangeReader struct {
+	ctx      context.Context
+	imp      *Importer
+	body     io.ReadCloser
+	url      string
+	offset   uint64
+	end      uint64
+	srcSize  uint64
+	attempts uint
+}
+
+func (r *rangeReader) Read(p []byte) (int, error) {
+	for {
+		if r.offset >= r.end {
+			return 0, io.EOF
+		}
+		if r.body == nil {
+			if err := r.open(); err != nil {
+				return 0, err
+			}
+		}
+		if uint64(len(p)) > r.end-r.offset {
+			p = p[:r.end-r.offset]
+		}
+		n, err := r.body.Read(p)
+		r.offset += uint64(n)
+		if err != nil {
+			r.close()
+			if r.offset < r.end {
+				if errors.Is(err, io.EOF) {
+					err = io.ErrUnexpectedEOF
+				}
+				if errR := r.retry(err); errR != nil {
+					return n, errR
+				}
+			}
+		}
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+// open requests remaining range, transient failures are retried.
+func (r *rangeReader) open() error {
+	for {
+		err := r.request()
+		if err == nil || errors.Is(err, errFatal) {
+			return err
+		}
+		if errR := r.retry(err); errR != nil {
+			return errR
+		}
+	}
+}
+
+func (r *rangeReader) request() error {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.url, http.NoBody)
+	if err != nil {
+		return errors.Join(errFatal, err)
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", r.offset, r.end-1))
+	resp, err := r.imp.client.Do(req)
+	if err != nil {
+		if errors.Is(err, errFatal) {
+			// redirect to not allowed host
+			return err
+		}
+		return fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		_ = resp.Body.Close()
+		err = fmt.Errorf("unexpected status: %s", resp.Status)
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			return err
+		}
+		// source is gone or does not support ranges anymore
+		return errors.Join(errFatal, err)
+	}
+	var start, end, total uint64
+	if _, err = fmt.Sscanf(resp.Header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &total); err != nil {
+		_ = resp.Body.Close()
+		return errors.Join(errFatal, fmt.Errorf("invalid content range: %w", err))
+	}
+	if total != r.srcSize {
+		_ = resp.Body.Close()
+		return errors.Join(errFatal, fmt.Errorf("source size has changed: %d", total))
+	}
+	if start != r.offset || end != r.end-1 {
+		_ = resp.Body.Close()
+		return errors.Join(errFatal, fmt.Errorf("unexpected content range: %d-%d", start, end))
+	}
+	r.body = resp.Body
+	return nil
+}
+
+// retry waits before next attempt or returns error if attempts are exhausted.
+func (r *rangeReader) retry(err error) error {
+	if r.attempts >= r.imp.retries {
+		return fmt.Errorf("download failed after %d retries: %w", r.attempts, err)
+	}
+	r.attempts++
+	select {
+	case <-r.ctx.Done():
+		return r.ctx.Err()
+	case <-time.After(r.imp.retryDelay):
+	}
+	return nil
+}
+
+func (r *rangeReader) close() {
+	if r.body != nil {
+		_ = r.body.Close()
+		r.body = nil
+	}
+}
@@ -0,0 +1,335 @@
+// Package ingest contains app that receives live streams.
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	video "github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/event"
+	"github.com/adwski/vidi/internal/event/notificator"
+	"github.com/adwski/vidi/internal/media/processor"
+	"github.com/adwski/vidi/internal/mp4"
+	"github.com/adwski/vidi/internal/session"
+	sessionStore "github.com/adwski/vidi/internal/session/store"
+	"github.com/valyala/fasthttp"
+	"github.com/vmihailenco/msgpack/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	internalError = "internal error"
+	notFoundError = "not found"
+)
+
+var (
+	methodPOST   = []byte("POST")
+	methodPUT    = []byte("PUT")
+	methodDELETE = []byte("DELETE")
+)
+
+// SessionStore provides ingest sessions.
+type SessionStore interface {
+	GetExpire(ctx context.Context, id string) (*session.Session, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// Service is a live stream ingest service. It implements fasthttp handler
+// that accepts CMAF fragments pushed by live encoder over HTTP
+// in a way similar to DASH-IF live media ingest (interface 1):
+//
+//	POST|PUT /prefix/<ingest-session-id>/<track>/init.mp4 - track init segment
+//	POST|PUT /prefix/<ingest-session-id>/<track>/<seq>.m4s - track media fragment
+//	DELETE /prefix/<ingest-session-id> - end of stream
+//
+// Every request is checked for valid "ingest"-session.
+//
+// Pushed fragments are re-segmented by live segmenter and stored in media store
+// along with dynamic MPD. Each time new segments become available, videoapi is notified
+// with updated live playback meta. When stream ends (explicitly or after idle timeout),
+// videoapi gets final VOD playback meta, so recorded stream becomes regular video.
+type Service struct {
+	logger       *zap.Logger
+	sessS        SessionStore
+	proc         *processor.Processor
+	notificator  *notificator.Notificator
+	streams      map[string]*stream
+	outputPrefix string
+	uriPrefixLen int
+	timeShift    time.Duration
+	idleTimeout  time.Duration
+	mx           sync.Mutex
+}
+
+type stream struct {
+	ls       *processor.LiveSegmenter
+	lastSeen time.Time
+	videoID  string
+	sessID   string
+}
+
+type Config struct {
+	Logger               *zap.Logger
+	Notificator          *notificator.Notificator
+	SessionStorage       SessionStore
+	MediaStore           processor.MediaStore
+	URIPathPrefix        string
+	OutputPathPrefix     string
+	SegmentDuration      time.Duration
+	TimeShiftBufferDepth time.Duration
+	IdleTimeout          time.Duration
+	// SkipMPD disables writing of MPD objects, see processor.Config.
+	SkipMPD bool
+}
+
+func New(cfg *Config) (*Service, error) {
+	proc, err := processor.New(&processor.Config{
+		Logger:          cfg.Logger,
+		Store:           cfg.MediaStore,
+		SegmentDuration: cfg.SegmentDuration,
+		SkipMPD:         cfg.SkipMPD,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create processor: %w", err)
+	}
+	return &Service{
+		logger:       cfg.Logger.With(zap.String("component", "ingest")),
+		sessS:        cfg.SessionStorage,
+		proc:         proc,
+		notificator:  cfg.Notificator,
+		streams:      make(map[string]*stream),
+		outputPrefix: strings.TrimSuffix(cfg.OutputPathPrefix, "/"),
+		uriPrefixLen: len(cfg.URIPathPrefix),
+		timeShift:    cfg.TimeShiftBufferDepth,
+		idleTimeout:  cfg.IdleTimeout,
+	}, nil
+}
+
+func (svc *Service) Handler() func(*fasthttp.RequestCtx) {
+	return svc.handleIngest
+}
+
+// Run periodically ends streams that have not received anything during idle timeout.
+// All active streams are ended on shutdown.
+func (svc *Service) Run(ctx context.Context, wg *sync.WaitGroup, _ chan<- error) {
+	defer wg.Done()
+	svc.logger.Info("started")
+	ticker := time.NewTicker(svc.idleTimeout / 2) //nolint:mnd // check twice per timeout
+	defer ticker.Stop()
+Loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break Loop
+		case <-ticker.C:
+			for _, st := range svc.popStreams(time.Now().Add(-svc.idleTimeout)) {
+				svc.logger.Info("live stream idle timeout", zap.String("vid", st.videoID))
+				svc.endStream(ctx, st)
+			}
+		}
+	}
+	svc.logger.Info("ending active streams")
+	for _, st := range svc.popStreams(time.Now()) {
+		svc.endStream(context.Background(), st)
+	}
+	svc.logger.Info("stopped")
+}
+
+func (svc *Service) handleIngest(ctx *fasthttp.RequestCtx) {
+	sessID, track, name, err := svc.getParamsFromURI(ctx.Request.RequestURI())
+	if err != nil {
+		svc.logger.Debug("cannot get params from uri", zap.Error(err))
+		ctx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	method := ctx.Method()
+	switch {
+	case bytes.Equal(method, methodDELETE) && track == nil:
+	case (bytes.Equal(method, methodPOST) || bytes.Equal(method, methodPUT)) && track != nil:
+	default:
+		ctx.Error("bad request", fasthttp.StatusBadRequest)
+		return
+	}
+
+	// Retrieve session, encoder pushes continuously, so session ttl is prolonged on every request
+	sess, errSess := svc.sessS.GetExpire(ctx, string(sessID))
+	if errSess != nil {
+		if errors.Is(errSess, sessionStore.ErrNotFound) {
+			ctx.Error(notFoundError, fasthttp.StatusNotFound)
+			return
+		}
+		svc.logger.Error("cannot get session", zap.Error(errSess))
+		ctx.Error(internalError, fasthttp.StatusInternalServerError)
+		return
+	}
+
+	if track == nil {
+		// End of stream
+		st := svc.popStream(sess.ID)
+		if st == nil {
+			ctx.Error(notFoundError, fasthttp.StatusNotFound)
+			return
+		}
+		svc.endStream(ctx, st)
+		ctx.SetStatusCode(fasthttp.StatusNoContent)
+		return
+	}
+
+	st := svc.getStream(sess)
+	body := bytes.NewReader(ctx.Request.Body())
+	if bytes.Equal(name, []byte(mp4.SegmentSuffixInit)) {
+		err = st.ls.AddInit(ctx, string(track), body)
+	} else {
+		var updated bool
+		if updated, err = st.ls.AddFragment(ctx, string(track), body); err == nil && updated {
+			svc.notifyLive(st)
+		}
+	}
+	switch {
+	case err == nil:
+		ctx.SetStatusCode(fasthttp.StatusNoContent)
+	case errors.Is(err, processor.ErrInvalidInit),
+		errors.Is(err, processor.ErrInvalidPayload),
+		errors.Is(err, processor.ErrUnknownTrack),
+		errors.Is(err, processor.ErrTrackStarted):
+		svc.logger.Debug("ingest request rejected", zap.Error(err), zap.String("vid", st.videoID))
+		ctx.Error(err.Error(), fasthttp.StatusBadRequest)
+	case errors.Is(err, processor.ErrStreamEnded):
+		ctx.Error(err.Error(), fasthttp.StatusConflict)
+	default:
+		svc.logger.Error("cannot ingest fragment",
+			zap.Error(err),
+			zap.String("vid", st.videoID),
+			zap.ByteString("track", track))
+		ctx.Error(internalError, fasthttp.StatusInternalServerError)
+	}
+}
+
+// getStream returns active stream for session or starts new one.
+func (svc *Service) getStream(sess *session.Session) *stream {
+	svc.mx.Lock()
+	defer svc.mx.Unlock()
+	st, ok := svc.streams[sess.ID]
+	if !ok {
+		st = &stream{
+			ls:      svc.proc.NewLiveSegmenter(fmt.Sprintf("%s/%s", svc.outputPrefix, sess.Location), svc.timeShift),
+			videoID: sess.VideoID,
+			sessID:  sess.ID,
+		}
+		svc.streams[sess.ID] = st
+		svc.logger.Info("live stream started", zap.String("vid", sess.VideoID))
+	}
+	st.lastSeen = time.Now()
+	return st
+}
+
+func (svc *Service) popStream(sessID string) *stream {
+	svc.mx.Lock()
+	defer svc.mx.Unlock()
+	st, ok := svc.streams[sessID]
+	if !ok {
+		return nil
+	}
+	delete(svc.streams, sessID)
+	return st
+}
+
+// popStreams removes and returns streams that were last seen before specified time.
+func (svc *Service) popStreams(before time.Time) []*stream {
+	svc.mx.Lock()
+	defer svc.mx.Unlock()
+	var streams []*stream
+	for id, st := range svc.streams {
+		if !st.lastSeen.After(before) {
+			streams = append(streams, st)
+			delete(svc.streams, id)
+		}
+	}
+	return streams
+}
+
+func (svc *Service) notifyLive(st *stream) {
+	m, err := st.ls.Meta()
+	if err != nil {
+		svc.logger.Error("cannot get live meta", zap.Error(err), zap.String("vid", st.videoID))
+		return
+	}
+	b, err := msgpack.Marshal(m)
+	if err != nil {
+		svc.logger.Error("cannot marshall live meta", zap.Error(err), zap.String("vid", st.videoID))
+		return
+	}
+	go svc.notificator.Send(&event.Event{
+		VideoInfo: &event.VideoInfo{
+			VideoID: st.videoID,
+			Meta:    b,
+		},
+		Kind: event.KindLiveUpdate,
+	})
+}
+
+// endStream finalizes live stream and notifies videoapi that video is ready for VOD playback.
+// Stream without any media is marked as errored.
+func (svc *Service) endStream(ctx context.Context, st *stream) {
+	if err := svc.sessS.Delete(ctx, st.sessID); err != nil {
+		svc.logger.Error("cannot delete ingest session", zap.Error(err), zap.String("vid", st.videoID))
+	}
+	m, err := st.ls.End(ctx)
+	if err != nil {
+		svc.logger.Error("cannot end live stream", zap.Error(err), zap.String("vid", st.videoID))
+		svc.notifyError(st)
+		return
+	}
+	b, err := msgpack.Marshal(m)
+	if err != nil {
+		svc.logger.Error("cannot marshall playback meta", zap.Error(err), zap.String("vid", st.videoID))
+		svc.notifyError(st)
+		return
+	}
+	svc.notificator.Send(&event.Event{
+		VideoInfo: &event.VideoInfo{
+			VideoID: st.videoID,
+			Meta:    b,
+		},
+		Kind: event.KindVideoReady,
+	})
+	svc.logger.Info("live stream ended", zap.String("vid", st.videoID), zap.Duration("duration", m.Duration))
+}
+
+func (svc *Service) notifyError(st *stream) {
+	svc.notificator.Send(&event.Event{
+		VideoInfo: &event.VideoInfo{
+			VideoID: st.videoID,
+			Status:  int(video.StatusError),
+		},
+		Kind: event.KindUpdateStatus,
+	})
+}
+
+func (svc *Service) getParamsFromURI(uri []byte) (sessID, track, name []byte, err error) {
+	// URI: /prefix/<ingest-session-id>[/<track>/<name>]
+	if svc.uriPrefixLen+1 >= len(uri) {
+		return nil, nil, nil, errors.New("request uri is less than configured prefix")
+	}
+	parts := bytes.Split(uri[svc.uriPrefixLen+1:], []byte("/"))
+	switch {
+	case len(parts) == 1 && len(parts[0]) > 0:
+		return parts[0], nil, nil, nil
+	case len(parts) == 3: //nolint:mnd // session, track and name
+	default:
+		return nil, nil, nil, errors.New("invalid uri")
+	}
+	sessID, track, name = parts[0], parts[1], parts[2]
+	if len(sessID) == 0 || len(track) == 0 {
+		return nil, nil, nil, errors.New("invalid uri")
+	}
+	if !bytes.Equal(name, []byte(mp4.SegmentSuffixInit)) && !bytes.HasSuffix(name, []byte(mp4.SegmentSuffix)) {
+		return nil, nil, nil, errors.New("unknown segment type")
+	}
+	return sessID, track, name, nil
+}
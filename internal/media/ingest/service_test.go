@@ -0,0 +1,74 @@
+package ingest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_getParamsFromURI(t *testing.T) {
+	tests := []struct {
+		name   string
+		uri    string
+		sessID string
+		track  string
+		seg    string
+		err    string
+	}{
+		{
+			name:   "init segment",
+			uri:    "/ingest/sess/video/init.mp4",
+			sessID: "sess",
+			track:  "video",
+			seg:    "init.mp4",
+		},
+		{
+			name:   "media segment",
+			uri:    "/ingest/sess/audio/123.m4s",
+			sessID: "sess",
+			track:  "audio",
+			seg:    "123.m4s",
+		},
+		{
+			name:   "end of stream",
+			uri:    "/ingest/sess",
+			sessID: "sess",
+		},
+		{
+			name: "short uri",
+			uri:  "/ingest",
+			err:  "less than configured prefix",
+		},
+		{
+			name: "missing segment",
+			uri:  "/ingest/sess/video",
+			err:  "invalid uri",
+		},
+		{
+			name: "empty track",
+			uri:  "/ingest/sess//init.mp4",
+			err:  "invalid uri",
+		},
+		{
+			name: "unknown segment",
+			uri:  "/ingest/sess/video/manifest.mpd",
+			err:  "unknown segment type",
+		},
+	}
+	svc := &Service{uriPrefixLen: len("/ingest")}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessID, track, seg, err := svc.getParamsFromURI([]byte(tt.uri))
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.sessID, string(sessID))
+			assert.Equal(t, tt.track, string(track))
+			assert.Equal(t, tt.seg, string(seg))
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	mp4ff "github.com/Eyevinn/mp4ff/mp4"
+	"github.com/adwski/vidi/internal/mp4"
+	"github.com/adwski/vidi/internal/mp4/meta"
+	"github.com/adwski/vidi/internal/mp4/segmentation"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUnknownTrack   = errors.New("unknown track")
+	ErrTrackStarted   = errors.New("track is already started")
+	ErrNoTracks       = errors.New("live stream has no tracks")
+	ErrStreamEnded    = errors.New("live stream has ended")
+	ErrInvalidInit    = errors.New("invalid init segment")
+	ErrInvalidPayload = errors.New("invalid media segment")
+)
+
+// LiveSegmenter re-segments live stream that is pushed as CMAF fragments
+// (DASH-IF live media ingest, interface 1). Each track is pushed separately:
+// first comes init segment, then media fragments in decode order.
+//
+// Samples of pushed fragments are accumulated per track and written to media store
+// as new segments with configured segment duration. Segment boundaries are placed
+// on a common time grid (relative to first sample of each track), so segments with
+// the same number correspond to the same time interval in every track.
+// Video segments can only start with sync sample, so encoder is expected
+// to produce GOPs aligned with segment duration.
+//
+// Resulting segments and MPD use the same naming as processed VOD files,
+// so when stream ends it can be served as regular video.
+//
+// LiveSegmenter is safe for concurrent use.
+type LiveSegmenter struct {
+	p         *Processor
+	tracks    map[string]*liveTrack
+	startTime time.Time
+	location  string
+	timeShift time.Duration
+	mx        sync.Mutex
+	ended     bool
+}
+
+type liveTrack struct {
+	init      *mp4ff.InitSegment
+	trak      *mp4ff.TrakBox
+	name      string
+	pending   []mp4ff.FullSample
+	segments  uint
+	start     uint64
+	end       uint64
+	step      uint64
+	timescale uint32
+	started   bool
+}
+
+// NewLiveSegmenter creates live segmenter that writes segments to specified location.
+// Processor's segment duration is used as live segment duration.
+func (p *Processor) NewLiveSegmenter(location string, timeShiftBufferDepth time.Duration) *LiveSegmenter {
+	return &LiveSegmenter{
+		p:         p,
+		location:  location,
+		timeShift: timeShiftBufferDepth,
+		tracks:    make(map[string]*liveTrack),
+	}
+}
+
+// AddInit reads init segment of ingested track and stores it.
+// Init segment must contain exactly one audio or video track.
+func (ls *LiveSegmenter) AddInit(ctx context.Context, track string, r io.Reader) error {
+	f, err := mp4ff.DecodeFile(r)
+	if err != nil {
+		return errors.Join(ErrInvalidInit, err)
+	}
+	if f.Init == nil || f.Init.Moov == nil || len(f.Init.Moov.Traks) != 1 || f.Init.Moov.Mvex == nil {
+		return fmt.Errorf("%w: single track fragmented init is expected", ErrInvalidInit)
+	}
+	trak := f.Init.Moov.Trak
+	switch trak.Mdia.Hdlr.HandlerType {
+	case "vide", "soun":
+	default:
+		return fmt.Errorf("%w: unsupported track type %s", ErrInvalidInit, trak.Mdia.Hdlr.HandlerType)
+	}
+	if trak.Mdia.Mdhd.Timescale == 0 {
+		return fmt.Errorf("%w: zero timescale", ErrInvalidInit)
+	}
+
+	ls.mx.Lock()
+	defer ls.mx.Unlock()
+	if ls.ended {
+		return ErrStreamEnded
+	}
+	lt, ok := ls.tracks[track]
+	if ok && lt.started {
+		return ErrTrackStarted
+	}
+	if !ok {
+		lt = &liveTrack{name: ls.makeTrackName(trak.Mdia.Hdlr.HandlerType)}
+	}
+	lt.init = f.Init
+	lt.trak = trak
+	lt.timescale = trak.Mdia.Mdhd.Timescale
+	lt.step = uint64(ls.p.segmentDuration.Seconds() * float64(lt.timescale))
+	if err = ls.p.storeBox(ctx,
+		fmt.Sprintf("%s/%s_%s", ls.location, lt.name, mp4.SegmentSuffixInit), f.Init, f.Init.Size()); err != nil {
+		return err
+	}
+	ls.tracks[track] = lt
+	ls.p.logger.Debug("live track initialized",
+		zap.String("location", ls.location),
+		zap.String("track", track),
+		zap.String("name", lt.name))
+	return nil
+}
+
+// makeTrackName generates output track name that is similar to VOD track names.
+func (ls *LiveSegmenter) makeTrackName(handlerType string) string {
+	var n int
+	for _, lt := range ls.tracks {
+		if lt.trak.Mdia.Hdlr.HandlerType == handlerType {
+			n++
+		}
+	}
+	return fmt.Sprintf("%s%d", handlerType, n+1)
+}
+
+// AddFragment reads media fragment of ingested track and writes
+// all segments that can be completed using accumulated samples.
+// It returns true if new segments became available in every track,
+// in this case dynamic MPD is also updated in media store.
+func (ls *LiveSegmenter) AddFragment(ctx context.Context, track string, r io.Reader) (bool, error) {
+	f, err := mp4ff.DecodeFile(r)
+	if err != nil {
+		return false, errors.Join(ErrInvalidPayload, err)
+	}
+
+	ls.mx.Lock()
+	defer ls.mx.Unlock()
+	if ls.ended {
+		return false, ErrStreamEnded
+	}
+	lt, ok := ls.tracks[track]
+	if !ok {
+		return false, ErrUnknownTrack
+	}
+	trex, okT := lt.init.Moov.Mvex.GetTrex(lt.trak.Tkhd.TrackID)
+	if !okT {
+		trex = lt.init.Moov.Mvex.Trex
+	}
+	for _, seg := range f.Segments {
+		for _, frag := range seg.Fragments {
+			samples, errS := frag.GetFullSamples(trex)
+			if errS != nil {
+				return false, errors.Join(ErrInvalidPayload, errS)
+			}
+			lt.addSamples(samples)
+		}
+	}
+	if ls.startTime.IsZero() && lt.started {
+		ls.startTime = time.Now()
+	}
+
+	before := ls.available()
+	if err = ls.writeSegments(ctx, lt, false); err != nil {
+		return false, err
+	}
+	if ls.available() == before {
+		return false, nil
+	}
+	m, err := ls.meta(false)
+	if err != nil {
+		return false, err
+	}
+	if err = ls.p.storeMPD(ctx, ls.location, m); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// End writes remaining samples of every track as last segments
+// and returns final VOD playback meta. Static MPD is stored in media store.
+func (ls *LiveSegmenter) End(ctx context.Context) (*meta.Meta, error) {
+	ls.mx.Lock()
+	defer ls.mx.Unlock()
+	if ls.ended {
+		return nil, ErrStreamEnded
+	}
+	ls.ended = true
+	for _, lt := range ls.tracks {
+		if err := ls.writeSegments(ctx, lt, true); err != nil {
+			return nil, err
+		}
+	}
+	m, err := ls.meta(true)
+	if err != nil {
+		return nil, err
+	}
+	if err = ls.p.storeMPD(ctx, ls.location, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Meta returns current live playback meta.
+func (ls *LiveSegmenter) Meta() (*meta.Meta, error) {
+	ls.mx.Lock()
+	defer ls.mx.Unlock()
+	return ls.meta(ls.ended)
+}
+
+func (ls *LiveSegmenter) meta(final bool) (*meta.Meta, error) {
+	if len(ls.tracks) == 0 {
+		return nil, ErrNoTracks
+	}
+	var (
+		duration time.Duration
+		tracks   = make([]meta.Track, 0, len(ls.tracks))
+	)
+	for _, lt := range ls.tracks {
+		mimeType, err := getMimeTypeFromMP4TrackHandlerType(lt.trak.Mdia.Hdlr.HandlerType)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get handler type: %w", err)
+		}
+		codec, errC := meta.NewCodecFromSTSD(lt.trak.Mdia.Minf.Stbl.Stsd)
+		if errC != nil {
+			return nil, fmt.Errorf("cannot get codec: %w", errC)
+		}
+		tracks = append(tracks, meta.Track{
+			Name:     lt.name,
+			MimeType: mimeType,
+			Language: trackLanguage(lt.trak),
+			Codec:    codec,
+			Segment: &meta.SegmentConfig{
+				Init:        mp4.SegmentSuffixInit,
+				StartNumber: 1,
+				Duration:    lt.step,
+				Timescale:   lt.timescale,
+			},
+		})
+		// Presentation ends with the shortest track, since tail of
+		// longer tracks may be written as extra segment.
+		if d := time.Duration(lt.end-lt.start) * time.Second / time.Duration(lt.timescale); duration == 0 || d < duration {
+			duration = d
+		}
+	}
+	sort.Slice(tracks, func(i, j int) bool { return tracks[i].Name < tracks[j].Name })
+	m := &meta.Meta{
+		Tracks:   tracks,
+		Duration: duration,
+	}
+	if !final {
+		m.Live = &meta.LiveInfo{
+			AvailabilityStartTime: ls.startTime,
+			PublishTime:           time.Now(),
+			TimeShiftBufferDepth:  ls.timeShift,
+			SegmentsAvailable:     ls.available(),
+		}
+	}
+	return m, nil
+}
+
+// available returns amount of segments that are written in every track.
+func (ls *LiveSegmenter) available() uint {
+	var (
+		n     uint
+		first = true
+	)
+	for _, lt := range ls.tracks {
+		if first || lt.segments < n {
+			n = lt.segments
+			first = false
+		}
+	}
+	return n
+}
+
+func (lt *liveTrack) addSamples(samples []mp4ff.FullSample) {
+	if len(samples) == 0 {
+		return
+	}
+	if !lt.started {
+		lt.start = samples[0].DecodeTime
+		lt.started = true
+	}
+	last := samples[len(samples)-1]
+	lt.end = last.DecodeTime + uint64(last.Dur)
+	lt.pending = append(lt.pending, samples...)
+}
+
+// writeSegments writes pending samples as segments. Segment is complete
+// when there is a sync sample past its end boundary. If final is set,
+// all remaining samples are written as last segment.
+func (ls *LiveSegmenter) writeSegments(ctx context.Context, lt *liveTrack, final bool) error {
+	for len(lt.pending) > 0 {
+		cut := len(lt.pending)
+		if !final {
+			if cut = lt.findCut(); cut == -1 {
+				return nil
+			}
+		}
+		segNum := int(lt.segments) + 1
+		seg, err := segmentation.CreateSegment(segNum, lt.trak.Tkhd.TrackID, lt.pending[:cut])
+		if err != nil {
+			return fmt.Errorf("cannot create live segment: %w", err)
+		}
+		name := fmt.Sprintf("%s/%s_%d%s", ls.location, lt.name, segNum, mp4.SegmentSuffix)
+		if err = ls.p.storeBox(ctx, name, seg, seg.Size()); err != nil {
+			return err
+		}
+		lt.segments++
+		lt.pending = lt.pending[cut:]
+	}
+	return nil
+}
+
+// findCut returns index of first pending sample of the next segment
+// or -1 if current segment is not complete yet.
+func (lt *liveTrack) findCut() int {
+	boundary := lt.start + uint64(lt.segments+1)*lt.step
+	for i := 1; i < len(lt.pending); i++ {
+		if lt.pending[i].DecodeTime >= boundary && lt.isSync(&lt.pending[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isSync checks if segment can start with sample.
+// Every audio sample is sync sample, so flags are checked only for video.
+func (lt *liveTrack) isSync(s *mp4ff.FullSample) bool {
+	if lt.trak.Mdia.Hdlr.HandlerType == "soun" {
+		return true
+	}
+	return !mp4ff.DecodeSampleFlags(s.Flags).SampleIsNonSync
+}
@@ -0,0 +1,184 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adwski/vidi/internal/media/store/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	testFile         = "../../../testfiles/test_seq_h264_high.mp4"
+	testFileSegments = 10
+)
+
+// makeLiveInput produces CMAF fragments that are used as pushed live input.
+func makeLiveInput(t *testing.T, dir string) {
+	t.Helper()
+
+	p, err := New(&Config{
+		Logger:          zap.NewNop(),
+		Store:           file.NewStore(dir, dir),
+		SegmentDuration: time.Second,
+	})
+	require.NoError(t, err)
+	f, err := os.Open(testFile)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	_, err = p.ProcessFileFromReader(context.Background(), f, "in")
+	require.NoError(t, err)
+}
+
+func pushFile(t *testing.T, name string, push func(*os.File) error) {
+	t.Helper()
+
+	f, err := os.Open(name)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	require.NoError(t, push(f))
+}
+
+func TestLiveSegmenter(t *testing.T) {
+	var (
+		ctx = context.Background()
+		tmp = t.TempDir()
+	)
+	makeLiveInput(t, tmp)
+
+	p, err := New(&Config{
+		Logger:          zap.NewNop(),
+		Store:           file.NewStore(tmp, tmp),
+		SegmentDuration: 2 * time.Second,
+	})
+	require.NoError(t, err)
+	ls := p.NewLiveSegmenter("live", time.Minute)
+
+	_, err = ls.Meta()
+	require.ErrorIs(t, err, ErrNoTracks)
+
+	tracks := map[string]string{"video": "vide1", "audio": "soun1"}
+	for track, in := range tracks {
+		pushFile(t, fmt.Sprintf("%s/in/%s_init.mp4", tmp, in), func(f *os.File) error {
+			return ls.AddInit(ctx, track, f)
+		})
+	}
+
+	var updates int
+	for i := 1; i <= testFileSegments; i++ {
+		for track, in := range tracks {
+			pushFile(t, fmt.Sprintf("%s/in/%s_%d.m4s", tmp, in, i), func(f *os.File) error {
+				updated, errF := ls.AddFragment(ctx, track, f)
+				if updated {
+					updates++
+				}
+				return errF
+			})
+		}
+	}
+	// last segment is written only after end of stream
+	assert.Equal(t, testFileSegments/2-1, updates)
+
+	m, err := ls.Meta()
+	require.NoError(t, err)
+	require.NotNil(t, m.Live)
+	assert.Equal(t, uint(testFileSegments/2-1), m.Live.SegmentsAvailable)
+	assert.False(t, m.Live.AvailabilityStartTime.IsZero())
+	assert.Equal(t, time.Minute, m.Live.TimeShiftBufferDepth)
+
+	bMPD, err := os.ReadFile(tmp + "/live/manifest.mpd")
+	require.NoError(t, err)
+	assert.Contains(t, string(bMPD), `type="dynamic"`)
+
+	m, err = ls.End(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, m.Live)
+	assert.InDelta(t, 10*time.Second, m.Duration, float64(100*time.Millisecond))
+	require.Len(t, m.Tracks, 2)
+	assert.Equal(t, "soun1", m.Tracks[0].Name)
+	assert.Equal(t, "vide1", m.Tracks[1].Name)
+
+	bMPD, err = os.ReadFile(tmp + "/live/manifest.mpd")
+	require.NoError(t, err)
+	assert.Contains(t, string(bMPD), `type="static"`)
+
+	entries, err := os.ReadDir(tmp + "/live")
+	require.NoError(t, err)
+	var segments int
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), "vide1_") && strings.HasSuffix(e.Name(), ".m4s") {
+			segments++
+		}
+	}
+	assert.Equal(t, testFileSegments/2, segments)
+
+	_, err = ls.AddFragment(ctx, "video", strings.NewReader(""))
+	require.ErrorIs(t, err, ErrStreamEnded)
+	_, err = ls.End(ctx)
+	require.ErrorIs(t, err, ErrStreamEnded)
+}
+
+func TestLiveSegmenter_Errors(t *testing.T) {
+	var (
+		ctx = context.Background()
+		tmp = t.TempDir()
+	)
+	makeLiveInput(t, tmp)
+
+	p, err := New(&Config{
+		Logger:          zap.NewNop(),
+		Store:           file.NewStore(tmp, tmp),
+		SegmentDuration: 2 * time.Second,
+	})
+	require.NoError(t, err)
+	ls := p.NewLiveSegmenter("live", time.Minute)
+
+	err = ls.AddInit(ctx, "video", strings.NewReader("not an mp4"))
+	require.ErrorIs(t, err, ErrInvalidInit)
+
+	pushFile(t, tmp+"/in/vide1_1.m4s", func(f *os.File) error {
+		_, errF := ls.AddFragment(ctx, "video", f)
+		assert.ErrorIs(t, errF, ErrUnknownTrack)
+		return nil
+	})
+
+	pushFile(t, tmp+"/in/vide1_init.mp4", func(f *os.File) error {
+		return ls.AddInit(ctx, "video", f)
+	})
+	pushFile(t, tmp+"/in/vide1_1.m4s", func(f *os.File) error {
+		_, errF := ls.AddFragment(ctx, "video", f)
+		return errF
+	})
+	pushFile(t, tmp+"/in/vide1_init.mp4", func(f *os.File) error {
+		assert.ErrorIs(t, ls.AddInit(ctx, "video", f), ErrTrackStarted)
+		return nil
+	})
+}
+
+func TestProcessor_SkipMPD(t *testing.T) {
+	tmp := t.TempDir()
+	p, err := New(&Config{
+		Logger:          zap.NewNop(),
+		Store:           file.NewStore(tmp, tmp),
+		SegmentDuration: time.Second,
+		SkipMPD:         true,
+	})
+	require.NoError(t, err)
+	f, err := os.Open(testFile)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	m, err := p.ProcessFileFromReader(context.Background(), f, "out")
+	require.NoError(t, err)
+	require.NotEmpty(t, m.Tracks)
+
+	_, err = os.Stat(tmp + "/out/manifest.mpd")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	_, err = os.Stat(tmp + "/out/vide1_init.mp4")
+	require.NoError(t, err)
+}
@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -33,6 +34,7 @@ func (p *Processor) generatePlaybackMeta(
 		dashTracks[i] = meta.Track{
 			Name:     mp4.SegmentName(track),
 			MimeType: mimeType,
+			Language: trackLanguage(track),
 			Codec:    codec,
 			// TODO idk if bandwidth is mandatory for only one representation (probably not).
 			//   For future, it probably could be estimated using avg bitrate
@@ -53,9 +55,37 @@ func (p *Processor) generatePlaybackMeta(
 	return &meta.Meta{
 		Duration: time.Duration(int64(totalDuration)/int64(timescale)) * time.Second,
 		Tracks:   dashTracks,
+		Processing: &meta.ProcessingInfo{
+			Version:         Version,
+			SegmentDuration: p.segmentDuration,
+		},
 	}, nil
 }
 
+// storeMPD places MPD to media store, so players could still use watch URLs
+// served by streamer as stored objects.
+func (p *Processor) storeMPD(ctx context.Context, location string, m *meta.Meta) error {
+	if p.skipMPD {
+		return nil
+	}
+	bMPD, err := m.MPD("")
+	if err != nil {
+		return fmt.Errorf("cannot generate mpd: %w", err)
+	}
+	return p.storeBytes(ctx, fmt.Sprintf("%s/%s", location, mp4.MPDSuffix), bMPD)
+}
+
+// trackLanguage returns language of track, undetermined language is omitted.
+func trackLanguage(track *mp4ff.TrakBox) string {
+	if track.Mdia.Mdhd.Language == 0 {
+		return ""
+	}
+	if lang := track.Mdia.Mdhd.GetLanguage(); lang != "und" {
+		return lang
+	}
+	return ""
+}
+
 func getMimeTypeFromMP4TrackHandlerType(handlerType string) (string, error) {
 	switch handlerType {
 	case "soun":
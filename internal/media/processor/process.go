@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 
-	"github.com/adwski/vidi/internal/mp4"
 	"github.com/adwski/vidi/internal/mp4/meta"
 
 	mp4ff "github.com/Eyevinn/mp4ff/mp4"
@@ -44,12 +43,7 @@ func (p *Processor) ProcessFileFromReader(ctx context.Context, rs io.ReadSeeker,
 		return nil, fmt.Errorf("cannot generate playback meta: %w", err)
 	}
 
-	// place static MPD to s3 as well so generated watch URLs would still work until we have proper web UI
-	bMPD, err := playbackMeta.StaticMPD("")
-	if err != nil {
-		return nil, fmt.Errorf("cannot generate static mpd: %w", err)
-	}
-	if err = p.storeBytes(ctx, fmt.Sprintf("%s/%s", location, mp4.MPDSuffix), bMPD); err != nil {
+	if err = p.storeMPD(ctx, location, playbackMeta); err != nil {
 		return nil, err
 	}
 
@@ -23,9 +23,10 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-const (
-	defaultPartSize = 10 * 1024 * 1024
-)
+// Version is a processor version that is stored in playback meta of processed videos.
+// It should be changed whenever processing produces different output for the same input,
+// so previously processed videos could be found and reprocessed.
+const Version = "1"
 
 type MediaStore interface {
 	Put(ctx context.Context, name string, r io.Reader, size int64) error
@@ -49,6 +50,7 @@ type Processor struct {
 	outputPathPrefix string
 	segmentDuration  time.Duration
 	videoCheckPeriod time.Duration
+	skipMPD          bool
 }
 
 type Config struct {
@@ -61,6 +63,9 @@ type Config struct {
 	OutputPathPrefix string
 	SegmentDuration  time.Duration
 	VideoCheckPeriod time.Duration
+	// SkipMPD disables writing of MPD objects to media store.
+	// It could be set once streamer renders MPDs from playback meta.
+	SkipMPD bool
 }
 
 func New(cfg *Config) (*Processor, error) {
@@ -71,6 +76,7 @@ func New(cfg *Config) (*Processor, error) {
 			logger:          cfg.Logger.With(zap.String("component", "processor")),
 			st:              cfg.Store,
 			segmentDuration: cfg.SegmentDuration,
+			skipMPD:         cfg.SkipMPD,
 		}, nil
 	}
 	cc, err := grpc.Dial(cfg.VideoAPIEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -83,6 +89,7 @@ func New(cfg *Config) (*Processor, error) {
 		notificator:      cfg.Notificator,
 		segmentDuration:  cfg.SegmentDuration,
 		videoCheckPeriod: cfg.VideoCheckPeriod,
+		skipMPD:          cfg.SkipMPD,
 		inputPathPrefix:  strings.TrimSuffix(cfg.InputPathPrefix, "/"),
 		outputPathPrefix: strings.TrimSuffix(cfg.OutputPathPrefix, "/"),
 		videoAPI:         pb.NewServicesideapiClient(cc),
@@ -105,22 +112,27 @@ Loop:
 	p.logger.Info("stopped")
 }
 
-func (p *Processor) getUploadedVideos(ctx context.Context) ([]*pb.Video, error) {
-	resp, err := p.videoAPI.GetVideosByStatus(metadata.NewOutgoingContext(ctx, p.authMD),
-		&pb.GetByStatusRequest{Status: int32(video.StatusUploaded)})
-	if err != nil {
-		if status.Code(err) != codes.NotFound {
-			return nil, fmt.Errorf("unable to retrieve uploaded videos: %w", err)
+// getVideosForProcessing returns uploaded videos and videos queued for reprocessing.
+func (p *Processor) getVideosForProcessing(ctx context.Context) ([]*pb.Video, error) {
+	var videos []*pb.Video
+	for _, st := range []video.Status{video.StatusUploaded, video.StatusReprocessing} {
+		resp, err := p.videoAPI.GetVideosByStatus(metadata.NewOutgoingContext(ctx, p.authMD),
+			&pb.GetByStatusRequest{Status: int32(st)})
+		if err != nil {
+			if status.Code(err) != codes.NotFound {
+				return nil, fmt.Errorf("unable to retrieve %s videos: %w", st, err)
+			}
+			continue
 		}
-		return nil, nil
+		videos = append(videos, resp.Videos...)
 	}
-	return resp.Videos, nil
+	return videos, nil
 }
 
 func (p *Processor) checkAndProcessVideos(ctx context.Context) {
 	p.logger.Debug("checking videos")
 
-	videos, err := p.getUploadedVideos(ctx)
+	videos, err := p.getVideosForProcessing(ctx)
 	if err != nil {
 		p.logger.Error("cannot get videos from video API", zap.Error(err))
 		return
@@ -167,22 +179,30 @@ func (p *Processor) processVideo(ctx context.Context, v *pb.Video) ([]byte, erro
 	p.logger.Debug("processing video",
 		zap.String("id", v.Id),
 		zap.String("location", v.Location),
+		zap.String("output_location", v.OutputLocation),
 		zap.Int("parts", len(v.Parts)),
-		zap.Uint64("size", v.Size))
+		zap.Uint64("size", v.Size),
+		zap.Uint64("part_size", v.PartSize))
+	partSize := v.PartSize
+	if partSize == 0 {
+		// videoapi does not provide part size
+		partSize = video.DefaultPartSize
+	}
 	switch {
 	case len(v.Parts) == 0:
 		return nil, errors.New("video has no parts")
 	case v.Size == 0:
 		return nil, errors.New("video has zero size")
-	case defaultPartSize*uint64(len(v.Parts)-1) > v.Size || v.Size > defaultPartSize*uint64(len(v.Parts)):
-		return nil, fmt.Errorf("incorrect parts amount(%d) for video size(%d)", len(v.Parts), v.Size)
+	case !video.PartsMatchSize(len(v.Parts), partSize, v.Size):
+		return nil, fmt.Errorf("incorrect parts amount(%d) for video size(%d) and part size(%d)",
+			len(v.Parts), v.Size, partSize)
 	}
 	mr := newMediaReader(
 		p.st,
 		fmt.Sprintf("%s/%s", p.inputPathPrefix, v.Location),
 		uint(len(v.Parts)),
 		v.Size,
-		defaultPartSize)
+		partSize)
 	defer func() {
 		if err := mr.Close(); err != nil {
 			p.logger.Error("error closing media reader",
@@ -190,7 +210,12 @@ func (p *Processor) processVideo(ctx context.Context, v *pb.Video) ([]byte, erro
 				zap.String("vid", v.Id))
 		}
 	}()
+	// Reprocessed videos are written to new output location,
+	// so previous output is served until processing is finished.
 	outLocation := fmt.Sprintf("%s/%s", p.outputPathPrefix, v.Location)
+	if v.OutputLocation != "" {
+		outLocation = fmt.Sprintf("%s/%s", p.outputPathPrefix, v.OutputLocation)
+	}
 	playbackMeta, err := p.ProcessFileFromReader(ctx, mr, outLocation)
 	if err != nil {
 		return nil, fmt.Errorf("error processing file: %w", err)
@@ -0,0 +1,130 @@
+// Package purger contains media purger which removes media of deleted videos.
+package purger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/grpc/serviceside/pb"
+	video "github.com/adwski/vidi/internal/api/video/model"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	defaultBatchSize = 10
+)
+
+type MediaStore interface {
+	DeletePrefix(ctx context.Context, prefix string) (int, error)
+}
+
+// Purger is worker-style app that polls videoapi for media purge jobs,
+// removes upload parts and processed output of deleted videos
+// and reports progress back to videoapi.
+//
+// Failed jobs are retried later by videoapi with backoff.
+type Purger struct {
+	logger           *zap.Logger
+	videoAPI         pb.ServicesideapiClient
+	authMD           metadata.MD
+	st               MediaStore
+	inputPathPrefix  string
+	outputPathPrefix string
+	checkPeriod      time.Duration
+	batchSize        uint32
+}
+
+type Config struct {
+	Logger           *zap.Logger
+	Store            MediaStore
+	VideoAPIEndpoint string
+	VideoAPIToken    string
+	InputPathPrefix  string
+	OutputPathPrefix string
+	CheckPeriod      time.Duration
+	BatchSize        uint
+}
+
+func New(cfg *Config) (*Purger, error) {
+	cc, err := grpc.Dial(cfg.VideoAPIEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create vidi connection: %w", err)
+	}
+	batchSize := uint32(cfg.BatchSize)
+	if batchSize == 0 {
+		batchSize = defaultBatchSize
+	}
+	return &Purger{
+		logger:           cfg.Logger.With(zap.String("component", "purger")),
+		st:               cfg.Store,
+		checkPeriod:      cfg.CheckPeriod,
+		batchSize:        batchSize,
+		inputPathPrefix:  strings.TrimSuffix(cfg.InputPathPrefix, "/"),
+		outputPathPrefix: strings.TrimSuffix(cfg.OutputPathPrefix, "/"),
+		videoAPI:         pb.NewServicesideapiClient(cc),
+		authMD:           metadata.Pairs("authorization", "bearer "+cfg.VideoAPIToken),
+	}, nil
+}
+
+func (p *Purger) Run(ctx context.Context, wg *sync.WaitGroup, _ chan<- error) {
+	defer wg.Done()
+	p.logger.Info("started")
+	ticker := time.NewTicker(p.checkPeriod)
+	defer ticker.Stop()
+Loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break Loop
+		case <-ticker.C:
+			p.purge(ctx)
+		}
+	}
+	p.logger.Info("stopped")
+}
+
+func (p *Purger) purge(ctx context.Context) {
+	ctx = metadata.NewOutgoingContext(ctx, p.authMD)
+	resp, err := p.videoAPI.GetPurgeJobs(ctx, &pb.GetPurgeJobsRequest{Limit: p.batchSize})
+	if err != nil {
+		p.logger.Error("cannot get purge jobs from video API", zap.Error(err))
+		return
+	}
+	for _, job := range resp.Jobs {
+		upd := &pb.UpdatePurgeJobRequest{Id: job.Id, Done: true}
+		n, errP := p.st.DeletePrefix(ctx, p.prefix(job))
+		upd.Deleted = uint64(n)
+		if errP != nil {
+			upd.Done = false
+			upd.Error = errP.Error()
+			p.logger.Error("cannot purge media",
+				zap.String("video_id", job.VideoId),
+				zap.String("location", job.Location),
+				zap.Uint32("attempts", job.Attempts),
+				zap.Error(errP))
+		} else {
+			p.logger.Debug("media purged",
+				zap.String("video_id", job.VideoId),
+				zap.String("location", job.Location),
+				zap.Int("deleted", n))
+		}
+		if _, err = p.videoAPI.UpdatePurgeJob(ctx, upd); err != nil {
+			// job will be returned again after lease expires
+			p.logger.Error("cannot report purge progress", zap.Int64("id", job.Id), zap.Error(err))
+		}
+	}
+}
+
+func (p *Purger) prefix(job *pb.PurgeJob) string {
+	pathPrefix := p.outputPathPrefix
+	if video.PurgeKind(job.Kind) == video.PurgeKindUpload {
+		pathPrefix = p.inputPathPrefix
+	}
+	return fmt.Sprintf("%s/%s/", pathPrefix, job.Location)
+}
@@ -0,0 +1,83 @@
+package purger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/adwski/vidi/internal/api/video/grpc/serviceside/pb"
+	video "github.com/adwski/vidi/internal/api/video/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+type fakeVideoAPI struct {
+	pb.ServicesideapiClient
+	jobs    []*pb.PurgeJob
+	updates []*pb.UpdatePurgeJobRequest
+}
+
+func (f *fakeVideoAPI) GetPurgeJobs(
+	_ context.Context,
+	_ *pb.GetPurgeJobsRequest,
+	_ ...grpc.CallOption,
+) (*pb.PurgeJobsResponse, error) {
+	return &pb.PurgeJobsResponse{Jobs: f.jobs}, nil
+}
+
+func (f *fakeVideoAPI) UpdatePurgeJob(
+	_ context.Context,
+	req *pb.UpdatePurgeJobRequest,
+	_ ...grpc.CallOption,
+) (*pb.UpdatePurgeJobResponse, error) {
+	f.updates = append(f.updates, req)
+	return &pb.UpdatePurgeJobResponse{}, nil
+}
+
+type fakeStore struct {
+	deleted map[string]int
+	fail    string
+}
+
+func (f *fakeStore) DeletePrefix(_ context.Context, prefix string) (int, error) {
+	if prefix == f.fail {
+		return 1, errors.New("test")
+	}
+	return f.deleted[prefix], nil
+}
+
+func TestPurger_purge(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	api := &fakeVideoAPI{
+		jobs: []*pb.PurgeJob{
+			{Id: 1, VideoId: "v1", Kind: int32(video.PurgeKindUpload), Location: "loc1"},
+			{Id: 2, VideoId: "v1", Kind: int32(video.PurgeKindOutput), Location: "loc1"},
+			{Id: 3, VideoId: "v2", Kind: int32(video.PurgeKindOutput), Location: "loc2"},
+		},
+	}
+	st := &fakeStore{
+		deleted: map[string]int{
+			"/upload/loc1/": 3,
+			"/watch/loc1/":  10,
+		},
+		fail: "/watch/loc2/",
+	}
+	p := &Purger{
+		logger:           logger,
+		videoAPI:         api,
+		st:               st,
+		inputPathPrefix:  "/upload",
+		outputPathPrefix: "/watch",
+	}
+
+	p.purge(context.Background())
+
+	require.Len(t, api.updates, 3)
+	assert.Equal(t, &pb.UpdatePurgeJobRequest{Id: 1, Deleted: 3, Done: true}, api.updates[0])
+	assert.Equal(t, &pb.UpdatePurgeJobRequest{Id: 2, Deleted: 10, Done: true}, api.updates[1])
+	assert.Equal(t, &pb.UpdatePurgeJobRequest{Id: 3, Deleted: 1, Error: "test"}, api.updates[2])
+}
@@ -32,6 +32,10 @@ type Config struct {
 	WriteTimeout  time.Duration
 	IdleTimeout   time.Duration
 	MaxBodySize   uint
+
+	// StreamRequestBody enables request body streaming.
+	// Bodies larger than MaxBodySize are streamed to handler instead of being rejected.
+	StreamRequestBody bool
 }
 
 func New(cfg *Config) *Server {
@@ -47,8 +51,8 @@ func New(cfg *Config) *Server {
 			WriteTimeout:       cfg.WriteTimeout,
 			IdleTimeout:        cfg.IdleTimeout,
 			MaxRequestBodySize: int(cfg.MaxBodySize),
+			StreamRequestBody:  cfg.StreamRequestBody,
 			CloseOnShutdown:    true,
-			//StreamRequestBody:  true,
 
 			// Prevent handling of potentially large requests
 			// by forbidding all 100s, since we're not using them now.
@@ -71,7 +75,8 @@ func (s *Server) Run(ctx context.Context, wg *sync.WaitGroup, errc chan<- error)
 
 	s.logger.Info("server started",
 		zap.String("address", s.addr),
-		zap.Int("maxBodySize", s.srv.MaxRequestBodySize))
+		zap.Int("maxBodySize", s.srv.MaxRequestBodySize),
+		zap.Bool("streamRequestBody", s.srv.StreamRequestBody))
 
 	select {
 	case <-ctx.Done():
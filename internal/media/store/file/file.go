@@ -2,18 +2,32 @@ package file
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
+
+	"github.com/minio/sha256-simd"
 )
 
 const (
 	defaultFilePermissions = 0600
 	defaultDirPermissions  = 0700
+
+	tmpFilePattern = ".tmp-*"
 )
 
+var ErrInvalidName = errors.New("invalid name")
+
 // Store is media store that uses file system.
+// Files are read from input path, while written, listed and deleted in output path.
+//
+// Names are always resolved inside corresponding path, names with ".." elements are rejected.
+// Files are written atomically: content is written to temporary file which is then renamed.
 type Store struct {
 	inputPathPrefix string
 	outPathPrefix   string
@@ -26,37 +40,159 @@ func NewStore(inPath, outPath string) *Store {
 	}
 }
 
+// resolve returns path of name inside root.
+func resolve(root, name string) (string, error) {
+	for _, elem := range strings.Split(name, "/") {
+		if elem == ".." {
+			return "", fmt.Errorf("%w: %s", ErrInvalidName, name)
+		}
+	}
+	return filepath.Join(root, filepath.FromSlash(name)), nil
+}
+
 func (s *Store) Get(_ context.Context, name string) (io.ReadSeekCloser, int64, error) {
-	fullName := fmt.Sprintf("%s/%s", s.inputPathPrefix, name)
+	fullName, err := resolve(s.inputPathPrefix, name)
+	if err != nil {
+		return nil, 0, err
+	}
 	f, err := os.Open(fullName)
 	if err != nil {
 		return nil, 0, fmt.Errorf("cannot open file: %w", err)
 	}
 	stat, errS := f.Stat()
 	if errS != nil {
+		_ = f.Close()
 		return nil, 0, fmt.Errorf("cannot get file stats: %w", errS)
 	}
 	return f, stat.Size(), nil
 }
 
+// CalcSha256 calculates sha256 checksum of file and returns it base64 encoded.
+func (s *Store) CalcSha256(ctx context.Context, name string) (string, error) {
+	f, _, err := s.Get(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	shaW := sha256.New()
+	if _, err = io.Copy(shaW, f); err != nil {
+		return "", fmt.Errorf("cannot calculate file sha256: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(shaW.Sum(nil)), nil
+}
+
 func (s *Store) Put(_ context.Context, name string, r io.Reader, size int64) error {
-	fullName := fmt.Sprintf("%s/%s", s.outPathPrefix, name)
-	if errD := os.MkdirAll(fullName[:strings.LastIndexByte(fullName, '/')], defaultDirPermissions); errD != nil {
+	fullName, err := resolve(s.outPathPrefix, name)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(fullName)
+	if errD := os.MkdirAll(dir, defaultDirPermissions); errD != nil {
 		return fmt.Errorf("cannot create dir: %w", errD)
 	}
-	f, err := os.OpenFile(fullName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, defaultFilePermissions)
+	f, err := os.CreateTemp(dir, tmpFilePattern)
 	if err != nil {
-		return fmt.Errorf("cannot open file for writing: %w", err)
+		return fmt.Errorf("cannot create temp file: %w", err)
 	}
+	tmpName := f.Name()
 	defer func() {
-		_ = f.Close()
+		// does nothing if file was renamed
+		_ = os.Remove(tmpName)
 	}()
 	n, errW := io.Copy(f, r) // Reader MUST send EOF otherwise there will be deadlock
+	if errC := f.Close(); errC != nil && errW == nil {
+		errW = errC
+	}
 	if errW != nil {
 		return fmt.Errorf("error writing to file: %w", errW)
 	}
 	if n != size {
 		return fmt.Errorf("wrote incorrect amount of bytes: expected: %d, actual: %d", size, n)
 	}
+	if err = os.Chmod(tmpName, defaultFilePermissions); err != nil {
+		return fmt.Errorf("cannot set file permissions: %w", err)
+	}
+	if err = os.Rename(tmpName, fullName); err != nil {
+		return fmt.Errorf("cannot rename temp file: %w", err)
+	}
 	return nil
 }
+
+// Delete removes file. Removing nonexistent file is not an error.
+func (s *Store) Delete(_ context.Context, name string) error {
+	fullName, err := resolve(s.outPathPrefix, name)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(fullName)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("cannot remove file: %w", err)
+	}
+	return nil
+}
+
+// List returns names of all files with specified prefix.
+// Prefix that ends with slash matches only files inside that directory.
+func (s *Store) List(_ context.Context, prefix string) ([]string, error) {
+	full, err := resolve(s.outPathPrefix, prefix)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		names []string
+		root  = full
+	)
+	if strings.HasSuffix(prefix, "/") {
+		full += string(filepath.Separator)
+	} else {
+		root = filepath.Dir(full)
+	}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasPrefix(path, full) || isTemp(d.Name()) {
+			return nil
+		}
+		name, errR := filepath.Rel(s.outPathPrefix, path)
+		if errR != nil {
+			return errR //nolint:wrapcheck // wrapped below
+		}
+		names = append(names, name)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list files: %w", err)
+	}
+	return names, nil
+}
+
+// DeletePrefix removes all files with specified prefix
+// and returns amount of removed files. If prefix is a directory
+// (ends with slash), directory itself is also removed.
+func (s *Store) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	names, err := s.List(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+	for i, name := range names {
+		if err = s.Delete(ctx, name); err != nil {
+			return i, err
+		}
+	}
+	if strings.HasSuffix(prefix, "/") {
+		dir, _ := resolve(s.outPathPrefix, prefix) // already checked by List
+		if err = os.RemoveAll(dir); err != nil {
+			return len(names), fmt.Errorf("cannot remove dir: %w", err)
+		}
+	}
+	return len(names), nil
+}
+
+// isTemp returns true if file is temporary file of unfinished write.
+func isTemp(name string) bool {
+	return strings.HasPrefix(name, tmpFilePattern[:len(tmpFilePattern)-1])
+}
@@ -0,0 +1,96 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_DeletePrefix(t *testing.T) {
+	var (
+		ctx = context.Background()
+		out = t.TempDir()
+		s   = NewStore(out, out)
+	)
+	for _, name := range []string{"aaa/1.m4s", "aaa/2.m4s", "aaa/init/v.mp4", "aaab/1.m4s", "bbb/1.m4s"} {
+		require.NoError(t, s.Put(ctx, name, bytes.NewReader([]byte("test")), 4))
+	}
+
+	names, err := s.List(ctx, "aaa/")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"aaa/1.m4s", "aaa/2.m4s", "aaa/init/v.mp4"}, names)
+
+	names, err = s.List(ctx, "aaa")
+	require.NoError(t, err)
+	assert.Len(t, names, 4)
+
+	n, err := s.DeletePrefix(ctx, "aaa/")
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	_, err = os.Stat(filepath.Join(out, "aaa"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	names, err = s.List(ctx, "")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"aaab/1.m4s", "bbb/1.m4s"}, names)
+
+	// purge is retried, so deleting nonexistent files is not an error
+	n, err = s.DeletePrefix(ctx, "aaa/")
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	require.NoError(t, s.Delete(ctx, "aaa/1.m4s"))
+}
+
+func TestStore_PutGet(t *testing.T) {
+	var (
+		ctx = context.Background()
+		out = t.TempDir()
+		s   = NewStore(out, out)
+	)
+	require.NoError(t, s.Put(ctx, "/upload/loc/0", bytes.NewReader([]byte("test")), 4))
+
+	rc, size, err := s.Get(ctx, "upload/loc/0")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), size)
+	require.NoError(t, rc.Close())
+
+	checksum, err := s.CalcSha256(ctx, "upload/loc/0")
+	require.NoError(t, err)
+	assert.Equal(t, "n4bQgYhMfWWaL+qgxVrQFaO/TxsrC4Is0V1sFbDwCgg=", checksum)
+
+	// incomplete write must not leave any file
+	err = s.Put(ctx, "upload/loc/1", bytes.NewReader([]byte("tes")), 4)
+	require.Error(t, err)
+	names, err := s.List(ctx, "upload/loc/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"upload/loc/0"}, names)
+	dir, err := os.ReadDir(filepath.Join(out, "upload", "loc"))
+	require.NoError(t, err)
+	assert.Len(t, dir, 1)
+
+	_, _, err = s.Get(ctx, "upload/loc/1")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestStore_InvalidName(t *testing.T) {
+	var (
+		ctx = context.Background()
+		out = t.TempDir()
+		s   = NewStore(out, out)
+	)
+	for _, name := range []string{"../qwe", "loc/../../qwe", "loc/.."} {
+		_, _, err := s.Get(ctx, name)
+		require.ErrorIs(t, err, ErrInvalidName, name)
+		err = s.Put(ctx, name, bytes.NewReader([]byte("test")), 4)
+		require.ErrorIs(t, err, ErrInvalidName, name)
+		err = s.Delete(ctx, name)
+		require.ErrorIs(t, err, ErrInvalidName, name)
+		_, err = s.DeletePrefix(ctx, name)
+		require.ErrorIs(t, err, ErrInvalidName, name)
+	}
+}
@@ -3,21 +3,25 @@ package s3
 import (
 	"context"
 	"encoding/base64"
-	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/sha256-simd"
 	"go.uber.org/zap"
 )
 
-var ErrNotFount = errors.New("not found")
+// ErrNotFount wraps fs.ErrNotExist, so not found objects are checked the same way for every media store.
+var ErrNotFount = fmt.Errorf("not found: %w", fs.ErrNotExist)
 
 // Store is media store that uses s3 compatible storage.
-// It implements simple Get/Set operations, and in addition
-// it can calculate sha256 checksum of already uploaded object.
+// It implements simple Get/Set/Delete operations, and in addition
+// it can calculate sha256 checksum of already uploaded object
+// and list or delete all objects with common prefix.
 //
 // TODO Seems like S3 API actually can calculate sha256 on server side,
 // TODO but I couldn't get it working with minio. Need to investigate further.
@@ -64,3 +68,91 @@ func (s *Store) Get(ctx context.Context, name string) (io.ReadSeekCloser, int64,
 	}
 	return obj, stat.Size, nil
 }
+
+// PresignPut returns presigned url that could be used
+// to upload object directly to s3 with PUT request.
+// Content-Length and x-amz-checksum-sha256 headers are signed, so PUT request
+// must have them with specified values, i.e. only object of specified size can be uploaded,
+// and s3 rejects object if its content does not match checksum (base64 encoded sha256).
+func (s *Store) PresignPut(ctx context.Context, name string, size uint64, checksum string, expires time.Duration) (string, error) {
+	headers := make(http.Header)
+	headers.Set("Content-Length", strconv.FormatUint(size, 10))
+	headers.Set("X-Amz-Checksum-Sha256", checksum)
+	u, err := s.client.PresignHeader(ctx, http.MethodPut, s.bucket, name, expires, nil, headers)
+	if err != nil {
+		return "", fmt.Errorf("cannot presign object upload: %w", err)
+	}
+	return u.String(), nil
+}
+
+// StatSha256 returns size and sha256 checksum (base64 encoded) of object
+// that s3 verified on upload. Checksum is empty if object was uploaded without it.
+func (s *Store) StatSha256(ctx context.Context, name string) (int64, string, error) {
+	stat, err := s.client.StatObject(ctx, s.bucket, name, minio.StatObjectOptions{Checksum: true})
+	if err != nil {
+		er := minio.ToErrorResponse(err)
+		if er.StatusCode == http.StatusNotFound {
+			return 0, "", ErrNotFount
+		}
+		return 0, "", fmt.Errorf("cannot get object stats: %w", err)
+	}
+	return stat.Size, stat.ChecksumSHA256, nil
+}
+
+// Delete removes object from s3. Removing nonexistent object is not an error.
+func (s *Store) Delete(ctx context.Context, name string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, name, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("cannot remove object from s3: %w", err)
+	}
+	return nil
+}
+
+// List returns names of all objects with specified prefix.
+func (s *Store) List(ctx context.Context, prefix string) ([]string, error) {
+	var names []string
+	for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("cannot list objects in s3: %w", obj.Err)
+		}
+		names = append(names, obj.Key)
+	}
+	return names, nil
+}
+
+// DeletePrefix removes all objects with specified prefix
+// and returns amount of removed objects.
+func (s *Store) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	names, err := s.List(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+	objects := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(objects)
+		for _, name := range names {
+			select {
+			case <-ctx.Done():
+				return
+			case objects <- minio.ObjectInfo{Key: name}:
+			}
+		}
+	}()
+	var (
+		failed int
+		errR   error
+	)
+	for rErr := range s.client.RemoveObjects(ctx, s.bucket, objects, minio.RemoveObjectsOptions{}) {
+		failed++
+		errR = rErr.Err
+	}
+	if errR != nil {
+		return len(names) - failed, fmt.Errorf("cannot remove %d objects from s3: %w", failed, errR)
+	}
+	if err = ctx.Err(); err != nil {
+		return 0, fmt.Errorf("objects removal interrupted: %w", err)
+	}
+	return len(names), nil
+}
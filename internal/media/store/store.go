@@ -0,0 +1,67 @@
+// Package store provides media store that is selected by configuration.
+// Media could be stored either in s3 compatible storage or in local file system.
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/adwski/vidi/internal/media/store/file"
+	"github.com/adwski/vidi/internal/media/store/s3"
+	"go.uber.org/zap"
+)
+
+// Media store types.
+const (
+	TypeS3   = "s3"
+	TypeFile = "file"
+)
+
+const (
+	defaultDirPermissions = 0700
+)
+
+// MediaStore is implemented by every media store.
+// Get returns error that wraps fs.ErrNotExist if object does not exist.
+type MediaStore interface {
+	Put(ctx context.Context, name string, r io.Reader, size int64) error
+	Get(ctx context.Context, name string) (io.ReadSeekCloser, int64, error)
+	CalcSha256(ctx context.Context, name string) (string, error)
+	Delete(ctx context.Context, name string) error
+	List(ctx context.Context, prefix string) ([]string, error)
+	DeletePrefix(ctx context.Context, prefix string) (int, error)
+}
+
+type Config struct {
+	Logger *zap.Logger
+	S3     *s3.StoreConfig
+	Type   string
+	// Path is a root directory of file store.
+	Path string
+}
+
+// New creates media store of configured type.
+func New(cfg *Config) (MediaStore, error) {
+	switch cfg.Type {
+	case TypeS3:
+		cfg.S3.Logger = cfg.Logger
+		st, err := s3.NewStore(cfg.S3)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create s3 media store: %w", err)
+		}
+		return st, nil
+	case TypeFile:
+		if cfg.Path == "" {
+			return nil, errors.New("file media store path is not set")
+		}
+		if err := os.MkdirAll(cfg.Path, defaultDirPermissions); err != nil {
+			return nil, fmt.Errorf("cannot create file media store dir: %w", err)
+		}
+		cfg.Logger.Info("using file media store", zap.String("path", cfg.Path))
+		return file.NewStore(cfg.Path, cfg.Path), nil
+	}
+	return nil, fmt.Errorf("unknown media store type: %s", cfg.Type)
+}
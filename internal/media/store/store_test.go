@@ -0,0 +1,28 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNew(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "media")
+	st, err := New(&Config{Logger: logger, Type: TypeFile, Path: path})
+	require.NoError(t, err)
+	assert.NotNil(t, st)
+	assert.DirExists(t, path)
+
+	_, err = New(&Config{Logger: logger, Type: TypeFile})
+	require.Error(t, err)
+
+	_, err = New(&Config{Logger: logger, Type: "qwe"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown media store type")
+}
@@ -0,0 +1,271 @@
+package streamer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/grpc/serviceside/pb"
+	"github.com/adwski/vidi/internal/session"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	defaultFlushInterval = 30 * time.Second
+
+	// reportBatchSize limits amount of session reports in single videoapi call.
+	reportBatchSize = 500
+	// maxFailedReports limits amount of reports kept for retry while videoapi is unavailable.
+	maxFailedReports = 10000
+
+	finalFlushTimeout = 10 * time.Second
+)
+
+// Analytics aggregates playback activity of watch sessions and periodically
+// reports it to videoapi.
+//
+// Every segment number is reported once per session, so videoapi could
+// calculate watched duration and audience retention from segment numbers.
+// Activity of multi-period sessions (i.e. playlists) is tracked and reported
+// separately for every video, period of requested object points to its video.
+type Analytics struct {
+	logger        *zap.Logger
+	videoAPI      pb.ServicesideapiClient
+	authMD        metadata.MD
+	sessions      map[viewKey]*sessionViews
+	failed        []*pb.ViewReport
+	mx            sync.Mutex
+	flushInterval time.Duration
+	idleTimeout   time.Duration
+}
+
+// viewKey identifies activity of watch session on one of session videos.
+type viewKey struct {
+	sessionID string
+	videoID   string
+}
+
+// sessionViews is a playback activity of watch session on one video.
+// Segments, requests and bytes are reset after every report.
+type sessionViews struct {
+	seen       map[uint32]struct{}
+	lastActive time.Time
+	userID     string
+	segments   []uint32
+	requests   uint64
+	bytes      uint64
+	reported   bool
+}
+
+type AnalyticsConfig struct {
+	Logger           *zap.Logger
+	VideoAPIEndpoint string
+	VideoAPIToken    string
+	// FlushInterval is a period of reports to videoapi.
+	FlushInterval time.Duration
+	// IdleTimeout is a time after which inactive session is forgotten.
+	// Activity of forgotten session is counted as new view.
+	IdleTimeout time.Duration
+}
+
+func NewAnalytics(cfg *AnalyticsConfig) (*Analytics, error) {
+	cc, err := grpc.Dial(cfg.VideoAPIEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create vidi connection: %w", err)
+	}
+	return newAnalytics(cfg, pb.NewServicesideapiClient(cc)), nil
+}
+
+func newAnalytics(cfg *AnalyticsConfig, api pb.ServicesideapiClient) *Analytics {
+	flushInterval := cfg.FlushInterval
+	if flushInterval == 0 {
+		flushInterval = defaultFlushInterval
+	}
+	return &Analytics{
+		logger:        cfg.Logger.With(zap.String("component", "analytics")),
+		videoAPI:      api,
+		authMD:        metadata.Pairs("authorization", "bearer "+cfg.VideoAPIToken),
+		sessions:      make(map[viewKey]*sessionViews),
+		flushInterval: flushInterval,
+		idleTimeout:   max(cfg.IdleTimeout, flushInterval),
+	}
+}
+
+func (a *Analytics) Run(ctx context.Context, wg *sync.WaitGroup, _ chan<- error) {
+	defer wg.Done()
+	a.logger.Info("started")
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+Loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break Loop
+		case <-ticker.C:
+			a.flush(ctx, time.Now())
+		}
+	}
+	// report what is collected so far
+	flushCtx, cancel := context.WithTimeout(context.Background(), finalFlushTimeout)
+	defer cancel()
+	a.flush(flushCtx, time.Now())
+	a.logger.Info("stopped")
+}
+
+// record adds served object to session activity. Segment number is taken from segment name,
+// which is <track>_<number>.m4s, other objects (init segments and manifests) only add to traffic.
+func (a *Analytics) record(sess *session.Session, path []byte, size int64) {
+	videoID, ok := objectVideo(sess, path)
+	if !ok {
+		return
+	}
+	num, isSegment := segmentNumber(path)
+	key := viewKey{sessionID: sess.ID, videoID: videoID}
+
+	a.mx.Lock()
+	defer a.mx.Unlock()
+	sv, ok := a.sessions[key]
+	if !ok {
+		sv = &sessionViews{
+			userID: sess.UserID,
+			seen:   make(map[uint32]struct{}),
+		}
+		a.sessions[key] = sv
+	}
+	sv.lastActive = time.Now()
+	sv.requests++
+	sv.bytes += uint64(size)
+	if isSegment {
+		if _, seen := sv.seen[num]; !seen {
+			sv.seen[num] = struct{}{}
+			sv.segments = append(sv.segments, num)
+		}
+	}
+}
+
+// flush sends activity collected since previous flush along with previously failed reports.
+// If videoapi could not record some reports of batch, only these reports are retried,
+// so activity that is already recorded is never counted twice.
+func (a *Analytics) flush(ctx context.Context, now time.Time) {
+	reports := a.collect(now)
+	if len(reports) == 0 {
+		return
+	}
+	ctx = metadata.NewOutgoingContext(ctx, a.authMD)
+	var failed []*pb.ViewReport
+	for len(reports) > 0 {
+		batch := reports[:min(reportBatchSize, len(reports))]
+		resp, err := a.videoAPI.ReportViews(ctx, &pb.ReportViewsRequest{Reports: batch})
+		if err != nil {
+			a.logger.Error("cannot report views", zap.Int("reports", len(reports)), zap.Error(err))
+			failed = append(failed, reports...)
+			break
+		}
+		if len(resp.Failed) > 0 {
+			failed = append(failed, failedReports(batch, resp.Failed)...)
+		}
+		reports = reports[len(batch):]
+	}
+	if len(failed) > 0 {
+		a.logger.Warn("views will be reported later", zap.Int("reports", len(failed)))
+		a.retryLater(failed)
+		return
+	}
+	a.logger.Debug("views reported")
+}
+
+// failedReports returns reports that videoapi could not record.
+func failedReports(batch []*pb.ViewReport, keys []*pb.ViewReportKey) []*pb.ViewReport {
+	failed := make([]*pb.ViewReport, 0, len(keys))
+	for _, r := range batch {
+		if slices.ContainsFunc(keys, func(key *pb.ViewReportKey) bool {
+			return key.SessionId == r.SessionId && key.VideoId == r.VideoId
+		}) {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// collect returns reports of sessions that had activity since previous flush.
+// Sessions that are idle for too long are forgotten.
+func (a *Analytics) collect(now time.Time) []*pb.ViewReport {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+	reports := a.failed
+	a.failed = nil
+	for key, sv := range a.sessions {
+		if sv.requests == 0 {
+			if now.Sub(sv.lastActive) > a.idleTimeout {
+				delete(a.sessions, key)
+			}
+			continue
+		}
+		reports = append(reports, &pb.ViewReport{
+			SessionId: key.sessionID,
+			VideoId:   key.videoID,
+			UserId:    sv.userID,
+			NewView:   !sv.reported,
+			Requests:  sv.requests,
+			Bytes:     sv.bytes,
+			Segments:  sv.segments,
+		})
+		sv.reported = true
+		sv.requests, sv.bytes, sv.segments = 0, 0, nil
+	}
+	return reports
+}
+
+// retryLater keeps failed reports for next flush. The oldest reports are dropped
+// if videoapi is unavailable for too long.
+func (a *Analytics) retryLater(reports []*pb.ViewReport) {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+	if len(reports) > maxFailedReports {
+		a.logger.Warn("dropping view reports", zap.Int("dropped", len(reports)-maxFailedReports))
+		reports = reports[len(reports)-maxFailedReports:]
+	}
+	a.failed = reports
+}
+
+// objectVideo returns video of session which object is requested. Objects of playlist
+// session are prefixed with period, which is index of video in playlist.
+// Playlist manifest does not belong to any video, so it is not tracked.
+func objectVideo(sess *session.Session, path []byte) (string, bool) {
+	if sess.PlaylistID == "" {
+		return sess.VideoID, true
+	}
+	period, _, found := bytes.Cut(bytes.TrimPrefix(path, []byte("/")), []byte("/"))
+	if !found {
+		return "", false
+	}
+	idx, err := strconv.Atoi(string(period))
+	if err != nil || idx < 0 || idx >= len(sess.VideoIDs) {
+		return "", false
+	}
+	return sess.VideoIDs[idx], true
+}
+
+// segmentNumber returns number of media segment from path.
+func segmentNumber(path []byte) (uint32, bool) {
+	if !bytes.HasSuffix(path, objTypeSegment) {
+		return 0, false
+	}
+	name := path[bytes.LastIndexByte(path, '/')+1 : len(path)-len(objTypeSegment)]
+	idx := bytes.LastIndexByte(name, '_')
+	if idx == -1 {
+		return 0, false
+	}
+	num, err := strconv.ParseUint(string(name[idx+1:]), 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint32(num), true
+}
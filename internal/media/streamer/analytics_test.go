@@ -0,0 +1,188 @@
+package streamer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/grpc/serviceside/pb"
+	"github.com/adwski/vidi/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+type fakeVideoAPI struct {
+	pb.ServicesideapiClient
+	err      error
+	requests []*pb.ReportViewsRequest
+	// failed are reports returned as not recorded by next call
+	failed []*pb.ViewReportKey
+}
+
+func (f *fakeVideoAPI) ReportViews(
+	_ context.Context,
+	req *pb.ReportViewsRequest,
+	_ ...grpc.CallOption,
+) (*pb.ReportViewsResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.requests = append(f.requests, req)
+	failed := f.failed
+	f.failed = nil
+	return &pb.ReportViewsResponse{Failed: failed}, nil
+}
+
+func TestSegmentNumber(t *testing.T) {
+	for path, want := range map[string]uint32{
+		"/vide1_1.m4s":    1,
+		"/1/soun2_15.m4s": 15,
+	} {
+		num, ok := segmentNumber([]byte(path))
+		require.True(t, ok, path)
+		assert.Equal(t, want, num, path)
+	}
+	for _, path := range []string{"/vide1_init.mp4", "/manifest.mpd", "/vide1.m4s", "/vide1_x.m4s"} {
+		_, ok := segmentNumber([]byte(path))
+		assert.False(t, ok, path)
+	}
+}
+
+func TestAnalytics_flush(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	api := &fakeVideoAPI{}
+	a := newAnalytics(&AnalyticsConfig{
+		Logger:        logger,
+		FlushInterval: time.Second,
+		IdleTimeout:   time.Minute,
+	}, api)
+	sess := &session.Session{ID: "sess", VideoID: "vid", UserID: "user"}
+	a.record(sess, []byte("/vide1_init.mp4"), 10)
+	a.record(sess, []byte("/vide1_1.m4s"), 100)
+	a.record(sess, []byte("/soun1_1.m4s"), 20)
+	a.record(sess, []byte("/vide1_2.m4s"), 100)
+
+	ctx := context.Background()
+	a.flush(ctx, time.Now())
+	require.Len(t, api.requests, 1)
+	require.Len(t, api.requests[0].Reports, 1)
+	r := api.requests[0].Reports[0]
+	assert.Equal(t, "sess", r.SessionId)
+	assert.Equal(t, "vid", r.VideoId)
+	assert.Equal(t, "user", r.UserId)
+	assert.True(t, r.NewView)
+	assert.Equal(t, uint64(4), r.Requests)
+	assert.Equal(t, uint64(230), r.Bytes)
+	assert.Equal(t, []uint32{1, 2}, r.Segments)
+
+	// nothing to report
+	a.flush(ctx, time.Now())
+	require.Len(t, api.requests, 1)
+
+	// already seen segments are not reported again, view is counted once
+	a.record(sess, []byte("/vide1_2.m4s"), 100)
+	a.record(sess, []byte("/vide1_3.m4s"), 100)
+	api.err = errors.New("unavailable")
+	a.flush(ctx, time.Now())
+	require.Len(t, api.requests, 1)
+
+	// failed report is sent again
+	api.err = nil
+	a.flush(ctx, time.Now())
+	require.Len(t, api.requests, 2)
+	require.Len(t, api.requests[1].Reports, 1)
+	r = api.requests[1].Reports[0]
+	assert.False(t, r.NewView)
+	assert.Equal(t, uint64(2), r.Requests)
+	assert.Equal(t, []uint32{3}, r.Segments)
+
+	// idle session is forgotten
+	a.flush(ctx, time.Now().Add(2*time.Minute))
+	assert.Empty(t, a.sessions)
+}
+
+func TestAnalytics_flushPartialFailure(t *testing.T) {
+	api := &fakeVideoAPI{}
+	a := newAnalytics(&AnalyticsConfig{
+		Logger:        zap.NewNop(),
+		FlushInterval: time.Second,
+		IdleTimeout:   time.Minute,
+	}, api)
+	var (
+		s1 = &session.Session{ID: "s1", VideoID: "v1", UserID: "user"}
+		s2 = &session.Session{ID: "s2", VideoID: "v2", UserID: "user"}
+	)
+	a.record(s1, []byte("/vide1_1.m4s"), 100)
+	a.record(s2, []byte("/vide1_1.m4s"), 100)
+
+	ctx := context.Background()
+	api.failed = []*pb.ViewReportKey{{SessionId: "s2", VideoId: "v2"}}
+	a.flush(ctx, time.Now())
+	require.Len(t, api.requests, 1)
+	require.Len(t, api.requests[0].Reports, 2)
+
+	// only report that was not recorded is sent again
+	a.flush(ctx, time.Now())
+	require.Len(t, api.requests, 2)
+	require.Len(t, api.requests[1].Reports, 1)
+	r := api.requests[1].Reports[0]
+	assert.Equal(t, "s2", r.SessionId)
+	assert.True(t, r.NewView)
+	assert.Equal(t, []uint32{1}, r.Segments)
+
+	a.flush(ctx, time.Now())
+	require.Len(t, api.requests, 2)
+}
+
+func TestAnalytics_recordPlaylist(t *testing.T) {
+	api := &fakeVideoAPI{}
+	a := newAnalytics(&AnalyticsConfig{
+		Logger:        zap.NewNop(),
+		FlushInterval: time.Second,
+		IdleTimeout:   time.Minute,
+	}, api)
+	sess := &session.Session{
+		ID:         "pl-sess",
+		PlaylistID: "pl",
+		UserID:     "user",
+		VideoIDs:   []string{"a", "b"},
+		Locations:  []string{"a", "b"},
+	}
+	// manifest and objects of unknown periods are not tracked
+	a.record(sess, []byte("/manifest.mpd"), 1000)
+	a.record(sess, []byte("/2/vide1_1.m4s"), 100)
+	a.record(sess, []byte("/x/vide1_1.m4s"), 100)
+
+	a.record(sess, []byte("/0/vide1_init.mp4"), 10)
+	a.record(sess, []byte("/0/vide1_1.m4s"), 100)
+	a.record(sess, []byte("/1/vide1_1.m4s"), 100)
+	a.record(sess, []byte("/1/vide1_2.m4s"), 100)
+
+	ctx := context.Background()
+	api.failed = []*pb.ViewReportKey{{SessionId: "pl-sess", VideoId: "b"}}
+	a.flush(ctx, time.Now())
+	require.Len(t, api.requests, 1)
+	reports := make(map[string]*pb.ViewReport)
+	for _, r := range api.requests[0].Reports {
+		assert.Equal(t, "pl-sess", r.SessionId)
+		assert.Equal(t, "user", r.UserId)
+		assert.True(t, r.NewView)
+		reports[r.VideoId] = r
+	}
+	require.Len(t, reports, 2)
+	assert.Equal(t, uint64(110), reports["a"].Bytes)
+	assert.Equal(t, []uint32{1}, reports["a"].Segments)
+	assert.Equal(t, uint64(200), reports["b"].Bytes)
+	assert.Equal(t, []uint32{1, 2}, reports["b"].Segments)
+
+	// only report of video that was not recorded is sent again
+	a.flush(ctx, time.Now())
+	require.Len(t, api.requests, 2)
+	require.Len(t, api.requests[1].Reports, 1)
+	assert.Equal(t, "b", api.requests[1].Reports[0].VideoId)
+}
@@ -0,0 +1,161 @@
+package streamer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/dgraph-io/ristretto"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	defaultCacheMaxSize     = 512 << 20
+	defaultCacheMaxItemSize = 16 << 20
+	defaultCacheTTL         = time.Hour
+
+	// cacheAvgItemSize is an expected size of segment, it is used to estimate
+	// amount of cached items, since ristretto needs 10x counters of items.
+	cacheAvgItemSize    = 256 << 10
+	cacheMinCounters    = 1e4
+	cacheCountersFactor = 10
+	cacheBufferItems    = 64
+)
+
+// errOversized is returned when object is too large to be cached and should be streamed.
+var errOversized = errors.New("object is too large to be cached")
+
+// oversized is cached instead of objects that are too large, so subsequent
+// requests are streamed right away without fetching object twice.
+type oversized struct{}
+
+// CacheConfig configures in-memory cache of media segments.
+type CacheConfig struct {
+	// MaxSize is a total size of cached segments in bytes.
+	MaxSize int64
+	// MaxItemSize is a size of the largest segment that could be cached.
+	// Larger segments are streamed from media store.
+	MaxItemSize int64
+	// TTL limits time during which segment is kept in cache.
+	TTL time.Duration
+}
+
+// CacheStats are cache counters.
+type CacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Coalesced uint64 `json:"coalesced"`
+	Bypassed  uint64 `json:"bypassed"`
+	Evictions uint64 `json:"evictions"`
+}
+
+// segmentCache keeps popular segments in memory. Concurrent misses of the same
+// segment are coalesced, so segment is fetched from media store only once.
+type segmentCache struct {
+	c           *ristretto.Cache
+	group       singleflight.Group
+	maxItemSize int64
+	ttl         time.Duration
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	coalesced atomic.Uint64
+	bypassed  atomic.Uint64
+	evictions atomic.Uint64
+}
+
+func newSegmentCache(cfg *CacheConfig) (*segmentCache, error) {
+	sc := &segmentCache{
+		maxItemSize: cfg.MaxItemSize,
+		ttl:         cfg.TTL,
+	}
+	maxSize := cfg.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultCacheMaxSize
+	}
+	if sc.maxItemSize <= 0 {
+		sc.maxItemSize = defaultCacheMaxItemSize
+	}
+	if sc.ttl <= 0 {
+		sc.ttl = defaultCacheTTL
+	}
+	c, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters: max(cacheCountersFactor*maxSize/cacheAvgItemSize, cacheMinCounters),
+		MaxCost:     maxSize,
+		BufferItems: cacheBufferItems,
+		OnEvict: func(_ *ristretto.Item) {
+			sc.evictions.Add(1)
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create segment cache: %w", err)
+	}
+	sc.c = c
+	return sc, nil
+}
+
+// get returns segment from cache or fetches it from media store.
+// errOversized is returned if segment should be streamed instead.
+func (sc *segmentCache) get(ctx context.Context, name string, st MediaStore) ([]byte, error) {
+	if v, ok := sc.c.Get(name); ok {
+		if _, tooLarge := v.(oversized); tooLarge {
+			sc.bypassed.Add(1)
+			return nil, errOversized
+		}
+		sc.hits.Add(1)
+		return v.([]byte), nil //nolint:errcheck // only segments and oversized markers are cached
+	}
+	var fetched bool
+	v, err, _ := sc.group.Do(name, func() (interface{}, error) {
+		fetched = true
+		return sc.fetch(ctx, name, st)
+	})
+	switch {
+	case errors.Is(err, errOversized):
+		sc.bypassed.Add(1)
+		return nil, err
+	case !fetched:
+		sc.coalesced.Add(1)
+	default:
+		sc.misses.Add(1)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil //nolint:errcheck // fetch returns bytes
+}
+
+func (sc *segmentCache) fetch(ctx context.Context, name string, st MediaStore) ([]byte, error) {
+	rc, size, err := st.Get(ctx, name)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // media store error is checked by caller
+	}
+	defer func() { _ = rc.Close() }()
+	if size > sc.maxItemSize {
+		sc.c.SetWithTTL(name, oversized{}, 1, sc.ttl)
+		return nil, errOversized
+	}
+	b := make([]byte, size)
+	if _, err = io.ReadFull(rc, b); err != nil {
+		return nil, fmt.Errorf("cannot read segment: %w", err)
+	}
+	sc.c.SetWithTTL(name, b, size, sc.ttl)
+	return b, nil
+}
+
+func (sc *segmentCache) stats() CacheStats {
+	return CacheStats{
+		Hits:      sc.hits.Load(),
+		Misses:    sc.misses.Load(),
+		Coalesced: sc.coalesced.Load(),
+		Bypassed:  sc.bypassed.Load(),
+		Evictions: sc.evictions.Load(),
+	}
+}
+
+func (sc *segmentCache) close() {
+	sc.c.Close()
+}
@@ -0,0 +1,155 @@
+package streamer
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adwski/vidi/internal/session"
+	"github.com/adwski/vidi/internal/session/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// blockingMediaStore counts fetches and holds them until released.
+type blockingMediaStore struct {
+	fakeMediaStore
+	release chan struct{}
+	gets    atomic.Int32
+}
+
+func (b *blockingMediaStore) Get(ctx context.Context, name string) (io.ReadSeekCloser, int64, error) {
+	b.gets.Add(1)
+	<-b.release
+	return b.fakeMediaStore.Get(ctx, name)
+}
+
+func TestSegmentCache_coalesce(t *testing.T) {
+	st := &blockingMediaStore{
+		fakeMediaStore: fakeMediaStore{objects: map[string][]byte{"seg": []byte("segment")}},
+		release:        make(chan struct{}),
+	}
+	sc, err := newSegmentCache(&CacheConfig{MaxSize: 1 << 20})
+	require.NoError(t, err)
+	defer sc.close()
+
+	const clients = 10
+	var wg sync.WaitGroup
+	for range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b, errG := sc.get(context.Background(), "seg", st)
+			assert.NoError(t, errG)
+			assert.Equal(t, []byte("segment"), b)
+		}()
+	}
+	// let all clients wait for the same fetch
+	require.Eventually(t, func() bool { return st.gets.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(st.release)
+	wg.Wait()
+	assert.Equal(t, int32(1), st.gets.Load())
+
+	stats := sc.stats()
+	assert.Equal(t, uint64(clients), stats.Misses+stats.Coalesced+stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+}
+
+func TestSegmentCache_get(t *testing.T) {
+	st := &fakeMediaStore{objects: map[string][]byte{
+		"small": []byte("segment"),
+		"large": []byte(strings.Repeat("x", 100)),
+	}}
+	sc, err := newSegmentCache(&CacheConfig{MaxSize: 1 << 20, MaxItemSize: 10})
+	require.NoError(t, err)
+	defer sc.close()
+
+	ctx := context.Background()
+	b, err := sc.get(ctx, "small", st)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("segment"), b)
+	sc.c.Wait()
+
+	// served from cache even if it is gone from store
+	delete(st.objects, "small")
+	b, err = sc.get(ctx, "small", st)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("segment"), b)
+
+	for range 2 {
+		_, err = sc.get(ctx, "large", st)
+		require.ErrorIs(t, err, errOversized)
+		sc.c.Wait()
+	}
+
+	_, err = sc.get(ctx, "missing", st)
+	require.Error(t, err)
+
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 2, Bypassed: 2}, sc.stats())
+}
+
+func TestService_handleWatchCached(t *testing.T) {
+	key := &token.Key{ID: "k1", Alg: token.AlgHS256, Secret: []byte(strings.Repeat("k", 32))}
+	signer, err := token.NewSigner(key, time.Minute)
+	require.NoError(t, err)
+	verifier, err := token.NewVerifier([]*token.Key{key})
+	require.NoError(t, err)
+
+	svc, err := New(&Config{
+		Logger: zap.NewNop(),
+		MediaStore: &fakeMediaStore{objects: map[string][]byte{
+			"/watch/loc/vide1_1.m4s":  []byte("segment"),
+			"/watch/loc/manifest.mpd": []byte("mpd"),
+		}},
+		URIPathPrefix:  "/watch",
+		PathPrefix:     "/watch",
+		Verifier:       verifier,
+		Cache:          &CacheConfig{},
+		CacheStatsPath: "/cache/stats",
+		CacheControl: CacheControlConfig{
+			Segment:  "public, max-age=86400",
+			Manifest: "no-cache",
+		},
+	})
+	require.NoError(t, err)
+	defer svc.Close()
+
+	tkn, err := signer.Sign(&session.Session{ID: "sess", VideoID: "vid", Location: "loc"}, "")
+	require.NoError(t, err)
+
+	get := func(uri string) *fasthttp.RequestCtx {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(fasthttp.MethodGet)
+		ctx.Request.SetRequestURI(uri)
+		svc.handleWatch(ctx)
+		return ctx
+	}
+	for range 2 {
+		ctx := get("/watch/" + tkn + "/vide1_1.m4s")
+		require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+		assert.Equal(t, []byte("segment"), ctx.Response.Body())
+		assert.Equal(t, "public, max-age=86400", string(ctx.Response.Header.Peek("Cache-Control")))
+		svc.cache.c.Wait()
+	}
+	ctx := get("/watch/" + tkn + "/manifest.mpd")
+	require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+	assert.Equal(t, "no-cache", string(ctx.Response.Header.Peek("Cache-Control")))
+
+	ctx = get("/watch/" + tkn + "/vide1_2.m4s")
+	require.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+
+	ctx = get("/cache/stats")
+	require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+	var stats CacheStats
+	require.NoError(t, json.Unmarshal(ctx.Response.Body(), &stats))
+	// manifest is not cached
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 2}, stats)
+}
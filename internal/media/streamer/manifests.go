@@ -0,0 +1,207 @@
+package streamer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/grpc/serviceside/pb"
+	"github.com/adwski/vidi/internal/mp4/meta"
+	"github.com/adwski/vidi/internal/session"
+	"github.com/dgraph-io/ristretto"
+	"github.com/valyala/fasthttp"
+	"github.com/vmihailenco/msgpack/v5"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultMetaTTL = 5 * time.Second
+
+	metaCacheMaxItems = 1e4
+	metaCacheCounters = 10 * metaCacheMaxItems
+
+	filterAudioOnly    = "audio_only"
+	filterMaxBandwidth = "max_bandwidth"
+	filterLanguage     = "lang"
+)
+
+// errNoWatchMeta is returned when watch session target cannot be played anymore,
+// i.e. video is deleted or playlist has changed.
+var errNoWatchMeta = errors.New("watch session target is not available")
+
+// Manifests renders MPDs of watch sessions from playback meta provided by videoapi,
+// so manifests always correspond to stored meta and could be tailored per request.
+//
+// Playback meta is cached for a short time, since meta of live streams
+// changes with every new segment. Concurrent misses are coalesced.
+type Manifests struct {
+	logger   *zap.Logger
+	videoAPI pb.ServicesideapiClient
+	authMD   metadata.MD
+	metas    *ristretto.Cache
+	group    singleflight.Group
+	baseURL  string
+	metaTTL  time.Duration
+}
+
+type ManifestsConfig struct {
+	Logger           *zap.Logger
+	VideoAPIEndpoint string
+	VideoAPIToken    string
+	// BaseURL is a public URL prefix of watch sessions. If set, MPD has BaseURL
+	// <prefix>/<session-id>/, otherwise segments are requested relative to MPD location.
+	BaseURL string
+	// MetaTTL limits time during which playback meta is cached.
+	MetaTTL time.Duration
+}
+
+func NewManifests(cfg *ManifestsConfig) (*Manifests, error) {
+	cc, err := grpc.Dial(cfg.VideoAPIEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create vidi connection: %w", err)
+	}
+	return newManifests(cfg, pb.NewServicesideapiClient(cc))
+}
+
+func newManifests(cfg *ManifestsConfig, api pb.ServicesideapiClient) (*Manifests, error) {
+	metaTTL := cfg.MetaTTL
+	if metaTTL <= 0 {
+		metaTTL = defaultMetaTTL
+	}
+	c, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters: metaCacheCounters,
+		MaxCost:     metaCacheMaxItems,
+		BufferItems: cacheBufferItems,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create meta cache: %w", err)
+	}
+	return &Manifests{
+		logger:   cfg.Logger.With(zap.String("component", "manifests")),
+		videoAPI: api,
+		authMD:   metadata.Pairs("authorization", "bearer "+cfg.VideoAPIToken),
+		metas:    c,
+		baseURL:  strings.TrimSuffix(cfg.BaseURL, "/"),
+		metaTTL:  metaTTL,
+	}, nil
+}
+
+// render returns MPD of watch session. Multi-period sessions get period per playlist video.
+// sessID is a session identifier from request URI, it is used in BaseURL.
+func (m *Manifests) render(ctx context.Context, sessID string, sess *session.Session, f *meta.Filter) ([]byte, error) {
+	periods, err := m.getMeta(ctx, sess)
+	if err != nil {
+		return nil, err
+	}
+	var baseURL string
+	if m.baseURL != "" {
+		baseURL = fmt.Sprintf("%s/%s/", m.baseURL, sessID)
+	}
+	if len(sess.Locations) == 0 {
+		mt, errF := periods[0].Filter(f)
+		if errF != nil {
+			return nil, errF //nolint:wrapcheck // filter error is checked by caller
+		}
+		return mt.MPD(baseURL) //nolint:wrapcheck // mpd error is returned as is
+	}
+	filtered := make([]*meta.Meta, 0, len(periods))
+	for _, period := range periods {
+		mt, errF := period.Filter(f)
+		if errF != nil {
+			return nil, errF //nolint:wrapcheck // filter error is checked by caller
+		}
+		filtered = append(filtered, mt)
+	}
+	return meta.MultiPeriodMPD(baseURL, filtered) //nolint:wrapcheck // mpd error is returned as is
+}
+
+// getMeta returns playback meta of watch session target from cache or from videoapi.
+func (m *Manifests) getMeta(ctx context.Context, sess *session.Session) ([]*meta.Meta, error) {
+	var (
+		id       = sess.VideoID
+		playlist = sess.PlaylistID != ""
+	)
+	if playlist {
+		id = sess.PlaylistID
+	}
+	key := fmt.Sprintf("%s:%s:%t", sess.UserID, id, playlist)
+	if v, ok := m.metas.Get(key); ok {
+		return v.([]*meta.Meta), nil //nolint:errcheck // only meta is cached
+	}
+	v, err, _ := m.group.Do(key, func() (interface{}, error) {
+		periods, err := m.fetchMeta(ctx, id, sess.UserID, playlist)
+		if err != nil {
+			return nil, err
+		}
+		m.metas.SetWithTTL(key, periods, 1, m.metaTTL)
+		return periods, nil
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck // fetch error is already wrapped
+	}
+	periods := v.([]*meta.Meta) //nolint:errcheck // fetchMeta returns meta
+	if (playlist && len(periods) != len(sess.Locations)) || (!playlist && len(periods) != 1) {
+		// period indexes would not match session locations
+		return nil, errNoWatchMeta
+	}
+	return periods, nil
+}
+
+func (m *Manifests) fetchMeta(ctx context.Context, id, userID string, playlist bool) ([]*meta.Meta, error) {
+	resp, err := m.videoAPI.GetWatchMeta(metadata.NewOutgoingContext(ctx, m.authMD), &pb.GetWatchMetaRequest{
+		Id:       id,
+		UserId:   userID,
+		Playlist: playlist,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound, codes.FailedPrecondition:
+			return nil, errors.Join(errNoWatchMeta, err)
+		default:
+			return nil, fmt.Errorf("cannot get playback meta: %w", err)
+		}
+	}
+	periods := make([]*meta.Meta, 0, len(resp.PlaybackMeta))
+	for _, b := range resp.PlaybackMeta {
+		var mt meta.Meta
+		if err = msgpack.Unmarshal(b, &mt); err != nil {
+			return nil, fmt.Errorf("cannot decode playback meta: %w", err)
+		}
+		periods = append(periods, &mt)
+	}
+	return periods, nil
+}
+
+func (m *Manifests) close() {
+	m.metas.Close()
+}
+
+// parseFilter returns track filter from MPD request query args:
+// audio_only=true, max_bandwidth=<bits/s> and lang=<code>[,<code>...].
+func parseFilter(args *fasthttp.Args) (*meta.Filter, error) {
+	f := &meta.Filter{AudioOnly: args.GetBool(filterAudioOnly)}
+	if mb := args.Peek(filterMaxBandwidth); len(mb) > 0 {
+		bw, err := strconv.ParseUint(string(mb), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", filterMaxBandwidth, err)
+		}
+		f.MaxBandwidth = uint32(bw)
+	}
+	for _, langs := range args.PeekMulti(filterLanguage) {
+		for _, lang := range strings.Split(string(langs), ",") {
+			if lang = strings.TrimSpace(lang); lang != "" {
+				f.Languages = append(f.Languages, lang)
+			}
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,171 @@
+package streamer
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/grpc/serviceside/pb"
+	"github.com/adwski/vidi/internal/mp4/meta"
+	"github.com/adwski/vidi/internal/session"
+	"github.com/adwski/vidi/internal/session/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"github.com/vmihailenco/msgpack/v5"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeMetaAPI struct {
+	pb.ServicesideapiClient
+	metas    map[string][]*meta.Meta
+	requests []*pb.GetWatchMetaRequest
+}
+
+func (f *fakeMetaAPI) GetWatchMeta(
+	_ context.Context,
+	req *pb.GetWatchMetaRequest,
+	_ ...grpc.CallOption,
+) (*pb.WatchMetaResponse, error) {
+	f.requests = append(f.requests, req)
+	periods, ok := f.metas[req.Id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	resp := &pb.WatchMetaResponse{}
+	for _, mt := range periods {
+		b, err := msgpack.Marshal(mt)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		resp.PlaybackMeta = append(resp.PlaybackMeta, b)
+	}
+	return resp, nil
+}
+
+func testManifestMeta() *meta.Meta {
+	segment := &meta.SegmentConfig{Init: "init.mp4", StartNumber: 1, Duration: 3000, Timescale: 1000}
+	return &meta.Meta{
+		Duration: 30 * time.Second,
+		Tracks: []meta.Track{
+			{Name: "vide1", MimeType: "video/mp4", Codec: &meta.Codec{Profile: "avc1.64001f"}, Segment: segment},
+			{Name: "soun1", MimeType: "audio/mp4", Codec: &meta.Codec{Profile: "mp4a.40.2"}, Segment: segment, Language: "eng"},
+			{Name: "soun2", MimeType: "audio/mp4", Codec: &meta.Codec{Profile: "mp4a.40.2"}, Segment: segment, Language: "fra"},
+		},
+	}
+}
+
+func TestService_handleWatchManifest(t *testing.T) {
+	key := &token.Key{ID: "k1", Alg: token.AlgHS256, Secret: []byte(strings.Repeat("k", 32))}
+	signer, err := token.NewSigner(key, time.Minute)
+	require.NoError(t, err)
+	verifier, err := token.NewVerifier([]*token.Key{key})
+	require.NoError(t, err)
+
+	api := &fakeMetaAPI{metas: map[string][]*meta.Meta{
+		"vid": {testManifestMeta()},
+		"pl":  {testManifestMeta(), testManifestMeta()},
+	}}
+	manifests, err := newManifests(&ManifestsConfig{
+		Logger:  zap.NewNop(),
+		BaseURL: "https://cdn.example/watch/",
+		MetaTTL: time.Minute,
+	}, api)
+	require.NoError(t, err)
+
+	svc, err := New(&Config{
+		Logger: zap.NewNop(),
+		MediaStore: &fakeMediaStore{objects: map[string][]byte{
+			"/watch/loc/manifest.mpd": []byte("stored mpd"),
+		}},
+		URIPathPrefix: "/watch",
+		PathPrefix:    "/watch",
+		Verifier:      verifier,
+		Manifests:     manifests,
+	})
+	require.NoError(t, err)
+	defer svc.Close()
+
+	sign := func(sess *session.Session) string {
+		tkn, errS := signer.Sign(sess, "")
+		require.NoError(t, errS)
+		return tkn
+	}
+	get := func(uri string) *fasthttp.RequestCtx {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(fasthttp.MethodGet)
+		ctx.Request.SetRequestURI(uri)
+		svc.handleWatch(ctx)
+		return ctx
+	}
+
+	tkn := sign(&session.Session{ID: "sess", VideoID: "vid", UserID: "user", Location: "loc"})
+	ctx := get("/watch/" + tkn + "/manifest.mpd")
+	require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+	assert.Equal(t, contentTypeMPD, string(ctx.Response.Header.ContentType()))
+	out := string(ctx.Response.Body())
+	assert.Contains(t, out, "<BaseURL>https://cdn.example/watch/"+tkn+"/</BaseURL>")
+	assert.Contains(t, out, `id="vide1"`)
+	assert.Contains(t, out, `id="soun2"`)
+	manifests.metas.Wait()
+
+	ctx = get("/watch/" + tkn + "/manifest.mpd?audio_only=true&lang=eng")
+	require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+	out = string(ctx.Response.Body())
+	assert.NotContains(t, out, `id="vide1"`)
+	assert.Contains(t, out, `id="soun1"`)
+	assert.NotContains(t, out, `id="soun2"`)
+	// meta is cached
+	require.Len(t, api.requests, 1)
+	assert.Equal(t, &pb.GetWatchMetaRequest{Id: "vid", UserId: "user"}, api.requests[0])
+
+	ctx = get("/watch/" + tkn + "/manifest.mpd?max_bandwidth=abc")
+	assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+
+	// session without user is served with stored mpd
+	ctx = get("/watch/" + sign(&session.Session{ID: "old", VideoID: "vid", Location: "loc"}) + "/manifest.mpd")
+	require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+	assert.Equal(t, "stored mpd", string(ctx.Response.Body()))
+
+	// playlist
+	ctx = get("/watch/" + sign(&session.Session{
+		ID: "pls", PlaylistID: "pl", VideoIDs: []string{"v1", "v2"}, UserID: "user", Locations: []string{"a", "b"},
+	}) + "/manifest.mpd")
+	require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+	out = string(ctx.Response.Body())
+	assert.Contains(t, out, `<Period id="p1" start="PT30S" duration="PT30S">`)
+	assert.True(t, api.requests[1].Playlist)
+
+	// playlist has changed since session was created
+	ctx = get("/watch/" + sign(&session.Session{
+		ID: "pls2", PlaylistID: "pl", VideoIDs: []string{"v1"}, UserID: "user", Locations: []string{"a"},
+	}) + "/manifest.mpd")
+	assert.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+
+	ctx = get("/watch/" + sign(&session.Session{ID: "s", VideoID: "deleted", UserID: "user", Location: "loc"}) + "/manifest.mpd")
+	assert.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+}
+
+func TestParseFilter(t *testing.T) {
+	args := &fasthttp.Args{}
+	args.Parse("audio_only=1&max_bandwidth=500000&lang=eng,fra&lang=deu")
+	f, err := parseFilter(args)
+	require.NoError(t, err)
+	assert.Equal(t, &meta.Filter{
+		AudioOnly:    true,
+		MaxBandwidth: 500000,
+		Languages:    []string{"eng", "fra", "deu"},
+	}, f)
+
+	f, err = parseFilter(&fasthttp.Args{})
+	require.NoError(t, err)
+	assert.True(t, f.IsZero())
+
+	args.Parse("max_bandwidth=-1")
+	_, err = parseFilter(args)
+	require.Error(t, err)
+}
@@ -3,20 +3,27 @@ package streamer
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
 	"strings"
 
-	"github.com/adwski/vidi/internal/media/store/s3"
+	"github.com/adwski/vidi/internal/mp4/meta"
 	"github.com/adwski/vidi/internal/session"
 	sessionStore "github.com/adwski/vidi/internal/session/store"
+	"github.com/adwski/vidi/internal/session/token"
 	"github.com/valyala/fasthttp"
 	"go.uber.org/zap"
 )
 
 const (
-	internalError = "internal error"
-	notFoundError = "not found"
+	internalError  = "internal error"
+	notFoundError  = "not found"
+	forbiddenError = "forbidden"
 
 	contentTypeSegment  = "video/iso.segment"
 	contentTypeVideoMP4 = "video/mp4"
@@ -29,22 +36,61 @@ var (
 	objTypeSegment = []byte(".m4s")
 	objTypeMP4     = []byte(".mp4")
 	objTypeMPD     = []byte(".mpd")
+	dotDot         = []byte("..")
 
 	trackTypeAudio = []byte("soun")
 	trackTypeVideo = []byte("vide")
 )
 
+// SessionStore provides watch sessions.
+type SessionStore interface {
+	GetExpireCached(ctx context.Context, id string) (*session.Session, error)
+}
+
+// MediaStore provides segments. Get should return error
+// that wraps fs.ErrNotExist if segment does not exist.
+type MediaStore interface {
+	Get(ctx context.Context, name string) (io.ReadSeekCloser, int64, error)
+}
+
 // Service is a streaming service. It implements fasthttp handler that
 // serves MPEG-DASH segments.
 // Segments are taken from media store.
 // Every request is also checked for valid "watch"-session.
+//
+// If signed watch urls are enabled, session could also be a signed token
+// issued by videoapi. Such sessions are verified without session store.
+//
+// If analytics is enabled, served segments are recorded as playback activity of session.
+//
+// Segments could be cached in memory. Manifests are never cached since
+// manifests of live streams are updated in place.
+//
+// If manifests are enabled, MPD is rendered from playback meta per request
+// instead of serving stored object. Such MPD could be tailored with query args,
+// see parseFilter. Sessions without user (i.e. created by older videoapi)
+// are still served with stored MPD.
 type Service struct {
-	logger       *zap.Logger
-	sessS        *sessionStore.Store
-	mediaS       *s3.Store
-	cors         *CORSConfig
-	s3PathPrefix []byte
-	uriPrefixLen int
+	logger         *zap.Logger
+	sessS          SessionStore
+	verifier       *token.Verifier
+	analytics      *Analytics
+	manifests      *Manifests
+	cache          *segmentCache
+	mediaS         MediaStore
+	cors           *CORSConfig
+	cacheControl   CacheControlConfig
+	clientIPHeader string
+	statsPath      []byte
+	pathPrefix     []byte
+	uriPrefixLen   int
+}
+
+// CacheControlConfig holds Cache-Control header values of responses.
+// Header is not set if value is empty.
+type CacheControlConfig struct {
+	Segment  string
+	Manifest string
 }
 
 type CORSConfig struct {
@@ -53,37 +99,78 @@ type CORSConfig struct {
 
 type Config struct {
 	Logger        *zap.Logger
-	SessionStore  *sessionStore.Store
+	SessionStore  SessionStore
 	CORSConfig    *CORSConfig
+	MediaStore    MediaStore
 	URIPathPrefix string
-	S3PathPrefix  string
-	S3Endpoint    string
-	S3AccessKey   string
-	S3SecretKey   string
-	S3Bucket      string
-	S3SSL         bool
+	PathPrefix    string
+
+	// Verifier enables signed watch urls if set.
+	Verifier *token.Verifier
+	// ClientIPHeader is a request header with client ip, it is used to check
+	// client binding of signed urls. Remote address is used if not set.
+	ClientIPHeader string
+
+	// Analytics enables playback activity reports if set.
+	Analytics *Analytics
+
+	// Manifests enables MPD rendering from playback meta if set.
+	Manifests *Manifests
+
+	// Cache enables in-memory segment cache if set.
+	Cache *CacheConfig
+	// CacheStatsPath is a request path at which cache counters are served.
+	// Counters are not served if not set.
+	CacheStatsPath string
+	CacheControl   CacheControlConfig
 }
 
 func New(cfg *Config) (*Service, error) {
-	s3Store, errS3 := s3.NewStore(&s3.StoreConfig{
-		Logger:    cfg.Logger,
-		Endpoint:  cfg.S3Endpoint,
-		AccessKey: cfg.S3AccessKey,
-		SecretKey: cfg.S3SecretKey,
-		Bucket:    cfg.S3Bucket,
-		SSL:       cfg.S3SSL,
-	})
-	if errS3 != nil {
-		return nil, fmt.Errorf("cannot configure s3 media store: %w", errS3)
-	}
-	return &Service{
-		logger:       cfg.Logger,
-		sessS:        cfg.SessionStore,
-		cors:         cfg.CORSConfig,
-		mediaS:       s3Store,
-		s3PathPrefix: []byte(fmt.Sprintf("%s/", strings.TrimSuffix(cfg.S3PathPrefix, "/"))),
-		uriPrefixLen: len(cfg.URIPathPrefix),
-	}, nil
+	if cfg.MediaStore == nil {
+		return nil, errors.New("media store is not set")
+	}
+	svc := &Service{
+		logger:         cfg.Logger,
+		sessS:          cfg.SessionStore,
+		verifier:       cfg.Verifier,
+		analytics:      cfg.Analytics,
+		manifests:      cfg.Manifests,
+		cors:           cfg.CORSConfig,
+		mediaS:         cfg.MediaStore,
+		cacheControl:   cfg.CacheControl,
+		clientIPHeader: cfg.ClientIPHeader,
+		pathPrefix:     []byte(fmt.Sprintf("%s/", strings.TrimSuffix(cfg.PathPrefix, "/"))),
+		uriPrefixLen:   len(cfg.URIPathPrefix),
+	}
+	if cfg.Cache != nil {
+		cache, err := newSegmentCache(cfg.Cache)
+		if err != nil {
+			return nil, err
+		}
+		svc.cache = cache
+		if cfg.CacheStatsPath != "" {
+			svc.statsPath = []byte(cfg.CacheStatsPath)
+		}
+	}
+	return svc, nil
+}
+
+// Close releases segment and meta caches.
+func (svc *Service) Close() {
+	if svc.cache != nil {
+		svc.cache.close()
+	}
+	if svc.manifests != nil {
+		svc.manifests.close()
+	}
+}
+
+// CacheStats returns segment cache counters. False is returned if cache is disabled.
+func (svc *Service) CacheStats() (CacheStats, bool) {
+	if svc.cache == nil {
+		return CacheStats{}, false
+	}
+	return svc.cache.stats(), true
 }
 
 func (svc *Service) Handler() func(*fasthttp.RequestCtx) {
@@ -98,22 +185,24 @@ func (svc *Service) handleWatch(ctx *fasthttp.RequestCtx) {
 		ctx.Error("bad request", fasthttp.StatusBadRequest)
 		return
 	}
+	if svc.statsPath != nil && bytes.Equal(ctx.Path(), svc.statsPath) {
+		svc.handleCacheStats(ctx)
+		return
+	}
 	// Get all necessary params from request URI
-	sessID, path, cType, err := svc.getSessionIDAndSegmentPathFromURI(ctx.Request.RequestURI())
+	sessID, path, cType, err := svc.getSessionIDAndSegmentPathFromURI(ctx.URI().PathOriginal())
 	if err != nil {
 		svc.logger.Debug("uri is not valid", zap.Error(err))
 		ctx.Error(err.Error(), fasthttp.StatusBadRequest)
 		return
 	}
 	// Retrieve session
-	sess, errSess := svc.sessS.GetExpireCached(ctx, sessID)
-	if errSess != nil {
-		if errors.Is(errSess, sessionStore.ErrNotFound) {
-			ctx.Error(notFoundError, fasthttp.StatusNotFound)
-			return
-		}
-		svc.logger.Debug("cannot get session", zap.Error(errSess))
-		ctx.Error(internalError, fasthttp.StatusInternalServerError)
+	sess, ok := svc.getSession(ctx, sessID)
+	if !ok {
+		return
+	}
+	if cType == contentTypeMPD && svc.manifests != nil && sess.UserID != "" {
+		svc.handleManifest(ctx, sessID, sess, path)
 		return
 	}
 
@@ -122,42 +211,191 @@ func (svc *Service) handleWatch(ctx *fasthttp.RequestCtx) {
 	// Proceed with segment handling
 	// --------------------------------------------------
 	// Get segment reader
-	rc, size, errS3 := svc.mediaS.Get(ctx, svc.getSegmentName(sess, path))
-	if errS3 != nil {
-		if errors.Is(errS3, s3.ErrNotFount) {
+	name, errN := svc.getSegmentName(sess, path)
+	if errN != nil {
+		svc.logger.Debug("cannot get segment name", zap.Error(errN))
+		ctx.Error(notFoundError, fasthttp.StatusNotFound)
+		return
+	}
+	body, rc, size, errMS := svc.getSegment(ctx, name, cType)
+	if errMS != nil {
+		if errors.Is(errMS, fs.ErrNotExist) {
 			ctx.Error(notFoundError, fasthttp.StatusNotFound)
 			return
 		}
-		svc.logger.Error("error while retrieving segment", zap.Error(errS3))
+		svc.logger.Error("error while retrieving segment", zap.Error(errMS))
 		ctx.Error(internalError, fasthttp.StatusInternalServerError)
 		return
 	}
 
 	svc.logger.Debug("serving segment",
 		zap.String("video_id", sess.VideoID),
+		zap.String("playlist_id", sess.PlaylistID),
 		zap.String("session_id", sess.ID),
 		zap.String("path", string(path)),
 		zap.Int64("size", size),
 		zap.String("type", cType))
+	if svc.analytics != nil {
+		svc.analytics.record(sess, path, size)
+	}
 
 	// --------------------------------------------------
 	// Set headers and body
 	// --------------------------------------------------
+	svc.setHeaders(ctx, cType)
+	if body != nil {
+		ctx.SetBody(body)
+		return
+	}
+	// Set body reader, fasthttp will handle the rest
+	ctx.SetBodyStream(rc, int(size)) // reader will be closed by fasthttp
+}
+
+// handleManifest serves MPD rendered from playback meta of session.
+func (svc *Service) handleManifest(ctx *fasthttp.RequestCtx, sessID string, sess *session.Session, path []byte) {
+	f, err := parseFilter(ctx.QueryArgs())
+	if err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	body, err := svc.manifests.render(ctx, sessID, sess, f)
+	switch {
+	case errors.Is(err, meta.ErrNoTracks):
+		ctx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	case errors.Is(err, errNoWatchMeta):
+		svc.logger.Debug("cannot render manifest", zap.Error(err))
+		ctx.Error(notFoundError, fasthttp.StatusNotFound)
+		return
+	case err != nil:
+		svc.logger.Error("cannot render manifest", zap.Error(err))
+		ctx.Error(internalError, fasthttp.StatusInternalServerError)
+		return
+	}
+	svc.logger.Debug("serving rendered manifest",
+		zap.String("video_id", sess.VideoID),
+		zap.String("playlist_id", sess.PlaylistID),
+		zap.String("session_id", sess.ID),
+		zap.Int("size", len(body)))
+	if svc.analytics != nil {
+		svc.analytics.record(sess, path, int64(len(body)))
+	}
+	svc.setHeaders(ctx, contentTypeMPD)
+	ctx.SetBody(body)
+}
+
+func (svc *Service) setHeaders(ctx *fasthttp.RequestCtx, cType string) {
 	if svc.cors != nil {
 		ctx.Response.Header.Set("Access-Control-Allow-Origin", svc.cors.AllowOrigin)
 	}
 	ctx.Response.Header.Set("Content-Type", cType)
-	// Set body reader, fasthttp will handle the rest
-	ctx.SetBodyStream(rc, int(size)) // reader will be closed by fasthttp
+	if cc := svc.getCacheControl(cType); cc != "" {
+		ctx.Response.Header.Set("Cache-Control", cc)
+	}
 }
 
-func (svc *Service) getSegmentName(sess *session.Session, path []byte) string {
+// getSegment returns segment either from cache or as a reader from media store.
+// Manifests and segments that are too large for cache are always read from media store.
+func (svc *Service) getSegment(
+	ctx context.Context,
+	name, cType string,
+) ([]byte, io.ReadSeekCloser, int64, error) {
+	if svc.cache != nil && cType != contentTypeMPD {
+		body, err := svc.cache.get(ctx, name, svc.mediaS)
+		if err == nil {
+			return body, nil, int64(len(body)), nil
+		}
+		if !errors.Is(err, errOversized) {
+			return nil, nil, 0, err
+		}
+	}
+	rc, size, err := svc.mediaS.Get(ctx, name)
+	if err != nil {
+		return nil, nil, 0, err //nolint:wrapcheck // media store error is checked by caller
+	}
+	return nil, rc, size, nil
+}
+
+func (svc *Service) getCacheControl(cType string) string {
+	if cType == contentTypeMPD {
+		return svc.cacheControl.Manifest
+	}
+	return svc.cacheControl.Segment
+}
+
+func (svc *Service) handleCacheStats(ctx *fasthttp.RequestCtx) {
+	b, err := json.Marshal(svc.cache.stats())
+	if err != nil {
+		svc.logger.Error("cannot encode cache stats", zap.Error(err))
+		ctx.Error(internalError, fasthttp.StatusInternalServerError)
+		return
+	}
+	ctx.SetContentType("application/json")
+	ctx.SetBody(b)
+}
+
+// getSession returns session from session store or from signed token.
+// If session cannot be retrieved, error response is set and false is returned.
+func (svc *Service) getSession(ctx *fasthttp.RequestCtx, sessID string) (*session.Session, bool) {
+	if svc.verifier != nil && token.IsToken(sessID) {
+		sess, err := svc.verifier.Verify(sessID, svc.clientIP(ctx))
+		if err != nil {
+			svc.logger.Debug("invalid watch token", zap.Error(err))
+			ctx.Error(forbiddenError, fasthttp.StatusForbidden)
+			return nil, false
+		}
+		return sess, true
+	}
+	sess, err := svc.sessS.GetExpireCached(ctx, sessID)
+	if err != nil {
+		if errors.Is(err, sessionStore.ErrNotFound) {
+			ctx.Error(notFoundError, fasthttp.StatusNotFound)
+			return nil, false
+		}
+		svc.logger.Debug("cannot get session", zap.Error(err))
+		ctx.Error(internalError, fasthttp.StatusInternalServerError)
+		return nil, false
+	}
+	return sess, true
+}
+
+// clientIP returns ip of client from configured header (i.e. when streamer is behind proxy)
+// or remote address of connection.
+func (svc *Service) clientIP(ctx *fasthttp.RequestCtx) string {
+	if svc.clientIPHeader != "" {
+		if ip := ctx.Request.Header.Peek(svc.clientIPHeader); len(ip) > 0 {
+			return string(ip)
+		}
+	}
+	return ctx.RemoteIP().String()
+}
+
+// getSegmentName returns media store path of requested segment.
+// Multi-period sessions have period index as first path element:
+// /<period>/<segment>, period index selects session location.
+func (svc *Service) getSegmentName(sess *session.Session, path []byte) (string, error) {
+	if bytes.Contains(path, dotDot) {
+		// segment must not be taken outside of session location
+		return "", errors.New("invalid path")
+	}
+	location := sess.Location
+	if len(sess.Locations) > 0 {
+		idx := bytes.IndexByte(path[1:], '/')
+		if idx == -1 {
+			return "", errors.New("period is not specified")
+		}
+		period, err := strconv.Atoi(string(path[1 : idx+1]))
+		if err != nil || period < 0 || period >= len(sess.Locations) {
+			return "", fmt.Errorf("invalid period: %s", path[1:idx+1])
+		}
+		location, path = sess.Locations[period], path[idx+1:]
+	}
 	var b []byte
-	return string(append(append(append(b, svc.s3PathPrefix...), []byte(sess.Location)...), path...))
+	return string(append(append(append(b, svc.pathPrefix...), []byte(location)...), path...)), nil
 }
 
 func (svc *Service) getSessionIDAndSegmentPathFromURI(uri []byte) (string, []byte, string, error) {
-	// URI: /prefix/<session-id>/<segment>
+	// URI: /prefix/<session-id>/<segment> or /prefix/<session-id>/<period>/<segment>
 	if svc.uriPrefixLen >= len(uri) {
 		return "", nil, "", errors.New("request uri is less than configured prefix")
 	}
@@ -175,17 +413,18 @@ func (svc *Service) getSessionIDAndSegmentPathFromURI(uri []byte) (string, []byt
 	case bytes.HasSuffix(path, objTypeSegment):
 		cType = contentTypeSegment
 	case bytes.HasSuffix(path, objTypeMP4): // for init segments
+		// path may contain period index, so track type is checked in last element
+		name := path[bytes.LastIndexByte(path, '/')+1:]
 		switch {
-		case bytes.HasPrefix(path[1:], trackTypeAudio):
+		case bytes.HasPrefix(name, trackTypeAudio):
 			cType = contentTypeAudioMP4
-		case bytes.HasPrefix(path[1:], trackTypeVideo):
+		case bytes.HasPrefix(name, trackTypeVideo):
 			cType = contentTypeVideoMP4
 		default:
 			return "", nil, "", fmt.Errorf("cannot determine mp4 track type")
 		}
 	case bytes.HasSuffix(path, objTypeMPD):
-		// TODO MPD is also served as segment at the moment.
-		//  In the future it should be moved to video api.
+		// MPD is rendered by manifests if enabled, otherwise it is served as stored object
 		cType = contentTypeMPD
 	default:
 		return "", nil, "", fmt.Errorf("invalid segment type")
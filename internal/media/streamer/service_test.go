@@ -0,0 +1,158 @@
+package streamer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adwski/vidi/internal/session"
+	"github.com/adwski/vidi/internal/session/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestService_getSessionIDAndSegmentPathFromURI(t *testing.T) {
+	svc := &Service{uriPrefixLen: len("/watch")}
+
+	type want struct {
+		sess  string
+		path  string
+		cType string
+	}
+	tests := []struct {
+		name string
+		uri  string
+		want want
+		err  bool
+	}{
+		{
+			name: "segment",
+			uri:  "/watch/sess/vide1_1.m4s",
+			want: want{sess: "sess", path: "/vide1_1.m4s", cType: contentTypeSegment},
+		},
+		{
+			name: "audio init",
+			uri:  "/watch/sess/soun1_init.mp4",
+			want: want{sess: "sess", path: "/soun1_init.mp4", cType: contentTypeAudioMP4},
+		},
+		{
+			name: "period video init",
+			uri:  "/watch/sess/1/vide1_init.mp4",
+			want: want{sess: "sess", path: "/1/vide1_init.mp4", cType: contentTypeVideoMP4},
+		},
+		{
+			name: "unknown init",
+			uri:  "/watch/sess/1/init.mp4",
+			err:  true,
+		},
+		{
+			name: "no segment",
+			uri:  "/watch/sess/",
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sess, path, cType, err := svc.getSessionIDAndSegmentPathFromURI([]byte(tt.uri))
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.sess, sess)
+			assert.Equal(t, tt.want.path, string(path))
+			assert.Equal(t, tt.want.cType, cType)
+		})
+	}
+}
+
+func TestService_getSegmentName(t *testing.T) {
+	svc := &Service{pathPrefix: []byte("/vidi/")}
+
+	name, err := svc.getSegmentName(&session.Session{Location: "loc"}, []byte("/vide1_1.m4s"))
+	require.NoError(t, err)
+	assert.Equal(t, "/vidi/loc/vide1_1.m4s", name)
+
+	multi := &session.Session{Locations: []string{"loc0", "loc1"}}
+	name, err = svc.getSegmentName(multi, []byte("/1/vide1_1.m4s"))
+	require.NoError(t, err)
+	assert.Equal(t, "/vidi/loc1/vide1_1.m4s", name)
+
+	_, err = svc.getSegmentName(&session.Session{Location: "loc"}, []byte("/../loc2/vide1_1.m4s"))
+	require.Error(t, err)
+
+	for _, path := range []string{"/vide1_1.m4s", "/2/vide1_1.m4s", "/-1/vide1_1.m4s", "/x/vide1_1.m4s"} {
+		_, err = svc.getSegmentName(multi, []byte(path))
+		require.Error(t, err, path)
+	}
+}
+
+type fakeMediaStore struct {
+	objects map[string][]byte
+}
+
+type nopCloser struct {
+	*bytes.Reader
+}
+
+func (nopCloser) Close() error { return nil }
+
+func (f *fakeMediaStore) Get(_ context.Context, name string) (io.ReadSeekCloser, int64, error) {
+	b, ok := f.objects[name]
+	if !ok {
+		return nil, 0, fs.ErrNotExist
+	}
+	return nopCloser{bytes.NewReader(b)}, int64(len(b)), nil
+}
+
+func TestService_handleWatchSigned(t *testing.T) {
+	key := &token.Key{ID: "k1", Alg: token.AlgHS256, Secret: []byte(strings.Repeat("k", 32))}
+	signer, err := token.NewSigner(key, time.Minute)
+	require.NoError(t, err)
+	verifier, err := token.NewVerifier([]*token.Key{key})
+	require.NoError(t, err)
+
+	svc, err := New(&Config{
+		Logger:         zap.NewNop(),
+		MediaStore:     &fakeMediaStore{objects: map[string][]byte{"/watch/loc/vide1_1.m4s": []byte("segment")}},
+		URIPathPrefix:  "/watch",
+		PathPrefix:     "/watch",
+		Verifier:       verifier,
+		ClientIPHeader: "X-Real-IP",
+	})
+	require.NoError(t, err)
+
+	sess := &session.Session{ID: "sess", VideoID: "vid", Location: "loc"}
+	bound, err := signer.Sign(sess, "1.2.3.4")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		token  string
+		ip     string
+		status int
+	}{
+		{name: "valid", token: bound, ip: "1.2.3.4", status: fasthttp.StatusOK},
+		{name: "other client", token: bound, ip: "4.3.2.1", status: fasthttp.StatusForbidden},
+		{name: "invalid signature", token: bound[:len(bound)-2] + "xx", ip: "1.2.3.4", status: fasthttp.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.Header.SetMethod(fasthttp.MethodGet)
+			ctx.Request.SetRequestURI("/watch/" + tt.token + "/vide1_1.m4s")
+			ctx.Request.Header.Set("X-Real-IP", tt.ip)
+			svc.handleWatch(ctx)
+			assert.Equal(t, tt.status, ctx.Response.StatusCode())
+			if tt.status == fasthttp.StatusOK {
+				assert.Equal(t, []byte("segment"), ctx.Response.Body())
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package uploader
+
+import "sync"
+
+// limiter bounds amount of concurrent uploads and total size of parts
+// that are being uploaded at the same time. Zero limit is not enforced.
+type limiter struct {
+	mx         sync.Mutex
+	maxUploads uint
+	maxBytes   uint64
+	uploads    uint
+	bytes      uint64
+}
+
+// fits returns false if part of specified size could never be accepted.
+func (l *limiter) fits(size uint64) bool {
+	return l.maxBytes == 0 || size <= l.maxBytes
+}
+
+// acquire reserves upload slot and specified amount of bytes.
+// It does not wait and returns false if limits are exceeded.
+func (l *limiter) acquire(size uint64) bool {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if l.maxUploads > 0 && l.uploads >= l.maxUploads {
+		return false
+	}
+	if l.maxBytes > 0 && l.bytes+size > l.maxBytes {
+		return false
+	}
+	l.uploads++
+	l.bytes += size
+	return true
+}
+
+func (l *limiter) release(size uint64) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.uploads--
+	l.bytes -= size
+}
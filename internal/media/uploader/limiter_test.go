@@ -0,0 +1,35 @@
+package uploader
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	l := &limiter{maxUploads: 2, maxBytes: 100}
+
+	assert.True(t, l.fits(100))
+	assert.False(t, l.fits(101))
+
+	assert.True(t, l.acquire(60))
+	assert.False(t, l.acquire(50), "bytes limit")
+	assert.True(t, l.acquire(40))
+	assert.False(t, l.acquire(0), "uploads limit")
+
+	l.release(60)
+	assert.True(t, l.acquire(50))
+	l.release(50)
+	l.release(40)
+	assert.Equal(t, uint(0), l.uploads)
+	assert.Equal(t, uint64(0), l.bytes)
+}
+
+func TestLimiter_Unlimited(t *testing.T) {
+	l := &limiter{}
+
+	assert.True(t, l.fits(1<<40))
+	for range 1000 {
+		assert.True(t, l.acquire(1<<30))
+	}
+}
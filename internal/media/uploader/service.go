@@ -3,22 +3,32 @@ package uploader
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/adwski/vidi/internal/event"
 	"github.com/adwski/vidi/internal/event/notificator"
-	"github.com/adwski/vidi/internal/media/store/s3"
+	"github.com/adwski/vidi/internal/session"
 	sessionStore "github.com/adwski/vidi/internal/session/store"
+	"github.com/minio/sha256-simd"
 	"github.com/valyala/fasthttp"
 	"go.uber.org/zap"
 )
 
 const (
-	internalError = "internal error"
-	sizeError     = "incorrect size"
-	notFoundError = "not found"
+	internalError    = "internal error"
+	sizeError        = "incorrect size"
+	notFoundError    = "not found"
+	unknownPartError = "unknown part"
+	checksumError    = "checksum mismatch"
+	busyError        = "too many uploads"
+	tooLargeError    = "part is too large"
+
+	retryAfterSeconds = "1"
 )
 
 var (
@@ -26,59 +36,105 @@ var (
 	methodPOST               = []byte("POST")
 )
 
+// SessionStore provides upload sessions.
+type SessionStore interface {
+	Get(ctx context.Context, id string) (*session.Session, error)
+}
+
+// MediaStore stores uploaded parts.
+type MediaStore interface {
+	Put(ctx context.Context, name string, r io.Reader, size int64) error
+}
+
 // Service is a media file uploader service. It implements fasthttp handler that
 // reads uploaded part and stores it in media store.
 // Every request is also checked for valid "upload"-session.
 //
-// After each successful part upload, uploader calculates sha256 checksum
-// and asynchronously notifies videoapi.
+// Request body is streamed straight to media store, so memory usage does not
+// depend on part size. Amount of concurrent uploads and total size of parts
+// in flight are limited, requests above limits are rejected with 503.
+// Upload bandwidth could also be throttled per session and per user,
+// throttled requests are rejected with 429.
+//
+// Sha256 checksum of part is calculated while part is streamed to media store.
+// After each part upload uploader asynchronously notifies videoapi. If session
+// has expected part checksum, part with other checksum is rejected with 422.
+//
+// Uploads could also be done with tus protocol if tus endpoint is enabled.
 type Service struct {
 	logger       *zap.Logger
-	sessS        *sessionStore.Store
-	mediaS       *s3.Store
+	sessS        SessionStore
+	mediaS       MediaStore
 	notificator  *notificator.Notificator
-	s3pathPrefix []byte
+	limiter      *limiter
+	throttler    *throttler
+	tus          *tus
+	pathPrefix   []byte
 	uriPrefixLen int
 }
 
 type Config struct {
 	Logger         *zap.Logger
 	Notificator    *notificator.Notificator
-	SessionStorage *sessionStore.Store
+	SessionStorage SessionStore
+	MediaStore     MediaStore
 	URIPathPrefix  string
-	S3PathPrefix   string
-	S3Endpoint     string
-	S3AccessKey    string
-	S3SecretKey    string
-	S3Bucket       string
-	S3SSL          bool
+	PathPrefix     string
+
+	// MaxConcurrentUploads limits amount of parts uploaded at the same time.
+	MaxConcurrentUploads uint
+	// MaxInflightSize limits total size of parts uploaded at the same time.
+	MaxInflightSize uint64
+
+	// Throttle enables upload bandwidth throttling if set.
+	Throttle *ThrottleConfig
+
+	// Tus enables tus endpoint if set.
+	Tus *TusConfig
 }
 
 func New(cfg *Config) (*Service, error) {
-	s3Store, errS3 := s3.NewStore(&s3.StoreConfig{
-		Logger:       cfg.Logger,
-		Endpoint:     cfg.S3Endpoint,
-		AccessKey:    cfg.S3AccessKey,
-		SecretKey:    cfg.S3SecretKey,
-		Bucket:       cfg.S3Bucket,
-		SSL:          cfg.S3SSL,
-		CreateBucket: true,
-	})
-	if errS3 != nil {
-		return nil, fmt.Errorf("cannot configure s3 media store: %w", errS3)
+	if cfg.MediaStore == nil {
+		return nil, errors.New("media store is not set")
 	}
-	return &Service{
+	svc := &Service{
 		uriPrefixLen: len(cfg.URIPathPrefix),
-		s3pathPrefix: []byte(fmt.Sprintf("%s/", strings.TrimSuffix(cfg.S3PathPrefix, "/"))),
+		pathPrefix:   []byte(fmt.Sprintf("%s/", strings.TrimSuffix(cfg.PathPrefix, "/"))),
 		logger:       cfg.Logger.With(zap.String("component", "uploader")),
 		sessS:        cfg.SessionStorage,
-		mediaS:       s3Store,
+		mediaS:       cfg.MediaStore,
 		notificator:  cfg.Notificator,
-	}, nil
+		limiter: &limiter{
+			maxUploads: cfg.MaxConcurrentUploads,
+			maxBytes:   cfg.MaxInflightSize,
+		},
+	}
+	if cfg.Throttle != nil {
+		var err error
+		if svc.throttler, err = newThrottler(cfg.Throttle); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Tus != nil {
+		if cfg.Tus.VideoAPI == nil || cfg.Tus.MediaStore == nil || cfg.Tus.Locker == nil {
+			return nil, errors.New("tus videoapi client, media store or locker is not set")
+		}
+		svc.tus = newTus(cfg.Tus, cfg.URIPathPrefix)
+	}
+	return svc, nil
 }
 
 func (svc *Service) Handler() func(*fasthttp.RequestCtx) {
-	return svc.handleUpload
+	if svc.tus == nil {
+		return svc.handleUpload
+	}
+	return func(ctx *fasthttp.RequestCtx) {
+		if svc.tus.match(ctx.Path()) {
+			svc.handleTus(ctx)
+			return
+		}
+		svc.handleUpload(ctx)
+	}
 }
 
 func (svc *Service) handleUpload(ctx *fasthttp.RequestCtx) {
@@ -125,13 +181,42 @@ func (svc *Service) handleUpload(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	// validate part number
+	num := parseUint(partNum) // getParamsFromURI ensures that partNum contains valid number
+	if len(sess.PartChecksums) > 0 && num >= uint(len(sess.PartChecksums)) {
+		ctx.Error(unknownPartError, fasthttp.StatusBadRequest)
+		return
+	}
+
+	// check limits
+	if !svc.limiter.fits(uint64(size)) {
+		ctx.Error(tooLargeError, fasthttp.StatusRequestEntityTooLarge)
+		return
+	}
+	if !svc.limiter.acquire(uint64(size)) {
+		ctx.Error(busyError, fasthttp.StatusServiceUnavailable)
+		ctx.Response.Header.Set(fasthttp.HeaderRetryAfter, retryAfterSeconds)
+		return
+	}
+	defer svc.limiter.release(uint64(size))
+	if wait := svc.throttled(ctx, sess, uint64(size)); wait > 0 {
+		ctx.Error(throttledError, fasthttp.StatusTooManyRequests)
+		ctx.Response.Header.Set(fasthttp.HeaderRetryAfter, retryAfter(wait))
+		return
+	}
+
 	// --------------------------------------------------
 	// Request is valid and session exists
 	// Proceed with upload
 	// --------------------------------------------------
 	artifactName := svc.getUploadArtifactName(sessID, partNum)
-	buf := bytes.NewBuffer(ctx.Request.Body())
-	err = svc.mediaS.Put(ctx, artifactName, buf, int64(size))
+	body := ctx.RequestBodyStream()
+	if body == nil {
+		// body streaming is disabled in server
+		body = bytes.NewReader(ctx.Request.Body())
+	}
+	shaW := sha256.New()
+	err = svc.mediaS.Put(ctx, artifactName, io.TeeReader(io.LimitReader(body, int64(size)), shaW), int64(size))
 	if err != nil {
 		svc.logger.Error("error while uploading artifact",
 			zap.Int("size", size),
@@ -141,26 +226,36 @@ func (svc *Service) handleUpload(ctx *fasthttp.RequestCtx) {
 		ctx.Error(internalError, fasthttp.StatusInternalServerError)
 		return
 	}
-	checksum, err := svc.mediaS.CalcSha256(ctx, artifactName)
-	if err != nil {
-		svc.logger.Error("unable to calc sha256",
-			zap.String("artifactName", artifactName))
-		ctx.Error(internalError, fasthttp.StatusInternalServerError)
-		return
-	}
-	ctx.SetStatusCode(fasthttp.StatusNoContent)
+	checksum := base64.StdEncoding.EncodeToString(shaW.Sum(nil))
 
 	// --------------------------------------------------
 	// Postprocessing phase
 	// --------------------------------------------------
+	// videoapi is notified about corrupted part as well, so part is marked as invalid
 	go svc.notificator.Send(&event.Event{
 		PartInfo: &event.PartInfo{
 			VideoID:  sess.VideoID,
-			Checksum: checksum,           // this is already base64 encoded
-			Num:      parseUint(partNum), // getParamsFromURI ensures that partNum contains valid number
+			Checksum: checksum,
+			Num:      num,
 		},
 		Kind: event.KindVideoPartUploaded,
 	})
+	if expected := expectedChecksum(sess.PartChecksums, num); expected != "" && expected != checksum {
+		svc.logger.Debug("part checksum mismatch",
+			zap.String("artifactName", artifactName),
+			zap.String("expected", expected),
+			zap.String("actual", checksum))
+		ctx.Error(checksumError, fasthttp.StatusUnprocessableEntity)
+		return
+	}
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+func expectedChecksum(checksums []string, num uint) string {
+	if num < uint(len(checksums)) {
+		return checksums[num]
+	}
+	return ""
 }
 
 func parseUint(b []byte) (num uint) {
@@ -184,8 +279,8 @@ func checkHeader(ctx *fasthttp.RequestCtx) (int, error) {
 }
 
 func (svc *Service) getUploadArtifactName(sessID, partNum []byte) string {
-	b := make([]byte, 0, len(svc.s3pathPrefix)+len(sessID)+len(partNum)+1)
-	b = append(b, svc.s3pathPrefix...)
+	b := make([]byte, 0, len(svc.pathPrefix)+len(sessID)+len(partNum)+1)
+	b = append(b, svc.pathPrefix...)
 	b = append(b, sessID...)
 	b = append(b, '/')
 	b = append(b, partNum...)
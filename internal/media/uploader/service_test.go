@@ -1,11 +1,17 @@
 package uploader
 
 import (
+	"bytes"
+	"context"
+	"io"
 	"testing"
 
+	"github.com/adwski/vidi/internal/event/notificator"
+	"github.com/adwski/vidi/internal/session"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
 )
 
 func Test_checkHeader(t *testing.T) {
@@ -63,3 +69,79 @@ func Test_checkHeader(t *testing.T) {
 		})
 	}
 }
+
+func Test_expectedChecksum(t *testing.T) {
+	checksums := []string{"aaa", "", "ccc"}
+	assert.Equal(t, "aaa", expectedChecksum(checksums, 0))
+	assert.Equal(t, "", expectedChecksum(checksums, 1))
+	assert.Equal(t, "ccc", expectedChecksum(checksums, 2))
+	assert.Equal(t, "", expectedChecksum(checksums, 3))
+	assert.Equal(t, "", expectedChecksum(nil, 0))
+}
+
+type fakeSessionStore struct {
+	sess *session.Session
+}
+
+func (f *fakeSessionStore) Get(_ context.Context, _ string) (*session.Session, error) {
+	return f.sess, nil
+}
+
+type fakeMediaStore struct {
+	data []byte
+}
+
+func (f *fakeMediaStore) Put(_ context.Context, _ string, r io.Reader, _ int64) error {
+	var err error
+	f.data, err = io.ReadAll(r)
+	return err
+}
+
+func TestService_handleUploadLimits(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	n, err := notificator.New(&notificator.Config{Logger: logger, VideoAPIURL: "localhost:1"})
+	require.NoError(t, err)
+
+	ms := &fakeMediaStore{}
+	svc, err := New(&Config{
+		Logger:               logger,
+		Notificator:          n,
+		SessionStorage:       &fakeSessionStore{sess: &session.Session{VideoID: "v", PartSize: 100}},
+		MediaStore:           ms,
+		URIPathPrefix:        "/upload",
+		PathPrefix:           "/upload",
+		MaxConcurrentUploads: 1,
+		MaxInflightSize:      50,
+	})
+	require.NoError(t, err)
+
+	newCtx := func(body []byte) *fasthttp.RequestCtx {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+		ctx.Request.SetRequestURI("/upload/sess/0")
+		ctx.Request.Header.SetContentType(string(contentTypeVidiMediapart))
+		ctx.Request.SetBodyStream(bytes.NewReader(body), len(body))
+		return ctx
+	}
+
+	// part is larger than in-flight limit
+	ctx := newCtx(make([]byte, 60))
+	svc.handleUpload(ctx)
+	assert.Equal(t, fasthttp.StatusRequestEntityTooLarge, ctx.Response.StatusCode())
+
+	// all upload slots are busy
+	require.True(t, svc.limiter.acquire(10))
+	ctx = newCtx([]byte("test"))
+	svc.handleUpload(ctx)
+	assert.Equal(t, fasthttp.StatusServiceUnavailable, ctx.Response.StatusCode())
+	assert.Equal(t, retryAfterSeconds, string(ctx.Response.Header.Peek(fasthttp.HeaderRetryAfter)))
+	svc.limiter.release(10)
+
+	ctx = newCtx([]byte("test"))
+	svc.handleUpload(ctx)
+	assert.Equal(t, fasthttp.StatusNoContent, ctx.Response.StatusCode())
+	assert.Equal(t, []byte("test"), ms.data)
+	assert.Equal(t, uint(0), svc.limiter.uploads)
+}
@@ -0,0 +1,176 @@
+package uploader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/adwski/vidi/internal/session"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	throttleKeyPrefix = "throttle:"
+	throttledError    = "upload rate exceeded"
+)
+
+// throttleScript implements token buckets for all passed keys. Buckets are refilled
+// with rate bytes per second up to burst. Request is allowed if none of buckets are
+// in debt, and then its cost is taken from every bucket at once. Since cost is taken
+// after the check, parts larger than burst are still accepted, but next request
+// has to wait until debt is repaid.
+//
+// ARGV[1] is request cost, ARGV[2*i] and ARGV[2*i+1] are rate and burst of KEYS[i].
+// Script returns amount of milliseconds to wait, or 0 if request is allowed.
+// Redis time is used, so buckets are consistent between replicas.
+var throttleScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local cost = tonumber(ARGV[1])
+local wait = 0
+local tokens = {}
+for i, key in ipairs(KEYS) do
+	local rate = tonumber(ARGV[i * 2])
+	local burst = tonumber(ARGV[i * 2 + 1])
+	local b = redis.call('HMGET', key, 'tokens', 'ts')
+	local tk = tonumber(b[1]) or burst
+	local ts = tonumber(b[2]) or now
+	tk = math.min(burst, tk + math.max(0, now - ts) * rate / 1000)
+	if tk < 0 then
+		wait = math.max(wait, math.ceil(-tk * 1000 / rate))
+	end
+	tokens[i] = tk
+end
+if wait > 0 then
+	return wait
+end
+for i, key in ipairs(KEYS) do
+	local rate = tonumber(ARGV[i * 2])
+	local burst = tonumber(ARGV[i * 2 + 1])
+	local tk = tokens[i] - cost
+	redis.call('HSET', key, 'tokens', tostring(tk), 'ts', now)
+	redis.call('PEXPIRE', key, math.ceil((burst - tk) * 1000 / rate) + 1000)
+end
+return 0
+`)
+
+// ThrottleConfig configures upload bandwidth limits. Rates are in bytes per second,
+// zero rate is not enforced. Limits are applied per upload session and per user.
+type ThrottleConfig struct {
+	// Redis is used to share buckets between uploader replicas.
+	Redis *redis.Client
+	// SessionRate limits upload rate of single upload session.
+	SessionRate uint64
+	// UserRate limits total upload rate of all sessions of user.
+	UserRate uint64
+	// Burst is a size of bucket, it defaults to one second worth of rate.
+	Burst uint64
+	// Tiers override user rate for listed users.
+	Tiers []ThrottleTier
+}
+
+// ThrottleTier is a group of users with its own upload rate.
+type ThrottleTier struct {
+	Name     string
+	UserRate uint64
+	Users    []string
+}
+
+// throttler limits upload bandwidth with token buckets stored in redis.
+type throttler struct {
+	r           *redis.Client
+	userRates   map[string]uint64
+	sessionRate uint64
+	userRate    uint64
+	burst       uint64
+}
+
+func newThrottler(cfg *ThrottleConfig) (*throttler, error) {
+	if cfg.Redis == nil {
+		return nil, errors.New("throttle redis client is not set")
+	}
+	t := &throttler{
+		r:           cfg.Redis,
+		sessionRate: cfg.SessionRate,
+		userRate:    cfg.UserRate,
+		burst:       cfg.Burst,
+		userRates:   make(map[string]uint64),
+	}
+	for _, tier := range cfg.Tiers {
+		for _, userID := range tier.Users {
+			if _, ok := t.userRates[userID]; ok {
+				return nil, fmt.Errorf("user %s belongs to more than one tier", userID)
+			}
+			t.userRates[userID] = tier.UserRate
+		}
+	}
+	return t, nil
+}
+
+// take consumes size bytes from session and user buckets. If any of them is in debt,
+// nothing is consumed and time to wait before next attempt is returned.
+func (t *throttler) take(ctx context.Context, sessID, userID string, size uint64) (time.Duration, error) {
+	var (
+		keys = make([]string, 0, 2)
+		args = make([]any, 1, 5)
+	)
+	args[0] = size
+	if t.sessionRate > 0 {
+		keys = append(keys, throttleKeyPrefix+"s:"+sessID)
+		args = append(args, t.sessionRate, t.burstOf(t.sessionRate))
+	}
+	if rate := t.userRateOf(userID); rate > 0 && userID != "" {
+		keys = append(keys, throttleKeyPrefix+"u:"+userID)
+		args = append(args, rate, t.burstOf(rate))
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	wait, err := throttleScript.Run(ctx, t.r, keys, args...).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("cannot check upload rate: %w", err)
+	}
+	return time.Duration(wait) * time.Millisecond, nil
+}
+
+func (t *throttler) userRateOf(userID string) uint64 {
+	if rate, ok := t.userRates[userID]; ok {
+		return rate
+	}
+	return t.userRate
+}
+
+func (t *throttler) burstOf(rate uint64) uint64 {
+	if t.burst == 0 {
+		return rate
+	}
+	return t.burst
+}
+
+// retryAfter formats wait duration for Retry-After header, it is rounded up to seconds.
+func retryAfter(wait time.Duration) string {
+	return strconv.FormatInt(int64((wait+time.Second-1)/time.Second), 10)
+}
+
+// throttled checks upload rate limits of session and returns time to wait if request is over the limit.
+// Throttler failures are logged and not enforced, so uploads still work if redis is degraded.
+func (svc *Service) throttled(ctx context.Context, sess *session.Session, size uint64) time.Duration {
+	if svc.throttler == nil {
+		return 0
+	}
+	wait, err := svc.throttler.take(ctx, sess.ID, sess.UserID, size)
+	if err != nil {
+		svc.logger.Error("cannot throttle upload", zap.Error(err))
+		return 0
+	}
+	if wait > 0 {
+		svc.logger.Debug("upload throttled",
+			zap.String("session", sess.ID),
+			zap.String("user", sess.UserID),
+			zap.Duration("wait", wait))
+	}
+	return wait
+}
@@ -0,0 +1,118 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adwski/vidi/internal/event/notificator"
+	"github.com/adwski/vidi/internal/session"
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+func TestThrottler_take(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	th, err := newThrottler(&ThrottleConfig{
+		Redis:       db,
+		SessionRate: 100,
+		UserRate:    200,
+		Tiers: []ThrottleTier{
+			{Name: "premium", UserRate: 1000, Users: []string{"vip"}},
+			{Name: "free", UserRate: 0, Users: []string{"unlimited"}},
+		},
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	sha := throttleScript.Hash()
+
+	mock.ExpectEvalSha(sha, []string{"throttle:s:sess", "throttle:u:user"},
+		uint64(10), uint64(100), uint64(100), uint64(200), uint64(200)).SetVal(int64(0))
+	mock.ExpectEvalSha(sha, []string{"throttle:s:sess", "throttle:u:vip"},
+		uint64(10), uint64(100), uint64(100), uint64(1000), uint64(1000)).SetVal(int64(1500))
+	mock.ExpectEvalSha(sha, []string{"throttle:s:sess"},
+		uint64(10), uint64(100), uint64(100)).SetVal(int64(0))
+	mock.ExpectEvalSha(sha, []string{"throttle:s:sess"},
+		uint64(10), uint64(100), uint64(100)).SetErr(errors.New("redis error"))
+
+	wait, err := th.take(ctx, "sess", "user", 10)
+	require.NoError(t, err)
+	assert.Zero(t, wait)
+
+	// user rate is overridden by tier
+	wait, err = th.take(ctx, "sess", "vip", 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, wait)
+	assert.Equal(t, "2", retryAfter(wait))
+
+	// unlimited tier and session without user
+	_, err = th.take(ctx, "sess", "unlimited", 10)
+	require.NoError(t, err)
+
+	_, err = th.take(ctx, "sess", "", 10)
+	require.Error(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNewThrottler(t *testing.T) {
+	_, err := newThrottler(&ThrottleConfig{})
+	require.Error(t, err)
+
+	db, _ := redismock.NewClientMock()
+	_, err = newThrottler(&ThrottleConfig{
+		Redis: db,
+		Tiers: []ThrottleTier{
+			{Name: "a", UserRate: 1, Users: []string{"user"}},
+			{Name: "b", UserRate: 2, Users: []string{"user"}},
+		},
+	})
+	require.ErrorContains(t, err, "more than one tier")
+
+	// nothing to throttle
+	th, err := newThrottler(&ThrottleConfig{Redis: db})
+	require.NoError(t, err)
+	wait, err := th.take(context.Background(), "sess", "user", 10)
+	require.NoError(t, err)
+	assert.Zero(t, wait)
+}
+
+func TestService_handleUploadThrottled(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	n, err := notificator.New(&notificator.Config{Logger: logger, VideoAPIURL: "localhost:1"})
+	require.NoError(t, err)
+
+	db, mock := redismock.NewClientMock()
+	svc, err := New(&Config{
+		Logger:         logger,
+		Notificator:    n,
+		SessionStorage: &fakeSessionStore{sess: &session.Session{ID: "sess", UserID: "user", PartSize: 100}},
+		MediaStore:     &fakeMediaStore{},
+		URIPathPrefix:  "/upload",
+		PathPrefix:     "/upload",
+		Throttle:       &ThrottleConfig{Redis: db, UserRate: 10},
+	})
+	require.NoError(t, err)
+
+	mock.ExpectEvalSha(throttleScript.Hash(), []string{"throttle:u:user"},
+		uint64(4), uint64(10), uint64(10)).SetVal(int64(2100))
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+	ctx.Request.SetRequestURI("/upload/sess/0")
+	ctx.Request.Header.SetContentType(string(contentTypeVidiMediapart))
+	ctx.Request.SetBodyStream(bytes.NewReader([]byte("test")), 4)
+	svc.handleUpload(ctx)
+
+	assert.Equal(t, fasthttp.StatusTooManyRequests, ctx.Response.StatusCode())
+	assert.Equal(t, "3", string(ctx.Response.Header.Peek(fasthttp.HeaderRetryAfter)))
+	assert.Equal(t, uint(0), svc.limiter.uploads)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
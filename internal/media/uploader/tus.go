@@ -0,0 +1,663 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1" //nolint:gosec // sha1 is required by tus checksum extension
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/grpc/userside/pb"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/event"
+	"github.com/adwski/vidi/internal/session"
+	sessionStore "github.com/adwski/vidi/internal/session/store"
+	"github.com/minio/sha256-simd"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	tusVersion            = "1.0.0"
+	tusExtensions         = "creation,checksum,termination"
+	tusChecksumAlgorithms = "sha1,sha256"
+	tusURISuffix          = "/tus"
+
+	headerTusResumable         = "Tus-Resumable"
+	headerTusVersion           = "Tus-Version"
+	headerTusExtension         = "Tus-Extension"
+	headerTusChecksumAlgorithm = "Tus-Checksum-Algorithm"
+	headerUploadLength         = "Upload-Length"
+	headerUploadDeferLength    = "Upload-Defer-Length"
+	headerUploadOffset         = "Upload-Offset"
+	headerUploadMetadata       = "Upload-Metadata"
+	headerUploadChecksum       = "Upload-Checksum"
+
+	// statusChecksumMismatch is defined by tus checksum extension.
+	statusChecksumMismatch = 460
+
+	// defaultTusLockTTL is used if lock ttl is not configured,
+	// it should be longer than any PATCH request.
+	defaultTusLockTTL = 10 * time.Minute
+
+	partialSuffix    = ".partial-"
+	defaultVideoName = "untitled"
+	authCookieName   = "vidiSessID"
+)
+
+var (
+	contentTypeOffsetOctetStream = []byte("application/offset+octet-stream")
+	methodPATCH                  = []byte("PATCH")
+	bearerPrefix                 = []byte("bearer ")
+
+	errUnsupportedChecksum = errors.New("unsupported checksum algorithm")
+)
+
+// TusMediaStore is a media store that is used by tus endpoint.
+// Unfinished parts are kept in media store between requests,
+// so it should also be able to read, list and delete objects.
+type TusMediaStore interface {
+	MediaStore
+	Get(ctx context.Context, name string) (io.ReadSeekCloser, int64, error)
+	Delete(ctx context.Context, name string) error
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// TusLocker provides exclusive locks of upload sessions that are shared between uploader replicas.
+type TusLocker interface {
+	Lock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, error)
+}
+
+// TusConfig is a tus endpoint config.
+type TusConfig struct {
+	// VideoAPI is a user-side videoapi client. It is used to create
+	// and delete videos on behalf of user, so quotas are checked by videoapi.
+	VideoAPI   pb.UsersideapiClient
+	MediaStore TusMediaStore
+	// Locker serializes PATCH requests of the same upload. Upload state is kept
+	// in media store, so concurrent requests could overwrite or delete data of each other.
+	Locker TusLocker
+	// LockTTL limits how long upload stays locked if uploader fails during request.
+	// It should be longer than any PATCH request.
+	LockTTL time.Duration
+	// PartSize is a part size of videos created with tus.
+	PartSize uint64
+}
+
+// tus implements tus 1.0 resumable upload protocol with creation, checksum
+// and termination extensions.
+//
+// Upload created with tus is a regular video with upload session. Upload offset
+// is mapped onto parts of video: complete parts are stored as usual and unfinished
+// part is stored along with them as separate object which name contains its length.
+// Uploaded data is appended to unfinished part until part is complete. So current
+// offset could always be determined by listing upload location, and processor reads
+// parts of such videos the same way.
+type tus struct {
+	videoAPI  pb.UsersideapiClient
+	mediaS    TusMediaStore
+	locker    TusLocker
+	uriPrefix []byte
+	partSize  uint64
+	lockTTL   time.Duration
+}
+
+func newTus(cfg *TusConfig, uriPrefix string) *tus {
+	t := &tus{
+		videoAPI:  cfg.VideoAPI,
+		mediaS:    cfg.MediaStore,
+		locker:    cfg.Locker,
+		uriPrefix: []byte(uriPrefix + tusURISuffix),
+		partSize:  cfg.PartSize,
+		lockTTL:   cfg.LockTTL,
+	}
+	if t.partSize == 0 {
+		t.partSize = model.DefaultPartSize
+	}
+	if t.lockTTL <= 0 {
+		t.lockTTL = defaultTusLockTTL
+	}
+	return t
+}
+
+// match checks that request path belongs to tus endpoint.
+func (t *tus) match(path []byte) bool {
+	if !bytes.HasPrefix(path, t.uriPrefix) {
+		return false
+	}
+	return len(path) == len(t.uriPrefix) || path[len(t.uriPrefix)] == '/'
+}
+
+// sessionID returns upload session id from request path.
+// Valid is false if path does not contain single path element after prefix.
+func (t *tus) sessionID(path []byte) (sessID []byte, valid bool) {
+	rest := bytes.TrimPrefix(bytes.TrimPrefix(path, t.uriPrefix), []byte("/"))
+	return rest, !bytes.ContainsRune(rest, '/')
+}
+
+func (svc *Service) handleTus(ctx *fasthttp.RequestCtx) {
+	ctx.Response.Header.Set(headerTusResumable, tusVersion)
+	if ctx.IsOptions() {
+		ctx.Response.Header.Set(headerTusVersion, tusVersion)
+		ctx.Response.Header.Set(headerTusExtension, tusExtensions)
+		ctx.Response.Header.Set(headerTusChecksumAlgorithm, tusChecksumAlgorithms)
+		ctx.SetStatusCode(fasthttp.StatusNoContent)
+		return
+	}
+	if string(ctx.Request.Header.Peek(headerTusResumable)) != tusVersion {
+		ctx.Response.Header.Set(headerTusVersion, tusVersion)
+		ctx.SetStatusCode(fasthttp.StatusPreconditionFailed)
+		return
+	}
+	sessID, valid := svc.tus.sessionID(ctx.Path())
+	switch {
+	case !valid:
+		tusError(ctx, notFoundError, fasthttp.StatusNotFound)
+	case len(sessID) == 0 && ctx.IsPost():
+		svc.tusCreate(ctx)
+	case len(sessID) == 0:
+		tusError(ctx, "method not allowed", fasthttp.StatusMethodNotAllowed)
+	case ctx.IsHead():
+		svc.tusHead(ctx, sessID)
+	case bytes.Equal(ctx.Method(), methodPATCH):
+		svc.tusPatch(ctx, sessID)
+	case ctx.IsDelete():
+		svc.tusTerminate(ctx, sessID)
+	default:
+		tusError(ctx, "method not allowed", fasthttp.StatusMethodNotAllowed)
+	}
+}
+
+// tusCreate creates video in videoapi and responds with location of upload.
+func (svc *Service) tusCreate(ctx *fasthttp.RequestCtx) {
+	if len(ctx.Request.Header.Peek(headerUploadDeferLength)) > 0 {
+		tusError(ctx, "deferred upload length is not supported", fasthttp.StatusBadRequest)
+		return
+	}
+	size, err := strconv.ParseUint(string(ctx.Request.Header.Peek(headerUploadLength)), 10, 64)
+	if err != nil || size == 0 {
+		tusError(ctx, "invalid upload length", fasthttp.StatusBadRequest)
+		return
+	}
+	rpcCtx, ok := authContext(ctx)
+	if !ok {
+		tusError(ctx, "unauthorized", fasthttp.StatusUnauthorized)
+		return
+	}
+
+	// check quotas
+	quota, err := svc.tus.videoAPI.GetQuota(rpcCtx, &pb.GetQuotaRequest{})
+	if err != nil {
+		svc.tusAPIError(ctx, "cannot get quota", err)
+		return
+	}
+	if quota.VideosUsage >= quota.VideosQuota {
+		tusError(ctx, "video quota exceeded", fasthttp.StatusForbidden)
+		return
+	}
+	if quota.SizeUsage >= quota.SizeQuota || quota.SizeQuota-quota.SizeUsage < size {
+		tusError(ctx, "size quota exceeded", fasthttp.StatusRequestEntityTooLarge)
+		return
+	}
+
+	md := parseTusMetadata(ctx.Request.Header.Peek(headerUploadMetadata))
+	name := md["filename"]
+	if name == "" {
+		name = md["name"]
+	}
+	if name == "" {
+		name = defaultVideoName
+	}
+	// Checksums of parts are not known in advance.
+	resp, err := svc.tus.videoAPI.CreateVideo(rpcCtx, &pb.CreateVideoRequest{
+		Size:     size,
+		Name:     name,
+		PartSize: svc.tus.partSize,
+		Parts:    makeTusParts(size, svc.tus.partSize),
+	})
+	if err != nil {
+		svc.tusAPIError(ctx, "cannot create video", err)
+		return
+	}
+	// Upload url ends with upload session id.
+	sessID := resp.UploadUrl[strings.LastIndexByte(resp.UploadUrl, '/')+1:]
+	svc.logger.Debug("tus upload created",
+		zap.String("vid", resp.Id),
+		zap.String("session", sessID),
+		zap.Uint64("size", size))
+	ctx.Response.Header.Set(fasthttp.HeaderLocation, string(svc.tus.uriPrefix)+"/"+sessID)
+	ctx.SetStatusCode(fasthttp.StatusCreated)
+}
+
+func (svc *Service) tusHead(ctx *fasthttp.RequestCtx, sessID []byte) {
+	sess, ok := svc.getTusSession(ctx, sessID)
+	if !ok {
+		return
+	}
+	st, err := svc.getTusState(ctx, sessID, sess)
+	if err != nil {
+		svc.logger.Error("cannot get tus upload state", zap.Error(err))
+		tusError(ctx, internalError, fasthttp.StatusInternalServerError)
+		return
+	}
+	ctx.Response.Header.Set(headerUploadOffset, strconv.FormatUint(st.offset, 10))
+	ctx.Response.Header.Set(headerUploadLength, strconv.FormatUint(sess.Size, 10))
+	ctx.Response.Header.Set(fasthttp.HeaderCacheControl, "no-store")
+	ctx.SetStatusCode(fasthttp.StatusOK)
+}
+
+// tusPatch appends request body to upload. Request is applied as a whole:
+// if it could not be completed or checksum does not match,
+// everything that was written during request is removed.
+// Upload is locked during request, concurrent requests are rejected with 423.
+func (svc *Service) tusPatch(ctx *fasthttp.RequestCtx, sessID []byte) {
+	if !bytes.Equal(ctx.Request.Header.ContentType(), contentTypeOffsetOctetStream) {
+		tusError(ctx, "wrong content type", fasthttp.StatusUnsupportedMediaType)
+		return
+	}
+	offset, err := strconv.ParseUint(string(ctx.Request.Header.Peek(headerUploadOffset)), 10, 64)
+	if err != nil {
+		tusError(ctx, "invalid upload offset", fasthttp.StatusBadRequest)
+		return
+	}
+	length := ctx.Request.Header.ContentLength()
+	if length < 0 {
+		tusError(ctx, "wrong or missing content length", fasthttp.StatusBadRequest)
+		return
+	}
+	var (
+		checksumH        hash.Hash
+		expectedChecksum []byte
+	)
+	if checksumHeader := ctx.Request.Header.Peek(headerUploadChecksum); len(checksumHeader) > 0 {
+		if checksumH, expectedChecksum, err = parseUploadChecksum(checksumHeader); err != nil {
+			tusError(ctx, err.Error(), fasthttp.StatusBadRequest)
+			return
+		}
+	}
+
+	sess, ok := svc.getTusSession(ctx, sessID)
+	if !ok {
+		return
+	}
+	unlock, err := svc.tus.locker.Lock(ctx, string(sessID), svc.tus.lockTTL)
+	if err != nil {
+		if errors.Is(err, sessionStore.ErrLocked) {
+			tusError(ctx, "upload is locked by another request", fasthttp.StatusLocked)
+			return
+		}
+		svc.logger.Error("cannot lock tus upload", zap.Error(err))
+		tusError(ctx, internalError, fasthttp.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		// lock is released even if server is shutting down
+		if errU := unlock(context.WithoutCancel(ctx)); errU != nil {
+			svc.logger.Error("cannot unlock tus upload", zap.Error(errU), zap.ByteString("session", sessID))
+		}
+	}()
+	st, err := svc.getTusState(ctx, sessID, sess)
+	if err != nil {
+		svc.logger.Error("cannot get tus upload state", zap.Error(err))
+		tusError(ctx, internalError, fasthttp.StatusInternalServerError)
+		return
+	}
+	if offset != st.offset {
+		tusError(ctx, "offset does not match", fasthttp.StatusConflict)
+		return
+	}
+	if offset+uint64(length) > sess.Size {
+		tusError(ctx, "upload length exceeded", fasthttp.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// check limits
+	if !svc.limiter.fits(uint64(length)) {
+		tusError(ctx, tooLargeError, fasthttp.StatusRequestEntityTooLarge)
+		return
+	}
+	if !svc.limiter.acquire(uint64(length)) {
+		tusError(ctx, busyError, fasthttp.StatusServiceUnavailable)
+		ctx.Response.Header.Set(fasthttp.HeaderRetryAfter, retryAfterSeconds)
+		return
+	}
+	defer svc.limiter.release(uint64(length))
+	if wait := svc.throttled(ctx, sess, uint64(length)); wait > 0 {
+		tusError(ctx, throttledError, fasthttp.StatusTooManyRequests)
+		ctx.Response.Header.Set(fasthttp.HeaderRetryAfter, retryAfter(wait))
+		return
+	}
+
+	body := ctx.RequestBodyStream()
+	if body == nil {
+		// body streaming is disabled in server
+		body = bytes.NewReader(ctx.Request.Body())
+	}
+	body = io.LimitReader(body, int64(length))
+	if checksumH != nil {
+		body = io.TeeReader(body, checksumH)
+	}
+
+	w := &tusWriter{svc: svc, sessID: sessID, sess: sess}
+	newOffset, err := w.write(ctx, body, offset, uint64(length), st.partialSize)
+	switch {
+	case err != nil:
+		svc.logger.Error("cannot write tus upload", zap.Error(err), zap.ByteString("session", sessID))
+		w.rollback(ctx)
+		tusError(ctx, internalError, fasthttp.StatusInternalServerError)
+		return
+	case checksumH != nil && !bytes.Equal(checksumH.Sum(nil), expectedChecksum),
+		w.checksumMismatch:
+		w.rollback(ctx)
+		tusError(ctx, checksumError, statusChecksumMismatch)
+		return
+	}
+
+	// Previous unfinished part is no longer needed.
+	if newOffset != offset && st.partial != "" {
+		st.stale = append(st.stale, st.partial)
+	}
+	svc.deleteTusObjects(ctx, st.stale)
+	for _, p := range w.completed {
+		go svc.notificator.Send(&event.Event{
+			PartInfo: &event.PartInfo{
+				VideoID:  sess.VideoID,
+				Checksum: p.checksum,
+				Num:      p.num,
+			},
+			Kind: event.KindVideoPartUploaded,
+		})
+	}
+	ctx.Response.Header.Set(headerUploadOffset, strconv.FormatUint(newOffset, 10))
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+// tusTerminate deletes video in videoapi, its media is purged by processor.
+func (svc *Service) tusTerminate(ctx *fasthttp.RequestCtx, sessID []byte) {
+	sess, ok := svc.getTusSession(ctx, sessID)
+	if !ok {
+		return
+	}
+	rpcCtx, ok := authContext(ctx)
+	if !ok {
+		tusError(ctx, "unauthorized", fasthttp.StatusUnauthorized)
+		return
+	}
+	if _, err := svc.tus.videoAPI.DeleteVideo(rpcCtx, &pb.DeleteRequest{Id: sess.VideoID}); err != nil {
+		svc.tusAPIError(ctx, "cannot delete video", err)
+		return
+	}
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+func (svc *Service) getTusSession(ctx *fasthttp.RequestCtx, sessID []byte) (*session.Session, bool) {
+	sess, err := svc.sessS.Get(ctx, string(sessID))
+	if err != nil {
+		if errors.Is(err, sessionStore.ErrNotFound) {
+			tusError(ctx, notFoundError, fasthttp.StatusNotFound)
+			return nil, false
+		}
+		svc.logger.Error("cannot get session", zap.Error(err))
+		tusError(ctx, internalError, fasthttp.StatusInternalServerError)
+		return nil, false
+	}
+	if sess.Size == 0 || sess.PartSize == 0 {
+		// session was created without size
+		tusError(ctx, notFoundError, fasthttp.StatusNotFound)
+		return nil, false
+	}
+	return sess, true
+}
+
+func (svc *Service) tusAPIError(ctx *fasthttp.RequestCtx, msg string, err error) {
+	switch status.Code(err) {
+	case codes.Unauthenticated:
+		tusError(ctx, "unauthorized", fasthttp.StatusUnauthorized)
+	case codes.InvalidArgument:
+		tusError(ctx, status.Convert(err).Message(), fasthttp.StatusBadRequest)
+	case codes.NotFound:
+		tusError(ctx, notFoundError, fasthttp.StatusNotFound)
+	default:
+		svc.logger.Error(msg, zap.Error(err))
+		tusError(ctx, internalError, fasthttp.StatusInternalServerError)
+	}
+}
+
+func (svc *Service) deleteTusObjects(ctx context.Context, names []string) {
+	for _, name := range names {
+		if err := svc.tus.mediaS.Delete(ctx, name); err != nil {
+			svc.logger.Error("cannot delete tus upload object",
+				zap.String("name", name),
+				zap.Error(err))
+		}
+	}
+}
+
+// tusState is a state of tus upload determined from objects in upload location.
+type tusState struct {
+	// partial is a name of unfinished part
+	partial string
+	// stale are names of obsolete unfinished parts
+	stale       []string
+	offset      uint64
+	partialSize uint64
+}
+
+func (svc *Service) getTusState(ctx context.Context, sessID []byte, sess *session.Session) (*tusState, error) {
+	names, err := svc.tus.mediaS.List(ctx, svc.getUploadLocation(sessID))
+	if err != nil {
+		return nil, fmt.Errorf("cannot list upload objects: %w", err)
+	}
+	type partial struct {
+		num  uint64
+		size uint64
+	}
+	var (
+		complete = make(map[uint64]bool)
+		partials []partial
+	)
+	for _, name := range names {
+		base := name[strings.LastIndexByte(name, '/')+1:]
+		if numStr, sizeStr, ok := strings.Cut(base, partialSuffix); ok {
+			num, errN := strconv.ParseUint(numStr, 10, 64)
+			size, errS := strconv.ParseUint(sizeStr, 10, 64)
+			if errN == nil && errS == nil {
+				partials = append(partials, partial{num: num, size: size})
+			}
+			continue
+		}
+		if num, errN := strconv.ParseUint(base, 10, 64); errN == nil {
+			complete[num] = true
+		}
+	}
+
+	// Parts are uploaded sequentially, so offset is
+	// determined by first missing part.
+	var num uint64
+	for complete[num] {
+		num++
+	}
+	st := &tusState{offset: min(num*sess.PartSize, sess.Size)}
+	for _, p := range partials {
+		name := svc.getPartialName(sessID, p.num, p.size)
+		switch {
+		case p.num != num || p.size >= sess.PartSize:
+			st.stale = append(st.stale, name)
+		case p.size > st.partialSize:
+			// Several unfinished parts could be left if upload was interrupted.
+			if st.partial != "" {
+				st.stale = append(st.stale, st.partial)
+			}
+			st.partial = name
+			st.partialSize = p.size
+		default:
+			st.stale = append(st.stale, name)
+		}
+	}
+	st.offset += st.partialSize
+	return st, nil
+}
+
+type completedPart struct {
+	checksum string
+	num      uint
+}
+
+// tusWriter writes uploaded data to parts and tracks everything that was written.
+type tusWriter struct {
+	svc       *Service
+	sess      *session.Session
+	sessID    []byte
+	written   []string
+	completed []completedPart
+
+	checksumMismatch bool
+}
+
+// write writes data to parts starting from specified offset. Unfinished part
+// of specified size is read from media store and prepended to data.
+func (w *tusWriter) write(ctx context.Context, r io.Reader, offset, length, partialSize uint64) (uint64, error) {
+	for length > 0 {
+		var (
+			num      = offset / w.sess.PartSize
+			partLen  = min(w.sess.PartSize, w.sess.Size-num*w.sess.PartSize)
+			pOffset  = offset % w.sess.PartSize
+			n        = min(partLen-pOffset, length)
+			data     = io.LimitReader(r, int64(n))
+			closeFun = func() {}
+		)
+		if pOffset > 0 {
+			if pOffset != partialSize {
+				return offset, errors.New("unfinished part is missing")
+			}
+			pr, _, err := w.svc.tus.mediaS.Get(ctx, w.svc.getPartialName(w.sessID, num, pOffset))
+			if err != nil {
+				return offset, fmt.Errorf("cannot get unfinished part: %w", err)
+			}
+			closeFun = func() { _ = pr.Close() }
+			data = io.MultiReader(io.LimitReader(pr, int64(pOffset)), data)
+		}
+		err := w.writePart(ctx, data, num, pOffset+n, partLen)
+		closeFun()
+		if err != nil {
+			return offset, err
+		}
+		offset += n
+		length -= n
+	}
+	return offset, nil
+}
+
+func (w *tusWriter) writePart(ctx context.Context, r io.Reader, num, size, partLen uint64) error {
+	if size < partLen {
+		name := w.svc.getPartialName(w.sessID, num, size)
+		if err := w.svc.mediaS.Put(ctx, name, r, int64(size)); err != nil {
+			return fmt.Errorf("cannot store unfinished part: %w", err)
+		}
+		w.written = append(w.written, name)
+		return nil
+	}
+	name := w.svc.getUploadArtifactName(w.sessID, []byte(strconv.FormatUint(num, 10)))
+	shaW := sha256.New()
+	if err := w.svc.mediaS.Put(ctx, name, io.TeeReader(r, shaW), int64(size)); err != nil {
+		return fmt.Errorf("cannot store part: %w", err)
+	}
+	w.written = append(w.written, name)
+	checksum := base64.StdEncoding.EncodeToString(shaW.Sum(nil))
+	if expected := expectedChecksum(w.sess.PartChecksums, uint(num)); expected != "" && expected != checksum {
+		w.checksumMismatch = true
+	}
+	w.completed = append(w.completed, completedPart{num: uint(num), checksum: checksum})
+	return nil
+}
+
+func (w *tusWriter) rollback(ctx context.Context) {
+	w.svc.deleteTusObjects(ctx, w.written)
+}
+
+func (svc *Service) getUploadLocation(sessID []byte) string {
+	return string(svc.pathPrefix) + string(sessID) + "/"
+}
+
+func (svc *Service) getPartialName(sessID []byte, num, size uint64) string {
+	return svc.getUploadLocation(sessID) + strconv.FormatUint(num, 10) + partialSuffix + strconv.FormatUint(size, 10)
+}
+
+func makeTusParts(size, partSize uint64) []*pb.VideoPart {
+	parts := make([]*pb.VideoPart, 0, (size+partSize-1)/partSize)
+	for offset := uint64(0); offset < size; offset += partSize {
+		parts = append(parts, &pb.VideoPart{
+			Num:  uint32(offset / partSize),
+			Size: min(partSize, size-offset),
+		})
+	}
+	return parts
+}
+
+// authContext makes outgoing grpc context with user token
+// taken either from Authorization header or from session cookie.
+func authContext(ctx *fasthttp.RequestCtx) (context.Context, bool) {
+	token := ctx.Request.Header.Peek(fasthttp.HeaderAuthorization)
+	if len(token) > len(bearerPrefix) && bytes.EqualFold(token[:len(bearerPrefix)], bearerPrefix) {
+		token = token[len(bearerPrefix):]
+	} else {
+		token = ctx.Request.Header.Cookie(authCookieName)
+	}
+	if len(token) == 0 {
+		return nil, false
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "bearer "+string(token)), true
+}
+
+// parseTusMetadata parses Upload-Metadata header. Invalid pairs are skipped.
+func parseTusMetadata(b []byte) map[string]string {
+	md := make(map[string]string)
+	for _, pair := range strings.Split(string(b), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			continue
+		}
+		md[key] = string(decoded)
+	}
+	return md
+}
+
+// parseUploadChecksum parses Upload-Checksum header and returns
+// hash of specified algorithm and expected checksum.
+func parseUploadChecksum(b []byte) (hash.Hash, []byte, error) {
+	alg, value, ok := strings.Cut(string(b), " ")
+	if !ok {
+		return nil, nil, errors.New("invalid checksum")
+	}
+	expected, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, nil, errors.New("invalid checksum")
+	}
+	switch alg {
+	case "sha1":
+		return sha1.New(), expected, nil //nolint:gosec // sha1 is required by tus checksum extension
+	case "sha256":
+		return sha256.New(), expected, nil
+	}
+	return nil, nil, errUnsupportedChecksum
+}
+
+// tusError responds with error, tus version header must be set
+// after error since ctx.Error resets response.
+func tusError(ctx *fasthttp.RequestCtx, msg string, code int) {
+	ctx.Error(msg, code)
+	ctx.Response.Header.Set(headerTusResumable, tusVersion)
+}
@@ -0,0 +1,319 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/grpc/userside/pb"
+	"github.com/adwski/vidi/internal/event/notificator"
+	"github.com/adwski/vidi/internal/session"
+	sessionStore "github.com/adwski/vidi/internal/session/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type memStore struct {
+	mx      sync.Mutex
+	objects map[string][]byte
+}
+
+type fakeLocker struct{}
+
+func (fakeLocker) Lock(_ context.Context, _ string, _ time.Duration) (func(context.Context) error, error) {
+	return func(context.Context) error { return nil }, nil
+}
+
+type readSeekNopCloser struct {
+	*bytes.Reader
+}
+
+func (readSeekNopCloser) Close() error { return nil }
+
+func (m *memStore) Put(_ context.Context, name string, r io.Reader, size int64) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if int64(len(b)) != size {
+		return errors.New("size mismatch")
+	}
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	m.objects[name] = b
+	return nil
+}
+
+func (m *memStore) Get(_ context.Context, name string) (io.ReadSeekCloser, int64, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	b, ok := m.objects[name]
+	if !ok {
+		return nil, 0, fs.ErrNotExist
+	}
+	return readSeekNopCloser{bytes.NewReader(b)}, int64(len(b)), nil
+}
+
+func (m *memStore) Delete(_ context.Context, name string) error {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	delete(m.objects, name)
+	return nil
+}
+
+func (m *memStore) List(_ context.Context, prefix string) ([]string, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	var names []string
+	for name := range m.objects {
+		if strings.HasPrefix(name, prefix) {
+			// names are returned without leading slash, like file store does
+			names = append(names, strings.TrimPrefix(name, "/"))
+		}
+	}
+	return names, nil
+}
+
+type fakeVideoAPI struct {
+	pb.UsersideapiClient
+	created *pb.CreateVideoRequest
+	deleted string
+	token   string
+}
+
+func (f *fakeVideoAPI) GetQuota(ctx context.Context, _ *pb.GetQuotaRequest, _ ...grpc.CallOption) (*pb.QuotaResponse, error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	f.token = md.Get("authorization")[0]
+	return &pb.QuotaResponse{SizeQuota: 100, VideosQuota: 1}, nil
+}
+
+func (f *fakeVideoAPI) CreateVideo(
+	_ context.Context,
+	req *pb.CreateVideoRequest,
+	_ ...grpc.CallOption,
+) (*pb.VideoResponse, error) {
+	f.created = req
+	return &pb.VideoResponse{Id: "vid", UploadUrl: "http://localhost/upload/sess"}, nil
+}
+
+func (f *fakeVideoAPI) DeleteVideo(
+	_ context.Context,
+	req *pb.DeleteRequest,
+	_ ...grpc.CallOption,
+) (*pb.DeleteVideoResponse, error) {
+	f.deleted = req.Id
+	return &pb.DeleteVideoResponse{}, nil
+}
+
+func newTusRequest(method, uri string, body []byte) *fasthttp.RequestCtx {
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(method)
+	ctx.Request.SetRequestURI(uri)
+	ctx.Request.Header.Set(headerTusResumable, tusVersion)
+	if body != nil {
+		ctx.Request.Header.SetContentType(string(contentTypeOffsetOctetStream))
+		ctx.Request.SetBodyStream(bytes.NewReader(body), len(body))
+	}
+	return ctx
+}
+
+func tusPatch(h fasthttp.RequestHandler, offset int, body []byte, checksum string) *fasthttp.RequestCtx {
+	ctx := newTusRequest(fasthttp.MethodPatch, "/upload/tus/sess", body)
+	ctx.Request.Header.Set(headerUploadOffset, strconv.Itoa(offset))
+	if checksum != "" {
+		ctx.Request.Header.Set(headerUploadChecksum, checksum)
+	}
+	h(ctx)
+	return ctx
+}
+
+func TestService_handleTus(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	n, err := notificator.New(&notificator.Config{Logger: logger, VideoAPIURL: "localhost:1"})
+	require.NoError(t, err)
+
+	locker, err := sessionStore.NewMemoryStore(&sessionStore.Config{Logger: logger, Name: "tus-test"})
+	require.NoError(t, err)
+	defer locker.Close()
+
+	var (
+		ms  = &memStore{objects: make(map[string][]byte)}
+		api = &fakeVideoAPI{}
+	)
+	svc, err := New(&Config{
+		Logger:      logger,
+		Notificator: n,
+		SessionStorage: &fakeSessionStore{sess: &session.Session{
+			ID:            "sess",
+			VideoID:       "vid",
+			PartSize:      4,
+			Size:          10,
+			PartChecksums: make([]string, 3),
+		}},
+		MediaStore:    ms,
+		URIPathPrefix: "/upload",
+		PathPrefix:    "/upload",
+		Tus: &TusConfig{
+			VideoAPI:   api,
+			MediaStore: ms,
+			Locker:     locker,
+			PartSize:   4,
+		},
+	})
+	require.NoError(t, err)
+	h := svc.Handler()
+
+	// options
+	ctx := newTusRequest(fasthttp.MethodOptions, "/upload/tus", nil)
+	h(ctx)
+	assert.Equal(t, fasthttp.StatusNoContent, ctx.Response.StatusCode())
+	assert.Equal(t, tusExtensions, string(ctx.Response.Header.Peek(headerTusExtension)))
+
+	// creation
+	ctx = newTusRequest(fasthttp.MethodPost, "/upload/tus", nil)
+	ctx.Request.Header.Set(headerUploadLength, "10")
+	ctx.Request.Header.Set(headerUploadMetadata, "filename "+base64.StdEncoding.EncodeToString([]byte("test.mp4")))
+	ctx.Request.Header.SetCookie(authCookieName, "token")
+	h(ctx)
+	require.Equal(t, fasthttp.StatusCreated, ctx.Response.StatusCode())
+	assert.Equal(t, "/upload/tus/sess", string(ctx.Response.Header.Peek(fasthttp.HeaderLocation)))
+	assert.Equal(t, "bearer token", api.token)
+	require.NotNil(t, api.created)
+	assert.Equal(t, "test.mp4", api.created.Name)
+	assert.Equal(t, uint64(4), api.created.PartSize)
+	require.Len(t, api.created.Parts, 3)
+	assert.Equal(t, uint64(2), api.created.Parts[2].Size)
+
+	// chunk within first part
+	ctx = tusPatch(h, 0, []byte("012"), "")
+	require.Equal(t, fasthttp.StatusNoContent, ctx.Response.StatusCode())
+	assert.Equal(t, "3", string(ctx.Response.Header.Peek(headerUploadOffset)))
+
+	// wrong offset
+	ctx = tusPatch(h, 0, []byte("0"), "")
+	assert.Equal(t, fasthttp.StatusConflict, ctx.Response.StatusCode())
+
+	// checksum mismatch, nothing is written
+	sum := sha256.Sum256([]byte("other"))
+	ctx = tusPatch(h, 3, []byte("3456"), "sha256 "+base64.StdEncoding.EncodeToString(sum[:]))
+	assert.Equal(t, statusChecksumMismatch, ctx.Response.StatusCode())
+	assert.Len(t, ms.objects, 1)
+
+	// chunk that completes first part and leaves unfinished second part
+	sum = sha256.Sum256([]byte("3456"))
+	ctx = tusPatch(h, 3, []byte("3456"), "sha256 "+base64.StdEncoding.EncodeToString(sum[:]))
+	require.Equal(t, fasthttp.StatusNoContent, ctx.Response.StatusCode())
+	assert.Equal(t, "7", string(ctx.Response.Header.Peek(headerUploadOffset)))
+
+	// offset is restored from store
+	ctx = newTusRequest(fasthttp.MethodHead, "/upload/tus/sess", nil)
+	h(ctx)
+	require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+	assert.Equal(t, "7", string(ctx.Response.Header.Peek(headerUploadOffset)))
+	assert.Equal(t, "10", string(ctx.Response.Header.Peek(headerUploadLength)))
+
+	// concurrent request is rejected while upload is locked
+	unlock, err := locker.Lock(context.Background(), "sess", time.Minute)
+	require.NoError(t, err)
+	ctx = tusPatch(h, 7, []byte("789"), "")
+	assert.Equal(t, fasthttp.StatusLocked, ctx.Response.StatusCode())
+	require.NoError(t, unlock(context.Background()))
+
+	// data beyond upload length
+	ctx = tusPatch(h, 7, []byte("7890"), "")
+	assert.Equal(t, fasthttp.StatusRequestEntityTooLarge, ctx.Response.StatusCode())
+
+	ctx = tusPatch(h, 7, []byte("789"), "")
+	require.Equal(t, fasthttp.StatusNoContent, ctx.Response.StatusCode())
+	assert.Equal(t, "10", string(ctx.Response.Header.Peek(headerUploadOffset)))
+
+	// parts are stored as regular parts
+	assert.Equal(t, map[string][]byte{
+		"/upload/sess/0": []byte("0123"),
+		"/upload/sess/1": []byte("4567"),
+		"/upload/sess/2": []byte("89"),
+	}, ms.objects)
+
+	// termination
+	ctx = newTusRequest(fasthttp.MethodDelete, "/upload/tus/sess", nil)
+	ctx.Request.Header.Set(fasthttp.HeaderAuthorization, "Bearer token")
+	h(ctx)
+	assert.Equal(t, fasthttp.StatusNoContent, ctx.Response.StatusCode())
+	assert.Equal(t, "vid", api.deleted)
+}
+
+func TestService_handleTusErrors(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	ms := &memStore{objects: make(map[string][]byte)}
+	svc, err := New(&Config{
+		Logger:         logger,
+		SessionStorage: &fakeSessionStore{sess: &session.Session{}},
+		MediaStore:     ms,
+		URIPathPrefix:  "/upload",
+		PathPrefix:     "/upload",
+		Tus:            &TusConfig{VideoAPI: &fakeVideoAPI{}, MediaStore: ms, Locker: fakeLocker{}},
+	})
+	require.NoError(t, err)
+	h := svc.Handler()
+
+	ctx := newTusRequest(fasthttp.MethodPost, "/upload/tus", nil)
+	ctx.Request.Header.Del(headerTusResumable)
+	h(ctx)
+	assert.Equal(t, fasthttp.StatusPreconditionFailed, ctx.Response.StatusCode())
+
+	ctx = newTusRequest(fasthttp.MethodPost, "/upload/tus", nil)
+	ctx.Request.Header.Set(headerUploadLength, "10")
+	h(ctx)
+	assert.Equal(t, fasthttp.StatusUnauthorized, ctx.Response.StatusCode())
+	assert.Equal(t, tusVersion, string(ctx.Response.Header.Peek(headerTusResumable)))
+
+	ctx = newTusRequest(fasthttp.MethodPost, "/upload/tus", nil)
+	ctx.Request.Header.Set(headerUploadLength, "1000")
+	ctx.Request.Header.SetCookie(authCookieName, "token")
+	h(ctx)
+	assert.Equal(t, fasthttp.StatusRequestEntityTooLarge, ctx.Response.StatusCode())
+
+	// session without size is not a tus upload
+	ctx = newTusRequest(fasthttp.MethodHead, "/upload/tus/sess", nil)
+	h(ctx)
+	assert.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+
+	ctx = newTusRequest(fasthttp.MethodHead, "/upload/tus/sess/0", nil)
+	h(ctx)
+	assert.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+}
+
+func Test_parseTusMetadata(t *testing.T) {
+	md := parseTusMetadata([]byte("filename dGVzdC5tcDQ=, is_confidential,name !!!"))
+	assert.Equal(t, map[string]string{"filename": "test.mp4", "is_confidential": ""}, md)
+}
+
+func Test_parseUploadChecksum(t *testing.T) {
+	_, _, err := parseUploadChecksum([]byte("md5 dGVzdA=="))
+	require.ErrorIs(t, err, errUnsupportedChecksum)
+
+	_, _, err = parseUploadChecksum([]byte("sha1"))
+	require.Error(t, err)
+
+	h, expected, err := parseUploadChecksum([]byte("sha1 dGVzdA=="))
+	require.NoError(t, err)
+	assert.NotNil(t, h)
+	assert.Equal(t, []byte("test"), expected)
+}
@@ -2,9 +2,13 @@ package mp4
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
+	mp4ff "github.com/Eyevinn/mp4ff/mp4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -23,3 +27,82 @@ func TestDump(t *testing.T) {
 	assert.Contains(t, str, "TrackID: 2, type: soun, sampleCount: [472]")
 	assert.Contains(t, str, "Codecs are supported!")
 }
+
+func TestNewReport(t *testing.T) {
+	r := NewReport("../../testfiles/test_seq_h264_high.mp4", time.Second)
+
+	require.False(t, r.HasBlockingProblems())
+	assert.Equal(t, uint32(15360), r.Timescale)
+	assert.Equal(t, uint64(10000), r.DurationMs)
+	assert.False(t, r.Fragmented)
+
+	require.NotNil(t, r.GOP)
+	assert.Equal(t, 10, r.GOP.Count)
+	assert.Equal(t, uint32(30), r.GOP.MinFrames)
+	assert.Equal(t, uint32(30), r.GOP.MaxFrames)
+	assert.Equal(t, uint64(1000), r.GOP.MaxDurationMs)
+
+	require.NotNil(t, r.Segmentation)
+	assert.False(t, r.Segmentation.Adjusted)
+	assert.Equal(t, int64(1000), r.Segmentation.EffectiveDurationMs)
+	require.Len(t, r.Segmentation.Points, 10)
+	assert.Equal(t, SegmentPoint{Sample: 31, DecodeTime: 15360, PresentationTime: 15360}, r.Segmentation.Points[1])
+
+	require.Len(t, r.Tracks, 3)
+	assert.Equal(t, "avc1.64001f", r.Tracks[0].Codec)
+	assert.Equal(t, 10, r.Tracks[0].Segments)
+	assert.Equal(t, "mp4a.40.2", r.Tracks[1].Codec)
+	assert.False(t, r.Tracks[2].Supported)
+
+	require.Len(t, r.Problems, 1)
+	assert.False(t, r.Problems[0].Blocking)
+	assert.Contains(t, r.Problems[0].Message, "tmcd")
+}
+
+func TestNewReport_InvalidFile(t *testing.T) {
+	r := NewReport("not-exists.mp4", time.Second)
+
+	require.True(t, r.HasBlockingProblems())
+	assert.Nil(t, r.Segmentation)
+	assert.Empty(t, r.Tracks)
+}
+
+func TestNewReport_MissingSampleBoxes(t *testing.T) {
+	tests := []struct {
+		box     string
+		problem string
+	}{
+		{box: "stsz", problem: "video track 1 has no stsz box"},
+		{box: "stsc", problem: "video track 1 has no stsc box"},
+		// decoder of mp4ff cannot handle it
+		{box: "stts", problem: "malformed mp4 file"},
+	}
+	for _, tt := range tests {
+		box := tt.box
+		t.Run(box, func(t *testing.T) {
+			mF, err := mp4ff.ReadMP4File("../../testfiles/test_seq_h264_high.mp4")
+			require.NoError(t, err)
+			stbl := mF.Moov.Traks[0].Mdia.Minf.Stbl
+			stbl.Children = slices.DeleteFunc(stbl.Children, func(b mp4ff.Box) bool {
+				return b.Type() == box
+			})
+
+			path := filepath.Join(t.TempDir(), "truncated.mp4")
+			f, err := os.Create(path)
+			require.NoError(t, err)
+			require.NoError(t, mF.Encode(f))
+			require.NoError(t, f.Close())
+
+			r := NewReport(path, time.Second)
+			require.True(t, r.HasBlockingProblems())
+			assert.Nil(t, r.GOP)
+			assert.Nil(t, r.Segmentation)
+			assert.Contains(t, r.Problems[0].Message, tt.problem)
+			for _, tr := range r.Tracks {
+				if tr.ID == 1 {
+					assert.False(t, tr.Supported)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package meta
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidInterval = errors.New("invalid clip interval")
+	ErrClipOfClip      = errors.New("cannot create clip from another clip")
+	ErrLive            = errors.New("cannot create clip from live stream")
+)
+
+// ClipInfo describes clip that references media of another video.
+// Start and End are requested clip boundaries relative to source video.
+type ClipInfo struct {
+	SourceID       string
+	SourceLocation string
+	Start          time.Duration
+	End            time.Duration
+}
+
+// MakeClip creates playback meta that references range of source segments
+// covering [start, end) interval. No media is copied: clip tracks use the same
+// segments with adjusted start and end numbers. Interval start is snapped to
+// the beginning of segment that contains it, and presentationTimeOffset is set
+// to this segment start, so playback starts at segment boundary closest to requested start.
+func (mt *Meta) MakeClip(start, end time.Duration) (*Meta, error) {
+	if mt.Clip != nil {
+		return nil, ErrClipOfClip
+	}
+	if mt.Live != nil {
+		return nil, ErrLive
+	}
+	if end > mt.Duration {
+		end = mt.Duration
+	}
+	if start < 0 || start >= end || len(mt.Tracks) == 0 {
+		return nil, ErrInvalidInterval
+	}
+	var (
+		clipStart time.Duration
+		tracks    = make([]Track, 0, len(mt.Tracks))
+	)
+	for i, track := range mt.Tracks {
+		seg := track.Segment
+		if seg == nil || seg.Duration == 0 || seg.Timescale == 0 {
+			return nil, ErrInvalidInterval
+		}
+		var (
+			first     = uint64(start.Seconds()*float64(seg.Timescale)) / seg.Duration
+			last      = (uint64(end.Seconds()*float64(seg.Timescale)) + seg.Duration - 1) / seg.Duration
+			clipTrack = track
+		)
+		clipTrack.Segment = &SegmentConfig{
+			Init:                   seg.Init,
+			StartNumber:            seg.StartNumber + uint(first),
+			EndNumber:              seg.StartNumber + uint(last) - 1,
+			Duration:               seg.Duration,
+			Timescale:              seg.Timescale,
+			PresentationTimeOffset: first * seg.Duration,
+		}
+		if i == 0 {
+			clipStart = time.Duration(first*seg.Duration) * time.Second / time.Duration(seg.Timescale)
+		}
+		tracks = append(tracks, clipTrack)
+	}
+	return &Meta{
+		Clip: &ClipInfo{
+			Start: start,
+			End:   end,
+		},
+		Tracks:   tracks,
+		Duration: end - clipStart,
+	}, nil
+}
@@ -0,0 +1,92 @@
+package meta
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMeta() *Meta {
+	return &Meta{
+		Tracks: []Track{
+			{
+				Name:     "vide1",
+				MimeType: "video/mp4",
+				Codec:    &Codec{Profile: "avc1.64001f"},
+				Segment: &SegmentConfig{
+					Init:        "init.mp4",
+					StartNumber: 1,
+					Duration:    45000,
+					Timescale:   15000,
+				},
+			},
+			{
+				Name:     "soun1",
+				MimeType: "audio/mp4",
+				Codec:    &Codec{Profile: "mp4a.40.2", SampleRate: 48000},
+				Segment: &SegmentConfig{
+					Init:        "init.mp4",
+					StartNumber: 1,
+					Duration:    144000,
+					Timescale:   48000,
+				},
+			},
+		},
+		Duration: 30 * time.Second,
+	}
+}
+
+func TestMeta_MakeClip(t *testing.T) {
+	clip, err := testMeta().MakeClip(10*time.Second, 17*time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, clip.Clip)
+	assert.Equal(t, 10*time.Second, clip.Clip.Start)
+	assert.Equal(t, 17*time.Second, clip.Clip.End)
+
+	// 3s segments: clip starts with segment #4 (9s-12s) and ends with segment #6 (15s-18s)
+	assert.Equal(t, 8*time.Second, clip.Duration)
+	require.Len(t, clip.Tracks, 2)
+	assert.Equal(t, uint(4), clip.Tracks[0].Segment.StartNumber)
+	assert.Equal(t, uint(6), clip.Tracks[0].Segment.EndNumber)
+	assert.Equal(t, uint64(9*15000), clip.Tracks[0].Segment.PresentationTimeOffset)
+	assert.Equal(t, uint(4), clip.Tracks[1].Segment.StartNumber)
+	assert.Equal(t, uint(6), clip.Tracks[1].Segment.EndNumber)
+	assert.Equal(t, uint64(9*48000), clip.Tracks[1].Segment.PresentationTimeOffset)
+
+	b, err := clip.StaticMPD("")
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `<Period id="p0" start="PT0S">`)
+	assert.Contains(t, string(b), `endNumber="6"`)
+	assert.Contains(t, string(b), `presentationTimeOffset="135000"`)
+}
+
+func TestMeta_MakeClipEndIsClamped(t *testing.T) {
+	clip, err := testMeta().MakeClip(28*time.Second, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Second, clip.Duration)
+	assert.Equal(t, uint(10), clip.Tracks[0].Segment.StartNumber)
+	assert.Equal(t, uint(10), clip.Tracks[0].Segment.EndNumber)
+}
+
+func TestMeta_MakeClipErrors(t *testing.T) {
+	_, err := testMeta().MakeClip(10*time.Second, 10*time.Second)
+	require.ErrorIs(t, err, ErrInvalidInterval)
+
+	_, err = testMeta().MakeClip(-time.Second, 10*time.Second)
+	require.ErrorIs(t, err, ErrInvalidInterval)
+
+	_, err = testMeta().MakeClip(time.Minute, 2*time.Minute)
+	require.ErrorIs(t, err, ErrInvalidInterval)
+
+	clip, err := testMeta().MakeClip(time.Second, 2*time.Second)
+	require.NoError(t, err)
+	_, err = clip.MakeClip(0, time.Second)
+	require.ErrorIs(t, err, ErrClipOfClip)
+
+	live := testMeta()
+	live.Live = &LiveInfo{}
+	_, err = live.MakeClip(0, time.Second)
+	require.ErrorIs(t, err, ErrLive)
+}
@@ -0,0 +1,63 @@
+package meta
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+const mimeTypeAudio = "audio/mp4"
+
+// ErrNoTracks is returned when filter excludes every track of presentation.
+var ErrNoTracks = errors.New("no tracks match filter")
+
+// Filter selects tracks that are included in MPD. Zero Filter selects every track.
+type Filter struct {
+	// Languages is a subset of audio languages. Tracks with undetermined
+	// language and video tracks are always selected.
+	Languages []string
+	// MaxBandwidth excludes tracks with higher bandwidth.
+	// Tracks with unknown bandwidth are always selected.
+	MaxBandwidth uint32
+	// AudioOnly excludes video tracks.
+	AudioOnly bool
+}
+
+// IsZero checks if filter selects every track.
+func (f *Filter) IsZero() bool {
+	return f == nil || (len(f.Languages) == 0 && f.MaxBandwidth == 0 && !f.AudioOnly)
+}
+
+// Filter returns copy of Meta with tracks selected by filter.
+// ErrNoTracks is returned if no tracks are selected.
+func (mt *Meta) Filter(f *Filter) (*Meta, error) {
+	if f.IsZero() {
+		return mt, nil
+	}
+	filtered := *mt
+	filtered.Tracks = make([]Track, 0, len(mt.Tracks))
+	for _, track := range mt.Tracks {
+		if f.selects(&track) {
+			filtered.Tracks = append(filtered.Tracks, track)
+		}
+	}
+	if len(filtered.Tracks) == 0 {
+		return nil, ErrNoTracks
+	}
+	return &filtered, nil
+}
+
+func (f *Filter) selects(track *Track) bool {
+	isAudio := track.MimeType == mimeTypeAudio
+	switch {
+	case f.AudioOnly && !isAudio:
+		return false
+	case f.MaxBandwidth != 0 && track.Bandwidth > f.MaxBandwidth:
+		return false
+	case isAudio && track.Language != "" && len(f.Languages) > 0:
+		return slices.ContainsFunc(f.Languages, func(lang string) bool {
+			return strings.EqualFold(lang, track.Language)
+		})
+	}
+	return true
+}
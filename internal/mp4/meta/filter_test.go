@@ -0,0 +1,80 @@
+package meta
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMultiTrackMeta() *Meta {
+	mt := testMeta()
+	mt.Tracks[0].Bandwidth = 4000000
+	hd := mt.Tracks[0]
+	hd.Name, hd.Bandwidth = "vide2", 8000000
+	en := mt.Tracks[1]
+	en.Name, en.Language = "soun2", "eng"
+	fr := mt.Tracks[1]
+	fr.Name, fr.Language = "soun3", "fra"
+	mt.Tracks = append(mt.Tracks, hd, en, fr)
+	return mt
+}
+
+func trackNames(mt *Meta) []string {
+	names := make([]string, 0, len(mt.Tracks))
+	for _, track := range mt.Tracks {
+		names = append(names, track.Name)
+	}
+	return names
+}
+
+func TestMeta_Filter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *Filter
+		want   []string
+	}{
+		{
+			name: "no filter",
+			want: []string{"vide1", "soun1", "vide2", "soun2", "soun3"},
+		},
+		{
+			name:   "audio only",
+			filter: &Filter{AudioOnly: true},
+			want:   []string{"soun1", "soun2", "soun3"},
+		},
+		{
+			name:   "max bandwidth",
+			filter: &Filter{MaxBandwidth: 5000000},
+			want:   []string{"vide1", "soun1", "soun2", "soun3"},
+		},
+		{
+			name:   "languages",
+			filter: &Filter{Languages: []string{"FRA"}},
+			want:   []string{"vide1", "soun1", "vide2", "soun3"},
+		},
+		{
+			name:   "combined",
+			filter: &Filter{AudioOnly: true, Languages: []string{"eng", "deu"}},
+			want:   []string{"soun1", "soun2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := testMultiTrackMeta()
+			filtered, err := mt.Filter(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, trackNames(filtered))
+			// source meta is not modified
+			assert.Len(t, mt.Tracks, 5)
+		})
+	}
+
+	_, err := testMeta().Filter(&Filter{MaxBandwidth: 1, AudioOnly: true, Languages: []string{"eng"}})
+	require.NoError(t, err, "audio track with unknown bandwidth and language is selected")
+
+	mt := testMultiTrackMeta()
+	mt.Tracks = mt.Tracks[:1]
+	_, err = mt.Filter(&Filter{AudioOnly: true})
+	require.ErrorIs(t, err, ErrNoTracks)
+}
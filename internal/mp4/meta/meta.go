@@ -15,26 +15,53 @@ var (
 )
 
 // Meta is a generic media file structure.
+// Live is set only for ongoing live streams.
+// Clip is set only for clips of other videos.
+// Processing is set only for media produced by processor.
 type Meta struct {
-	Tracks   []Track
-	Duration time.Duration
+	Live       *LiveInfo
+	Clip       *ClipInfo
+	Processing *ProcessingInfo
+	Tracks     []Track
+	Duration   time.Duration
+}
+
+// ProcessingInfo describes processor version and parameters that were used
+// to produce media, so outdated media could be found and reprocessed.
+type ProcessingInfo struct {
+	Version         string
+	SegmentDuration time.Duration
+}
+
+// LiveInfo holds state of ongoing live stream.
+// Segment n of every track becomes available at AvailabilityStartTime + n * segment duration.
+type LiveInfo struct {
+	AvailabilityStartTime time.Time
+	PublishTime           time.Time
+	TimeShiftBufferDepth  time.Duration
+	SegmentsAvailable     uint
 }
 
 // Track is a media file video or audio track.
+// Language is ISO 639-2 code, it is empty if language is undetermined.
 type Track struct {
 	Codec     *Codec
 	Segment   *SegmentConfig
 	Name      string
 	MimeType  string
+	Language  string
 	Bandwidth uint32
 }
 
 // SegmentConfig holds segment related info of segmented media file.
+// EndNumber and PresentationTimeOffset are set only for clips.
 type SegmentConfig struct {
-	Init        string
-	StartNumber uint
-	Duration    uint64
-	Timescale   uint32
+	Init                   string
+	StartNumber            uint
+	EndNumber              uint
+	Duration               uint64
+	PresentationTimeOffset uint64
+	Timescale              uint32
 }
 
 func (mt *Meta) TextValue() (pgtype.Text, error) {
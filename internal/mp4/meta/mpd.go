@@ -1,13 +1,23 @@
 package meta
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/Eyevinn/dash-mpd/mpd"
 	"github.com/Eyevinn/dash-mpd/xml"
 )
 
+// MPD generates dynamic MPD for ongoing live stream and static MPD otherwise.
+func (mt *Meta) MPD(baseURL string) ([]byte, error) {
+	if mt.Live != nil {
+		return mt.DynamicMPD(baseURL)
+	}
+	return mt.StaticMPD(baseURL)
+}
+
 // StaticMPD generates media presentation description (MPD) corresponding to current state of Meta.
 // Based on https://github.com/Eyevinn/dash-mpd/blob/main/examples/newmpd_test.go
 // Refs: ISO/IEC 23009-1 4.3 DASH data model overview.
@@ -25,6 +35,10 @@ func (mt *Meta) StaticMPD(baseURL string) ([]byte, error) {
 	// Create Period
 	p := mpd.NewPeriod()
 	p.Id = "p0"
+	if mt.Clip != nil {
+		// Clip media timeline starts at presentationTimeOffset of segment templates
+		p.Start = mpd.Ptr(mpd.Duration(0))
+	}
 	m.AppendPeriod(p)
 
 	// Create adaptation sets
@@ -40,16 +54,111 @@ func (mt *Meta) StaticMPD(baseURL string) ([]byte, error) {
 	return out, nil
 }
 
+// MultiPeriodMPD generates static MPD that plays several presentations back to back.
+// Every Meta becomes a separate Period that starts when previous one ends.
+// Period i has relative BaseURL "i/", so segments of every period
+// are requested with period index prefix and can be routed to different locations.
+// Refs: ISO/IEC 23009-1 5.3.2 Period, DASH-IF IOP 4.3 Multi-Period content.
+func MultiPeriodMPD(baseURL string, periods []*Meta) ([]byte, error) {
+	if len(periods) == 0 {
+		return nil, errors.New("no periods")
+	}
+	m := mpd.NewMPD(mpd.STATIC_TYPE)
+	m.Profiles = mpd.PROFILE_ONDEMAND
+	if len(baseURL) > 0 {
+		m.BaseURL = append(m.BaseURL, &mpd.BaseURLType{
+			Value: mpd.AnyURI(baseURL),
+		})
+	}
+	var start time.Duration
+	for i, mt := range periods {
+		if mt.Live != nil {
+			return nil, fmt.Errorf("period %d is live", i)
+		}
+		p := mpd.NewPeriod()
+		p.Id = fmt.Sprintf("p%d", i)
+		p.Start = mpd.Ptr(mpd.Duration(start))
+		p.Duration = mpd.Ptr(mpd.Duration(mt.Duration))
+		p.BaseURLs = append(p.BaseURLs, &mpd.BaseURLType{
+			Value: mpd.AnyURI(fmt.Sprintf("%d/", i)),
+		})
+		for _, track := range mt.Tracks {
+			p.AppendAdaptationSet(track.makeAdaptationSet())
+		}
+		m.AppendPeriod(p)
+		start += mt.Duration
+	}
+	m.MediaPresentationDuration = mpd.Ptr(mpd.Duration(start))
+
+	out, err := xml.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal mpd: %w", err)
+	}
+	return out, nil
+}
+
+// DynamicMPD generates MPD for ongoing live stream.
+// Clients calculate latest segment number using availabilityStartTime and segment duration,
+// and refresh MPD after minimumUpdatePeriod. Only segments within
+// timeShiftBufferDepth are expected to be requested.
+// Refs: ISO/IEC 23009-1 5.3.9.5.3 Media Segment information, DASH-IF IOP 4.3 Live services.
+func (mt *Meta) DynamicMPD(baseURL string) ([]byte, error) {
+	if mt.Live == nil {
+		return nil, errors.New("meta does not have live info")
+	}
+	m := mpd.NewMPD(mpd.DYNAMIC_TYPE)
+	m.Profiles = mpd.PROFILE_LIVE
+	if len(baseURL) > 0 {
+		m.BaseURL = append(m.BaseURL, &mpd.BaseURLType{
+			Value: mpd.AnyURI(baseURL),
+		})
+	}
+	m.AvailabilityStartTime = mpd.ConvertToDateTimeMS(mt.Live.AvailabilityStartTime.UnixMilli())
+	m.PublishTime = mpd.ConvertToDateTimeMS(mt.Live.PublishTime.UnixMilli())
+	if mt.Live.TimeShiftBufferDepth > 0 {
+		m.TimeShiftBufferDepth = mpd.Ptr(mpd.Duration(mt.Live.TimeShiftBufferDepth))
+	}
+	var segmentDuration time.Duration
+	if len(mt.Tracks) > 0 && mt.Tracks[0].Segment.Timescale != 0 {
+		segmentDuration = time.Duration(mt.Tracks[0].Segment.Duration) *
+			time.Second / time.Duration(mt.Tracks[0].Segment.Timescale)
+	}
+	m.MinimumUpdatePeriod = mpd.Ptr(mpd.Duration(segmentDuration))
+	m.MinBufferTime = mpd.Ptr(mpd.Duration(segmentDuration))
+
+	p := mpd.NewPeriod()
+	p.Id = "p0"
+	p.Start = mpd.Ptr(mpd.Duration(0))
+	m.AppendPeriod(p)
+
+	for _, track := range mt.Tracks {
+		p.AppendAdaptationSet(track.makeAdaptationSet())
+	}
+
+	out, err := xml.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal mpd: %w", err)
+	}
+	return out, nil
+}
+
 func (track *Track) makeAdaptationSet() *mpd.AdaptationSetType {
 	// Create AdaptationSet
 	as := mpd.NewAdaptationSet()
 	as.MimeType = track.MimeType
+	as.Lang = track.Language
 
 	// Create SegmentTemplate
 	st := mpd.NewSegmentTemplate()
 	st.StartNumber = mpd.Ptr(uint32(track.Segment.StartNumber))
 	st.Timescale = mpd.Ptr(track.Segment.Timescale)
 	st.Duration = mpd.Ptr(uint32(track.Segment.Duration))
+	if track.Segment.EndNumber != 0 {
+		st.EndNumber = mpd.Ptr(uint32(track.Segment.EndNumber))
+	}
+	if track.Segment.PresentationTimeOffset != 0 {
+		st.PresentationTimeOffset = mpd.Ptr(track.Segment.PresentationTimeOffset)
+	}
 	st.Initialization = fmt.Sprintf("$RepresentationID$_%s", track.Segment.Init)
 	st.Media = "$RepresentationID$_$Number$.m4s"
 	as.SegmentTemplate = st
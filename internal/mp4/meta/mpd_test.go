@@ -0,0 +1,53 @@
+package meta
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiPeriodMPD(t *testing.T) {
+	clip, err := testMeta().MakeClip(10*time.Second, 20*time.Second)
+	require.NoError(t, err)
+
+	b, err := MultiPeriodMPD("http://test/sess/", []*Meta{testMeta(), clip})
+	require.NoError(t, err)
+	out := string(b)
+	assert.Contains(t, out, `type="static"`)
+	assert.Contains(t, out, `mediaPresentationDuration="PT41S"`)
+	assert.Contains(t, out, `<BaseURL>http://test/sess/</BaseURL>`)
+	assert.Contains(t, out, `<Period id="p0" start="PT0S" duration="PT30S">`)
+	assert.Contains(t, out, `<Period id="p1" start="PT30S" duration="PT11S">`)
+	assert.Contains(t, out, `<BaseURL>0/</BaseURL>`)
+	assert.Contains(t, out, `<BaseURL>1/</BaseURL>`)
+	assert.Contains(t, out, `presentationTimeOffset="135000"`)
+}
+
+func TestMultiPeriodMPDErrors(t *testing.T) {
+	_, err := MultiPeriodMPD("", nil)
+	require.Error(t, err)
+
+	live := testMeta()
+	live.Live = &LiveInfo{}
+	_, err = MultiPeriodMPD("", []*Meta{testMeta(), live})
+	require.Error(t, err)
+}
+
+func TestMeta_MPD(t *testing.T) {
+	mt := testMultiTrackMeta()
+	b, err := mt.MPD("http://test/sess/")
+	require.NoError(t, err)
+	out := string(b)
+	assert.Contains(t, out, `type="static"`)
+	assert.Contains(t, out, `<BaseURL>http://test/sess/</BaseURL>`)
+	assert.Contains(t, out, `lang="fra"`)
+
+	mt.Live = &LiveInfo{}
+	b, err = mt.MPD("")
+	require.NoError(t, err)
+	out = string(b)
+	assert.Contains(t, out, `type="dynamic"`)
+	assert.NotContains(t, out, `<BaseURL>`)
+}
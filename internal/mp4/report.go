@@ -0,0 +1,327 @@
+package mp4
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	mp4ff "github.com/Eyevinn/mp4ff/mp4"
+	"github.com/adwski/vidi/internal/mp4/meta"
+	"github.com/adwski/vidi/internal/mp4/segmentation"
+)
+
+const (
+	msecsInSec = 1000
+)
+
+// Report is a machine-readable conformance report of mp4 file.
+// It describes everything that processor will look at during segmentation
+// and lists problems that will prevent (blocking) or affect (non-blocking) processing.
+type Report struct {
+	Segmentation *SegmentationReport `json:"segmentation,omitempty"`
+	GOP          *GOPStats           `json:"gop,omitempty"`
+	File         string              `json:"file"`
+	Brands       []string            `json:"brands,omitempty"`
+	Tracks       []TrackReport       `json:"tracks,omitempty"`
+	Problems     []Problem           `json:"problems"`
+	DurationMs   uint64              `json:"duration_ms"`
+	Timescale    uint32              `json:"timescale"`
+	Fragmented   bool                `json:"fragmented"`
+}
+
+// TrackReport holds track info.
+type TrackReport struct {
+	Type       string `json:"type"`
+	Codec      string `json:"codec,omitempty"`
+	CodecError string `json:"codec_error,omitempty"`
+	ID         uint32 `json:"id"`
+	Samples    uint32 `json:"samples"`
+	Segments   int    `json:"segments"`
+	SampleRate uint16 `json:"sample_rate,omitempty"`
+	Supported  bool   `json:"supported"`
+}
+
+// GOPStats holds group-of-pictures statistics of first video track.
+// GOP boundaries are determined by sync samples.
+type GOPStats struct {
+	Count         int     `json:"count"`
+	MinFrames     uint32  `json:"min_frames"`
+	MaxFrames     uint32  `json:"max_frames"`
+	AvgFrames     float64 `json:"avg_frames"`
+	MinDurationMs uint64  `json:"min_duration_ms"`
+	MaxDurationMs uint64  `json:"max_duration_ms"`
+	AvgDurationMs float64 `json:"avg_duration_ms"`
+}
+
+// SegmentationReport holds segmentation info.
+// EffectiveDurationMs is the segment duration that processor will actually use,
+// it may differ from requested one if GOPs are longer than requested duration.
+type SegmentationReport struct {
+	Points              []SegmentPoint `json:"points"`
+	RequestedDurationMs int64          `json:"requested_duration_ms"`
+	EffectiveDurationMs int64          `json:"effective_duration_ms"`
+	Adjusted            bool           `json:"adjusted"`
+}
+
+// SegmentPoint is a segmentation point. Times are in track timescale.
+type SegmentPoint struct {
+	Sample           uint32 `json:"sample"`
+	DecodeTime       uint64 `json:"decode_time"`
+	PresentationTime uint64 `json:"presentation_time"`
+}
+
+// Problem is an issue found in mp4 file.
+// Blocking problems will make processing fail.
+type Problem struct {
+	Message  string `json:"message"`
+	Blocking bool   `json:"blocking"`
+}
+
+// HasBlockingProblems returns true if file cannot be processed.
+func (r *Report) HasBlockingProblems() bool {
+	for _, p := range r.Problems {
+		if p.Blocking {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Report) blocking(format string, a ...any) {
+	r.Problems = append(r.Problems, Problem{Message: fmt.Sprintf(format, a...), Blocking: true})
+}
+
+func (r *Report) warning(format string, a ...any) {
+	r.Problems = append(r.Problems, Problem{Message: fmt.Sprintf(format, a...)})
+}
+
+// DumpJSON writes conformance report of mp4 file to w in json format.
+func DumpJSON(w io.Writer, path string, segDuration time.Duration) (*Report, error) {
+	r := NewReport(path, segDuration)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return nil, fmt.Errorf("cannot encode report: %w", err)
+	}
+	return r, nil
+}
+
+// NewReport checks mp4 file and creates conformance report.
+// Checks are made using the same segmentation functions that are used by processor.
+// Errors are never returned, instead they are added to report as blocking problems.
+func NewReport(path string, segDuration time.Duration) *Report {
+	segmentDuration := segDuration
+	if segDuration < defaultSegmentDuration {
+		segmentDuration = defaultSegmentDuration
+	}
+	r := &Report{
+		File:     path,
+		Problems: make([]Problem, 0),
+	}
+	mF, err := readMP4File(path)
+	if err != nil {
+		r.blocking("cannot open mp4 file: %v", err)
+		return r
+	}
+	if mF.Ftyp != nil {
+		r.Brands = mF.Ftyp.CompatibleBrands()
+	}
+	r.Fragmented = mF.IsFragmented()
+	if r.Fragmented {
+		r.blocking("fragmented mp4 is not supported")
+	}
+	if mF.Moov == nil {
+		r.blocking("moov box is not present")
+		return r
+	}
+
+	vTrack, timescale, totalDuration, errV := segmentation.GetFirstVideoTrackParams(mF)
+	if errV != nil {
+		r.blocking("cannot get first video track: %v", errV)
+		r.checkTracks(mF, nil)
+		return r
+	}
+	r.Timescale = timescale
+	if timescale != 0 {
+		r.DurationMs = totalDuration * msecsInSec / uint64(timescale)
+	}
+	if vTrack.Mdia.Minf.Stbl.Stss == nil {
+		r.blocking("video track %d has no sync sample table", vTrack.Tkhd.TrackID)
+		r.checkTracks(mF, nil)
+		return r
+	}
+	if missing := missingSampleBoxes(vTrack.Mdia.Minf.Stbl); missing != "" {
+		r.blocking("video track %d has no %s box", vTrack.Tkhd.TrackID, missing)
+		r.checkTracks(mF, nil)
+		return r
+	}
+	r.GOP = makeGOPStats(vTrack, totalDuration)
+
+	updSegDuration, points, errSP := segmentation.MakePoints(vTrack, timescale, segmentDuration)
+	if errSP != nil {
+		r.blocking("cannot make segmentation points: %v", errSP)
+		r.checkTracks(mF, nil)
+		return r
+	}
+	r.Segmentation = &SegmentationReport{
+		RequestedDurationMs: segmentDuration.Milliseconds(),
+		EffectiveDurationMs: segmentDuration.Milliseconds(),
+		Points:              make([]SegmentPoint, 0, len(points)),
+	}
+	if updSegDuration != 0 {
+		r.Segmentation.Adjusted = true
+		r.Segmentation.EffectiveDurationMs = updSegDuration.Milliseconds()
+		r.warning("segment duration was increased from %v to %v to fit longest GOP",
+			segmentDuration, updSegDuration)
+	}
+	for _, p := range points {
+		r.Segmentation.Points = append(r.Segmentation.Points, SegmentPoint{
+			Sample:           p.SampleNum(),
+			DecodeTime:       p.DecodeTime(),
+			PresentationTime: p.PresentationTime(),
+		})
+	}
+	r.checkTracks(mF, points)
+	return r
+}
+
+// readMP4File reads mp4 file. Decoder could panic on malformed boxes,
+// such panics are returned as errors.
+func readMP4File(path string) (mF *mp4ff.File, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("malformed mp4 file: %v", p)
+		}
+	}()
+	mF, err = mp4ff.ReadMP4File(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read mp4 file: %w", err)
+	}
+	return mF, nil
+}
+
+// checkTracks adds tracks info to report. Segments are counted only if points are provided.
+func (r *Report) checkTracks(mF *mp4ff.File, points []segmentation.Point) {
+	var audioTrack bool
+	for _, track := range mF.Moov.Traks {
+		var (
+			stbl = track.Mdia.Minf.Stbl
+			tr   = TrackReport{
+				ID:   track.Tkhd.TrackID,
+				Type: track.Mdia.Hdlr.HandlerType,
+			}
+		)
+		if stbl.Stsz != nil {
+			tr.Samples = stbl.Stsz.SampleNumber
+		}
+		switch tr.Type {
+		case "vide", "soun":
+		default:
+			r.warning("track %d of type %s is not supported and will be skipped", tr.ID, tr.Type)
+			r.Tracks = append(r.Tracks, tr)
+			continue
+		}
+		codec, errC := meta.NewCodecFromSTSD(stbl.Stsd)
+		if errC != nil {
+			tr.CodecError = errC.Error()
+			r.blocking("track %d has unsupported codec: %v", tr.ID, errC)
+		} else {
+			tr.Supported = true
+			tr.Codec = codec.Profile
+			tr.SampleRate = codec.SampleRate
+			if tr.Type == "soun" {
+				audioTrack = true
+			}
+		}
+		if stbl.Stco == nil {
+			tr.Supported = false
+			r.blocking("track %d has no stco box (co64 present: %v)", tr.ID, stbl.Co64 != nil)
+		}
+		if missing := missingSampleBoxes(stbl); missing != "" {
+			tr.Supported = false
+			r.blocking("track %d has no %s box", tr.ID, missing)
+			r.Tracks = append(r.Tracks, tr)
+			continue
+		}
+		if points != nil {
+			intervals, errI := segmentation.MakeIntervals(r.Timescale, points, track)
+			if errI != nil {
+				tr.Supported = false
+				r.blocking("cannot make segment intervals for track %d: %v", tr.ID, errI)
+			}
+			tr.Segments = len(intervals)
+		}
+		r.Tracks = append(r.Tracks, tr)
+	}
+	if !audioTrack {
+		r.warning("file has no supported audio tracks")
+	}
+}
+
+// missingSampleBoxes returns name of the first sample table box that is required
+// for segmentation but is not present, or empty string if all boxes are present.
+func missingSampleBoxes(stbl *mp4ff.StblBox) string {
+	switch {
+	case stbl.Stts == nil:
+		return "stts"
+	case stbl.Stsz == nil:
+		return "stsz"
+	case stbl.Stsc == nil:
+		return "stsc"
+	}
+	return ""
+}
+
+// makeGOPStats calculates GOP statistics using sync samples of video track.
+// Last GOP lasts until the end of track. Track must have stss, stts and stsz boxes,
+// otherwise nil is returned.
+func makeGOPStats(track *mp4ff.TrakBox, totalDuration uint64) *GOPStats {
+	stbl := track.Mdia.Minf.Stbl
+	if stbl.Stss == nil || stbl.Stts == nil || stbl.Stsz == nil {
+		return nil
+	}
+	var (
+		timescale = uint64(track.Mdia.Mdhd.Timescale)
+		syncs     = stbl.Stss.SampleNumber
+		samples   = stbl.Stsz.SampleNumber
+		stats     = &GOPStats{Count: len(syncs)}
+
+		sumFrames, sumDuration uint64
+	)
+	if len(syncs) == 0 || timescale == 0 {
+		return stats
+	}
+	for i, start := range syncs {
+		var (
+			end          = samples + 1
+			endTime      = totalDuration
+			startTime, _ = stbl.Stts.GetDecodeTime(start)
+		)
+		if i+1 < len(syncs) {
+			end = syncs[i+1]
+			endTime, _ = stbl.Stts.GetDecodeTime(end)
+		}
+		var (
+			frames   = end - start
+			duration = (endTime - startTime) * msecsInSec / timescale
+		)
+		if i == 0 || frames < stats.MinFrames {
+			stats.MinFrames = frames
+		}
+		if frames > stats.MaxFrames {
+			stats.MaxFrames = frames
+		}
+		if i == 0 || duration < stats.MinDurationMs {
+			stats.MinDurationMs = duration
+		}
+		if duration > stats.MaxDurationMs {
+			stats.MaxDurationMs = duration
+		}
+		sumFrames += uint64(frames)
+		sumDuration += duration
+	}
+	stats.AvgFrames = float64(sumFrames) / float64(len(syncs))
+	stats.AvgDurationMs = float64(sumDuration) / float64(len(syncs))
+	return stats
+}
@@ -24,6 +24,15 @@ type Point struct {
 	presentationTime uint64
 }
 
+// SampleNum returns number of sync sample at which segment starts.
+func (p Point) SampleNum() uint32 { return p.sampleNum }
+
+// DecodeTime returns decode time of segment start in track timescale.
+func (p Point) DecodeTime() uint64 { return p.decodeTime }
+
+// PresentationTime returns presentation time of segment start in track timescale.
+func (p Point) PresentationTime() uint64 { return p.presentationTime }
+
 func GetFirstVideoTrackParams(m *mp4.File) (track *mp4.TrakBox, timescale uint32, duration uint64, err error) {
 	for _, t := range m.Moov.Traks {
 		if t.Mdia.Hdlr.HandlerType == "vide" {
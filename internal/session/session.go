@@ -1,14 +1,43 @@
 package session
 
+import "time"
+
 const (
 	KindUpload = "upload"
 	KindWatch  = "watch"
+	KindIngest = "ingest"
 )
 
 // Session represents session created for user interactions with media.
+// Multi-period watch sessions (i.e. playlists) have PlaylistID instead of VideoID
+// and Locations instead of Location, each location corresponds to period with the same
+// index, and so does video in VideoIDs.
+// Upload sessions have expected checksums of parts indexed by part number
+// and total size of video. Upload and watch sessions also have owner of video,
+// so uploads could be throttled and streams could be limited per user.
 type Session struct {
-	ID       string `json:"sid"`
-	VideoID  string `json:"vid"`
-	Location string `json:"loc"`
-	PartSize uint64 `json:"psz"`
+	ID            string   `json:"sid"`
+	VideoID       string   `json:"vid"`
+	PlaylistID    string   `json:"plid,omitempty"`
+	VideoIDs      []string `json:"vids,omitempty"`
+	UserID        string   `json:"uid,omitempty"`
+	Location      string   `json:"loc"`
+	Locations     []string `json:"locs,omitempty"`
+	PartChecksums []string `json:"pcs,omitempty"`
+	PartSize      uint64   `json:"psz"`
+	Size          uint64   `json:"sz,omitempty"`
+}
+
+// Videos returns videos that are accessed within session.
+func (s *Session) Videos() []string {
+	if s.PlaylistID != "" {
+		return s.VideoIDs
+	}
+	return []string{s.VideoID}
+}
+
+// Activity is a session along with time of its last activity.
+type Activity struct {
+	Session  *Session  `json:"session"`
+	ActiveAt time.Time `json:"active_at"`
 }
@@ -0,0 +1,187 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/adwski/vidi/internal/session"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// VideoSessions returns active sessions of video, the oldest first.
+func (s *Store) VideoSessions(ctx context.Context, videoID string) ([]*session.Activity, error) {
+	return s.indexedSessions(ctx, s.getVideoKey(videoID))
+}
+
+// Revoke deletes session along with its index entries and notifies
+// other store instances, so session is evicted from their in-memory caches.
+func (s *Store) Revoke(ctx context.Context, sess *session.Session) error {
+	_, err := s.r.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, s.getFullKey(sess.ID))
+		if sess.UserID != "" {
+			pipe.ZRem(ctx, s.getUserKey(sess.UserID), sess.ID)
+		}
+		for _, videoID := range sess.Videos() {
+			pipe.ZRem(ctx, s.getVideoKey(videoID), sess.ID)
+		}
+		pipe.Publish(ctx, s.getRevokedChannel(), sess.ID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot revoke session: %w", err)
+	}
+	s.evict(sess.ID)
+	return nil
+}
+
+// Run listens for revoked sessions and evicts them from in-memory cache.
+// It should be run by services that use GetExpireCached.
+func (s *Store) Run(ctx context.Context, wg *sync.WaitGroup, _ chan<- error) {
+	defer wg.Done()
+	sub := s.r.Subscribe(ctx, s.getRevokedChannel())
+	defer func() {
+		if err := sub.Close(); err != nil {
+			s.logger.Error("cannot close revocation subscription", zap.Error(err))
+		}
+	}()
+	s.logger.Info("revocation listener started")
+	ch := sub.Channel()
+Loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break Loop
+		case msg, ok := <-ch:
+			if !ok {
+				break Loop
+			}
+			s.evict(msg.Payload)
+			s.logger.Debug("revoked session evicted", zap.String("id", msg.Payload))
+		}
+	}
+	s.logger.Info("revocation listener stopped")
+}
+
+// indexedSessions returns sessions from index set. Index entries of sessions
+// that were not active during ttl or that are already deleted are dropped.
+func (s *Store) indexedSessions(ctx context.Context, indexKey string) ([]*session.Activity, error) {
+	stale := strconv.FormatInt(time.Now().Add(-s.ttl).UnixMilli(), 10)
+	if err := s.r.ZRemRangeByScore(ctx, indexKey, "-inf", stale).Err(); err != nil {
+		return nil, fmt.Errorf("cannot cleanup indexed sessions: %w", err)
+	}
+	members, err := s.r.ZRangeWithScores(ctx, indexKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve indexed sessions: %w", err)
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(members))
+	for _, m := range members {
+		keys = append(keys, s.getFullKey(m.Member.(string))) //nolint:errcheck // members are always strings
+	}
+	values, err := s.r.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve sessions: %w", err)
+	}
+	var (
+		gone       []interface{}
+		activities = make([]*session.Activity, 0, len(members))
+	)
+	for i, v := range values {
+		data, ok := v.(string)
+		if !ok {
+			// session is expired or deleted
+			gone = append(gone, members[i].Member)
+			continue
+		}
+		var sess session.Session
+		if err = s.enc.Unmarshal([]byte(data), &sess); err != nil {
+			return nil, fmt.Errorf("cannot decode session: %w", err)
+		}
+		activities = append(activities, &session.Activity{
+			Session:  &sess,
+			ActiveAt: time.UnixMilli(int64(members[i].Score)),
+		})
+	}
+	if len(gone) > 0 {
+		if err = s.r.ZRem(ctx, indexKey, gone...).Err(); err != nil {
+			return nil, fmt.Errorf("cannot cleanup indexed sessions: %w", err)
+		}
+	}
+	return activities, nil
+}
+
+// touchIndexScript updates activity time of session in user and video indexes
+// only if session still exists, so revoked session is never brought back to indexes.
+// It returns 0 if session does not exist.
+//
+// KEYS[1] is session key, KEYS[2] is user set, the rest are sets of session videos.
+// ARGV: now, ttl ms, session id.
+var touchIndexScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+for i = 2, #KEYS do
+	redis.call('ZADD', KEYS[i], ARGV[1], ARGV[3])
+	redis.call('PEXPIRE', KEYS[i], ARGV[2])
+end
+return 1
+`)
+
+// touchIndex updates activity time of session in user and video indexes.
+// It returns false if session was deleted meanwhile.
+func (s *Store) touchIndex(ctx context.Context, sess *session.Session) (bool, error) {
+	keys := []string{s.getFullKey(sess.ID), s.getUserKey(sess.UserID)}
+	for _, videoID := range sess.Videos() {
+		keys = append(keys, s.getVideoKey(videoID))
+	}
+	n, err := touchIndexScript.Run(ctx, s.r, keys, time.Now().UnixMilli(), s.ttl.Milliseconds(), sess.ID).Int64()
+	if err != nil {
+		return false, fmt.Errorf("cannot update session activity: %w", err)
+	}
+	return n == 1, nil
+}
+
+// evict drops revoked session from in-memory cache and remembers it for a while,
+// so session that is being cached concurrently is not served from cache.
+func (s *Store) evict(id string) {
+	now := time.Now()
+	s.revokedMx.Lock()
+	for revokedID, at := range s.revoked {
+		if now.Sub(at) > revokedTTL {
+			delete(s.revoked, revokedID)
+		}
+	}
+	s.revoked[id] = now
+	s.revokedMx.Unlock()
+	s.cache.Del(id)
+}
+
+func (s *Store) isRevoked(id string) bool {
+	s.revokedMx.Lock()
+	defer s.revokedMx.Unlock()
+	_, ok := s.revoked[id]
+	return ok
+}
+
+func (s *Store) getUserKey(userID string) string {
+	return s.getIndexKey(":users:", userID)
+}
+
+func (s *Store) getVideoKey(videoID string) string {
+	return s.getIndexKey(":videos:", videoID)
+}
+
+func (s *Store) getIndexKey(index, id string) string {
+	b := make([]byte, 0, len(s.name)+len(index)+len(id))
+	return string(append(append(append(b, s.name...), index...), id...))
+}
+
+func (s *Store) getRevokedChannel() string {
+	return string(s.name) + ":revoked"
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adwski/vidi/internal/session"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_VideoSessions(t *testing.T) {
+	store, mock := newTestIndexStore(t)
+	sess := &session.Session{ID: "sess", VideoID: "vid", UserID: "user"}
+	data, err := store.enc.Marshal(sess)
+	require.NoError(t, err)
+
+	mock.CustomMatch(matchSkipping(3)).
+		ExpectZRemRangeByScore("test:videos:vid", "-inf", "0").SetVal(0)
+	mock.ExpectZRangeWithScores("test:videos:vid", 0, -1).SetVal([]redis.Z{
+		{Member: "sess", Score: float64(time.Now().UnixMilli())},
+	})
+	mock.ExpectMGet("test:sess").SetVal([]interface{}{string(data)})
+
+	activities, err := store.VideoSessions(context.Background(), "vid")
+	require.NoError(t, err)
+	require.Len(t, activities, 1)
+	assert.Equal(t, sess, activities[0].Session)
+
+	mock.CustomMatch(matchSkipping(3)).
+		ExpectZRemRangeByScore("test:videos:other", "-inf", "0").SetVal(0)
+	mock.ExpectZRangeWithScores("test:videos:other", 0, -1).SetVal(nil)
+	activities, err = store.VideoSessions(context.Background(), "other")
+	require.NoError(t, err)
+	assert.Empty(t, activities)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStore_Revoke(t *testing.T) {
+	store, mock := newTestIndexStore(t)
+	sess := &session.Session{ID: "sess", VideoID: "vid", UserID: "user"}
+
+	store.cache.Set("sess", *sess, 1)
+	store.cache.Wait()
+
+	mock.ExpectTxPipeline()
+	mock.ExpectDel("test:sess").SetVal(1)
+	mock.ExpectZRem("test:users:user", "sess").SetVal(1)
+	mock.ExpectZRem("test:videos:vid", "sess").SetVal(1)
+	mock.ExpectPublish("test:revoked", "sess").SetVal(1)
+	mock.ExpectTxPipelineExec()
+
+	require.NoError(t, store.Revoke(context.Background(), sess))
+	_, found := store.cache.Get("sess")
+	assert.False(t, found)
+
+	mock.ExpectTxPipeline()
+	mock.ExpectDel("test:sess").SetErr(errors.New("unavailable"))
+	require.Error(t, store.Revoke(context.Background(), sess))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStore_GetExpireCachedRevoked(t *testing.T) {
+	store, mock := newTestIndexStore(t)
+	sess := &session.Session{ID: "sess", VideoID: "vid"}
+	data, err := store.enc.Marshal(sess)
+	require.NoError(t, err)
+
+	// revocation is received while session is being retrieved
+	mock.ExpectGet("test:sess").SetVal(string(data))
+	mock.ExpectExpire("test:sess", 600*time.Second).SetVal(true)
+	store.evict("sess")
+
+	_, err = store.GetExpireCached(context.Background(), "sess")
+	require.ErrorIs(t, err, ErrNotFound)
+	store.cache.Wait()
+	_, found := store.cache.Get("sess")
+	assert.False(t, found)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
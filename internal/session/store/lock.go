@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const lockTokenSize = 16
+
+var ErrLocked = errors.New("session is locked")
+
+// unlockScript deletes lock only if it is still held by caller,
+// so expired lock that was taken by someone else is not released.
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Lock acquires exclusive lock of session key, so concurrent requests of the same
+// session could be rejected. It returns ErrLocked if lock is held by someone else.
+// Lock expires after ttl, so it is not held forever if holder has failed.
+// Returned func releases lock.
+func (s *Store) Lock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, error) {
+	token, err := newLockToken()
+	if err != nil {
+		return nil, err
+	}
+	lockKey := s.getLockKey(key)
+	ok, err := s.r.SetNX(ctx, lockKey, token, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("cannot acquire lock: %w", err)
+	}
+	if !ok {
+		return nil, ErrLocked
+	}
+	return func(ctx context.Context) error {
+		if errU := unlockScript.Run(ctx, s.r, []string{lockKey}, token).Err(); errU != nil {
+			return fmt.Errorf("cannot release lock: %w", errU)
+		}
+		return nil
+	}, nil
+}
+
+func (s *Store) getLockKey(key string) string {
+	return s.getIndexKey(":locks:", key)
+}
+
+func newLockToken() (string, error) {
+	b := make([]byte, lockTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("cannot generate lock token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,34 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_Lock(t *testing.T) {
+	store, mock := newTestIndexStore(t)
+	ctx := context.Background()
+
+	// lock token is random
+	mock.CustomMatch(matchSkipping(2)).ExpectSetNX("test:locks:sess", "", time.Minute).SetVal(true)
+	unlock, err := store.Lock(ctx, "sess", time.Minute)
+	require.NoError(t, err)
+
+	mock.CustomMatch(matchSkipping(2)).ExpectSetNX("test:locks:sess", "", time.Minute).SetVal(false)
+	_, err = store.Lock(ctx, "sess", time.Minute)
+	require.ErrorIs(t, err, ErrLocked)
+
+	mock.CustomMatch(matchSkipping(4)).
+		ExpectEvalSha(unlockScript.Hash(), []string{"test:locks:sess"}, "").SetVal(int64(1))
+	require.NoError(t, unlock(ctx))
+
+	mock.CustomMatch(matchSkipping(2)).ExpectSetNX("test:locks:sess", "", time.Minute).
+		SetErr(errors.New("unavailable"))
+	_, err = store.Lock(ctx, "sess", time.Minute)
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
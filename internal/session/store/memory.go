@@ -0,0 +1,353 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/adwski/vidi/internal/session"
+	"go.uber.org/zap"
+)
+
+// memoryBackends are shared by memory stores with the same name, so services
+// that run in one process see the same sessions, just like with shared redis.
+var memoryBackends = struct {
+	sync.Mutex
+	m map[string]*memoryBackend
+}{m: make(map[string]*memoryBackend)}
+
+// MemoryStore is a session store that keeps sessions in process memory.
+// It is intended for single-node deployments and tests, sessions are lost on restart
+// and are not shared between processes.
+//
+// Sessions expire after ttl just like in redis store, and GetExpire refreshes
+// expiration of session. Since sessions are already in memory, GetExpireCached
+// is the same as GetExpire and revoked sessions are never served from cache.
+type MemoryStore struct {
+	logger *zap.Logger
+	b      *memoryBackend
+	name   string
+	ttl    time.Duration
+
+	index bool
+}
+
+type memoryBackend struct {
+	sessions map[string]*memorySession
+	users    map[string]map[string]time.Time
+	videos   map[string]map[string]time.Time
+	locks    map[string]*memoryLock
+	now      func() time.Time
+	mx       sync.Mutex
+	refs     int
+}
+
+type memorySession struct {
+	sess    session.Session
+	expires time.Time
+}
+
+type memoryLock struct {
+	expires time.Time
+}
+
+// NewMemoryStore creates memory store. Stores with the same name share sessions.
+func NewMemoryStore(cfg *Config) (*MemoryStore, error) {
+	ttl := cfg.TTL
+	if ttl < minTTL {
+		ttl = minTTL
+	}
+	if len(cfg.Name) == 0 {
+		return nil, errors.New("store name is required")
+	}
+	memoryBackends.Lock()
+	defer memoryBackends.Unlock()
+	b, ok := memoryBackends.m[cfg.Name]
+	if !ok {
+		b = &memoryBackend{
+			sessions: make(map[string]*memorySession),
+			users:    make(map[string]map[string]time.Time),
+			videos:   make(map[string]map[string]time.Time),
+			locks:    make(map[string]*memoryLock),
+			now:      time.Now,
+		}
+		memoryBackends.m[cfg.Name] = b
+	}
+	b.refs++
+	return &MemoryStore{
+		logger: cfg.Logger.With(
+			zap.String("component", "session-store"),
+			zap.String("name", cfg.Name)),
+		b:     b,
+		name:  cfg.Name,
+		ttl:   ttl,
+		index: cfg.Index,
+	}, nil
+}
+
+// Close releases store. Sessions are dropped when the last store with the same name is closed.
+func (s *MemoryStore) Close() {
+	memoryBackends.Lock()
+	defer memoryBackends.Unlock()
+	s.b.refs--
+	if s.b.refs == 0 {
+		delete(memoryBackends.m, s.name)
+	}
+}
+
+func (s *MemoryStore) Name() string {
+	return s.name
+}
+
+func (s *MemoryStore) Set(_ context.Context, sess *session.Session) error {
+	s.b.mx.Lock()
+	defer s.b.mx.Unlock()
+	now := s.b.now()
+	s.b.set(sess, now.Add(s.ttl))
+	if s.index && sess.UserID != "" {
+		s.b.touchIndex(sess, now)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) (*session.Session, error) {
+	s.b.mx.Lock()
+	defer s.b.mx.Unlock()
+	ms, ok := s.b.get(key)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	sess := ms.sess
+	return &sess, nil
+}
+
+func (s *MemoryStore) GetExpireCached(ctx context.Context, key string) (*session.Session, error) {
+	return s.GetExpire(ctx, key)
+}
+
+func (s *MemoryStore) GetExpire(_ context.Context, key string) (*session.Session, error) {
+	s.b.mx.Lock()
+	defer s.b.mx.Unlock()
+	ms, ok := s.b.get(key)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	now := s.b.now()
+	ms.expires = now.Add(s.ttl)
+	sess := ms.sess
+	if s.index && sess.UserID != "" {
+		s.b.touchIndex(&sess, now)
+	}
+	return &sess, nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.b.mx.Lock()
+	defer s.b.mx.Unlock()
+	delete(s.b.sessions, key)
+	return nil
+}
+
+// SetLimited stores session of user if user has less than limit active sessions.
+// It has the same semantics as Store.SetLimited.
+func (s *MemoryStore) SetLimited(_ context.Context, sess *session.Session, limit uint, evictOldest bool) error {
+	if sess.UserID == "" {
+		return errors.New("session does not have user")
+	}
+	s.b.mx.Lock()
+	defer s.b.mx.Unlock()
+	now := s.b.now()
+	active := s.b.indexed(s.b.users, sess.UserID, now.Add(-s.ttl))
+	if limit > 0 && uint(len(active)) >= limit {
+		if !evictOldest {
+			return ErrLimitExceeded
+		}
+		for _, victim := range active[:uint(len(active))-limit+1] {
+			s.b.revoke(&victim.sess)
+		}
+	}
+	s.b.set(sess, now.Add(s.ttl))
+	s.b.touchIndex(sess, now)
+	return nil
+}
+
+// UserSessions returns active sessions of user, the oldest first.
+func (s *MemoryStore) UserSessions(_ context.Context, userID string) ([]*session.Activity, error) {
+	return s.indexedSessions(s.b.users, userID), nil
+}
+
+// VideoSessions returns active sessions of video, the oldest first.
+func (s *MemoryStore) VideoSessions(_ context.Context, videoID string) ([]*session.Activity, error) {
+	return s.indexedSessions(s.b.videos, videoID), nil
+}
+
+// Revoke deletes session along with its index entries.
+func (s *MemoryStore) Revoke(_ context.Context, sess *session.Session) error {
+	s.b.mx.Lock()
+	defer s.b.mx.Unlock()
+	s.b.revoke(sess)
+	return nil
+}
+
+// Lock acquires exclusive lock of session key. It has the same semantics as Store.Lock.
+func (s *MemoryStore) Lock(_ context.Context, key string, ttl time.Duration) (func(context.Context) error, error) {
+	s.b.mx.Lock()
+	defer s.b.mx.Unlock()
+	now := s.b.now()
+	if l, ok := s.b.locks[key]; ok && now.Before(l.expires) {
+		return nil, ErrLocked
+	}
+	l := &memoryLock{expires: now.Add(ttl)}
+	s.b.locks[key] = l
+	return func(context.Context) error {
+		s.b.mx.Lock()
+		defer s.b.mx.Unlock()
+		if s.b.locks[key] == l {
+			delete(s.b.locks, key)
+		}
+		return nil
+	}, nil
+}
+
+// Run drops expired sessions periodically. Expired sessions are never returned
+// even if Run is not running, so it only limits memory usage.
+func (s *MemoryStore) Run(ctx context.Context, wg *sync.WaitGroup, _ chan<- error) {
+	defer wg.Done()
+	ticker := time.NewTicker(s.ttl / 2) //nolint:mnd // same as cache ttl of redis store
+	defer ticker.Stop()
+	s.logger.Info("expired sessions cleanup started")
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("expired sessions cleanup stopped")
+			return
+		case <-ticker.C:
+			s.b.mx.Lock()
+			n := s.b.cleanup(s.ttl)
+			s.b.mx.Unlock()
+			if n > 0 {
+				s.logger.Debug("expired sessions dropped", zap.Int("count", n))
+			}
+		}
+	}
+}
+
+func (s *MemoryStore) indexedSessions(index map[string]map[string]time.Time, id string) []*session.Activity {
+	s.b.mx.Lock()
+	defer s.b.mx.Unlock()
+	active := s.b.indexed(index, id, s.b.now().Add(-s.ttl))
+	if len(active) == 0 {
+		return nil
+	}
+	activities := make([]*session.Activity, 0, len(active))
+	for _, a := range active {
+		sess := a.sess
+		activities = append(activities, &session.Activity{Session: &sess, ActiveAt: a.activeAt})
+	}
+	return activities
+}
+
+type memoryActivity struct {
+	activeAt time.Time
+	sess     session.Session
+}
+
+func (b *memoryBackend) set(sess *session.Session, expires time.Time) {
+	b.sessions[sess.ID] = &memorySession{sess: *sess, expires: expires}
+}
+
+func (b *memoryBackend) get(key string) (*memorySession, bool) {
+	ms, ok := b.sessions[key]
+	if !ok {
+		return nil, false
+	}
+	if !b.now().Before(ms.expires) {
+		delete(b.sessions, key)
+		return nil, false
+	}
+	return ms, true
+}
+
+// indexed returns sessions from index set, the oldest first. Index entries of sessions
+// that were not active since stale time or that are already deleted are dropped.
+func (b *memoryBackend) indexed(index map[string]map[string]time.Time, id string, stale time.Time) []memoryActivity {
+	set := index[id]
+	active := make([]memoryActivity, 0, len(set))
+	for sessID, activeAt := range set {
+		ms, ok := b.get(sessID)
+		if !ok || !activeAt.After(stale) {
+			delete(set, sessID)
+			continue
+		}
+		active = append(active, memoryActivity{activeAt: activeAt, sess: ms.sess})
+	}
+	if len(set) == 0 {
+		delete(index, id)
+	}
+	slices.SortFunc(active, func(a, b memoryActivity) int {
+		return a.activeAt.Compare(b.activeAt)
+	})
+	return active
+}
+
+func (b *memoryBackend) touchIndex(sess *session.Session, now time.Time) {
+	touch := func(index map[string]map[string]time.Time, id string) {
+		set, ok := index[id]
+		if !ok {
+			set = make(map[string]time.Time)
+			index[id] = set
+		}
+		set[sess.ID] = now
+	}
+	touch(b.users, sess.UserID)
+	for _, videoID := range sess.Videos() {
+		touch(b.videos, videoID)
+	}
+}
+
+func (b *memoryBackend) revoke(sess *session.Session) {
+	delete(b.sessions, sess.ID)
+	if set, ok := b.users[sess.UserID]; ok {
+		delete(set, sess.ID)
+	}
+	for _, videoID := range sess.Videos() {
+		if set, ok := b.videos[videoID]; ok {
+			delete(set, sess.ID)
+		}
+	}
+}
+
+// cleanup drops expired sessions and stale index entries, it returns amount of dropped sessions.
+func (b *memoryBackend) cleanup(ttl time.Duration) int {
+	var (
+		n     int
+		now   = b.now()
+		stale = now.Add(-ttl)
+	)
+	for key, ms := range b.sessions {
+		if !now.Before(ms.expires) {
+			delete(b.sessions, key)
+			n++
+		}
+	}
+	for key, l := range b.locks {
+		if !now.Before(l.expires) {
+			delete(b.locks, key)
+		}
+	}
+	for _, index := range []map[string]map[string]time.Time{b.users, b.videos} {
+		for id, set := range index {
+			for sessID, activeAt := range set {
+				if !activeAt.After(stale) {
+					delete(set, sessID)
+				}
+			}
+			if len(set) == 0 {
+				delete(index, id)
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,226 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/adwski/vidi/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestMemoryStore returns memory store with controlled clock.
+func newTestMemoryStore(t *testing.T, name string, index bool) (*MemoryStore, *time.Time) {
+	t.Helper()
+	st, err := NewMemoryStore(&Config{Logger: zap.NewNop(), Name: name, Index: index})
+	require.NoError(t, err)
+	t.Cleanup(st.Close)
+	now := time.Now()
+	st.b.now = func() time.Time { return now }
+	return st, &now
+}
+
+func TestNew(t *testing.T) {
+	st, err := New(&Config{Logger: zap.NewNop(), Type: TypeMemory, Name: "test-new"})
+	require.NoError(t, err)
+	defer st.Close()
+	assert.IsType(t, &MemoryStore{}, st)
+
+	st, err = New(&Config{Logger: zap.NewNop(), Name: "test", RedisDSN: "redis://localhost:1111/0"})
+	require.NoError(t, err)
+	defer st.Close()
+	assert.IsType(t, &Store{}, st)
+
+	_, err = New(&Config{Logger: zap.NewNop(), Type: "etcd", Name: "test"})
+	require.ErrorContains(t, err, "unknown session store type")
+}
+
+func TestMemoryStore_expire(t *testing.T) {
+	st, now := newTestMemoryStore(t, "test-expire", false)
+	ctx := context.Background()
+	sess := &session.Session{ID: "sess", VideoID: "vid"}
+	require.NoError(t, st.Set(ctx, sess))
+
+	*now = now.Add(minTTL - time.Second)
+	got, err := st.GetExpireCached(ctx, "sess")
+	require.NoError(t, err)
+	assert.Equal(t, sess, got)
+
+	// ttl is refreshed by previous call
+	*now = now.Add(minTTL - time.Second)
+	got, err = st.Get(ctx, "sess")
+	require.NoError(t, err)
+	assert.Equal(t, sess, got)
+
+	// Get does not refresh ttl
+	*now = now.Add(time.Second)
+	_, err = st.GetExpire(ctx, "sess")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, st.Set(ctx, sess))
+	require.NoError(t, st.Delete(ctx, "sess"))
+	_, err = st.Get(ctx, "sess")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryStore_Lock(t *testing.T) {
+	st, now := newTestMemoryStore(t, "test-lock", false)
+	ctx := context.Background()
+
+	unlock, err := st.Lock(ctx, "sess", time.Minute)
+	require.NoError(t, err)
+	_, err = st.Lock(ctx, "sess", time.Minute)
+	require.ErrorIs(t, err, ErrLocked)
+	_, err = st.Lock(ctx, "other", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, unlock(ctx))
+
+	// expired lock could be acquired again and is not released by previous holder
+	unlock, err = st.Lock(ctx, "sess", time.Minute)
+	require.NoError(t, err)
+	*now = now.Add(time.Minute)
+	_, err = st.Lock(ctx, "sess", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, unlock(ctx))
+	_, err = st.Lock(ctx, "sess", time.Minute)
+	require.ErrorIs(t, err, ErrLocked)
+}
+
+func TestMemoryStore_shared(t *testing.T) {
+	st1, _ := newTestMemoryStore(t, "test-shared", false)
+	st2, err := NewMemoryStore(&Config{Logger: zap.NewNop(), Name: "test-shared"})
+	require.NoError(t, err)
+	st3, _ := newTestMemoryStore(t, "test-other", false)
+
+	ctx := context.Background()
+	require.NoError(t, st1.Set(ctx, &session.Session{ID: "sess"}))
+	_, err = st2.Get(ctx, "sess")
+	require.NoError(t, err)
+	_, err = st3.Get(ctx, "sess")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// sessions are kept until the last store is closed
+	st2.Close()
+	_, err = st1.Get(ctx, "sess")
+	require.NoError(t, err)
+}
+
+func TestMemoryStore_SetLimited(t *testing.T) {
+	st, now := newTestMemoryStore(t, "test-limited", false)
+	ctx := context.Background()
+	for _, id := range []string{"s1", "s2"} {
+		require.NoError(t, st.SetLimited(ctx, &session.Session{ID: id, VideoID: "vid", UserID: "user"}, 2, false))
+		*now = now.Add(time.Second)
+	}
+	err := st.SetLimited(ctx, &session.Session{ID: "s3", VideoID: "vid", UserID: "user"}, 2, false)
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	require.NoError(t, st.SetLimited(ctx, &session.Session{ID: "s3", VideoID: "vid", UserID: "user"}, 2, true))
+	_, err = st.Get(ctx, "s1")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	activities, err := st.UserSessions(ctx, "user")
+	require.NoError(t, err)
+	require.Len(t, activities, 2)
+	assert.Equal(t, "s2", activities[0].Session.ID)
+	assert.Equal(t, "s3", activities[1].Session.ID)
+
+	// inactive sessions are not counted
+	*now = now.Add(minTTL)
+	require.NoError(t, st.SetLimited(ctx, &session.Session{ID: "s4", VideoID: "vid", UserID: "user"}, 1, false))
+
+	err = st.SetLimited(ctx, &session.Session{ID: "s5"}, 1, false)
+	require.Error(t, err)
+}
+
+func TestMemoryStore_index(t *testing.T) {
+	st, now := newTestMemoryStore(t, "test-index", true)
+	ctx := context.Background()
+	s1 := &session.Session{ID: "s1", VideoID: "vid", UserID: "user"}
+	s2 := &session.Session{ID: "s2", VideoID: "vid", UserID: "other"}
+	require.NoError(t, st.Set(ctx, s1))
+	*now = now.Add(time.Second)
+	require.NoError(t, st.Set(ctx, s2))
+	require.NoError(t, st.Set(ctx, &session.Session{ID: "anon", VideoID: "vid"}))
+
+	activities, err := st.VideoSessions(ctx, "vid")
+	require.NoError(t, err)
+	require.Len(t, activities, 2)
+	assert.Equal(t, s1, activities[0].Session)
+	assert.Equal(t, s2, activities[1].Session)
+
+	// activity moves session to the end
+	*now = now.Add(time.Second)
+	_, err = st.GetExpire(ctx, "s1")
+	require.NoError(t, err)
+	activities, err = st.VideoSessions(ctx, "vid")
+	require.NoError(t, err)
+	require.Len(t, activities, 2)
+	assert.Equal(t, s1, activities[1].Session)
+	assert.Equal(t, *now, activities[1].ActiveAt)
+
+	require.NoError(t, st.Revoke(ctx, s1))
+	_, err = st.GetExpireCached(ctx, "s1")
+	require.ErrorIs(t, err, ErrNotFound)
+	activities, err = st.UserSessions(ctx, "user")
+	require.NoError(t, err)
+	assert.Empty(t, activities)
+
+	// deleted sessions are dropped from index
+	require.NoError(t, st.Delete(ctx, "s2"))
+	activities, err = st.VideoSessions(ctx, "vid")
+	require.NoError(t, err)
+	assert.Empty(t, activities)
+}
+
+func TestMemoryStore_indexPlaylist(t *testing.T) {
+	st, _ := newTestMemoryStore(t, "test-index-playlist", true)
+	ctx := context.Background()
+	sess := &session.Session{
+		ID:         "s1",
+		PlaylistID: "pl",
+		VideoIDs:   []string{"vid1", "vid2"},
+		UserID:     "user",
+		Locations:  []string{"loc1", "loc2"},
+	}
+	require.NoError(t, st.Set(ctx, sess))
+
+	// playlist session is indexed under every video of playlist
+	for _, vid := range sess.VideoIDs {
+		activities, err := st.VideoSessions(ctx, vid)
+		require.NoError(t, err)
+		require.Len(t, activities, 1)
+		assert.Equal(t, sess, activities[0].Session)
+	}
+	activities, err := st.VideoSessions(ctx, "pl")
+	require.NoError(t, err)
+	assert.Empty(t, activities)
+
+	require.NoError(t, st.Revoke(ctx, sess))
+	for _, vid := range sess.VideoIDs {
+		activities, err = st.VideoSessions(ctx, vid)
+		require.NoError(t, err)
+		assert.Empty(t, activities)
+	}
+}
+
+func TestMemoryStore_cleanup(t *testing.T) {
+	st, now := newTestMemoryStore(t, "test-cleanup", true)
+	ctx := context.Background()
+	require.NoError(t, st.Set(ctx, &session.Session{ID: "s1", VideoID: "vid", UserID: "user"}))
+	*now = now.Add(time.Second)
+	require.NoError(t, st.Set(ctx, &session.Session{ID: "s2", VideoID: "vid", UserID: "user"}))
+
+	*now = now.Add(minTTL - time.Second)
+	assert.Equal(t, 1, st.b.cleanup(st.ttl))
+	assert.Len(t, st.b.sessions, 1)
+	assert.Len(t, st.b.users["user"], 1)
+
+	*now = now.Add(time.Second)
+	assert.Equal(t, 1, st.b.cleanup(st.ttl))
+	assert.Empty(t, st.b.sessions)
+	assert.Empty(t, st.b.users)
+	assert.Empty(t, st.b.videos)
+}
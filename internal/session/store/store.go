@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -25,12 +26,41 @@ const (
 	ristrettoDefaultCost = 1
 
 	minTTL = 300 * time.Second
+
+	// revokedTTL is how long revoked session is remembered by store instance.
+	// It should be longer than any session retrieval.
+	revokedTTL = time.Minute
+)
+
+// Session store types.
+const (
+	TypeRedis  = "redis"
+	TypeMemory = "memory"
 )
 
 var (
 	ErrNotFound = errors.New("session not found")
 )
 
+// SessionStore is implemented by every session store.
+// Get, GetExpire and GetExpireCached return ErrNotFound if session does not exist
+// or is expired.
+type SessionStore interface {
+	Set(ctx context.Context, sess *session.Session) error
+	Get(ctx context.Context, key string) (*session.Session, error)
+	GetExpire(ctx context.Context, key string) (*session.Session, error)
+	GetExpireCached(ctx context.Context, key string) (*session.Session, error)
+	Delete(ctx context.Context, key string) error
+	SetLimited(ctx context.Context, sess *session.Session, limit uint, evictOldest bool) error
+	UserSessions(ctx context.Context, userID string) ([]*session.Activity, error)
+	VideoSessions(ctx context.Context, videoID string) ([]*session.Activity, error)
+	Revoke(ctx context.Context, sess *session.Session) error
+	Lock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, error)
+	Run(ctx context.Context, wg *sync.WaitGroup, errc chan<- error)
+	Close()
+	Name() string
+}
+
 // Store is a session store.
 // It supports Name parameter (which basically is session key prefix like "<prefix>:<key>")
 // Redis session is stored with preconfigured TTL
@@ -40,22 +70,54 @@ var (
 // it is half of configured redis TTL. This helps to survive traffic bursts and still
 // update session TTL in redis.
 //
-// Only GetExpireCached() supports session caching.
+// Only GetExpireCached() supports session caching. Revoked sessions are evicted
+// from cache of every store instance that runs revocation listener (see Run).
 type Store struct {
 	logger   *zap.Logger
 	r        *redis.Client
 	cache    *ristretto.Cache
 	enc      jsoniter.API
+	revoked  map[string]time.Time
 	name     []byte
 	ttl      time.Duration
 	cacheTTL time.Duration
+
+	revokedMx sync.Mutex
+	index     bool
 }
 
 type Config struct {
-	Logger   *zap.Logger
-	Name     string
+	Logger *zap.Logger
+	Name   string
+	// Type is either redis or memory, redis is used if it is empty.
+	Type     string
 	RedisDSN string
 	TTL      time.Duration
+
+	// Index enables indexes of sessions per user and per video, so sessions
+	// could be listed and revoked. Index entries are refreshed along with session
+	// by GetExpire, so user index also tracks active sessions of user.
+	Index bool
+}
+
+// New creates session store of configured type.
+func New(cfg *Config) (SessionStore, error) {
+	switch cfg.Type {
+	case TypeRedis, "":
+		st, err := NewStore(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return st, nil
+	case TypeMemory:
+		st, err := NewMemoryStore(cfg)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Logger.Info("using memory session store", zap.String("name", cfg.Name))
+		return st, nil
+	}
+	return nil, fmt.Errorf("unknown session store type: %s", cfg.Type)
 }
 
 func NewStore(cfg *Config) (*Store, error) {
@@ -90,11 +152,14 @@ func NewStore(cfg *Config) (*Store, error) {
 		logger: cfg.Logger.With(
 			zap.String("component", "session-store"),
 			zap.String("name", cfg.Name)),
-		r:     r,
-		cache: cache,
-		enc:   jsoniter.ConfigCompatibleWithStandardLibrary,
-		name:  []byte(cfg.Name),
-		ttl:   ttl,
+		r:       r,
+		cache:   cache,
+		enc:     jsoniter.ConfigCompatibleWithStandardLibrary,
+		revoked: make(map[string]time.Time),
+		name:    []byte(cfg.Name),
+		ttl:     ttl,
+
+		index: cfg.Index,
 
 		// Local cache ttl is half of redis ttl.
 		// Because of this after half-time we goto redis and update expiration,
@@ -122,6 +187,10 @@ func (s *Store) Set(ctx context.Context, sess *session.Session) error {
 	if err = s.r.Set(ctx, s.getFullKey(sess.ID), data, s.ttl).Err(); err != nil {
 		return fmt.Errorf("cannot store session: %w", err)
 	}
+	if s.index && sess.UserID != "" {
+		_, err = s.touchIndex(ctx, sess)
+		return err
+	}
 	return nil
 }
 
@@ -154,6 +223,12 @@ func (s *Store) GetExpireCached(ctx context.Context, key string) (*session.Sessi
 	// During continuous get calls it will be stored eventually.
 	// TODO Find out is it better to store copies or pointers
 	_ = s.cache.SetWithTTL(key, *sess, ristrettoDefaultCost, s.cacheTTL)
+	// Revocation could be processed after session was retrieved but before
+	// it was cached, so session is evicted once more in this case.
+	if s.isRevoked(key) {
+		s.cache.Del(key)
+		return nil, ErrNotFound
+	}
 	return sess, err
 }
 
@@ -174,6 +249,16 @@ func (s *Store) GetExpire(ctx context.Context, key string) (*session.Session, er
 	if err = s.enc.Unmarshal(b, &sess); err != nil {
 		return nil, fmt.Errorf("cannot decode session: %w", err)
 	}
+	if s.index && sess.UserID != "" {
+		exists, errT := s.touchIndex(ctx, &sess)
+		if errT != nil {
+			return nil, errT
+		}
+		if !exists {
+			// session was revoked meanwhile
+			return nil, ErrNotFound
+		}
+	}
 	return &sess, nil
 }
 
@@ -187,6 +272,11 @@ func (s *Store) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// Redis returns redis client of store, so other components could share connection.
+func (s *Store) Redis() *redis.Client {
+	return s.r
+}
+
 func (s *Store) Name() string {
 	return string(s.name)
 }
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adwski/vidi/internal/session"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrLimitExceeded is returned when user has maximum amount of active sessions.
+var ErrLimitExceeded = errors.New("session limit exceeded")
+
+// setLimitedScript stores session and adds it to active sessions of user and to sessions of video.
+// Sessions that were not active during ttl are dropped from user set first.
+// If user has limit of active sessions already, then either nothing is stored
+// and -1 is returned, or the oldest sessions are dropped from user set to free one slot.
+// Script returns ids of dropped sessions, they are revoked by caller, since their keys
+// are not known in advance and script must not access undeclared keys.
+//
+// KEYS[1] is user set, KEYS[2] is session key, the rest are sets of session videos.
+// ARGV: now, stale time, limit, evict flag, session data, ttl ms, session id.
+var setLimitedScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[2])
+local n = redis.call('ZCARD', KEYS[1])
+local limit = tonumber(ARGV[3])
+local victims = {}
+if limit > 0 and n >= limit then
+	if ARGV[4] ~= '1' then
+		return -1
+	end
+	victims = redis.call('ZRANGE', KEYS[1], 0, n - limit)
+	redis.call('ZREM', KEYS[1], unpack(victims))
+end
+redis.call('SET', KEYS[2], ARGV[5], 'PX', ARGV[6])
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[7])
+redis.call('PEXPIRE', KEYS[1], ARGV[6])
+for i = 3, #KEYS do
+	redis.call('ZADD', KEYS[i], ARGV[1], ARGV[7])
+	redis.call('PEXPIRE', KEYS[i], ARGV[6])
+end
+return victims
+`)
+
+// SetLimited stores session of user if user has less than limit active sessions.
+// Session is active until it is not refreshed with GetExpire during ttl.
+// If limit is reached, either ErrLimitExceeded is returned, or the oldest sessions
+// are deleted if evictOldest is set. Zero limit is not enforced.
+//
+// Evicted sessions are revoked, so they are also evicted from in-memory caches.
+// Their entries in video indexes are dropped when video sessions are listed.
+func (s *Store) SetLimited(ctx context.Context, sess *session.Session, limit uint, evictOldest bool) error {
+	if sess.UserID == "" {
+		return errors.New("session does not have user")
+	}
+	data, err := s.enc.Marshal(sess)
+	if err != nil {
+		return fmt.Errorf("cannot encode session: %w", err)
+	}
+	var (
+		now     = time.Now()
+		evict   = "0"
+		userKey = s.getUserKey(sess.UserID)
+		keys    = []string{userKey, s.getFullKey(sess.ID)}
+	)
+	if evictOldest {
+		evict = "1"
+	}
+	for _, videoID := range sess.Videos() {
+		keys = append(keys, s.getVideoKey(videoID))
+	}
+	cmd := setLimitedScript.Run(ctx, s.r,
+		keys,
+		now.UnixMilli(),
+		now.Add(-s.ttl).UnixMilli(),
+		limit,
+		evict,
+		data,
+		s.ttl.Milliseconds(),
+		sess.ID,
+	)
+	if n, errN := cmd.Int64(); errN == nil && n < 0 {
+		return ErrLimitExceeded
+	}
+	victims, err := cmd.StringSlice()
+	if err != nil {
+		return fmt.Errorf("cannot store session: %w", err)
+	}
+	if len(victims) == 0 {
+		return nil
+	}
+	// Victims are dropped from user set once more, so activity
+	// refreshed before deletion does not bring them back.
+	_, err = s.r.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range victims {
+			pipe.Del(ctx, s.getFullKey(id))
+			pipe.ZRem(ctx, userKey, id)
+			pipe.Publish(ctx, s.getRevokedChannel(), id)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot revoke evicted sessions: %w", err)
+	}
+	for _, id := range victims {
+		s.evict(id)
+	}
+	return nil
+}
+
+// UserSessions returns active sessions of user, the oldest first.
+func (s *Store) UserSessions(ctx context.Context, userID string) ([]*session.Activity, error) {
+	return s.indexedSessions(ctx, s.getUserKey(userID))
+}
@@ -0,0 +1,140 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/adwski/vidi/internal/session"
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// matchSkipping compares command arguments except those at specified positions, i.e. timestamps.
+func matchSkipping(skip ...int) redismock.CustomMatch {
+	return func(expected, actual []interface{}) error {
+	Loop:
+		for i := range expected {
+			for _, s := range skip {
+				if i == s {
+					continue Loop
+				}
+			}
+			if !reflect.DeepEqual(expected[i], actual[i]) {
+				return fmt.Errorf("argument %d mismatch: expected %v, got %v", i, expected[i], actual[i])
+			}
+		}
+		return nil
+	}
+}
+
+func newTestIndexStore(t *testing.T) (*Store, redismock.ClientMock) {
+	t.Helper()
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	store, err := NewStore(&Config{
+		Logger:   logger,
+		Name:     "test",
+		RedisDSN: "redis://placeholder:1111/0",
+		TTL:      600 * time.Second,
+		Index:    true,
+	})
+	require.NoError(t, err)
+
+	db, mock := redismock.NewClientMock()
+	store.r = db
+	return store, mock
+}
+
+func TestStore_SetLimited(t *testing.T) {
+	store, mock := newTestIndexStore(t)
+	sess := &session.Session{ID: "sess", VideoID: "vid", UserID: "user"}
+	data, err := store.enc.Marshal(sess)
+	require.NoError(t, err)
+
+	keys := []string{"test:users:user", "test:sess", "test:videos:vid"}
+	// evalsha, sha, numkeys, 3 keys, then now and stale timestamps
+	for _, tt := range []struct {
+		evict  string
+		result interface{}
+	}{
+		{evict: "0", result: []interface{}{}},
+		{evict: "0", result: int64(-1)},
+		{evict: "1", result: []interface{}{"old"}},
+	} {
+		mock.CustomMatch(matchSkipping(6, 7)).
+			ExpectEvalSha(setLimitedScript.Hash(), keys,
+				int64(0), int64(0), uint(2), tt.evict, data, int64(600000), "sess").
+			SetVal(tt.result)
+	}
+	mock.ExpectTxPipeline()
+	mock.ExpectDel("test:old").SetVal(1)
+	mock.ExpectZRem("test:users:user", "old").SetVal(0)
+	mock.ExpectPublish("test:revoked", "old").SetVal(1)
+	mock.ExpectTxPipelineExec()
+
+	ctx := context.Background()
+	require.NoError(t, store.SetLimited(ctx, sess, 2, false))
+	require.ErrorIs(t, store.SetLimited(ctx, sess, 2, false), ErrLimitExceeded)
+	require.NoError(t, store.SetLimited(ctx, sess, 2, true))
+
+	require.Error(t, store.SetLimited(ctx, &session.Session{ID: "sess"}, 2, false))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStore_UserSessions(t *testing.T) {
+	store, mock := newTestIndexStore(t)
+	sess := &session.Session{ID: "sess2", VideoID: "vid", UserID: "user"}
+	data, err := store.enc.Marshal(sess)
+	require.NoError(t, err)
+
+	activeAt := time.UnixMilli(time.Now().UnixMilli())
+	mock.CustomMatch(matchSkipping(3)).
+		ExpectZRemRangeByScore("test:users:user", "-inf", "0").SetVal(0)
+	mock.ExpectZRangeWithScores("test:users:user", 0, -1).SetVal([]redis.Z{
+		{Member: "sess1", Score: float64(activeAt.Add(-time.Minute).UnixMilli())},
+		{Member: "sess2", Score: float64(activeAt.UnixMilli())},
+	})
+	// sess1 is already expired
+	mock.ExpectMGet("test:sess1", "test:sess2").SetVal([]interface{}{nil, string(data)})
+	mock.ExpectZRem("test:users:user", "sess1").SetVal(1)
+
+	activities, err := store.UserSessions(context.Background(), "user")
+	require.NoError(t, err)
+	require.Len(t, activities, 1)
+	assert.Equal(t, sess, activities[0].Session)
+	assert.True(t, activeAt.Equal(activities[0].ActiveAt))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStore_GetExpireTracksUser(t *testing.T) {
+	store, mock := newTestIndexStore(t)
+	sess := &session.Session{ID: "sess", VideoID: "vid", UserID: "user"}
+	data, err := store.enc.Marshal(sess)
+	require.NoError(t, err)
+
+	keys := []string{"test:sess", "test:users:user", "test:videos:vid"}
+	for _, result := range []int64{1, 0} {
+		mock.ExpectGet("test:sess").SetVal(string(data))
+		mock.ExpectExpire("test:sess", 600*time.Second).SetVal(true)
+		// evalsha, sha, numkeys, 3 keys, then now timestamp
+		mock.CustomMatch(matchSkipping(6)).
+			ExpectEvalSha(touchIndexScript.Hash(), keys, int64(0), int64(600000), "sess").
+			SetVal(result)
+	}
+
+	got, err := store.GetExpire(context.Background(), "sess")
+	require.NoError(t, err)
+	assert.Equal(t, sess, got)
+
+	// session was revoked after it was retrieved
+	_, err = store.GetExpire(context.Background(), "sess")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
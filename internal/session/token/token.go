@@ -0,0 +1,215 @@
+// Package token contains signed session tokens. Signed token carries session
+// itself, so it could be verified without session store.
+//
+// Token has form of <key-id>.<payload>.<signature> where payload is base64url encoded
+// json of session with expiration time and optional client binding.
+// Signature is calculated over <key-id>.<payload> with key identified by key id,
+// so keys could be rotated by adding new key to verifiers before it is used for signing.
+package token
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/adwski/vidi/internal/session"
+)
+
+// Supported signing algorithms.
+const (
+	AlgHS256   = "hs256"
+	AlgEd25519 = "ed25519"
+)
+
+const hmacMinKeyLen = 32
+
+var (
+	ErrInvalid        = errors.New("invalid token")
+	ErrExpired        = errors.New("token is expired")
+	ErrUnknownKey     = errors.New("unknown key id")
+	ErrClientMismatch = errors.New("token is bound to other client")
+
+	keyIDRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	enc     = base64.RawURLEncoding
+)
+
+// Key is a signing key. For hs256 Secret is a shared secret, for ed25519 it is
+// a private key seed when used by Signer and a public key when used by Verifier.
+type Key struct {
+	ID     string
+	Alg    string
+	Secret []byte
+}
+
+type claims struct {
+	session.Session
+	Client    string `json:"cli,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// ParseKey parses key from "<key-id>:<alg>:<base64-secret>" string.
+func ParseKey(spec string) (*Key, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) != 3 { //nolint:mnd // id, alg, secret
+		return nil, errors.New("key must be in form <key-id>:<alg>:<base64-secret>")
+	}
+	secret, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode key secret: %w", err)
+	}
+	key := &Key{ID: parts[0], Alg: parts[1], Secret: secret}
+	if !keyIDRe.MatchString(key.ID) {
+		return nil, fmt.Errorf("invalid key id: %s", key.ID)
+	}
+	switch key.Alg {
+	case AlgHS256:
+		if len(secret) < hmacMinKeyLen {
+			return nil, fmt.Errorf("hs256 secret must be at least %d bytes", hmacMinKeyLen)
+		}
+	case AlgEd25519:
+		// seed and public key have the same size
+		if len(secret) != ed25519.SeedSize {
+			return nil, fmt.Errorf("ed25519 key must be %d bytes", ed25519.SeedSize)
+		}
+	default:
+		return nil, fmt.Errorf("unknown algorithm: %s", key.Alg)
+	}
+	return key, nil
+}
+
+// Signer issues signed session tokens.
+type Signer struct {
+	key     *Key
+	private ed25519.PrivateKey
+	ttl     time.Duration
+}
+
+// NewSigner creates signer that signs tokens with specified key.
+// Tokens are valid for ttl after they are issued.
+func NewSigner(key *Key, ttl time.Duration) (*Signer, error) {
+	s := &Signer{key: key, ttl: ttl}
+	if key.Alg == AlgEd25519 {
+		if len(key.Secret) != ed25519.SeedSize {
+			return nil, errors.New("ed25519 signing key must be a private key seed")
+		}
+		s.private = ed25519.NewKeyFromSeed(key.Secret)
+	}
+	if ttl <= 0 {
+		return nil, errors.New("token ttl must be positive")
+	}
+	return s, nil
+}
+
+// TTL returns validity period of issued tokens.
+func (s *Signer) TTL() time.Duration {
+	return s.ttl
+}
+
+// Sign returns signed token of session. If client is not empty,
+// token could be used only by that client.
+func (s *Signer) Sign(sess *session.Session, client string) (string, error) {
+	payload, err := json.Marshal(&claims{
+		Session:   *sess,
+		Client:    client,
+		ExpiresAt: time.Now().Add(s.ttl).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot encode token payload: %w", err)
+	}
+	var b bytes.Buffer
+	b.WriteString(s.key.ID)
+	b.WriteByte('.')
+	b.WriteString(enc.EncodeToString(payload))
+	sig := sign(s.key, s.private, b.Bytes())
+	b.WriteByte('.')
+	b.WriteString(enc.EncodeToString(sig))
+	return b.String(), nil
+}
+
+// Verifier checks signed session tokens. It could have several keys, i.e. during key rotation.
+type Verifier struct {
+	keys map[string]*Key
+}
+
+// NewVerifier creates verifier that accepts tokens signed with any of specified keys.
+func NewVerifier(keys []*Key) (*Verifier, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no verification keys")
+	}
+	v := &Verifier{keys: make(map[string]*Key, len(keys))}
+	for _, key := range keys {
+		if _, ok := v.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id: %s", key.ID)
+		}
+		v.keys[key.ID] = key
+	}
+	return v, nil
+}
+
+// Verify checks token signature and expiration and returns session that token carries.
+// Tokens that are bound to client are accepted only from that client.
+func (v *Verifier) Verify(token, client string) (*session.Session, error) {
+	sigIdx := strings.LastIndexByte(token, '.')
+	kidIdx := strings.IndexByte(token, '.')
+	if sigIdx == -1 || kidIdx == sigIdx {
+		return nil, ErrInvalid
+	}
+	key, ok := v.keys[token[:kidIdx]]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	sig, err := enc.DecodeString(token[sigIdx+1:])
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	signed := []byte(token[:sigIdx])
+	if !verify(key, signed, sig) {
+		return nil, ErrInvalid
+	}
+	payload, err := enc.DecodeString(token[kidIdx+1 : sigIdx])
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	var c claims
+	if err = json.Unmarshal(payload, &c); err != nil {
+		return nil, ErrInvalid
+	}
+	if time.Now().Unix() >= c.ExpiresAt {
+		return nil, ErrExpired
+	}
+	if c.Client != "" && c.Client != client {
+		return nil, ErrClientMismatch
+	}
+	return &c.Session, nil
+}
+
+// IsToken returns true if session id looks like signed token.
+func IsToken(id string) bool {
+	return strings.IndexByte(id, '.') != -1
+}
+
+func sign(key *Key, private ed25519.PrivateKey, data []byte) []byte {
+	if key.Alg == AlgEd25519 {
+		return ed25519.Sign(private, data)
+	}
+	mac := hmac.New(sha256.New, key.Secret)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func verify(key *Key, data, sig []byte) bool {
+	if key.Alg == AlgEd25519 {
+		return ed25519.Verify(key.Secret, data, sig)
+	}
+	mac := hmac.New(sha256.New, key.Secret)
+	mac.Write(data)
+	return hmac.Equal(mac.Sum(nil), sig)
+}
@@ -0,0 +1,119 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adwski/vidi/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func b64(b []byte) string {
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func TestParseKey(t *testing.T) {
+	secret := b64([]byte(strings.Repeat("s", 32)))
+
+	key, err := ParseKey("k1:hs256:" + secret)
+	require.NoError(t, err)
+	assert.Equal(t, "k1", key.ID)
+	assert.Equal(t, AlgHS256, key.Alg)
+
+	_, err = ParseKey("k2:ed25519:" + secret)
+	require.NoError(t, err)
+
+	for _, spec := range []string{
+		"",
+		"k1:hs256",
+		"k.1:hs256:" + secret,
+		"k1:rs256:" + secret,
+		"k1:hs256:" + b64([]byte("short")),
+		"k1:ed25519:" + b64([]byte(strings.Repeat("s", 64))),
+		"k1:hs256:???",
+	} {
+		_, err = ParseKey(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	var (
+		seed     = []byte(strings.Repeat("e", ed25519.SeedSize))
+		public   = ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey) //nolint:errcheck // always ed25519
+		hmacKey  = &Key{ID: "h1", Alg: AlgHS256, Secret: []byte(strings.Repeat("h", 32))}
+		hmacKey2 = &Key{ID: "h2", Alg: AlgHS256, Secret: []byte(strings.Repeat("x", 32))}
+		sess     = &session.Session{ID: "sess", VideoID: "vid", Location: "loc"}
+	)
+	verifier, err := NewVerifier([]*Key{hmacKey, hmacKey2, {ID: "e1", Alg: AlgEd25519, Secret: public}})
+	require.NoError(t, err)
+
+	for _, key := range []*Key{hmacKey, hmacKey2, {ID: "e1", Alg: AlgEd25519, Secret: seed}} {
+		t.Run(key.ID, func(t *testing.T) {
+			signer, errS := NewSigner(key, time.Minute)
+			require.NoError(t, errS)
+
+			tkn, errS := signer.Sign(sess, "")
+			require.NoError(t, errS)
+			assert.True(t, IsToken(tkn))
+			assert.True(t, strings.HasPrefix(tkn, key.ID+"."))
+
+			got, errV := verifier.Verify(tkn, "1.2.3.4")
+			require.NoError(t, errV)
+			assert.Equal(t, sess, got)
+
+			// tampered payload
+			parts := strings.Split(tkn, ".")
+			forged := base64.RawURLEncoding.EncodeToString([]byte(`{"sid":"sess","vid":"vid","loc":"other","exp":9999999999}`))
+			_, errV = verifier.Verify(parts[0]+"."+forged+"."+parts[2], "")
+			require.ErrorIs(t, errV, ErrInvalid)
+
+			// client binding
+			tkn, errS = signer.Sign(sess, "1.2.3.4")
+			require.NoError(t, errS)
+			_, errV = verifier.Verify(tkn, "1.2.3.4")
+			require.NoError(t, errV)
+			_, errV = verifier.Verify(tkn, "4.3.2.1")
+			require.ErrorIs(t, errV, ErrClientMismatch)
+		})
+	}
+}
+
+func TestVerifyErrors(t *testing.T) {
+	key := &Key{ID: "h1", Alg: AlgHS256, Secret: []byte(strings.Repeat("h", 32))}
+	sess := &session.Session{ID: "sess", VideoID: "vid", Location: "loc"}
+	verifier, err := NewVerifier([]*Key{key})
+	require.NoError(t, err)
+
+	signer, err := NewSigner(key, time.Minute)
+	require.NoError(t, err)
+	signer.ttl = -time.Second
+	expired, err := signer.Sign(sess, "")
+	require.NoError(t, err)
+	_, err = verifier.Verify(expired, "")
+	require.ErrorIs(t, err, ErrExpired)
+
+	// key is rotated out
+	other, err := NewSigner(&Key{ID: "h0", Alg: AlgHS256, Secret: key.Secret}, time.Minute)
+	require.NoError(t, err)
+	tkn, err := other.Sign(sess, "")
+	require.NoError(t, err)
+	_, err = verifier.Verify(tkn, "")
+	require.ErrorIs(t, err, ErrUnknownKey)
+
+	for _, tkn = range []string{"", "sess", "h1.abc", "h1.abc.!!!", "h1.!!!.abc"} {
+		_, err = verifier.Verify(tkn, "")
+		assert.Error(t, err, tkn)
+	}
+
+	_, err = NewVerifier(nil)
+	require.Error(t, err)
+	_, err = NewVerifier([]*Key{key, key})
+	require.Error(t, err)
+	_, err = NewSigner(key, 0)
+	require.Error(t, err)
+}
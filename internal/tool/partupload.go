@@ -0,0 +1,284 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/adwski/vidi/internal/api/video/grpc/userside/pb"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/file"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultUploadWorkers = 4
+	defaultUploadRetries = 3
+	defaultUploadBackoff = 500 * time.Millisecond
+	maxUploadBackoff     = 10 * time.Second
+	defaultVerifyDelay   = time.Second
+
+	// uploadVerifyRounds is an amount of attempts to re-upload parts
+	// that were not accepted by videoapi.
+	uploadVerifyRounds = 3
+)
+
+// Upload worker states.
+const (
+	workerIdle = iota
+	workerUploading
+	workerRetrying
+	workerDone
+)
+
+type (
+	// uploadWorker is a state of single upload worker, it is displayed in upload screen.
+	uploadWorker struct {
+		state   int
+		part    uint
+		attempt uint
+	}
+
+	// partsUploader uploads file parts with pool of workers.
+	// Failed part uploads are retried with backoff.
+	partsUploader struct {
+		t         *Tool
+		f         io.ReaderAt
+		url       string
+		workers   []uploadWorker
+		mx        sync.Mutex
+		partSize  uint64
+		completed uint64
+		total     uint64
+	}
+)
+
+// errPartRejected is returned when uploader rejects part and retry will not help.
+var errPartRejected = errors.New("part is rejected")
+
+func (w uploadWorker) String() string {
+	switch w.state {
+	case workerUploading:
+		return fmt.Sprintf("uploading part %d", w.part)
+	case workerRetrying:
+		return fmt.Sprintf("retrying part %d (attempt %d)", w.part, w.attempt)
+	case workerDone:
+		return "done"
+	default:
+		return "idle"
+	}
+}
+
+// uploadAndVerify uploads parts and then checks part statuses in videoapi.
+// Parts that are invalid (i.e. corrupted during upload) or missing are uploaded again.
+func (t *Tool) uploadAndVerify(f io.ReaderAt, upload *Upload, uploadURL string, parts []file.Part, size uint64) error {
+	completed := size
+	for _, p := range parts {
+		completed -= uint64(p.Size)
+	}
+	for round := 0; ; round++ {
+		pu := &partsUploader{
+			t:         t,
+			f:         f,
+			url:       uploadURL,
+			partSize:  upload.getPartSize(),
+			completed: completed,
+			total:     size,
+		}
+		if err := pu.upload(parts); err != nil {
+			return err
+		}
+		badParts, newURL, err := t.getPartsToReupload(upload)
+		if err != nil {
+			return err
+		}
+		if len(badParts) == 0 {
+			return nil
+		}
+		if round == uploadVerifyRounds {
+			return fmt.Errorf("%d parts are not accepted after %d re-uploads", len(badParts), round)
+		}
+		t.logger.Debug("re-uploading parts", zap.Int("round", round+1), zap.Any("parts", badParts))
+		for _, p := range badParts {
+			completed -= uint64(p.Size)
+		}
+		parts, uploadURL = badParts, newURL
+	}
+}
+
+// getPartsToReupload returns parts which are not accepted by videoapi
+// along with new upload url. Uploader notifies videoapi asynchronously,
+// so statuses are checked after delay.
+func (t *Tool) getPartsToReupload(upload *Upload) ([]file.Part, string, error) {
+	time.Sleep(t.verifyDelay)
+	resp, err := t.videoapi.GetVideo(t.getUserMDCtx(), &pb.VideoRequest{Id: upload.ID})
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to get video info: %w", err)
+	}
+	if st := model.Status(resp.Status); st != model.StatusCreated && st != model.StatusUploading {
+		// all parts are accepted
+		return nil, "", nil
+	}
+	var hasBadParts bool
+	for _, p := range resp.UploadParts {
+		if p.Status != model.PartStatusOK {
+			hasBadParts = true
+			break
+		}
+	}
+	if !hasBadParts {
+		return nil, "", nil
+	}
+	// resume upload to get new session, previous one could be expired already
+	partsToUpload, uploadURL, _, err := t.checkUploadPartsState(upload)
+	if err != nil {
+		return nil, "", err
+	}
+	parts := make([]file.Part, 0, len(partsToUpload))
+	for _, p := range upload.Parts {
+		if _, ok := partsToUpload[uint32(p.Num)]; ok {
+			parts = append(parts, p)
+		}
+	}
+	return parts, uploadURL, nil
+}
+
+// upload spawns workers and waits until all parts are uploaded.
+// Upload stops on first part that could not be uploaded.
+func (pu *partsUploader) upload(parts []file.Part) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		errU    error
+		partsCh = make(chan file.Part)
+	)
+	pu.workers = make([]uploadWorker, min(pu.t.uploadWorkers, uint(len(parts))))
+	for i := range pu.workers {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for part := range partsCh {
+				if err := pu.uploadPart(ctx, w, part); err != nil {
+					errOnce.Do(func() {
+						errU = err
+						cancel()
+					})
+					return
+				}
+			}
+			pu.setWorkerState(w, uploadWorker{state: workerDone})
+		}(i)
+	}
+Loop:
+	for _, part := range parts {
+		select {
+		case <-ctx.Done():
+			break Loop
+		case partsCh <- part:
+		}
+	}
+	close(partsCh)
+	wg.Wait()
+	return errU
+}
+
+// uploadPart reads part from file and uploads it. Network errors and
+// responses that may succeed later are retried with exponential backoff.
+func (pu *partsUploader) uploadPart(ctx context.Context, w int, part file.Part) error {
+	// Reading body bytes fully, otherwise resty will not send Content-Length
+	b := make([]byte, part.Size)
+	if _, err := pu.f.ReadAt(b, int64(part.Num)*int64(pu.partSize)); err != nil {
+		return fmt.Errorf("unable to read file part %d: %w", part.Num, err)
+	}
+	backoff := pu.t.uploadBackoff
+	for attempt := uint(0); ; attempt++ {
+		state := uploadWorker{state: workerUploading, part: part.Num, attempt: attempt}
+		if attempt > 0 {
+			state.state = workerRetrying
+		}
+		pu.setWorkerState(w, state)
+
+		wait, err := pu.post(ctx, part.Num, b)
+		if err == nil {
+			pu.partCompleted(w, part)
+			return nil
+		}
+		if errors.Is(err, errPartRejected) || attempt >= pu.t.uploadRetries {
+			return fmt.Errorf("unable to upload part %d: %w", part.Num, err)
+		}
+		pu.t.logger.Debug("part upload failed, retrying",
+			zap.Uint("num", part.Num),
+			zap.Uint("attempt", attempt+1),
+			zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(max(backoff, wait)):
+		}
+		backoff = min(2*backoff, maxUploadBackoff)
+	}
+}
+
+// post sends part to uploader. It also returns time to wait before retry if uploader asks for it.
+func (pu *partsUploader) post(ctx context.Context, num uint, b []byte) (time.Duration, error) {
+	resp, err := pu.t.httpC.R().
+		SetContext(ctx).
+		SetBody(b).
+		SetHeader("Content-Type", "application/x-vidi-mediapart").
+		Post(pu.url + "/" + strconv.FormatUint(uint64(num), 10))
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	if !resp.IsError() {
+		return 0, nil
+	}
+	err = fmt.Errorf("server responded with error status: %s", resp.Status())
+	switch resp.StatusCode() {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		// uploader is busy or upload is throttled
+		seconds, _ := strconv.Atoi(resp.Header().Get("Retry-After"))
+		return time.Duration(seconds) * time.Second, err
+	case http.StatusUnprocessableEntity:
+		// checksum mismatch, part was corrupted in transit
+		return 0, err
+	}
+	if resp.StatusCode() >= http.StatusInternalServerError {
+		return 0, err
+	}
+	return 0, errors.Join(errPartRejected, err)
+}
+
+func (pu *partsUploader) setWorkerState(w int, state uploadWorker) {
+	pu.mx.Lock()
+	defer pu.mx.Unlock()
+	pu.workers[w] = state
+	pu.sendProgressUnsafe()
+}
+
+func (pu *partsUploader) partCompleted(w int, part file.Part) {
+	pu.mx.Lock()
+	defer pu.mx.Unlock()
+	pu.completed += uint64(part.Size)
+	pu.workers[w] = uploadWorker{state: workerIdle}
+	pu.sendProgressUnsafe()
+}
+
+// sendProgressUnsafe sends snapshot of upload progress, it is called under lock
+// so progress messages are delivered in order.
+func (pu *partsUploader) sendProgressUnsafe() {
+	workers := make([]uploadWorker, len(pu.workers))
+	copy(workers, pu.workers)
+	pu.t.fb <- uploadProgress{
+		completed: pu.completed,
+		total:     pu.total,
+		workers:   workers,
+	}
+}
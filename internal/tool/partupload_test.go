@@ -0,0 +1,173 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/adwski/vidi/internal/api/video/grpc/userside/pb"
+	"github.com/adwski/vidi/internal/api/video/model"
+	"github.com/adwski/vidi/internal/file"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+type fakeVideoAPI struct {
+	pb.UsersideapiClient
+	responses []*pb.VideoResponse
+	requests  []*pb.VideoRequest
+}
+
+func (f *fakeVideoAPI) GetVideo(_ context.Context, req *pb.VideoRequest, _ ...grpc.CallOption) (*pb.VideoResponse, error) {
+	f.requests = append(f.requests, req)
+	resp := f.responses[0]
+	f.responses = f.responses[1:]
+	return resp, nil
+}
+
+// fakeUploader stores received parts, first attempt of every part in failures
+// is answered with specified status.
+type fakeUploader struct {
+	failures map[string]int
+	parts    map[string][]byte
+	attempts map[string]int
+	mx       sync.Mutex
+}
+
+func (f *fakeUploader) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b, _ := io.ReadAll(r.Body)
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	f.attempts[r.URL.Path]++
+	if code, ok := f.failures[r.URL.Path]; ok && f.attempts[r.URL.Path] == 1 {
+		w.WriteHeader(code)
+		return
+	}
+	f.parts[r.URL.Path] = b
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newTestUploadTool(t *testing.T, api pb.UsersideapiClient) *Tool {
+	t.Helper()
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+	tool := &Tool{
+		logger:        logger,
+		videoapi:      api,
+		httpC:         resty.New(),
+		fb:            make(chan tea.Msg),
+		state:         &State{Users: []User{{Name: "test"}}, CurrentUser: 0},
+		uploadWorkers: 2,
+		uploadRetries: 1,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tool.fb:
+			}
+		}
+	}()
+	return tool
+}
+
+func TestTool_uploadAndVerify(t *testing.T) {
+	data := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	upload := &Upload{
+		ID:       "vid",
+		PartSize: 10,
+		Parts: []file.Part{
+			{Num: 0, Size: 10, Checksum: "c0"},
+			{Num: 1, Size: 10, Checksum: "c1"},
+			{Num: 2, Size: 10, Checksum: "c2"},
+			{Num: 3, Size: 6, Checksum: "c3"},
+		},
+	}
+	up := &fakeUploader{
+		failures: map[string]int{
+			"/sess/1": http.StatusServiceUnavailable,
+			"/sess/2": http.StatusUnprocessableEntity,
+		},
+		parts:    make(map[string][]byte),
+		attempts: make(map[string]int),
+	}
+	srv := httptest.NewServer(up)
+	defer srv.Close()
+
+	api := &fakeVideoAPI{
+		responses: []*pb.VideoResponse{
+			// part 3 is reported as invalid after upload
+			{Status: int32(model.StatusUploading), UploadParts: []*pb.VideoPart{
+				{Num: 0, Status: model.PartStatusOK},
+				{Num: 1, Status: model.PartStatusOK},
+				{Num: 2, Status: model.PartStatusOK},
+				{Num: 3, Status: model.PartStatusInvalid, Checksum: "c3"},
+			}},
+			// resume upload
+			{Status: int32(model.StatusUploading), UploadUrl: srv.URL + "/sess2", UploadParts: []*pb.VideoPart{
+				{Num: 0, Status: model.PartStatusOK},
+				{Num: 1, Status: model.PartStatusOK},
+				{Num: 2, Status: model.PartStatusOK},
+				{Num: 3, Status: model.PartStatusInvalid, Checksum: "c3"},
+			}},
+			{Status: int32(model.StatusUploaded)},
+		},
+	}
+	tool := newTestUploadTool(t, api)
+
+	err := tool.uploadAndVerify(bytes.NewReader(data), upload, srv.URL+"/sess", upload.Parts, uint64(len(data)))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]byte{
+		"/sess/0":  data[0:10],
+		"/sess/1":  data[10:20],
+		"/sess/2":  data[20:30],
+		"/sess/3":  data[30:],
+		"/sess2/3": data[30:],
+	}, up.parts)
+	assert.Equal(t, 2, up.attempts["/sess/1"])
+	assert.Equal(t, 2, up.attempts["/sess/2"])
+	require.Len(t, api.requests, 3)
+	assert.False(t, api.requests[0].ResumeUpload)
+	assert.True(t, api.requests[1].ResumeUpload)
+}
+
+func TestTool_uploadAndVerifyErrors(t *testing.T) {
+	data := []byte("0123456789abcdefghij")
+	upload := &Upload{
+		ID:       "vid",
+		PartSize: 10,
+		Parts:    []file.Part{{Num: 0, Size: 10}, {Num: 1, Size: 10}},
+	}
+	up := &fakeUploader{
+		failures: map[string]int{"/sess/1": http.StatusNotFound},
+		parts:    make(map[string][]byte),
+		attempts: make(map[string]int),
+	}
+	srv := httptest.NewServer(up)
+	defer srv.Close()
+	tool := newTestUploadTool(t, &fakeVideoAPI{})
+
+	// part rejection is not retried
+	err := tool.uploadAndVerify(bytes.NewReader(data), upload, srv.URL+"/sess", upload.Parts, uint64(len(data)))
+	require.ErrorContains(t, err, "unable to upload part 1")
+	assert.Equal(t, 1, up.attempts["/sess/1"])
+}
+
+func TestUploadWorker_String(t *testing.T) {
+	assert.Equal(t, "idle", uploadWorker{}.String())
+	assert.Equal(t, "uploading part 2", uploadWorker{state: workerUploading, part: 2}.String())
+	assert.Equal(t, "retrying part 2 (attempt 1)", uploadWorker{state: workerRetrying, part: 2, attempt: 1}.String())
+	assert.Equal(t, "done", uploadWorker{state: workerDone}.String())
+}
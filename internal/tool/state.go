@@ -9,6 +9,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/adwski/vidi/internal/api/video/model"
 	"github.com/adwski/vidi/internal/file"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -60,6 +61,7 @@ type (
 		Name     string      `json:"name"`
 		Filename string      `json:"filename"`
 		Parts    []file.Part `json:"parts"`
+		PartSize uint64      `json:"part_size,omitempty"`
 	}
 
 	QuotaParam struct {
@@ -219,3 +221,12 @@ func isURL(s string) error {
 	}
 	return nil
 }
+
+// getPartSize returns part size of upload.
+// Uploads that were started before part size became configurable have default part size.
+func (u *Upload) getPartSize() uint64 {
+	if u.PartSize == 0 {
+		return model.DefaultPartSize
+	}
+	return u.PartSize
+}
@@ -45,9 +45,11 @@ import (
 	"os"
 	"os/signal"
 	"sync"
+	"time"
 
 	userapi "github.com/adwski/vidi/internal/api/user/client"
 	videoapi "github.com/adwski/vidi/internal/api/video/grpc/userside/pb"
+	"github.com/adwski/vidi/internal/api/video/model"
 	"github.com/adwski/vidi/internal/logging"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/enescakir/emoji"
@@ -89,6 +91,16 @@ type (
 		dir string
 		// file picker dir
 		filePickerDir string
+		// size of parts of new uploads
+		partSize uint64
+		// amount of parallel part uploads
+		uploadWorkers uint
+		// amount of retries of failed part upload
+		uploadRetries uint
+		// initial delay between part upload retries
+		uploadBackoff time.Duration
+		// delay before checking part statuses after upload
+		verifyDelay time.Duration
 
 		// flag indicating that user selected to enter credentials
 		enterCreds bool
@@ -131,6 +143,9 @@ func New() (*Tool, error) {
 func NewWithConfig(cfg Config) (*Tool, error) {
 	fs := pflag.NewFlagSet("main", pflag.ContinueOnError)
 	defFPDir := fs.StringP("default-file-picker-dir", "d", "", "default upload file picker dir")
+	partSize := fs.Uint64("part-size", model.DefaultPartSize, "upload part size in bytes")
+	uploadWorkers := fs.Uint("upload-workers", defaultUploadWorkers, "amount of parts uploaded in parallel")
+	uploadRetries := fs.Uint("upload-retries", defaultUploadRetries, "amount of retries of failed part upload")
 	if err := fs.Parse(os.Args[1:]); err != nil {
 		return nil, fmt.Errorf("cannot parse command line arguments: %w", err)
 	}
@@ -145,10 +160,16 @@ func NewWithConfig(cfg Config) (*Tool, error) {
 		return nil, fmt.Errorf("cannot configure logger: %w", err)
 	}
 	t := &Tool{
-		logger: logger,
-		dir:    dir,
-		httpC:  resty.New(),
-		fb:     make(chan tea.Msg),
+		logger:   logger,
+		dir:      dir,
+		httpC:    resty.New(),
+		fb:       make(chan tea.Msg),
+		partSize: *partSize,
+
+		uploadWorkers: max(*uploadWorkers, 1),
+		uploadRetries: *uploadRetries,
+		uploadBackoff: defaultUploadBackoff,
+		verifyDelay:   defaultVerifyDelay,
 	}
 	if t.filePickerDir, err = getFilePickerDefaultDir(*defFPDir, cfg.EnforceHomeDir); err != nil {
 		return nil, err
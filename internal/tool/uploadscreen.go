@@ -2,6 +2,7 @@ package tool
 
 import (
 	"errors"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/filepicker"
@@ -30,6 +31,7 @@ type (
 		selectedFile     string
 		filePicker       filepicker.Model
 		progress         progress.Model
+		workers          []uploadWorker
 		keys             keyMap
 		uploading        bool
 		done             bool
@@ -47,6 +49,7 @@ type (
 	}
 
 	uploadProgress struct {
+		workers   []uploadWorker
 		completed uint64
 		total     uint64
 	}
@@ -178,7 +181,8 @@ func (s *sUpload) update(msg tea.Msg) (tea.Cmd, *outerControl) {
 		cmd := s.progress.SetPercent(1.0) // just in case
 		return cmd, nil
 	case uploadProgress:
-		// update progress bar percentage
+		// update progress bar percentage and workers state
+		s.workers = m.workers
 		cmd := s.progress.SetPercent(float64(m.completed) / float64(m.total))
 		return cmd, nil
 	case progress.FrameMsg:
@@ -221,7 +225,14 @@ func (s *sUpload) renderUploadProgress() string {
 	case s.done:
 		sb.WriteString("Upload completed successfully! Press any key to continue...\n\n")
 	}
-	return sb.String() + s.progress.View()
+	sb.WriteString(s.progress.View())
+	if !s.done && len(s.workers) > 0 {
+		sb.WriteString("\n")
+		for i, w := range s.workers {
+			sb.WriteString("\nWorker " + strconv.Itoa(i+1) + ": " + w.String())
+		}
+	}
+	return sb.String()
 }
 
 func (s *sUpload) renderForm() string {
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"strconv"
 	"time"
@@ -17,10 +16,6 @@ import (
 	"google.golang.org/grpc/metadata"
 )
 
-const (
-	partSize = 10 * 1024 * 1024
-)
-
 func (t *Tool) getWatchURL(vid string) {
 	ctx := t.getUserMDCtx()
 	resp, err := t.videoapi.WatchVideo(ctx, &pb.WatchRequest{Id: vid})
@@ -111,33 +106,15 @@ func (t *Tool) resumeUploadFileNotify(upload *Upload) {
 		return
 	}
 
-	var offset = size - uint64(partSize*len(partsToUpload))
-	t.fb <- uploadProgress{completed: offset, total: size}
-
-	for n, part := range partsToUpload {
-		if _, err = f.Seek(int64(n)*int64(partSize), io.SeekStart); err != nil {
-			t.fb <- fmt.Errorf("unable to seek file part %d: %w", n, err)
-		}
-
-		// Reading body bytes fully, otherwise resty will not send Content-Length
-		b, errB := io.ReadAll(io.LimitReader(f, int64(part.Size)))
-		if errB != nil {
-			t.fb <- fmt.Errorf("unable to read file part %d: %w", n, errB)
-		}
-		resp, rErr := t.httpC.R().
-			SetBody(b).
-			SetHeader("Content-Type", "application/x-vidi-mediapart").
-			Post(uploadURL + "/" + strconv.FormatUint(uint64(part.Num), 10))
-		if rErr != nil {
-			t.fb <- fmt.Errorf("unable to upload part %d: %w", n, rErr)
-			return
-		}
-		if resp.IsError() {
-			t.fb <- fmt.Errorf("server responded with error status: %s", resp.Status())
-			return
+	parts := make([]file.Part, 0, len(partsToUpload))
+	for _, p := range upload.Parts {
+		if _, ok := partsToUpload[uint32(p.Num)]; ok {
+			parts = append(parts, p)
 		}
-		offset += part.Size
-		t.fb <- uploadProgress{completed: offset, total: size}
+	}
+	if err = t.uploadAndVerify(f, upload, uploadURL, parts, size); err != nil {
+		t.fb <- err
+		return
 	}
 	t.fb <- uploadCompleted{}
 }
@@ -190,7 +167,7 @@ func (t *Tool) uploadFileNotify(name, filePath string) {
 	upload := t.state.activeUserUnsafe().CurrentUpload
 
 	// create video in videoapi
-	cv, err := t.createVideo(name, size, upload.Parts)
+	cv, err := t.createVideo(name, size, upload.PartSize, upload.Parts)
 	if err != nil {
 		t.fb <- err
 		return
@@ -202,6 +179,11 @@ func (t *Tool) uploadFileNotify(name, filePath string) {
 		t.fb <- err
 		return
 	}
+	if model.Status(cv.Status) == model.StatusReady {
+		// same content was uploaded before, video is created as alias
+		t.fb <- uploadCompleted{wasCompletedBefore: true}
+		return
+	}
 
 	// upload parts
 	f, err := os.Open(filePath)
@@ -220,27 +202,9 @@ func (t *Tool) uploadFileNotify(name, filePath string) {
 		return
 	}
 
-	var offset uint
-	for _, part := range upload.Parts {
-		// Reading body bytes fully, otherwise resty will not send Content-Length
-		b, errB := io.ReadAll(io.LimitReader(f, int64(part.Size)))
-		if errB != nil {
-			t.fb <- fmt.Errorf("unable to read file part %d: %w", part.Num, errB)
-		}
-		resp, rErr := t.httpC.R().
-			SetBody(b).
-			SetHeader("Content-Type", "application/x-vidi-mediapart").
-			Post(cv.UploadUrl + "/" + strconv.FormatUint(uint64(part.Num), 10))
-		if rErr != nil {
-			t.fb <- fmt.Errorf("unable to upload part: %w", rErr)
-			return
-		}
-		if resp.IsError() {
-			t.fb <- fmt.Errorf("server responded with error status: %s", resp.Status())
-			return
-		}
-		offset += part.Size
-		t.fb <- uploadProgress{completed: uint64(offset), total: size}
+	if err = t.uploadAndVerify(f, upload, cv.UploadUrl, upload.Parts, size); err != nil {
+		t.fb <- err
+		return
 	}
 	t.fb <- uploadCompleted{}
 }
@@ -253,7 +217,7 @@ func (t *Tool) prepareUpload(filePath, name string) (uint64, error) {
 	if err = t.checkQuotas(size); err != nil {
 		return 0, err
 	}
-	parts, err := file.MakePartsFromFile(filePath, partSize, size)
+	parts, err := file.MakePartsFromFile(filePath, t.partSize, size)
 	if err != nil {
 		return 0, fmt.Errorf("cannot prepare file parts: %w", err)
 	}
@@ -261,6 +225,7 @@ func (t *Tool) prepareUpload(filePath, name string) (uint64, error) {
 		Name:     name,
 		Filename: filePath,
 		Parts:    parts,
+		PartSize: t.partSize,
 	}
 	t.logger.Debug("prepared upload info", zap.Any("info", t.state.activeUserUnsafe().CurrentUpload))
 	return size, t.state.persist()
@@ -281,7 +246,7 @@ func (t *Tool) checkQuotas(size uint64) error {
 	return nil
 }
 
-func (t *Tool) createVideo(name string, size uint64, uploadParts []file.Part) (*pb.VideoResponse, error) {
+func (t *Tool) createVideo(name string, size, partSize uint64, uploadParts []file.Part) (*pb.VideoResponse, error) {
 	ctx := t.getUserMDCtx()
 	parts := make([]*pb.VideoPart, 0, len(uploadParts))
 	for _, part := range uploadParts {
@@ -292,9 +257,10 @@ func (t *Tool) createVideo(name string, size uint64, uploadParts []file.Part) (*
 		})
 	}
 	cvResp, err := t.videoapi.CreateVideo(ctx, &pb.CreateVideoRequest{
-		Size:  size,
-		Name:  name,
-		Parts: parts,
+		Size:     size,
+		Name:     name,
+		PartSize: partSize,
+		Parts:    parts,
 	})
 	t.logger.Debug("parts", zap.Any("parts", parts), zap.Any("cvResp", cvResp))
 	if err != nil {